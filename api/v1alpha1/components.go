@@ -1,6 +1,10 @@
 package v1alpha1
 
-import "fmt"
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
 
 const (
 	CoreName        = "core"
@@ -11,8 +15,159 @@ const (
 	NotaryName      = "notary"
 	ClairName       = "clair"
 	ChartMuseumName = "chartmuseum"
+	NginxName       = "nginx"
+	ExposeName      = "expose"
+
+	NotaryCertificateName    = "notary-certificate"
+	PublicCertificateName    = "public-certificate"
+	InternalTLSName          = "internal-tls"
+	CertificateAuthorityName = "ca"
+	NotaryServerName         = "notary-server"
+	NotarySignerName         = "notary-signer"
 )
 
 func (h *Harbor) NormalizeComponentName(componentName string) string {
 	return fmt.Sprintf("%s-%s", h.GetName(), componentName)
 }
+
+// AppliedAdminPasswordSecretName returns the name of the secret holding the admin password currently set in Harbor.
+func (h *Harbor) AppliedAdminPasswordSecretName() string {
+	return h.NormalizeComponentName("applied-admin-password")
+}
+
+// GetCertificatesMode returns how certificates used between components are issued.
+// The reconciler writes the default mode in the spec, CertManager is assumed until then.
+func (h *Harbor) GetCertificatesMode() CertificatesMode {
+	if h.Spec.Certificates == nil || h.Spec.Certificates.Mode == "" {
+		return CertificatesModeCertManager
+	}
+
+	return h.Spec.Certificates.Mode
+}
+
+// TokenCertificateSecretName returns the name of the TLS secret signing and verifying registry tokens.
+func (h *Harbor) TokenCertificateSecretName() string {
+	if h.GetCertificatesMode() == CertificatesModeProvided && h.Spec.Certificates.TokenSecretName != "" {
+		return h.Spec.Certificates.TokenSecretName
+	}
+
+	return h.NormalizeComponentName(CertificateName)
+}
+
+// NotaryCertificateSecretName returns the name of the TLS secret of notary signer.
+func (h *Harbor) NotaryCertificateSecretName() string {
+	if h.GetCertificatesMode() == CertificatesModeProvided && h.Spec.Certificates.NotarySignerSecretName != "" {
+		return h.Spec.Certificates.NotarySignerSecretName
+	}
+
+	return h.NormalizeComponentName(NotaryCertificateName)
+}
+
+// IsPublicCertificateManaged returns whether the certificate of ingresses is requested to cert-manager.
+// It is the case when no TLS secret is specified and an issuer is configured.
+func (h *Harbor) IsPublicCertificateManaged() bool {
+	return h.Spec.TLSSecretName == "" && h.Spec.CertificateIssuerRef.Name != ""
+}
+
+// PublicTLSSecretName returns the name of the TLS secret used by ingresses.
+func (h *Harbor) PublicTLSSecretName() string {
+	if h.IsPublicCertificateManaged() {
+		return h.NormalizeComponentName(PublicCertificateName)
+	}
+
+	return h.Spec.TLSSecretName
+}
+
+// InternalEndpointTLSSecretName returns the name of the TLS secret of the ingresses of the internal endpoint.
+func (h *Harbor) InternalEndpointTLSSecretName() string {
+	if h.Spec.InternalEndpoint == nil || h.Spec.InternalEndpoint.TLSSecretName == "" {
+		return h.PublicTLSSecretName()
+	}
+
+	return h.Spec.InternalEndpoint.TLSSecretName
+}
+
+// IsInternalTLSEnabled returns whether components are served over HTTPS.
+func (h *Harbor) IsInternalTLSEnabled() bool {
+	return h.Spec.InternalTLS != nil && h.Spec.InternalTLS.Enabled
+}
+
+// InternalTLSSecretName returns the name of the TLS secret serving the component with internal TLS.
+func (h *Harbor) InternalTLSSecretName(componentName string) string {
+	return h.NormalizeComponentName(fmt.Sprintf("%s-%s", componentName, InternalTLSName))
+}
+
+// GetExposeType returns how components are exposed, with ingresses by default.
+func (h *Harbor) GetExposeType() ExposeType {
+	if h.Spec.Expose == nil || h.Spec.Expose.Type == "" {
+		return ExposeTypeIngress
+	}
+
+	return h.Spec.Expose.Type
+}
+
+// IsIngressEnabled returns whether components are exposed with ingresses, replaced by virtual services in an istio mesh.
+func (h *Harbor) IsIngressEnabled() bool {
+	return h.GetExposeType() == ExposeTypeIngress && !h.IsServiceMeshEnabled()
+}
+
+// IsServiceMeshEnabled returns whether components are part of a service mesh.
+func (h *Harbor) IsServiceMeshEnabled() bool {
+	return h.Spec.ServiceMesh != ""
+}
+
+// IsExposeProxyEnabled returns whether components are exposed through the nginx proxy.
+func (h *Harbor) IsExposeProxyEnabled() bool {
+	switch h.GetExposeType() {
+	case ExposeTypeLoadBalancer, ExposeTypeNodePort:
+		return true
+	case ExposeTypeGateway:
+		return h.Spec.Expose.Gateway != nil && h.Spec.Expose.Gateway.TLSPassthrough
+	default:
+		return false
+	}
+}
+
+// GetIngressController returns the controller of ingresses, selecting preset annotations.
+func (h *Harbor) GetIngressController() IngressController {
+	if h.Spec.Expose == nil || h.Spec.Expose.Ingress == nil || h.Spec.Expose.Ingress.Controller == "" {
+		return IngressControllerDefault
+	}
+
+	return h.Spec.Expose.Ingress.Controller
+}
+
+// IsIPv6Enabled returns whether IPv6 is one of the IP families of the service.
+func (f ServiceIPFamilies) IsIPv6Enabled() bool {
+	for _, family := range f.IPFamilies {
+		if family == corev1.IPv6Protocol {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsIPv6Enabled returns whether the component listens on IPv6 addresses.
+func (s *ComponentServiceSpec) IsIPv6Enabled() bool {
+	return s != nil && s.ServiceIPFamilies.IsIPv6Enabled()
+}
+
+// IsIPv6Enabled returns whether the proxy listens on IPv6 addresses.
+func (s *ExposeServiceSpec) IsIPv6Enabled() bool {
+	return s != nil && s.ServiceIPFamilies.IsIPv6Enabled()
+}
+
+// GetPasswordKey returns the key of the password in the secret of the Redis server.
+func (r *RedisSpec) GetPasswordKey() string {
+	if r.PasswordKey == "" {
+		return HarborRedisPasswordKey
+	}
+
+	return r.PasswordKey
+}
+
+// IsSentinel returns whether hosts are sentinels monitoring the Redis server.
+func (r *RedisSpec) IsSentinel() bool {
+	return r.SentinelMasterSet != ""
+}
@@ -85,3 +85,11 @@ func (component *RegistryControllerComponent) GetImage() string {
 
 	return *component.Image
 }
+
+func (spec *ExposeServiceSpec) GetImage() string {
+	if spec == nil || spec.Image == nil {
+		return "goharbor/nginx-photon:v1.10.0"
+	}
+
+	return *spec.Image
+}
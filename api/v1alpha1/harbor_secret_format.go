@@ -67,3 +67,8 @@ const (
 	// ipaddress:port[,weight,password,database_index]
 	HarborRegistryURLKey = "url"
 )
+
+const (
+	// HarborRedisPasswordKey is the default key of the password in the secret of a Redis server
+	HarborRedisPasswordKey = "password"
+)
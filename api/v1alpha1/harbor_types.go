@@ -54,14 +54,25 @@ type HarborSpec struct {
 	// +kubebuilder:validation:Pattern="^https?://.*$"
 	PublicURL string `json:"publicURL"`
 
-	// The name of the secret containing the TLS secret used for ingresses
+	// Hosts routed like the host of publicURL, with the same scheme.
+	// +optional
+	AdditionalHostnames []string `json:"additionalHostnames,omitempty"`
+
+	// An endpoint for clients inside the network, such as nodes pulling images through an internal DNS name.
+	// It is exposed with ingresses, with the Ingress type of exposure only.
+	// +optional
+	InternalEndpoint *InternalEndpointSpec `json:"internalEndpoint,omitempty"`
+
+	// The name of the secret containing the TLS secret used for ingresses.
+	// When empty and certificateIssuerRef is set, a certificate is requested to cert-manager for public hosts.
 	// +optional
 	TLSSecretName string `json:"tlsSecretName"`
 
 	// +kubebuilder:validation:Required
 	Components HarborComponents `json:"components,omitempty"`
 
-	// The name of the secret containing the password for root user
+	// The name of the secret containing the password for root user.
+	// The secret is generated if it does not exist. When its content changes, the password is changed in Harbor.
 	// +kubebuilder:validation:Required
 	AdminPasswordSecret string `json:"adminPasswordSecret"`
 
@@ -77,13 +88,292 @@ type HarborSpec struct {
 	// +optional
 	Paused bool `json:"paused,omitempty"`
 
-	// The issuer for Harbor certificates.
+	// The issuer for Harbor certificates, required with the CertManager certificates mode.
 	// If the 'kind' field is not set, or set to 'Issuer', an Issuer resource
 	// with the given name in the same namespace as the Certificate will be used.
 	// If the 'kind' field is set to 'ClusterIssuer', a ClusterIssuer with the
 	// provided name will be used.
 	// The 'name' field in this stanza is required at all times.
-	CertificateIssuerRef cmmeta.ObjectReference `json:"certificateIssuerRef"`
+	// +optional
+	CertificateIssuerRef cmmeta.ObjectReference `json:"certificateIssuerRef,omitempty"`
+
+	// How certificates used between components are issued.
+	// +optional
+	Certificates *CertificatesSpec `json:"certificates,omitempty"`
+
+	// The policy applied to resources managed by the operator when the Harbor is deleted.
+	// Delete removes all resources, Retain keeps persistent volume claims, generated secrets and certificates,
+	// Orphan keeps all resources.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// The backup to run before releasing resources when the Harbor is deleted.
+	// +optional
+	FinalBackup *FinalBackupJob `json:"finalBackup,omitempty"`
+
+	// The rotation of secrets shared between components.
+	// +optional
+	SecretRotation *SecretRotation `json:"secretRotation,omitempty"`
+
+	// TLS between components.
+	// +optional
+	InternalTLS *InternalTLSSpec `json:"internalTLS,omitempty"`
+
+	// The proxy used by components for outbound traffic, such as replications or vulnerability database updates.
+	// +optional
+	Proxy *ProxySpec `json:"proxy,omitempty"`
+
+	// Certificate authorities trusted by components for outbound traffic, in addition to the system ones.
+	// +optional
+	TrustedCABundle *TrustedCABundleSpec `json:"trustedCABundle,omitempty"`
+
+	// How Harbor is exposed to clients.
+	// +optional
+	Expose *ExposeSpec `json:"expose,omitempty"`
+
+	// The service mesh components are part of. Its sidecar is injected in their pods, and it handles mTLS between them.
+	// +optional
+	// +kubebuilder:validation:Enum=istio
+	ServiceMesh ServiceMesh `json:"serviceMesh,omitempty"`
+}
+
+type ServiceMesh string
+
+const (
+	// ServiceMeshIstio routes requests with Istio virtual services instead of ingresses.
+	ServiceMeshIstio ServiceMesh = "istio"
+)
+
+type InternalEndpointSpec struct {
+	// The url of the endpoint.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^https?://.*$"
+	URL string `json:"url"`
+
+	// The class of the ingresses of the endpoint, set in the kubernetes.io/ingress.class annotation.
+	// +optional
+	IngressClassName string `json:"ingressClassName,omitempty"`
+
+	// The name of the TLS secret of the ingresses of the endpoint, the one of public ingresses by default.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// Whether the registry sends clients to the token service of the endpoint instead of the public one.
+	// Clients of the public url must then reach the endpoint too.
+	// +optional
+	TokenRealm bool `json:"tokenRealm,omitempty"`
+}
+
+type ExposeType string
+
+const (
+	// ExposeTypeIngress exposes components with ingresses.
+	ExposeTypeIngress ExposeType = "Ingress"
+	// ExposeTypeLoadBalancer exposes a proxy routing to components with a LoadBalancer service.
+	ExposeTypeLoadBalancer ExposeType = "LoadBalancer"
+	// ExposeTypeNodePort exposes a proxy routing to components with a NodePort service.
+	ExposeTypeNodePort ExposeType = "NodePort"
+	// ExposeTypeRoute exposes components with OpenShift routes.
+	ExposeTypeRoute ExposeType = "Route"
+	// ExposeTypeGateway exposes components with Gateway API routes.
+	ExposeTypeGateway ExposeType = "Gateway"
+)
+
+type ExposeSpec struct {
+	// How components are exposed.
+	// +optional
+	// +kubebuilder:validation:Enum=Ingress;LoadBalancer;NodePort;Route;Gateway
+	Type ExposeType `json:"type,omitempty"`
+
+	// The customization of ingresses of core, portal, registry, chartmuseum and notary.
+	// +optional
+	Ingress *IngressSpec `json:"ingress,omitempty"`
+
+	// The proxy and its service, with LoadBalancer and NodePort types or TLS passthrough of the Gateway type.
+	// +optional
+	Service *ExposeServiceSpec `json:"service,omitempty"`
+
+	// The gateways the routes are attached to, with the Gateway type.
+	// +optional
+	Gateway *ExposeGatewaySpec `json:"gateway,omitempty"`
+
+	// The Istio gateways the virtual services are bound to, with the istio service mesh.
+	// +optional
+	Istio *ExposeIstioSpec `json:"istio,omitempty"`
+}
+
+type ExposeIstioSpec struct {
+	// The gateways, as <namespace>/<name>. Virtual services only route requests inside the mesh when empty.
+	// +optional
+	Gateways []string `json:"gateways,omitempty"`
+}
+
+type ExposeServiceSpec struct {
+	ServiceIPFamilies `json:",inline"`
+
+	// The image of the proxy.
+	// +optional
+	Image *string `json:"image,omitempty"`
+
+	// The number of replicas of the proxy.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Annotations added to the service, often read by cloud providers.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// The IP requested to the cloud provider, with the LoadBalancer type.
+	// +optional
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+
+	// Whether the service routes external traffic to node-local or cluster-wide endpoints.
+	// +optional
+	// +kubebuilder:validation:Enum=Local;Cluster
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+}
+
+type ExposeGatewaySpec struct {
+	// The gateways, or their listeners, the routes are attached to.
+	// +kubebuilder:validation:MinItems=1
+	ParentRefs []GatewayParentReference `json:"parentRefs"`
+
+	// Route TLS connections to the proxy, which terminates TLS, instead of HTTP requests to components.
+	// +optional
+	TLSPassthrough bool `json:"tlsPassthrough,omitempty"`
+}
+
+type GatewayParentReference struct {
+	// The name of the gateway.
+	Name string `json:"name"`
+
+	// The namespace of the gateway, the namespace of the Harbor by default.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// The name of the listener of the gateway.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+type IngressController string
+
+const (
+	// IngressControllerDefault adds no controller specific annotation.
+	IngressControllerDefault IngressController = "default"
+	// IngressControllerNginx configures ingress-nginx for large uploads and long requests.
+	IngressControllerNginx IngressController = "nginx"
+	// IngressControllerTraefik configures Traefik routers.
+	IngressControllerTraefik IngressController = "traefik"
+	// IngressControllerContour configures Contour for long requests.
+	IngressControllerContour IngressController = "contour"
+)
+
+type IngressSpec struct {
+	// The class of the ingresses, set in the kubernetes.io/ingress.class annotation.
+	// +optional
+	IngressClassName string `json:"ingressClassName,omitempty"`
+
+	// The ingress controller, selecting preset annotations.
+	// +optional
+	// +kubebuilder:validation:Enum=default;nginx;traefik;contour
+	Controller IngressController `json:"controller,omitempty"`
+
+	// Annotations added to the ingresses, overriding preset ones.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ProxySpec struct {
+	// The proxy for HTTP requests.
+	// +optional
+	// +kubebuilder:validation:Pattern="^https?://.*$"
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// The proxy for HTTPS requests.
+	// +optional
+	// +kubebuilder:validation:Pattern="^https?://.*$"
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// Hosts reached without proxy, in addition to the services of the components which are always excluded.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+}
+
+type TrustedCABundleSpec struct {
+	// The key of the config map containing the PEM encoded certificates.
+	// Takes precedence over secretRef.
+	// +optional
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
+
+	// The key of the secret containing the PEM encoded certificates.
+	// +optional
+	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
+}
+
+type InternalTLSSpec struct {
+	// Serve components over HTTPS, with certificates issued by the CA of the Harbor.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+type CertificatesMode string
+
+const (
+	// CertificatesModeCertManager issues certificates with cert-manager, using spec.certificateIssuerRef.
+	CertificatesModeCertManager CertificatesMode = "CertManager"
+	// CertificatesModeSelfSigned issues certificates from a CA generated by the operator.
+	CertificatesModeSelfSigned CertificatesMode = "SelfSigned"
+	// CertificatesModeProvided uses TLS secrets created by the user.
+	CertificatesModeProvided CertificatesMode = "Provided"
+)
+
+type CertificatesSpec struct {
+	// +optional
+	// +kubebuilder:validation:Enum=CertManager;SelfSigned;Provided
+	Mode CertificatesMode `json:"mode,omitempty"`
+
+	// The validity of certificates issued in SelfSigned mode, 1 year by default.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// The TLS secret signing and verifying registry tokens, in Provided mode.
+	// +optional
+	TokenSecretName string `json:"tokenSecretName,omitempty"`
+
+	// The TLS secret of notary signer, with the CA in the ca.crt key, in Provided mode.
+	// +optional
+	NotarySignerSecretName string `json:"notarySignerSecretName,omitempty"`
+}
+
+type SecretRotation struct {
+	// The schedule of the rotation, in cron format.
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+}
+
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes all resources managed by the operator.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain keeps persistent volume claims, generated secrets and certificates.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyOrphan keeps all resources managed by the operator.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// FinalBackupJob is the backup of databases, the core secret key and storage run when the Harbor is deleted.
+// It is stored like a HarborBackup named <harbor>-final-backup.
+type FinalBackupJob struct {
+	// Where to store the backup.
+	// +kubebuilder:validation:Required
+	Target BackupTarget `json:"target"`
+
+	// Images used by backup jobs.
+	// +optional
+	Images BackupImages `json:"images,omitempty"`
 }
 
 type HarborComponents struct {
@@ -122,6 +412,61 @@ type HarborDeployment struct {
 	// +optional
 	NodeSelector     NodeSelector                  `json:"nodeSelector,omitempty"`
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// Customization of the service of the component.
+	// +optional
+	Service *ComponentServiceSpec `json:"service,omitempty"`
+}
+
+type ComponentServiceSpec struct {
+	ServiceIPFamilies `json:",inline"`
+
+	// The type of the service, ClusterIP by default.
+	// +optional
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// Annotations added to the service, often read by cloud providers.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Labels added to the service, labels set by the operator take precedence.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Enum=ClientIP;None
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+
+	// +optional
+	SessionAffinityConfig *corev1.SessionAffinityConfig `json:"sessionAffinityConfig,omitempty"`
+
+	// Ports added to the ports of the component, such as metrics ports.
+	// +optional
+	ExtraPorts []corev1.ServicePort `json:"extraPorts,omitempty"`
+}
+
+// IPFamilyPolicy is the policy of a service regarding IP families.
+// https://kubernetes.io/docs/concepts/services-networking/dual-stack/#services
+type IPFamilyPolicy string
+
+const (
+	IPFamilyPolicySingleStack      IPFamilyPolicy = "SingleStack"
+	IPFamilyPolicyPreferDualStack  IPFamilyPolicy = "PreferDualStack"
+	IPFamilyPolicyRequireDualStack IPFamilyPolicy = "RequireDualStack"
+)
+
+type ServiceIPFamilies struct {
+	// Whether the service gets a single IP family or both, defaulted by the cluster.
+	// +optional
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	IPFamilyPolicy IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+
+	// The IP families of the service, in order, defaulted by the cluster.
+	// Components listen on IPv6 addresses when IPv6 is listed.
+	// +optional
+	// +kubebuilder:validation:MaxItems=2
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
 }
 
 type NodeSelector map[string]string
@@ -131,6 +476,11 @@ type CoreComponent struct {
 
 	// +kubebuilder:validation:Required
 	DatabaseSecret string `json:"databaseSecret"`
+
+	// The Redis server storing sessions and the chart cache.
+	// Required to run more than one replica, sessions being stored in memory otherwise.
+	// +optional
+	Redis *RedisSpec `json:"redis,omitempty"`
 }
 
 type PortalComponent struct {
@@ -145,8 +495,14 @@ type RegistryComponent struct {
 	// +optional
 	StorageSecret string `json:"storageSecret,omitempty"`
 
+	// Deprecated: use cache instead.
+	// The secret holding the url of the Redis server caching blob descriptors, also used by core.
 	// +optional
 	CacheSecret string `json:"cacheSecret,omitempty"`
+
+	// The Redis server caching blob descriptors, also used by core.
+	// +optional
+	Cache *RedisSpec `json:"cache,omitempty"`
 }
 
 type RegistryControllerComponent struct {
@@ -157,8 +513,14 @@ type RegistryControllerComponent struct {
 type JobServiceComponent struct {
 	HarborDeployment `json:",inline"`
 
-	// +kubebuilder:validation:Required
-	RedisSecret string `json:"redisSecret"`
+	// Deprecated: use redis instead.
+	// The secret holding the url and the namespace of the Redis server of the job queue.
+	// +optional
+	RedisSecret string `json:"redisSecret,omitempty"`
+
+	// The Redis server of the job queue. Either redis or redisSecret is required.
+	// +optional
+	Redis *RedisSpec `json:"redis,omitempty"`
 
 	// +optional
 	WorkerCount int32 `json:"workerCount"`
@@ -168,8 +530,15 @@ type ClairAdapterComponent struct {
 	// +optional
 	Image *string `json:"image,omitempty"`
 
-	// +kubebuilder:validation:Required
-	RedisSecret string `json:"redisSecret"`
+	// Deprecated: use redis instead.
+	// The secret holding the url and the namespace of the Redis server storing scan reports.
+	// +optional
+	RedisSecret string `json:"redisSecret,omitempty"`
+
+	// The Redis server storing scan reports. Either redis or redisSecret is required.
+	// Sentinels are not supported by the adapter.
+	// +optional
+	Redis *RedisSpec `json:"redis,omitempty"`
 }
 
 type ClairComponent struct {
@@ -189,8 +558,42 @@ type ChartMuseumComponent struct {
 	// +optional
 	StorageSecret string `json:"storageSecret,omitempty"`
 
+	// Deprecated: use cache instead.
+	// The secret holding the url of the Redis server caching charts.
 	// +optional
 	CacheSecret string `json:"cacheSecret,omitempty"`
+
+	// The Redis server caching charts. Sentinels and TLS are not supported by chartmuseum.
+	// +optional
+	Cache *RedisSpec `json:"cache,omitempty"`
+}
+
+// RedisSpec references a Redis server, standalone or monitored by sentinels.
+type RedisSpec struct {
+	// The address of the server, or the addresses of the sentinels with sentinelMasterSet, as host:port.
+	// +kubebuilder:validation:MinItems=1
+	Hosts []string `json:"hosts"`
+
+	// The name of the master set monitored by the sentinels.
+	// +optional
+	SentinelMasterSet string `json:"sentinelMasterSet,omitempty"`
+
+	// The secret holding the password of the server.
+	// +optional
+	PasswordSecret string `json:"passwordSecret,omitempty"`
+
+	// The key of the password in the secret, password by default.
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+
+	// The index of the database.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Database int32 `json:"database,omitempty"`
+
+	// Whether connections use TLS. Certificate authorities of spec.trustedCABundle are trusted.
+	// +optional
+	TLS bool `json:"tls,omitempty"`
 }
 
 type NotaryComponent struct {
@@ -237,6 +640,23 @@ type HarborStatus struct {
 	Conditions []HarborCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,6,rep,name=conditions"`
 
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The last time the admin password was changed in Harbor.
+	// +optional
+	AdminPasswordRotationTime *metav1.Time `json:"adminPasswordRotationTime,omitempty"`
+
+	// The last time secrets shared between components were regenerated.
+	// +optional
+	InternalSecretsRotationTime *metav1.Time `json:"internalSecretsRotationTime,omitempty"`
+
+	// The value of the rotate-internal-secrets annotation at the last rotation.
+	// +optional
+	InternalSecretsRotationRequest string `json:"internalSecretsRotationRequest,omitempty"`
+
+	// The last time certificates issued in SelfSigned mode were renewed,
+	// as recorded on the certificate secrets.
+	// +optional
+	CertificatesRenewalTime *metav1.Time `json:"certificatesRenewalTime,omitempty"`
 }
 
 // HarborCondition describes the state of a Harbor at a certain point.
@@ -263,8 +683,11 @@ type HarborCondition struct {
 type HarborConditionType string
 
 const (
-	AppliedConditionType HarborConditionType = "Applied"
-	ReadyConditionType   HarborConditionType = "Ready"
+	AppliedConditionType   HarborConditionType = "Applied"
+	ReadyConditionType     HarborConditionType = "Ready"
+	FinalizedConditionType HarborConditionType = "Finalized"
+	// PublicCertificateReadyConditionType reflects the readiness of the certificate requested for public hosts.
+	PublicCertificateReadyConditionType HarborConditionType = "PublicCertificateReady"
 )
 
 func init() { // nolint:gochecknoinits
@@ -1,6 +1,12 @@
 package v1alpha1
 
 import (
+	"fmt"
+	"net"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -32,4 +38,152 @@ func (r *Harbor) Default() {
 	if r.Spec.HarborVersion == "" {
 		r.Spec.HarborVersion = "1.10.0"
 	}
+
+	if r.Spec.DeletionPolicy == "" {
+		r.Spec.DeletionPolicy = DeletionPolicyDelete
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-goharbor-io-v1alpha1-harbor,mutating=false,failurePolicy=fail,groups=goharbor.io,resources=harbors,versions=v1alpha1,name=vharbor.kb.io
+
+var _ webhook.Validator = &Harbor{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Harbor) ValidateCreate() error {
+	harborlog.Info("validate create", "name", r.Name)
+
+	r.logWarnings()
+
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Harbor) ValidateUpdate(old runtime.Object) error {
+	harborlog.Info("validate update", "name", r.Name)
+
+	r.logWarnings()
+
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Harbor) ValidateDelete() error {
+	return nil
+}
+
+func (r *Harbor) validate() error {
+	var errs field.ErrorList
+
+	components := field.NewPath("spec").Child("components")
+
+	if c := r.Spec.Components.Core; c != nil {
+		errs = append(errs, validateRedis(components.Child("core", "redis"), c.Redis, false, false)...)
+	}
+
+	if c := r.Spec.Components.Registry; c != nil {
+		path := components.Child("registry")
+
+		errs = append(errs, validateRedisReference(path.Child("cache"), c.Cache, path.Child("cacheSecret"), c.CacheSecret, false)...)
+		errs = append(errs, validateRedis(path.Child("cache"), c.Cache, true, true)...)
+	}
+
+	if c := r.Spec.Components.JobService; c != nil {
+		path := components.Child("jobService")
+
+		errs = append(errs, validateRedisReference(path.Child("redis"), c.Redis, path.Child("redisSecret"), c.RedisSecret, true)...)
+		errs = append(errs, validateRedis(path.Child("redis"), c.Redis, true, true)...)
+	}
+
+	if c := r.Spec.Components.ChartMuseum; c != nil {
+		path := components.Child("chartMuseum")
+
+		errs = append(errs, validateRedisReference(path.Child("cache"), c.Cache, path.Child("cacheSecret"), c.CacheSecret, false)...)
+		errs = append(errs, validateRedis(path.Child("cache"), c.Cache, false, false)...)
+	}
+
+	if c := r.Spec.Components.Clair; c != nil {
+		path := components.Child("clair", "adapter")
+
+		errs = append(errs, validateRedisReference(path.Child("redis"), c.Adapter.Redis, path.Child("redisSecret"), c.Adapter.RedisSecret, true)...)
+		errs = append(errs, validateRedis(path.Child("redis"), c.Adapter.Redis, false, true)...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("Harbor").GroupKind(), r.Name, errs)
+}
+
+// Warnings returns the settings of the spec which are accepted but lead to an unexpected behavior.
+func (r *Harbor) Warnings() []string {
+	var warnings []string
+
+	if c := r.Spec.Components.Core; c != nil && c.Redis == nil && c.Replicas != nil && *c.Replicas > 1 {
+		warnings = append(warnings, fmt.Sprintf("%s replicas store sessions in memory without spec.components.core.redis, users may be logged out between requests", CoreName))
+	}
+
+	return warnings
+}
+
+// logWarnings logs the warnings of the spec.
+// Admission responses of this controller-runtime version cannot hold warnings, the reconciler records them as events.
+func (r *Harbor) logWarnings() {
+	for _, warning := range r.Warnings() {
+		harborlog.Info("warning", "name", r.Name, "warning", warning)
+	}
+}
+
+// validateRedisReference checks the Redis server is referenced either with its spec or with the deprecated secret.
+func validateRedisReference(path *field.Path, redis *RedisSpec, secretPath *field.Path, secret string, required bool) field.ErrorList {
+	switch {
+	case redis != nil && secret != "":
+		return field.ErrorList{field.Forbidden(secretPath, fmt.Sprintf("cannot be set with %s", path.String()))}
+	case required && redis == nil && secret == "":
+		return field.ErrorList{field.Required(path, fmt.Sprintf("either %s or %s is required", path.String(), secretPath.String()))}
+	default:
+		return nil
+	}
+}
+
+// validateRedis checks the spec of a Redis server, sentinels and TLS being supported or not by the component.
+func validateRedis(path *field.Path, redis *RedisSpec, sentinel, tls bool) field.ErrorList {
+	if redis == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+
+	if len(redis.Hosts) == 0 {
+		errs = append(errs, field.Required(path.Child("hosts"), "at least one host is required"))
+	}
+
+	for i, host := range redis.Hosts {
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			errs = append(errs, field.Invalid(path.Child("hosts").Index(i), host, "must be host:port"))
+		}
+	}
+
+	switch {
+	case redis.IsSentinel() && !sentinel:
+		errs = append(errs, field.Forbidden(path.Child("sentinelMasterSet"), "sentinels are not supported by the component"))
+	case redis.IsSentinel() && redis.TLS:
+		errs = append(errs, field.Forbidden(path.Child("tls"), "TLS is not supported with sentinels"))
+	case !redis.IsSentinel() && len(redis.Hosts) > 1:
+		errs = append(errs, field.TooMany(path.Child("hosts"), len(redis.Hosts), 1))
+	}
+
+	if redis.TLS && !tls {
+		errs = append(errs, field.Forbidden(path.Child("tls"), "TLS is not supported by the component"))
+	}
+
+	if redis.PasswordKey != "" && redis.PasswordSecret == "" {
+		errs = append(errs, field.Required(path.Child("passwordSecret"), "the secret of passwordKey is required"))
+	}
+
+	if redis.Database < 0 {
+		errs = append(errs, field.Invalid(path.Child("database"), redis.Database, "must be positive"))
+	}
+
+	return errs
 }
@@ -0,0 +1,129 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborBackup is the Schema for the harborbackups API
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +resource:path=harborbackup
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="hb"
+// +kubebuilder:printcolumn:name="Harbor",type=string,JSONPath=`.spec.harborName`,description="The Harbor to backup",priority=0
+// +kubebuilder:printcolumn:name="Completed",type=string,JSONPath=`.status.conditions[?(@.type=="Completed")].status`,description="Whether the backup is completed",priority=0
+// +kubebuilder:printcolumn:name="Failed",type=string,JSONPath=`.status.conditions[?(@.type=="Failed")].status`,description="Whether the backup failed",priority=10
+type HarborBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborBackupSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the backup.
+	// +optional
+	Status HarborBackupStatus `json:"status,omitempty"`
+}
+
+// HarborBackupList contains a list of HarborBackup
+// +kubebuilder:object:root=true
+// +resource:path=harborbackups
+type HarborBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborBackup `json:"items"`
+}
+
+// HarborBackupSpec defines the desired state of HarborBackup
+type HarborBackupSpec struct {
+	// The name of the Harbor to backup, in the same namespace.
+	// +kubebuilder:validation:Required
+	HarborName string `json:"harborName"`
+
+	// Where to store the backup.
+	// +kubebuilder:validation:Required
+	Target BackupTarget `json:"target"`
+
+	// Images used by backup jobs.
+	// +optional
+	Images BackupImages `json:"images,omitempty"`
+}
+
+// BackupTarget is the location of a backup.
+// Exactly one of PersistentVolumeClaim and S3 must be set.
+type BackupTarget struct {
+	// The claim to store backups in. Each backup is stored in a directory named after the HarborBackup.
+	// +optional
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+
+	// The S3-compatible bucket to store backups in. Each backup is stored with the HarborBackup name as prefix.
+	// +optional
+	S3 *S3BackupTarget `json:"s3,omitempty"`
+}
+
+type S3BackupTarget struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^https?://.*$"
+	Endpoint string `json:"endpoint"`
+
+	// +kubebuilder:validation:Required
+	Bucket string `json:"bucket"`
+
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// The name of the secret containing access-key and secret-key keys.
+	// +kubebuilder:validation:Required
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+type BackupImages struct {
+	// The image providing pg_dump and pg_restore.
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The image used to transfer files from and to S3-compatible targets.
+	// +optional
+	S3 string `json:"s3,omitempty"`
+
+	// The image used to copy files.
+	// +optional
+	Copy string `json:"copy,omitempty"`
+
+	// The image used to copy registry and chart storage. Storage is skipped if not set.
+	// The storage configuration is mounted in /etc/storage (one file per driver),
+	// the files are expected in /backup. BACKUP_DIRECTION environment variable is set to backup or restore.
+	// +optional
+	Storage string `json:"storage,omitempty"`
+}
+
+// HarborBackupStatus defines the observed state of HarborBackup
+type HarborBackupStatus struct {
+	// Represents the latest available observations of the backup's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []HarborCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Whether the Harbor was read-only before the backup started.
+	// +optional
+	HarborReadOnly *bool `json:"harborReadOnly,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// The items contained in the backup.
+	// +optional
+	Items []string `json:"items,omitempty"`
+}
+
+const (
+	CompletedConditionType HarborConditionType = "Completed"
+	FailedConditionType    HarborConditionType = "Failed"
+)
+
+func init() { // nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborBackup{}, &HarborBackupList{})
+}
@@ -0,0 +1,140 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborConfiguration is the Schema for the harborconfigurations API
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +resource:path=harborconfiguration
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="hc"
+// +kubebuilder:printcolumn:name="Harbor",type=string,JSONPath=`.spec.harborName`,description="The configured Harbor",priority=0
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the configuration is applied",priority=0
+// +kubebuilder:printcolumn:name="Drifted",type=string,JSONPath=`.status.conditions[?(@.type=="Drifted")].status`,description="Whether the configuration was changed outside of the resource",priority=0
+type HarborConfiguration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborConfigurationSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the configuration.
+	// +optional
+	Status HarborConfigurationStatus `json:"status,omitempty"`
+}
+
+// HarborConfigurationList contains a list of HarborConfiguration
+// +kubebuilder:object:root=true
+// +resource:path=harborconfigurations
+type HarborConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborConfiguration `json:"items"`
+}
+
+// +kubebuilder:validation:Enum=everyone;adminonly
+type ProjectCreationRestriction string
+
+const (
+	ProjectCreationEveryone  ProjectCreationRestriction = "everyone"
+	ProjectCreationAdminOnly ProjectCreationRestriction = "adminonly"
+)
+
+// HarborConfigurationSpec defines the desired state of HarborConfiguration.
+// Unset fields are left untouched in Harbor.
+type HarborConfigurationSpec struct {
+	// The name of the configured Harbor, in the same namespace.
+	// +kubebuilder:validation:Required
+	HarborName string `json:"harborName"`
+
+	// +optional
+	Email *EmailConfiguration `json:"email,omitempty"`
+
+	// Who can create projects.
+	// +optional
+	ProjectCreationRestriction ProjectCreationRestriction `json:"projectCreationRestriction,omitempty"`
+
+	// The lifetime of robot account tokens, rounded to the minute.
+	// +optional
+	RobotTokenDuration *metav1.Duration `json:"robotTokenDuration,omitempty"`
+
+	// Allow users to register themselves, with database authentication only.
+	// +optional
+	SelfRegistration *bool `json:"selfRegistration,omitempty"`
+
+	// CVEs ignored by vulnerability scans of all projects.
+	// +optional
+	CVEAllowlist *CVEAllowlist `json:"cveAllowlist,omitempty"`
+}
+
+type EmailConfiguration struct {
+	// +kubebuilder:validation:Required
+	Host string `json:"host"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// The name of the secret containing the SMTP password under the password key.
+	// +optional
+	PasswordSecret string `json:"passwordSecret,omitempty"`
+
+	// The sender of emails.
+	// +kubebuilder:validation:Required
+	From string `json:"from"`
+
+	// Use SSL.
+	// +optional
+	SSL bool `json:"ssl,omitempty"`
+
+	// Skip certificate verification.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// +optional
+	Identity string `json:"identity,omitempty"`
+}
+
+const (
+	EmailPasswordKey = "password"
+)
+
+type CVEAllowlist struct {
+	// The CVE IDs, such as CVE-2019-10164.
+	// +optional
+	Items []string `json:"items,omitempty"`
+
+	// When the allowlist expires, never if not set.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// HarborConfigurationStatus defines the observed state of HarborConfiguration
+type HarborConfigurationStatus struct {
+	// Represents the latest available observations of the configuration's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []HarborCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// The generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The hash of the SMTP password sent to Harbor, which never returns it.
+	// +optional
+	EmailPasswordHash string `json:"emailPasswordHash,omitempty"`
+}
+
+const (
+	// DriftedConditionType is True when the configuration was changed outside of the resource
+	DriftedConditionType HarborConditionType = "Drifted"
+)
+
+func init() { // nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborConfiguration{}, &HarborConfigurationList{})
+}
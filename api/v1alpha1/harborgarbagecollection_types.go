@@ -0,0 +1,124 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborGarbageCollection is the Schema for the harborgarbagecollections API
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +resource:path=harborgarbagecollection
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="hgc"
+// +kubebuilder:printcolumn:name="Harbor",type=string,JSONPath=`.spec.harborName`,description="The Harbor to clean",priority=0
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`,description="The cron schedule",priority=0
+// +kubebuilder:printcolumn:name="Last schedule",type=date,JSONPath=`.status.lastScheduleTime`,description="The last time a garbage collection was triggered",priority=0
+// +kubebuilder:printcolumn:name="Result",type=string,JSONPath=`.status.lastRun.result`,description="The result of the last garbage collection",priority=0
+// +kubebuilder:printcolumn:name="Freed bytes",type=integer,JSONPath=`.status.lastRun.freedBytes`,description="The space freed by the last garbage collection",priority=10
+type HarborGarbageCollection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborGarbageCollectionSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the garbage collection.
+	// +optional
+	Status HarborGarbageCollectionStatus `json:"status,omitempty"`
+}
+
+// HarborGarbageCollectionList contains a list of HarborGarbageCollection
+// +kubebuilder:object:root=true
+// +resource:path=harborgarbagecollections
+type HarborGarbageCollectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborGarbageCollection `json:"items"`
+}
+
+// HarborGarbageCollectionSpec defines the desired state of HarborGarbageCollection
+type HarborGarbageCollectionSpec struct {
+	// The name of the Harbor to clean, in the same namespace.
+	// +kubebuilder:validation:Required
+	HarborName string `json:"harborName"`
+
+	// The schedule in Cron format, see https://en.wikipedia.org/wiki/Cron.
+	// Predefined schedules such as @daily are supported.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Delete untagged artifacts. Requires Harbor 2.0 or later.
+	// +optional
+	DeleteUntagged bool `json:"deleteUntagged,omitempty"`
+
+	// Switch the Harbor to read-only while the garbage collection runs,
+	// so no blob is pushed while it is being deleted.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// Do not trigger new garbage collections. Running ones are not interrupted.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// HarborGarbageCollectionStatus defines the observed state of HarborGarbageCollection
+type HarborGarbageCollectionStatus struct {
+	// Represents the latest available observations of the garbage collection's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []HarborCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// The last time a garbage collection was triggered.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// Whether the Harbor was read-only before the garbage collection started.
+	// Set only while a garbage collection with readOnly runs.
+	// +optional
+	HarborReadOnly *bool `json:"harborReadOnly,omitempty"`
+
+	// The last garbage collection, possibly still running.
+	// +optional
+	LastRun *GarbageCollectionRun `json:"lastRun,omitempty"`
+}
+
+type GarbageCollectionResult string
+
+const (
+	GarbageCollectionSucceeded GarbageCollectionResult = "Succeeded"
+	GarbageCollectionFailed    GarbageCollectionResult = "Failed"
+)
+
+// GarbageCollectionRun is a garbage collection execution in Harbor.
+type GarbageCollectionRun struct {
+	// The ID of the execution in Harbor.
+	ID int64 `json:"id"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// The result of the execution, empty while it runs.
+	// +optional
+	Result GarbageCollectionResult `json:"result,omitempty"`
+
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// The space freed, if reported by Harbor.
+	// +optional
+	FreedBytes *int64 `json:"freedBytes,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	RunningConditionType HarborConditionType = "Running"
+)
+
+func init() { // nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborGarbageCollection{}, &HarborGarbageCollectionList{})
+}
@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborImmutableTagRule is the Schema for the harborimmutabletagrules API
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +resource:path=harborimmutabletagrule
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="hitr"
+// +kubebuilder:printcolumn:name="Harbor",type=string,JSONPath=`.spec.harborName`,description="The Harbor hosting the project",priority=0
+// +kubebuilder:printcolumn:name="Project",type=integer,JSONPath=`.status.projectID`,description="The ID of the project in Harbor",priority=0
+// +kubebuilder:printcolumn:name="Repositories",type=string,JSONPath=`.spec.repositories.pattern`,description="The repositories pattern",priority=10
+// +kubebuilder:printcolumn:name="Tags",type=string,JSONPath=`.spec.tags.pattern`,description="The tags pattern",priority=10
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the rule is in sync",priority=0
+type HarborImmutableTagRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborImmutableTagRuleSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the immutable tag rule.
+	// +optional
+	Status HarborImmutableTagRuleStatus `json:"status,omitempty"`
+}
+
+// HarborImmutableTagRuleList contains a list of HarborImmutableTagRule
+// +kubebuilder:object:root=true
+// +resource:path=harborimmutabletagrules
+type HarborImmutableTagRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborImmutableTagRule `json:"items"`
+}
+
+// HarborImmutableTagRuleSpec defines the desired state of HarborImmutableTagRule
+type HarborImmutableTagRuleSpec struct {
+	// The name of the Harbor hosting the project, in the same namespace.
+	// +kubebuilder:validation:Required
+	HarborName string `json:"harborName"`
+
+	// The project of the rule.
+	// +kubebuilder:validation:Required
+	Project ProjectReference `json:"project"`
+
+	// The repositories whose tags are immutable.
+	// +optional
+	Repositories PatternSelector `json:"repositories,omitempty"`
+
+	// The immutable tags.
+	// +optional
+	Tags PatternSelector `json:"tags,omitempty"`
+
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// What happens to the rule in Harbor when the resource is deleted. Retain by default.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// HarborImmutableTagRuleStatus defines the observed state of HarborImmutableTagRule
+type HarborImmutableTagRuleStatus struct {
+	// Represents the latest available observations of the immutable tag rule's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []HarborCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// The generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The ID of the project in Harbor.
+	// +optional
+	ProjectID int64 `json:"projectID,omitempty"`
+
+	// The ID of the rule in Harbor.
+	// +optional
+	RuleID int64 `json:"ruleID,omitempty"`
+}
+
+func init() { // nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborImmutableTagRule{}, &HarborImmutableTagRuleList{})
+}
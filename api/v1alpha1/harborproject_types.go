@@ -0,0 +1,154 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborProject is the Schema for the harborprojects API
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +resource:path=harborproject
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="hp"
+// +kubebuilder:printcolumn:name="Harbor",type=string,JSONPath=`.spec.harborName`,description="The Harbor hosting the project",priority=0
+// +kubebuilder:printcolumn:name="ID",type=integer,JSONPath=`.status.projectID`,description="The ID of the project in Harbor",priority=0
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the project is in sync",priority=0
+// +kubebuilder:printcolumn:name="Used",type=string,JSONPath=`.status.quota.used`,description="The storage used by the project",priority=10
+type HarborProject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborProjectSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the project.
+	// +optional
+	Status HarborProjectStatus `json:"status,omitempty"`
+}
+
+// HarborProjectList contains a list of HarborProject
+// +kubebuilder:object:root=true
+// +resource:path=harborprojects
+type HarborProjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborProject `json:"items"`
+}
+
+// HarborProjectSpec defines the desired state of HarborProject
+type HarborProjectSpec struct {
+	// The name of the Harbor hosting the project, in the same namespace.
+	// +kubebuilder:validation:Required
+	HarborName string `json:"harborName"`
+
+	// The name of the project in Harbor. Defaults to the resource name.
+	// +optional
+	// +kubebuilder:validation:Pattern="^[a-z0-9]+(?:[._-][a-z0-9]+)*$"
+	Name string `json:"name,omitempty"`
+
+	// Allow anonymous pulls.
+	// +optional
+	Public bool `json:"public,omitempty"`
+
+	// Scan images automatically when they are pushed.
+	// +optional
+	AutoScan bool `json:"autoScan,omitempty"`
+
+	// Allow only signed images to be pulled.
+	// +optional
+	ContentTrust bool `json:"contentTrust,omitempty"`
+
+	// Prevent images with vulnerabilities of at least Severity to be pulled.
+	// +optional
+	PreventVulnerable bool `json:"preventVulnerable,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Enum=negligible;low;medium;high;critical
+	Severity string `json:"severity,omitempty"`
+
+	// The storage quota of the project, unlimited if not set.
+	// +optional
+	StorageQuota *resource.Quantity `json:"storageQuota,omitempty"`
+
+	// What happens to the project in Harbor when the resource is deleted.
+	// Retain by default. Harbor refuses to delete projects containing repositories.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// HarborProjectStatus defines the observed state of HarborProject
+type HarborProjectStatus struct {
+	// Represents the latest available observations of the project's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []HarborCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// The generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The ID of the project in Harbor.
+	// +optional
+	ProjectID int64 `json:"projectID,omitempty"`
+
+	// +optional
+	RepositoryCount int64 `json:"repositoryCount,omitempty"`
+
+	// +optional
+	Quota *ProjectQuotaStatus `json:"quota,omitempty"`
+}
+
+type ProjectQuotaStatus struct {
+	// The storage limit, unlimited if not set.
+	// +optional
+	Hard *resource.Quantity `json:"hard,omitempty"`
+
+	// The storage used.
+	// +optional
+	Used *resource.Quantity `json:"used,omitempty"`
+}
+
+// GetProjectName returns the name of the project in Harbor.
+func (p *HarborProject) GetProjectName() string {
+	if p.Spec.Name != "" {
+		return p.Spec.Name
+	}
+
+	return p.GetName()
+}
+
+// ProjectReference references a project, either by its HarborProject resource or by its name in Harbor.
+type ProjectReference struct {
+	// The name of the HarborProject resource, in the same namespace.
+	// +optional
+	HarborProject string `json:"harborProject,omitempty"`
+
+	// The name of the project in Harbor, for projects not managed by a HarborProject.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// PatternSelector selects repositories or tags with doublestar patterns.
+type PatternSelector struct {
+	// The doublestar pattern, ** by default.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+
+	// Select everything except what matches the pattern.
+	// +optional
+	Exclude bool `json:"exclude,omitempty"`
+}
+
+// GetPattern returns the pattern of the selector.
+func (s *PatternSelector) GetPattern() string {
+	if s.Pattern != "" {
+		return s.Pattern
+	}
+
+	return "**"
+}
+
+func init() { // nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborProject{}, &HarborProjectList{})
+}
@@ -0,0 +1,111 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborRegistryEndpoint is the Schema for the harborregistryendpoints API
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +resource:path=harborregistryendpoint
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="hre"
+// +kubebuilder:printcolumn:name="Harbor",type=string,JSONPath=`.spec.harborName`,description="The Harbor replicating from or to the registry",priority=0
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`,description="The type of the registry",priority=0
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`,description="The URL of the registry",priority=0
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the registry is in sync",priority=0
+type HarborRegistryEndpoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborRegistryEndpointSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the registry endpoint.
+	// +optional
+	Status HarborRegistryEndpointStatus `json:"status,omitempty"`
+}
+
+// HarborRegistryEndpointList contains a list of HarborRegistryEndpoint
+// +kubebuilder:object:root=true
+// +resource:path=harborregistryendpoints
+type HarborRegistryEndpointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborRegistryEndpoint `json:"items"`
+}
+
+// HarborRegistryEndpointSpec defines the desired state of HarborRegistryEndpoint
+type HarborRegistryEndpointSpec struct {
+	// The name of the Harbor replicating from or to the registry, in the same namespace.
+	// +kubebuilder:validation:Required
+	HarborName string `json:"harborName"`
+
+	// The name of the registry in Harbor. Defaults to the resource name.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// The type of the registry, as listed by the /api/replication/adapters endpoint of Harbor.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=harbor;docker-hub;docker-registry;huawei-SWR;google-gcr;aws-ecr;azure-acr;ali-acr;jfrog-artifactory;quay-io;gitlab;helm-hub
+	Type string `json:"type"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^https?://.*$"
+	URL string `json:"url"`
+
+	// The name of the secret containing access-key and access-secret keys.
+	// Anonymous access is used if not set.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// Skip certificate verification.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// What happens to the registry in Harbor when the resource is deleted.
+	// Retain by default. Harbor refuses to delete registries used by replication policies.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+const (
+	RegistryAccessKeyKey    = "access-key"
+	RegistryAccessSecretKey = "access-secret"
+)
+
+// HarborRegistryEndpointStatus defines the observed state of HarborRegistryEndpoint
+type HarborRegistryEndpointStatus struct {
+	// Represents the latest available observations of the registry endpoint's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []HarborCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// The generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The ID of the registry in Harbor.
+	// +optional
+	RegistryID int64 `json:"registryID,omitempty"`
+
+	// The hash of the credentials sent to Harbor, which never returns them.
+	// +optional
+	CredentialsHash string `json:"credentialsHash,omitempty"`
+}
+
+// GetRegistryName returns the name of the registry in Harbor.
+func (r *HarborRegistryEndpoint) GetRegistryName() string {
+	if r.Spec.Name != "" {
+		return r.Spec.Name
+	}
+
+	return r.GetName()
+}
+
+func init() { // nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborRegistryEndpoint{}, &HarborRegistryEndpointList{})
+}
@@ -0,0 +1,198 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborReplicationPolicy is the Schema for the harborreplicationpolicies API
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +resource:path=harborreplicationpolicy
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="hrp"
+// +kubebuilder:printcolumn:name="Harbor",type=string,JSONPath=`.spec.harborName`,description="The Harbor running the replication",priority=0
+// +kubebuilder:printcolumn:name="Registry",type=string,JSONPath=`.spec.registryEndpoint`,description="The remote registry",priority=0
+// +kubebuilder:printcolumn:name="Direction",type=string,JSONPath=`.spec.direction`,description="Whether images are pulled from or pushed to the registry",priority=0
+// +kubebuilder:printcolumn:name="Last execution",type=string,JSONPath=`.status.lastExecution.status`,description="The status of the last execution",priority=0
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the policy is in sync",priority=10
+type HarborReplicationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborReplicationPolicySpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the replication policy.
+	// +optional
+	Status HarborReplicationPolicyStatus `json:"status,omitempty"`
+}
+
+// HarborReplicationPolicyList contains a list of HarborReplicationPolicy
+// +kubebuilder:object:root=true
+// +resource:path=harborreplicationpolicies
+type HarborReplicationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborReplicationPolicy `json:"items"`
+}
+
+// +kubebuilder:validation:Enum=Pull;Push
+type ReplicationDirection string
+
+const (
+	// ReplicationPull replicates images from the registry to the Harbor
+	ReplicationPull ReplicationDirection = "Pull"
+	// ReplicationPush replicates images from the Harbor to the registry
+	ReplicationPush ReplicationDirection = "Push"
+)
+
+// +kubebuilder:validation:Enum=Manual;Scheduled;EventBased
+type ReplicationTriggerType string
+
+const (
+	ReplicationTriggerManual     ReplicationTriggerType = "Manual"
+	ReplicationTriggerScheduled  ReplicationTriggerType = "Scheduled"
+	ReplicationTriggerEventBased ReplicationTriggerType = "EventBased"
+)
+
+// HarborReplicationPolicySpec defines the desired state of HarborReplicationPolicy
+type HarborReplicationPolicySpec struct {
+	// The name of the Harbor running the replication, in the same namespace.
+	// +kubebuilder:validation:Required
+	HarborName string `json:"harborName"`
+
+	// The name of the policy in Harbor. Defaults to the resource name.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// The name of the HarborRegistryEndpoint to replicate from or to, in the same namespace.
+	// +kubebuilder:validation:Required
+	RegistryEndpoint string `json:"registryEndpoint"`
+
+	// +kubebuilder:validation:Required
+	Direction ReplicationDirection `json:"direction"`
+
+	// The namespace to replicate into, the source namespace is kept if not set.
+	// +optional
+	DestinationNamespace string `json:"destinationNamespace,omitempty"`
+
+	// +optional
+	Filters ReplicationFilters `json:"filters,omitempty"`
+
+	// +optional
+	Trigger ReplicationTrigger `json:"trigger,omitempty"`
+
+	// Replicate deletions.
+	// +optional
+	Deletion bool `json:"deletion,omitempty"`
+
+	// Override existing resources in the destination.
+	// +optional
+	Override bool `json:"override,omitempty"`
+
+	// Disable the policy.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// What happens to the policy in Harbor when the resource is deleted. Retain by default.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+type ReplicationFilters struct {
+	// Filter resources by name, with doublestar patterns such as library/**.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Filter resources by tag, with doublestar patterns.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// Filter resources having all the labels.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Enum=image;chart
+	Resource string `json:"resource,omitempty"`
+}
+
+type ReplicationTrigger struct {
+	// Manual by default.
+	// +optional
+	Type ReplicationTriggerType `json:"type,omitempty"`
+
+	// The schedule of Scheduled triggers, in the 6 fields cron format of Harbor, with seconds.
+	// +optional
+	Cron string `json:"cron,omitempty"`
+}
+
+// HarborReplicationPolicyStatus defines the observed state of HarborReplicationPolicy
+type HarborReplicationPolicyStatus struct {
+	// Represents the latest available observations of the replication policy's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []HarborCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// The generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The ID of the policy in Harbor.
+	// +optional
+	PolicyID int64 `json:"policyID,omitempty"`
+
+	// The last execution of the policy.
+	// +optional
+	LastExecution *ReplicationExecution `json:"lastExecution,omitempty"`
+}
+
+type ReplicationExecution struct {
+	ID int64 `json:"id"`
+
+	// Status of the execution in Harbor: InProgress, Succeed, Failed or Stopped.
+	Status string `json:"status"`
+
+	// +optional
+	StatusText string `json:"statusText,omitempty"`
+
+	// +optional
+	Trigger string `json:"trigger,omitempty"`
+
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+
+	// +optional
+	EndTime string `json:"endTime,omitempty"`
+
+	// +optional
+	Total int64 `json:"total,omitempty"`
+
+	// +optional
+	Succeeded int64 `json:"succeeded,omitempty"`
+
+	// +optional
+	Failed int64 `json:"failed,omitempty"`
+
+	// +optional
+	InProgress int64 `json:"inProgress,omitempty"`
+
+	// +optional
+	Stopped int64 `json:"stopped,omitempty"`
+}
+
+// GetPolicyName returns the name of the policy in Harbor.
+func (p *HarborReplicationPolicy) GetPolicyName() string {
+	if p.Spec.Name != "" {
+		return p.Spec.Name
+	}
+
+	return p.GetName()
+}
+
+func init() { // nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborReplicationPolicy{}, &HarborReplicationPolicyList{})
+}
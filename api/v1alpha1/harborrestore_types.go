@@ -0,0 +1,69 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborRestore is the Schema for the harborrestores API
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +resource:path=harborrestore
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="hr"
+// +kubebuilder:printcolumn:name="Harbor",type=string,JSONPath=`.spec.harborName`,description="The Harbor to restore",priority=0
+// +kubebuilder:printcolumn:name="Backup",type=string,JSONPath=`.spec.backupName`,description="The backup to restore",priority=0
+// +kubebuilder:printcolumn:name="Completed",type=string,JSONPath=`.status.conditions[?(@.type=="Completed")].status`,description="Whether the restoration is completed",priority=0
+// +kubebuilder:printcolumn:name="Failed",type=string,JSONPath=`.status.conditions[?(@.type=="Failed")].status`,description="Whether the restoration failed",priority=10
+type HarborRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborRestoreSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the restoration.
+	// +optional
+	Status HarborRestoreStatus `json:"status,omitempty"`
+}
+
+// HarborRestoreList contains a list of HarborRestore
+// +kubebuilder:object:root=true
+// +resource:path=harborrestores
+type HarborRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborRestore `json:"items"`
+}
+
+// HarborRestoreSpec defines the desired state of HarborRestore
+type HarborRestoreSpec struct {
+	// The name of the Harbor to restore into, in the same namespace.
+	// Data of the Harbor are overridden.
+	// +kubebuilder:validation:Required
+	HarborName string `json:"harborName"`
+
+	// The name of the completed HarborBackup to restore, in the same namespace.
+	// +kubebuilder:validation:Required
+	BackupName string `json:"backupName"`
+}
+
+// HarborRestoreStatus defines the observed state of HarborRestore
+type HarborRestoreStatus struct {
+	// Represents the latest available observations of the restoration's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []HarborCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Whether the Harbor was read-only before the restoration started.
+	// +optional
+	HarborReadOnly *bool `json:"harborReadOnly,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+func init() { // nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborRestore{}, &HarborRestoreList{})
+}
@@ -0,0 +1,122 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborRetentionPolicy is the Schema for the harborretentionpolicies API
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +resource:path=harborretentionpolicy
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="hrtp"
+// +kubebuilder:printcolumn:name="Harbor",type=string,JSONPath=`.spec.harborName`,description="The Harbor hosting the project",priority=0
+// +kubebuilder:printcolumn:name="Project",type=integer,JSONPath=`.status.projectID`,description="The ID of the project in Harbor",priority=0
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`,description="When the policy runs",priority=0
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the policy is in sync",priority=0
+type HarborRetentionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborRetentionPolicySpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the retention policy.
+	// +optional
+	Status HarborRetentionPolicyStatus `json:"status,omitempty"`
+}
+
+// HarborRetentionPolicyList contains a list of HarborRetentionPolicy
+// +kubebuilder:object:root=true
+// +resource:path=harborretentionpolicies
+type HarborRetentionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborRetentionPolicy `json:"items"`
+}
+
+// +kubebuilder:validation:Enum=latestPushedK;latestPulledN;nDaysSinceLastPush;nDaysSinceLastPull;always
+type RetentionTemplate string
+
+const (
+	// RetentionLatestPushed retains the most recently pushed tags
+	RetentionLatestPushed RetentionTemplate = "latestPushedK"
+	// RetentionLatestPulled retains the most recently pulled tags
+	RetentionLatestPulled RetentionTemplate = "latestPulledN"
+	// RetentionDaysSincePush retains the tags pushed in the last days
+	RetentionDaysSincePush RetentionTemplate = "nDaysSinceLastPush"
+	// RetentionDaysSincePull retains the tags pulled in the last days
+	RetentionDaysSincePull RetentionTemplate = "nDaysSinceLastPull"
+	// RetentionAlways retains all tags
+	RetentionAlways RetentionTemplate = "always"
+)
+
+// HarborRetentionPolicySpec defines the desired state of HarborRetentionPolicy
+type HarborRetentionPolicySpec struct {
+	// The name of the Harbor hosting the project, in the same namespace.
+	// +kubebuilder:validation:Required
+	HarborName string `json:"harborName"`
+
+	// The project of the policy. A project has a single retention policy.
+	// +kubebuilder:validation:Required
+	Project ProjectReference `json:"project"`
+
+	// Tags matching at least one rule are retained, the others are deleted.
+	// +optional
+	// +kubebuilder:validation:MaxItems=15
+	Rules []RetentionRule `json:"rules,omitempty"`
+
+	// The schedule of the policy, in the 6 fields cron format of Harbor, with seconds.
+	// The policy only runs manually if not set.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// What happens to the rules in Harbor when the resource is deleted. Retain by default.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+type RetentionRule struct {
+	// +kubebuilder:validation:Required
+	Template RetentionTemplate `json:"template"`
+
+	// The number of tags or days of the template, unused by the always template.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Parameter int64 `json:"parameter,omitempty"`
+
+	// The repositories the rule applies to.
+	// +optional
+	Repositories PatternSelector `json:"repositories,omitempty"`
+
+	// The tags the rule applies to.
+	// +optional
+	Tags PatternSelector `json:"tags,omitempty"`
+
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// HarborRetentionPolicyStatus defines the observed state of HarborRetentionPolicy
+type HarborRetentionPolicyStatus struct {
+	// Represents the latest available observations of the retention policy's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []HarborCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// The generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The ID of the project in Harbor.
+	// +optional
+	ProjectID int64 `json:"projectID,omitempty"`
+
+	// The ID of the policy in Harbor.
+	// +optional
+	PolicyID int64 `json:"policyID,omitempty"`
+}
+
+func init() { // nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborRetentionPolicy{}, &HarborRetentionPolicyList{})
+}
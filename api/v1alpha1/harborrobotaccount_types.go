@@ -0,0 +1,153 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborRobotAccount is the Schema for the harborrobotaccounts API
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +resource:path=harborrobotaccount
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="hra"
+// +kubebuilder:printcolumn:name="Harbor",type=string,JSONPath=`.spec.harborName`,description="The Harbor hosting the project",priority=0
+// +kubebuilder:printcolumn:name="Project",type=string,JSONPath=`.spec.projectName`,description="The project the robot account has access to",priority=0
+// +kubebuilder:printcolumn:name="Robot",type=string,JSONPath=`.status.robotName`,description="The name of the robot account in Harbor",priority=0
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.expiresAt`,description="The expiration of the current token",priority=0
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the secrets are up to date",priority=10
+type HarborRobotAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborRobotAccountSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the robot account.
+	// +optional
+	Status HarborRobotAccountStatus `json:"status,omitempty"`
+}
+
+// HarborRobotAccountList contains a list of HarborRobotAccount
+// +kubebuilder:object:root=true
+// +resource:path=harborrobotaccounts
+type HarborRobotAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborRobotAccount `json:"items"`
+}
+
+// RobotPermission is an access granted to a robot account on the repositories of the project.
+// +kubebuilder:validation:Enum=pull;push
+type RobotPermission string
+
+const (
+	RobotPullPermission RobotPermission = "pull"
+	RobotPushPermission RobotPermission = "push"
+)
+
+// HarborRobotAccountSpec defines the desired state of HarborRobotAccount
+type HarborRobotAccountSpec struct {
+	// The name of the Harbor hosting the project, in the same namespace.
+	// +kubebuilder:validation:Required
+	HarborName string `json:"harborName"`
+
+	// The name of the project in Harbor.
+	// +kubebuilder:validation:Required
+	ProjectName string `json:"projectName"`
+
+	// Permissions granted on the repositories of the project. Defaults to pull.
+	// +optional
+	Permissions []RobotPermission `json:"permissions,omitempty"`
+
+	// The lifetime of the token. The token is rotated before it expires.
+	// Defaults to the robot token expiration configured in Harbor.
+	// +optional
+	Expiration *metav1.Duration `json:"expiration,omitempty"`
+
+	// The name of the kubernetes.io/dockerconfigjson secrets. Defaults to the resource name.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Additional namespaces to write the secret into.
+	// The secret is always written in the namespace of the resource.
+	// +optional
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+}
+
+// HarborRobotAccountStatus defines the observed state of HarborRobotAccount
+type HarborRobotAccountStatus struct {
+	// Represents the latest available observations of the robot account's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []HarborCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// The generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The ID of the project in Harbor.
+	// +optional
+	ProjectID int64 `json:"projectID,omitempty"`
+
+	// The ID of the robot account in Harbor.
+	// +optional
+	RobotID int64 `json:"robotID,omitempty"`
+
+	// The name of the robot account in Harbor, used as username.
+	// +optional
+	RobotName string `json:"robotName,omitempty"`
+
+	// The permissions of the current robot account.
+	// +optional
+	Permissions []RobotPermission `json:"permissions,omitempty"`
+
+	// The last time the token was created.
+	// +optional
+	RotationTime *metav1.Time `json:"rotationTime,omitempty"`
+
+	// The expiration of the current token, not set if it never expires.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// The namespaces containing the secret.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Robot accounts replaced by the current one, deleted once the secrets and the status hold the current one.
+	// +optional
+	RetiredRobots []RetiredRobot `json:"retiredRobots,omitempty"`
+}
+
+// RetiredRobot is a robot account replaced by a rotation.
+type RetiredRobot struct {
+	// The ID of the project in Harbor.
+	ProjectID int64 `json:"projectID"`
+
+	// The ID of the robot account in Harbor.
+	RobotID int64 `json:"robotID"`
+
+	// The name of the robot account in Harbor.
+	// +optional
+	RobotName string `json:"robotName,omitempty"`
+}
+
+// GetSecretName returns the name of the secrets containing the credentials.
+func (r *HarborRobotAccount) GetSecretName() string {
+	if r.Spec.SecretName != "" {
+		return r.Spec.SecretName
+	}
+
+	return r.GetName()
+}
+
+// GetPermissions returns the permissions to grant, pull if none is specified.
+func (r *HarborRobotAccount) GetPermissions() []RobotPermission {
+	if len(r.Spec.Permissions) == 0 {
+		return []RobotPermission{RobotPullPermission}
+	}
+
+	return r.Spec.Permissions
+}
+
+func init() { // nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborRobotAccount{}, &HarborRobotAccountList{})
+}
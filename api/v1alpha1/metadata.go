@@ -2,6 +2,23 @@ package v1alpha1
 
 const (
 	HarborClassAnnotation = "goharbor.io/harbor-class"
+
+	// RotateInternalSecretsAnnotation requests a rotation of internal secrets when its value changes
+	RotateInternalSecretsAnnotation = "goharbor.io/rotate-internal-secrets"
+
+	// InternalSecretsRotationAnnotation is set on pod templates restarted after a rotation of internal secrets
+	InternalSecretsRotationAnnotation = "goharbor.io/internal-secrets-rotation"
+
+	// CertificatesRenewalAnnotation is set on secrets of renewed self-signed certificates,
+	// and on pod templates restarted after the renewal
+	CertificatesRenewalAnnotation = "goharbor.io/certificates-renewal"
+
+	// ReadOnlyAnnotation is set on the core pod template with the read-only mode it runs in
+	ReadOnlyAnnotation = "goharbor.io/read-only"
+)
+
+const (
+	HarborFinalizer = "goharbor.io/finalizer"
 )
 
 const (
@@ -10,3 +27,8 @@ const (
 	OperatorVersionLabel = "goharbor.io/version"
 	ComponentNameLabel   = "goharbor.io/component"
 )
+
+const (
+	// RobotAccountAnnotation is set on secrets managed by a HarborRobotAccount, with its namespace/name as value
+	RobotAccountAnnotation = "goharbor.io/robot-account"
+)
@@ -6,13 +6,103 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupImages) DeepCopyInto(out *BackupImages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupImages.
+func (in *BackupImages) DeepCopy() *BackupImages {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupTarget) DeepCopyInto(out *BackupTarget) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(v1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupTarget)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTarget.
+func (in *BackupTarget) DeepCopy() *BackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CVEAllowlist) DeepCopyInto(out *CVEAllowlist) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CVEAllowlist.
+func (in *CVEAllowlist) DeepCopy() *CVEAllowlist {
+	if in == nil {
+		return nil
+	}
+	out := new(CVEAllowlist)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatesSpec) DeepCopyInto(out *CertificatesSpec) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesSpec.
+func (in *CertificatesSpec) DeepCopy() *CertificatesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificatesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChartMuseumComponent) DeepCopyInto(out *ChartMuseumComponent) {
 	*out = *in
 	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(RedisSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartMuseumComponent.
@@ -20,13 +110,1781 @@ func (in *ChartMuseumComponent) DeepCopy() *ChartMuseumComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(ChartMuseumComponent)
+	out := new(ChartMuseumComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClairAdapterComponent) DeepCopyInto(out *ClairAdapterComponent) {
+	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClairAdapterComponent.
+func (in *ClairAdapterComponent) DeepCopy() *ClairAdapterComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(ClairAdapterComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClairComponent) DeepCopyInto(out *ClairComponent) {
+	*out = *in
+	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
+	if in.VulnerabilitySources != nil {
+		in, out := &in.VulnerabilitySources, &out.VulnerabilitySources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Adapter.DeepCopyInto(&out.Adapter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClairComponent.
+func (in *ClairComponent) DeepCopy() *ClairComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(ClairComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentServiceSpec) DeepCopyInto(out *ComponentServiceSpec) {
+	*out = *in
+	in.ServiceIPFamilies.DeepCopyInto(&out.ServiceIPFamilies)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SessionAffinityConfig != nil {
+		in, out := &in.SessionAffinityConfig, &out.SessionAffinityConfig
+		*out = new(v1.SessionAffinityConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraPorts != nil {
+		in, out := &in.ExtraPorts, &out.ExtraPorts
+		*out = make([]v1.ServicePort, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentServiceSpec.
+func (in *ComponentServiceSpec) DeepCopy() *ComponentServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoreComponent) DeepCopyInto(out *CoreComponent) {
+	*out = *in
+	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreComponent.
+func (in *CoreComponent) DeepCopy() *CoreComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(CoreComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailConfiguration) DeepCopyInto(out *EmailConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailConfiguration.
+func (in *EmailConfiguration) DeepCopy() *EmailConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeGatewaySpec) DeepCopyInto(out *ExposeGatewaySpec) {
+	*out = *in
+	if in.ParentRefs != nil {
+		in, out := &in.ParentRefs, &out.ParentRefs
+		*out = make([]GatewayParentReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeGatewaySpec.
+func (in *ExposeGatewaySpec) DeepCopy() *ExposeGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeIstioSpec) DeepCopyInto(out *ExposeIstioSpec) {
+	*out = *in
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeIstioSpec.
+func (in *ExposeIstioSpec) DeepCopy() *ExposeIstioSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeIstioSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeServiceSpec) DeepCopyInto(out *ExposeServiceSpec) {
+	*out = *in
+	in.ServiceIPFamilies.DeepCopyInto(&out.ServiceIPFamilies)
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeServiceSpec.
+func (in *ExposeServiceSpec) DeepCopy() *ExposeServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeSpec) DeepCopyInto(out *ExposeSpec) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ExposeServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(ExposeGatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(ExposeIstioSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeSpec.
+func (in *ExposeSpec) DeepCopy() *ExposeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalBackupJob) DeepCopyInto(out *FinalBackupJob) {
+	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+	out.Images = in.Images
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalBackupJob.
+func (in *FinalBackupJob) DeepCopy() *FinalBackupJob {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalBackupJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionRun) DeepCopyInto(out *GarbageCollectionRun) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FreedBytes != nil {
+		in, out := &in.FreedBytes, &out.FreedBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionRun.
+func (in *GarbageCollectionRun) DeepCopy() *GarbageCollectionRun {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectionRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayParentReference) DeepCopyInto(out *GatewayParentReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayParentReference.
+func (in *GatewayParentReference) DeepCopy() *GatewayParentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayParentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Harbor) DeepCopyInto(out *Harbor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Harbor.
+func (in *Harbor) DeepCopy() *Harbor {
+	if in == nil {
+		return nil
+	}
+	out := new(Harbor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Harbor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborBackup) DeepCopyInto(out *HarborBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackup.
+func (in *HarborBackup) DeepCopy() *HarborBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborBackupList) DeepCopyInto(out *HarborBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupList.
+func (in *HarborBackupList) DeepCopy() *HarborBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborBackupSpec) DeepCopyInto(out *HarborBackupSpec) {
+	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+	out.Images = in.Images
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupSpec.
+func (in *HarborBackupSpec) DeepCopy() *HarborBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborBackupStatus) DeepCopyInto(out *HarborBackupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HarborReadOnly != nil {
+		in, out := &in.HarborReadOnly, &out.HarborReadOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupStatus.
+func (in *HarborBackupStatus) DeepCopy() *HarborBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborComponents) DeepCopyInto(out *HarborComponents) {
+	*out = *in
+	if in.Core != nil {
+		in, out := &in.Core, &out.Core
+		*out = new(CoreComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Portal != nil {
+		in, out := &in.Portal, &out.Portal
+		*out = new(PortalComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(RegistryComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JobService != nil {
+		in, out := &in.JobService, &out.JobService
+		*out = new(JobServiceComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ChartMuseum != nil {
+		in, out := &in.ChartMuseum, &out.ChartMuseum
+		*out = new(ChartMuseumComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clair != nil {
+		in, out := &in.Clair, &out.Clair
+		*out = new(ClairComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notary != nil {
+		in, out := &in.Notary, &out.Notary
+		*out = new(NotaryComponent)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborComponents.
+func (in *HarborComponents) DeepCopy() *HarborComponents {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborComponents)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborCondition) DeepCopyInto(out *HarborCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborCondition.
+func (in *HarborCondition) DeepCopy() *HarborCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfiguration) DeepCopyInto(out *HarborConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfiguration.
+func (in *HarborConfiguration) DeepCopy() *HarborConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationList) DeepCopyInto(out *HarborConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationList.
+func (in *HarborConfigurationList) DeepCopy() *HarborConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationSpec) DeepCopyInto(out *HarborConfigurationSpec) {
+	*out = *in
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(EmailConfiguration)
+		**out = **in
+	}
+	if in.RobotTokenDuration != nil {
+		in, out := &in.RobotTokenDuration, &out.RobotTokenDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SelfRegistration != nil {
+		in, out := &in.SelfRegistration, &out.SelfRegistration
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CVEAllowlist != nil {
+		in, out := &in.CVEAllowlist, &out.CVEAllowlist
+		*out = new(CVEAllowlist)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationSpec.
+func (in *HarborConfigurationSpec) DeepCopy() *HarborConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationStatus) DeepCopyInto(out *HarborConfigurationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationStatus.
+func (in *HarborConfigurationStatus) DeepCopy() *HarborConfigurationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborDeployment) DeepCopyInto(out *HarborDeployment) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(NodeSelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ComponentServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborDeployment.
+func (in *HarborDeployment) DeepCopy() *HarborDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborGarbageCollection) DeepCopyInto(out *HarborGarbageCollection) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborGarbageCollection.
+func (in *HarborGarbageCollection) DeepCopy() *HarborGarbageCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborGarbageCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborGarbageCollection) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborGarbageCollectionList) DeepCopyInto(out *HarborGarbageCollectionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborGarbageCollection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborGarbageCollectionList.
+func (in *HarborGarbageCollectionList) DeepCopy() *HarborGarbageCollectionList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborGarbageCollectionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborGarbageCollectionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborGarbageCollectionSpec) DeepCopyInto(out *HarborGarbageCollectionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborGarbageCollectionSpec.
+func (in *HarborGarbageCollectionSpec) DeepCopy() *HarborGarbageCollectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborGarbageCollectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborGarbageCollectionStatus) DeepCopyInto(out *HarborGarbageCollectionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.HarborReadOnly != nil {
+		in, out := &in.HarborReadOnly, &out.HarborReadOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LastRun != nil {
+		in, out := &in.LastRun, &out.LastRun
+		*out = new(GarbageCollectionRun)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborGarbageCollectionStatus.
+func (in *HarborGarbageCollectionStatus) DeepCopy() *HarborGarbageCollectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborGarbageCollectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborImmutableTagRule) DeepCopyInto(out *HarborImmutableTagRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborImmutableTagRule.
+func (in *HarborImmutableTagRule) DeepCopy() *HarborImmutableTagRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborImmutableTagRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborImmutableTagRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborImmutableTagRuleList) DeepCopyInto(out *HarborImmutableTagRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborImmutableTagRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborImmutableTagRuleList.
+func (in *HarborImmutableTagRuleList) DeepCopy() *HarborImmutableTagRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborImmutableTagRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborImmutableTagRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborImmutableTagRuleSpec) DeepCopyInto(out *HarborImmutableTagRuleSpec) {
+	*out = *in
+	out.Project = in.Project
+	out.Repositories = in.Repositories
+	out.Tags = in.Tags
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborImmutableTagRuleSpec.
+func (in *HarborImmutableTagRuleSpec) DeepCopy() *HarborImmutableTagRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborImmutableTagRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborImmutableTagRuleStatus) DeepCopyInto(out *HarborImmutableTagRuleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborImmutableTagRuleStatus.
+func (in *HarborImmutableTagRuleStatus) DeepCopy() *HarborImmutableTagRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborImmutableTagRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborList) DeepCopyInto(out *HarborList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Harbor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborList.
+func (in *HarborList) DeepCopy() *HarborList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProject) DeepCopyInto(out *HarborProject) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProject.
+func (in *HarborProject) DeepCopy() *HarborProject {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborProject) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectList) DeepCopyInto(out *HarborProjectList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborProject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectList.
+func (in *HarborProjectList) DeepCopy() *HarborProjectList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborProjectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectSpec) DeepCopyInto(out *HarborProjectSpec) {
+	*out = *in
+	if in.StorageQuota != nil {
+		in, out := &in.StorageQuota, &out.StorageQuota
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectSpec.
+func (in *HarborProjectSpec) DeepCopy() *HarborProjectSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectStatus) DeepCopyInto(out *HarborProjectStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(ProjectQuotaStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectStatus.
+func (in *HarborProjectStatus) DeepCopy() *HarborProjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRegistryEndpoint) DeepCopyInto(out *HarborRegistryEndpoint) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRegistryEndpoint.
+func (in *HarborRegistryEndpoint) DeepCopy() *HarborRegistryEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRegistryEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRegistryEndpoint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRegistryEndpointList) DeepCopyInto(out *HarborRegistryEndpointList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborRegistryEndpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRegistryEndpointList.
+func (in *HarborRegistryEndpointList) DeepCopy() *HarborRegistryEndpointList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRegistryEndpointList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRegistryEndpointList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRegistryEndpointSpec) DeepCopyInto(out *HarborRegistryEndpointSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRegistryEndpointSpec.
+func (in *HarborRegistryEndpointSpec) DeepCopy() *HarborRegistryEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRegistryEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRegistryEndpointStatus) DeepCopyInto(out *HarborRegistryEndpointStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRegistryEndpointStatus.
+func (in *HarborRegistryEndpointStatus) DeepCopy() *HarborRegistryEndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRegistryEndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborReplicationPolicy) DeepCopyInto(out *HarborReplicationPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborReplicationPolicy.
+func (in *HarborReplicationPolicy) DeepCopy() *HarborReplicationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborReplicationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborReplicationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborReplicationPolicyList) DeepCopyInto(out *HarborReplicationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborReplicationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborReplicationPolicyList.
+func (in *HarborReplicationPolicyList) DeepCopy() *HarborReplicationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborReplicationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborReplicationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborReplicationPolicySpec) DeepCopyInto(out *HarborReplicationPolicySpec) {
+	*out = *in
+	in.Filters.DeepCopyInto(&out.Filters)
+	out.Trigger = in.Trigger
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborReplicationPolicySpec.
+func (in *HarborReplicationPolicySpec) DeepCopy() *HarborReplicationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborReplicationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborReplicationPolicyStatus) DeepCopyInto(out *HarborReplicationPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastExecution != nil {
+		in, out := &in.LastExecution, &out.LastExecution
+		*out = new(ReplicationExecution)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborReplicationPolicyStatus.
+func (in *HarborReplicationPolicyStatus) DeepCopy() *HarborReplicationPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborReplicationPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRestore) DeepCopyInto(out *HarborRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRestore.
+func (in *HarborRestore) DeepCopy() *HarborRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRestoreList) DeepCopyInto(out *HarborRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRestoreList.
+func (in *HarborRestoreList) DeepCopy() *HarborRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRestoreSpec) DeepCopyInto(out *HarborRestoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRestoreSpec.
+func (in *HarborRestoreSpec) DeepCopy() *HarborRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRestoreStatus) DeepCopyInto(out *HarborRestoreStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HarborReadOnly != nil {
+		in, out := &in.HarborReadOnly, &out.HarborReadOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRestoreStatus.
+func (in *HarborRestoreStatus) DeepCopy() *HarborRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRetentionPolicy) DeepCopyInto(out *HarborRetentionPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRetentionPolicy.
+func (in *HarborRetentionPolicy) DeepCopy() *HarborRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRetentionPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRetentionPolicyList) DeepCopyInto(out *HarborRetentionPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborRetentionPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRetentionPolicyList.
+func (in *HarborRetentionPolicyList) DeepCopy() *HarborRetentionPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRetentionPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRetentionPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRetentionPolicySpec) DeepCopyInto(out *HarborRetentionPolicySpec) {
+	*out = *in
+	out.Project = in.Project
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]RetentionRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRetentionPolicySpec.
+func (in *HarborRetentionPolicySpec) DeepCopy() *HarborRetentionPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRetentionPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRetentionPolicyStatus) DeepCopyInto(out *HarborRetentionPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRetentionPolicyStatus.
+func (in *HarborRetentionPolicyStatus) DeepCopy() *HarborRetentionPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRetentionPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRobotAccount) DeepCopyInto(out *HarborRobotAccount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRobotAccount.
+func (in *HarborRobotAccount) DeepCopy() *HarborRobotAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRobotAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRobotAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRobotAccountList) DeepCopyInto(out *HarborRobotAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborRobotAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRobotAccountList.
+func (in *HarborRobotAccountList) DeepCopy() *HarborRobotAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRobotAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRobotAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRobotAccountSpec) DeepCopyInto(out *HarborRobotAccountSpec) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]RobotPermission, len(*in))
+		copy(*out, *in)
+	}
+	if in.Expiration != nil {
+		in, out := &in.Expiration, &out.Expiration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRobotAccountSpec.
+func (in *HarborRobotAccountSpec) DeepCopy() *HarborRobotAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRobotAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRobotAccountStatus) DeepCopyInto(out *HarborRobotAccountStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]RobotPermission, len(*in))
+		copy(*out, *in)
+	}
+	if in.RotationTime != nil {
+		in, out := &in.RotationTime, &out.RotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetiredRobots != nil {
+		in, out := &in.RetiredRobots, &out.RetiredRobots
+		*out = make([]RetiredRobot, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRobotAccountStatus.
+func (in *HarborRobotAccountStatus) DeepCopy() *HarborRobotAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRobotAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborSpec) DeepCopyInto(out *HarborSpec) {
+	*out = *in
+	if in.AdditionalHostnames != nil {
+		in, out := &in.AdditionalHostnames, &out.AdditionalHostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InternalEndpoint != nil {
+		in, out := &in.InternalEndpoint, &out.InternalEndpoint
+		*out = new(InternalEndpointSpec)
+		**out = **in
+	}
+	in.Components.DeepCopyInto(&out.Components)
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	out.CertificateIssuerRef = in.CertificateIssuerRef
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(CertificatesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FinalBackup != nil {
+		in, out := &in.FinalBackup, &out.FinalBackup
+		*out = new(FinalBackupJob)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretRotation != nil {
+		in, out := &in.SecretRotation, &out.SecretRotation
+		*out = new(SecretRotation)
+		**out = **in
+	}
+	if in.InternalTLS != nil {
+		in, out := &in.InternalTLS, &out.InternalTLS
+		*out = new(InternalTLSSpec)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrustedCABundle != nil {
+		in, out := &in.TrustedCABundle, &out.TrustedCABundle
+		*out = new(TrustedCABundleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = new(ExposeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborSpec.
+func (in *HarborSpec) DeepCopy() *HarborSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborStatus) DeepCopyInto(out *HarborStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdminPasswordRotationTime != nil {
+		in, out := &in.AdminPasswordRotationTime, &out.AdminPasswordRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.InternalSecretsRotationTime != nil {
+		in, out := &in.InternalSecretsRotationTime, &out.InternalSecretsRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CertificatesRenewalTime != nil {
+		in, out := &in.CertificatesRenewalTime, &out.CertificatesRenewalTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborStatus.
+func (in *HarborStatus) DeepCopy() *HarborStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressSpec.
+func (in *IngressSpec) DeepCopy() *IngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalEndpointSpec) DeepCopyInto(out *InternalEndpointSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalEndpointSpec.
+func (in *InternalEndpointSpec) DeepCopy() *InternalEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalTLSSpec) DeepCopyInto(out *InternalTLSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalTLSSpec.
+func (in *InternalTLSSpec) DeepCopy() *InternalTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobServiceComponent) DeepCopyInto(out *JobServiceComponent) {
+	*out = *in
+	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobServiceComponent.
+func (in *JobServiceComponent) DeepCopy() *JobServiceComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(JobServiceComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in NodeSelector) DeepCopyInto(out *NodeSelector) {
+	{
+		in := &in
+		*out = make(NodeSelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSelector.
+func (in NodeSelector) DeepCopy() NodeSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSelector)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotaryComponent) DeepCopyInto(out *NotaryComponent) {
+	*out = *in
+	in.DBMigrator.DeepCopyInto(&out.DBMigrator)
+	in.Signer.DeepCopyInto(&out.Signer)
+	in.Server.DeepCopyInto(&out.Server)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotaryComponent.
+func (in *NotaryComponent) DeepCopy() *NotaryComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(NotaryComponent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClairAdapterComponent) DeepCopyInto(out *ClairAdapterComponent) {
+func (in *NotaryDBMigrator) DeepCopyInto(out *NotaryDBMigrator) {
 	*out = *in
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
@@ -35,417 +1893,354 @@ func (in *ClairAdapterComponent) DeepCopyInto(out *ClairAdapterComponent) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClairAdapterComponent.
-func (in *ClairAdapterComponent) DeepCopy() *ClairAdapterComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotaryDBMigrator.
+func (in *NotaryDBMigrator) DeepCopy() *NotaryDBMigrator {
 	if in == nil {
 		return nil
 	}
-	out := new(ClairAdapterComponent)
+	out := new(NotaryDBMigrator)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClairComponent) DeepCopyInto(out *ClairComponent) {
+func (in *NotaryServerComponent) DeepCopyInto(out *NotaryServerComponent) {
 	*out = *in
 	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
-	if in.VulnerabilitySources != nil {
-		in, out := &in.VulnerabilitySources, &out.VulnerabilitySources
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	in.Adapter.DeepCopyInto(&out.Adapter)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClairComponent.
-func (in *ClairComponent) DeepCopy() *ClairComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotaryServerComponent.
+func (in *NotaryServerComponent) DeepCopy() *NotaryServerComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(ClairComponent)
+	out := new(NotaryServerComponent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CoreComponent) DeepCopyInto(out *CoreComponent) {
+func (in *NotarySignerComponent) DeepCopyInto(out *NotarySignerComponent) {
 	*out = *in
 	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreComponent.
-func (in *CoreComponent) DeepCopy() *CoreComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotarySignerComponent.
+func (in *NotarySignerComponent) DeepCopy() *NotarySignerComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(CoreComponent)
+	out := new(NotarySignerComponent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Harbor) DeepCopyInto(out *Harbor) {
+func (in *PatternSelector) DeepCopyInto(out *PatternSelector) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Harbor.
-func (in *Harbor) DeepCopy() *Harbor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternSelector.
+func (in *PatternSelector) DeepCopy() *PatternSelector {
 	if in == nil {
 		return nil
 	}
-	out := new(Harbor)
+	out := new(PatternSelector)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Harbor) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortalComponent) DeepCopyInto(out *PortalComponent) {
+	*out = *in
+	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortalComponent.
+func (in *PortalComponent) DeepCopy() *PortalComponent {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(PortalComponent)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborComponents) DeepCopyInto(out *HarborComponents) {
+func (in *ProjectQuotaStatus) DeepCopyInto(out *ProjectQuotaStatus) {
 	*out = *in
-	if in.Core != nil {
-		in, out := &in.Core, &out.Core
-		*out = new(CoreComponent)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Portal != nil {
-		in, out := &in.Portal, &out.Portal
-		*out = new(PortalComponent)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Registry != nil {
-		in, out := &in.Registry, &out.Registry
-		*out = new(RegistryComponent)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.JobService != nil {
-		in, out := &in.JobService, &out.JobService
-		*out = new(JobServiceComponent)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ChartMuseum != nil {
-		in, out := &in.ChartMuseum, &out.ChartMuseum
-		*out = new(ChartMuseumComponent)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Clair != nil {
-		in, out := &in.Clair, &out.Clair
-		*out = new(ClairComponent)
-		(*in).DeepCopyInto(*out)
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		x := (*in).DeepCopy()
+		*out = &x
 	}
-	if in.Notary != nil {
-		in, out := &in.Notary, &out.Notary
-		*out = new(NotaryComponent)
-		(*in).DeepCopyInto(*out)
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		x := (*in).DeepCopy()
+		*out = &x
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborComponents.
-func (in *HarborComponents) DeepCopy() *HarborComponents {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectQuotaStatus.
+func (in *ProjectQuotaStatus) DeepCopy() *ProjectQuotaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborComponents)
+	out := new(ProjectQuotaStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborCondition) DeepCopyInto(out *HarborCondition) {
+func (in *ProjectReference) DeepCopyInto(out *ProjectReference) {
 	*out = *in
-	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborCondition.
-func (in *HarborCondition) DeepCopy() *HarborCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectReference.
+func (in *ProjectReference) DeepCopy() *ProjectReference {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborCondition)
+	out := new(ProjectReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborDeployment) DeepCopyInto(out *HarborDeployment) {
+func (in *ProxySpec) DeepCopyInto(out *ProxySpec) {
 	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(string)
-		**out = **in
-	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(NodeSelector, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborDeployment.
-func (in *HarborDeployment) DeepCopy() *HarborDeployment {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySpec.
+func (in *ProxySpec) DeepCopy() *ProxySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborDeployment)
+	out := new(ProxySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborList) DeepCopyInto(out *HarborList) {
+func (in *RedisSpec) DeepCopyInto(out *RedisSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Harbor, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborList.
-func (in *HarborList) DeepCopy() *HarborList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSpec.
+func (in *RedisSpec) DeepCopy() *RedisSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborList)
+	out := new(RedisSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HarborList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborSpec) DeepCopyInto(out *HarborSpec) {
+func (in *RegistryComponent) DeepCopyInto(out *RegistryComponent) {
 	*out = *in
-	in.Components.DeepCopyInto(&out.Components)
-	if in.Priority != nil {
-		in, out := &in.Priority, &out.Priority
-		*out = new(int32)
-		**out = **in
+	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
+	in.Controller.DeepCopyInto(&out.Controller)
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(RedisSpec)
+		(*in).DeepCopyInto(*out)
 	}
-	out.CertificateIssuerRef = in.CertificateIssuerRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborSpec.
-func (in *HarborSpec) DeepCopy() *HarborSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryComponent.
+func (in *RegistryComponent) DeepCopy() *RegistryComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborSpec)
+	out := new(RegistryComponent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborStatus) DeepCopyInto(out *HarborStatus) {
+func (in *RegistryControllerComponent) DeepCopyInto(out *RegistryControllerComponent) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]HarborCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborStatus.
-func (in *HarborStatus) DeepCopy() *HarborStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryControllerComponent.
+func (in *RegistryControllerComponent) DeepCopy() *RegistryControllerComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborStatus)
+	out := new(RegistryControllerComponent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JobServiceComponent) DeepCopyInto(out *JobServiceComponent) {
+func (in *ReplicationExecution) DeepCopyInto(out *ReplicationExecution) {
 	*out = *in
-	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobServiceComponent.
-func (in *JobServiceComponent) DeepCopy() *JobServiceComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationExecution.
+func (in *ReplicationExecution) DeepCopy() *ReplicationExecution {
 	if in == nil {
 		return nil
 	}
-	out := new(JobServiceComponent)
+	out := new(ReplicationExecution)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in NodeSelector) DeepCopyInto(out *NodeSelector) {
-	{
-		in := &in
-		*out = make(NodeSelector, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+func (in *ReplicationFilters) DeepCopyInto(out *ReplicationFilters) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSelector.
-func (in NodeSelector) DeepCopy() NodeSelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationFilters.
+func (in *ReplicationFilters) DeepCopy() *ReplicationFilters {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeSelector)
+	out := new(ReplicationFilters)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NotaryComponent) DeepCopyInto(out *NotaryComponent) {
+func (in *ReplicationTrigger) DeepCopyInto(out *ReplicationTrigger) {
 	*out = *in
-	in.DBMigrator.DeepCopyInto(&out.DBMigrator)
-	in.Signer.DeepCopyInto(&out.Signer)
-	in.Server.DeepCopyInto(&out.Server)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotaryComponent.
-func (in *NotaryComponent) DeepCopy() *NotaryComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationTrigger.
+func (in *ReplicationTrigger) DeepCopy() *ReplicationTrigger {
 	if in == nil {
 		return nil
 	}
-	out := new(NotaryComponent)
+	out := new(ReplicationTrigger)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NotaryDBMigrator) DeepCopyInto(out *NotaryDBMigrator) {
+func (in *RetentionRule) DeepCopyInto(out *RetentionRule) {
 	*out = *in
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(string)
-		**out = **in
-	}
+	out.Repositories = in.Repositories
+	out.Tags = in.Tags
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotaryDBMigrator.
-func (in *NotaryDBMigrator) DeepCopy() *NotaryDBMigrator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionRule.
+func (in *RetentionRule) DeepCopy() *RetentionRule {
 	if in == nil {
 		return nil
 	}
-	out := new(NotaryDBMigrator)
+	out := new(RetentionRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NotaryServerComponent) DeepCopyInto(out *NotaryServerComponent) {
+func (in *RetiredRobot) DeepCopyInto(out *RetiredRobot) {
 	*out = *in
-	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotaryServerComponent.
-func (in *NotaryServerComponent) DeepCopy() *NotaryServerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetiredRobot.
+func (in *RetiredRobot) DeepCopy() *RetiredRobot {
 	if in == nil {
 		return nil
 	}
-	out := new(NotaryServerComponent)
+	out := new(RetiredRobot)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NotarySignerComponent) DeepCopyInto(out *NotarySignerComponent) {
+func (in *S3BackupTarget) DeepCopyInto(out *S3BackupTarget) {
 	*out = *in
-	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotarySignerComponent.
-func (in *NotarySignerComponent) DeepCopy() *NotarySignerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupTarget.
+func (in *S3BackupTarget) DeepCopy() *S3BackupTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(NotarySignerComponent)
+	out := new(S3BackupTarget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PortalComponent) DeepCopyInto(out *PortalComponent) {
+func (in *SecretRotation) DeepCopyInto(out *SecretRotation) {
 	*out = *in
-	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortalComponent.
-func (in *PortalComponent) DeepCopy() *PortalComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretRotation.
+func (in *SecretRotation) DeepCopy() *SecretRotation {
 	if in == nil {
 		return nil
 	}
-	out := new(PortalComponent)
+	out := new(SecretRotation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RegistryComponent) DeepCopyInto(out *RegistryComponent) {
+func (in *ServiceIPFamilies) DeepCopyInto(out *ServiceIPFamilies) {
 	*out = *in
-	in.HarborDeployment.DeepCopyInto(&out.HarborDeployment)
-	in.Controller.DeepCopyInto(&out.Controller)
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]v1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryComponent.
-func (in *RegistryComponent) DeepCopy() *RegistryComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceIPFamilies.
+func (in *ServiceIPFamilies) DeepCopy() *ServiceIPFamilies {
 	if in == nil {
 		return nil
 	}
-	out := new(RegistryComponent)
+	out := new(ServiceIPFamilies)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RegistryControllerComponent) DeepCopyInto(out *RegistryControllerComponent) {
+func (in *TrustedCABundleSpec) DeepCopyInto(out *TrustedCABundleSpec) {
 	*out = *in
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(string)
-		**out = **in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryControllerComponent.
-func (in *RegistryControllerComponent) DeepCopy() *RegistryControllerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedCABundleSpec.
+func (in *TrustedCABundleSpec) DeepCopy() *TrustedCABundleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RegistryControllerComponent)
+	out := new(TrustedCABundleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	harborCtrl "github.com/goharbor/harbor-operator/controllers/harbor"
+)
+
+func certManagerCRD(versions ...string) *apiextensionsv1beta1.CustomResourceDefinition {
+	crd := &apiextensionsv1beta1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "certificates.cert-manager.io",
+		},
+		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+			Group: "cert-manager.io",
+			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+				Plural:   "certificates",
+				Singular: "certificate",
+				Kind:     "Certificate",
+				ListKind: "CertificateList",
+			},
+			Scope: apiextensionsv1beta1.NamespaceScoped,
+		},
+	}
+
+	for i, version := range versions {
+		crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1beta1.CustomResourceDefinitionVersion{
+			Name:    version,
+			Served:  true,
+			Storage: i == 0,
+		})
+	}
+
+	return crd
+}
+
+var _ = Describe("cert-manager version detection", func() {
+	detect := func(versions ...string) string {
+		env := &envtest.Environment{
+			CRDs: []*apiextensionsv1beta1.CustomResourceDefinition{certManagerCRD(versions...)},
+		}
+
+		config, err := env.Start()
+		Expect(err).ToNot(HaveOccurred())
+
+		defer func() {
+			Expect(env.Stop()).To(Succeed())
+		}()
+
+		client, err := discovery.NewDiscoveryClientForConfig(config)
+		Expect(err).ToNot(HaveOccurred())
+
+		version, err := harborCtrl.DetectCertManagerVersion(client)
+		Expect(err).ToNot(HaveOccurred())
+
+		return version
+	}
+
+	It("Should render v1 certificates when served", func() {
+		Expect(detect(harborCtrl.CertManagerV1, harborCtrl.CertManagerV1Alpha2)).To(Equal(harborCtrl.CertManagerV1))
+	})
+
+	It("Should fall back to v1alpha2 certificates", func() {
+		Expect(detect(harborCtrl.CertManagerV1Alpha2)).To(Equal(harborCtrl.CertManagerV1Alpha2))
+	})
+})
@@ -0,0 +1,265 @@
+package harbor
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"github.com/sethvargo/go-password/password"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+)
+
+const (
+	EventReasonAdminPasswordGenerated     = "AdminPasswordGenerated"
+	EventReasonAdminPasswordRotated       = "AdminPasswordRotated"
+	EventReasonAdminPasswordRotationError = "AdminPasswordRotationError"
+)
+
+const (
+	adminPasswordLength = 16
+	adminPasswordDigits = 4
+)
+
+var (
+	// Harbor requires an uppercase letter, a lowercase letter and a number
+	adminPasswordPolicy = []*regexp.Regexp{
+		regexp.MustCompile(`[A-Z]`),
+		regexp.MustCompile(`[a-z]`),
+		regexp.MustCompile(`[0-9]`),
+	}
+)
+
+// GenerateAdminPassword returns a random password matching the password policy of Harbor.
+func GenerateAdminPassword() (string, error) {
+	for {
+		value, err := password.Generate(adminPasswordLength, adminPasswordDigits, 0, false, true)
+		if err != nil {
+			return "", errors.Wrap(err, "cannot generate password")
+		}
+
+		if IsValidAdminPassword(value) {
+			return value, nil
+		}
+	}
+}
+
+// IsValidAdminPassword returns whether the password matches the password policy of Harbor.
+func IsValidAdminPassword(value string) bool {
+	for _, policy := range adminPasswordPolicy {
+		if !policy.MatchString(value) {
+			return false
+		}
+	}
+
+	return len(value) >= 8
+}
+
+// NewAdminPasswordSecret returns a secret holding the admin password, owned by the Harbor.
+func (r *Reconciler) NewAdminPasswordSecret(ctx context.Context, harbor *goharborv1alpha1.Harbor, name, value string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: harbor.GetNamespace(),
+			Labels: map[string]string{
+				goharborv1alpha1.OperatorNameLabel:    r.GetName(),
+				goharborv1alpha1.OperatorVersionLabel: r.GetVersion(),
+				goharborv1alpha1.ComponentNameLabel:   goharborv1alpha1.CoreName,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			goharborv1alpha1.HarborAdminPasswordKey: value,
+		},
+	}
+
+	r.MutateAnnotations(ctx, secret)
+
+	err := controllerutil.SetControllerReference(harbor, secret, r.Scheme)
+
+	return secret, errors.Wrap(err, "cannot set controller reference")
+}
+
+// getAdminPassword returns the admin password of the secret, empty if the secret does not exist.
+func (r *Reconciler) getAdminPassword(ctx context.Context, harbor *goharborv1alpha1.Harbor, name string) (string, *corev1.Secret, error) {
+	secret := &corev1.Secret{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: harbor.GetNamespace(), Name: name}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil, nil
+		}
+
+		return "", nil, errors.Wrapf(err, "cannot get secret %s", name)
+	}
+
+	value, ok := secret.Data[goharborv1alpha1.HarborAdminPasswordKey]
+	if !ok {
+		return "", secret, errors.Errorf("key %s not found in secret %s", goharborv1alpha1.HarborAdminPasswordKey, name)
+	}
+
+	return string(value), secret, nil
+}
+
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=get;list;watch;update;patch;create
+
+// EnsureAdminPasswordSecret generates the admin password secret if it does not exist.
+func (r *Reconciler) EnsureAdminPasswordSecret(ctx context.Context, harbor *goharborv1alpha1.Harbor) error {
+	_, secret, err := r.getAdminPassword(ctx, harbor, harbor.Spec.AdminPasswordSecret)
+	if err != nil || secret != nil {
+		return err
+	}
+
+	value, err := GenerateAdminPassword()
+	if err != nil {
+		return err
+	}
+
+	secret, err = r.NewAdminPasswordSecret(ctx, harbor, harbor.Spec.AdminPasswordSecret, value)
+	if err != nil {
+		return err
+	}
+
+	err = r.Client.Create(ctx, secret)
+	if err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+
+		return errors.Wrapf(err, "cannot create secret %s", secret.GetName())
+	}
+
+	logger.Get(ctx).Info("admin password generated", "Secret.Name", secret.GetName())
+	r.Recorder.Eventf(harbor, corev1.EventTypeNormal, EventReasonAdminPasswordGenerated, "secret %s generated", secret.GetName())
+
+	return nil
+}
+
+// RotateAdminPassword changes the admin password in Harbor when the content of the admin password secret changes.
+// Core only reads the secret at initialization: the password currently set is kept in a secret managed by the operator,
+// and used as the previous password.
+func (r *Reconciler) RotateAdminPassword(ctx context.Context, harbor *goharborv1alpha1.Harbor) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "rotateAdminPassword")
+	defer span.Finish()
+
+	desired, secret, err := r.getAdminPassword(ctx, harbor, harbor.Spec.AdminPasswordSecret)
+	if err != nil || secret == nil {
+		return err
+	}
+
+	applied, appliedSecret, err := r.getAdminPassword(ctx, harbor, harbor.AppliedAdminPasswordSecretName())
+	if err != nil {
+		return err
+	}
+
+	if appliedSecret == nil {
+		// Harbor has been initialized with the password of the secret, unless the secret changed since
+		err = r.checkAdminPassword(ctx, harbor)
+		if err != nil {
+			return err
+		}
+
+		appliedSecret, err = r.NewAdminPasswordSecret(ctx, harbor, harbor.AppliedAdminPasswordSecretName(), desired)
+		if err != nil {
+			return err
+		}
+
+		err = r.Client.Create(ctx, appliedSecret)
+
+		return errors.Wrapf(err, "cannot create secret %s", appliedSecret.GetName())
+	}
+
+	if applied == desired {
+		return nil
+	}
+
+	if !IsValidAdminPassword(desired) {
+		return errors.Errorf("password of secret %s requires at least 8 characters with an uppercase letter, a lowercase letter and a number", harbor.Spec.AdminPasswordSecret)
+	}
+
+	api, err := harborapi.New(ctx, r.Client, harbor, harborapi.UserAgent(r.GetName(), r.GetVersion()))
+	if err != nil {
+		return errors.Wrap(err, "cannot get harbor client")
+	}
+
+	err = api.ChangePassword(ctx, harborapi.AdminUserID, applied, desired)
+	if harborapi.IsUnauthorized(err) {
+		// The password may have been changed by a previous rotation which failed to update the applied secret
+		api.Password = desired
+
+		_, err = api.GetCurrentUser(ctx)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "cannot change password")
+	}
+
+	appliedSecret.Data[goharborv1alpha1.HarborAdminPasswordKey] = []byte(desired)
+
+	err = r.Client.Update(ctx, appliedSecret)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update secret %s", appliedSecret.GetName())
+	}
+
+	now := metav1.Now()
+	harbor.Status.AdminPasswordRotationTime = &now
+
+	logger.Get(ctx).Info("admin password rotated")
+	r.Recorder.Event(harbor, corev1.EventTypeNormal, EventReasonAdminPasswordRotated, "admin password changed")
+
+	return nil
+}
+
+// checkAdminPassword checks the password of the admin password secret is the one set in Harbor, before recording it as applied.
+// Otherwise the previous password is unknown: it has to be set in the applied secret to rotate the password.
+func (r *Reconciler) checkAdminPassword(ctx context.Context, harbor *goharborv1alpha1.Harbor) error {
+	// Without applied secret, the client authenticates with the password of the admin password secret
+	api, err := harborapi.New(ctx, r.Client, harbor, harborapi.UserAgent(r.GetName(), r.GetVersion()))
+	if err != nil {
+		return errors.Wrap(err, "cannot get harbor client")
+	}
+
+	_, err = api.GetCurrentUser(ctx)
+	if harborapi.IsUnauthorized(err) {
+		return errors.Errorf("password of secret %s is not the one set in harbor, create secret %s with the current password in key %s",
+			harbor.Spec.AdminPasswordSecret, harbor.AppliedAdminPasswordSecretName(), goharborv1alpha1.HarborAdminPasswordKey)
+	}
+
+	return errors.Wrap(err, "cannot check admin password")
+}
+
+// adminPasswordSecretRequests returns the Harbors of the class of the operator using the secret as admin password secret,
+// so the password is rotated as soon as the secret changes.
+func (r *Reconciler) adminPasswordSecretRequests(object handler.MapObject) []reconcile.Request {
+	harbors := &goharborv1alpha1.HarborList{}
+
+	err := r.Client.List(context.TODO(), harbors, client.InNamespace(object.Meta.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "cannot list harbors", "Secret.Namespace", object.Meta.GetNamespace(), "Secret.Name", object.Meta.GetName())
+		return nil
+	}
+
+	filter := r.GetEventFilter()
+	requests := []reconcile.Request{}
+
+	for i, harbor := range harbors.Items {
+		if harbor.Spec.AdminPasswordSecret == object.Meta.GetName() && filter.HarborClassAnnotationMatch(&harbors.Items[i]) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: harbor.GetNamespace(), Name: harbor.GetName()},
+			})
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,152 @@
+package harbor
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/harborapi/harborapitest"
+)
+
+var _ = Describe("Admin password", func() {
+	var r *Reconciler
+	var ctx context.Context
+
+	BeforeEach(func() {
+		r, ctx = setupTest(context.TODO())
+		r.Name = "harbor-operator"
+		r.Version = "test"
+	})
+
+	It("Should generate passwords matching Harbor policy", func() {
+		for i := 0; i < 100; i++ {
+			value, err := GenerateAdminPassword()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(value).To(HaveLen(adminPasswordLength))
+			Expect(IsValidAdminPassword(value)).To(BeTrue())
+		}
+	})
+
+	It("Should reject weak passwords", func() {
+		Expect(IsValidAdminPassword("Harbor12345")).To(BeTrue())
+		Expect(IsValidAdminPassword("harbor12345")).To(BeFalse())
+		Expect(IsValidAdminPassword("HARBOR12345")).To(BeFalse())
+		Expect(IsValidAdminPassword("HarborHarbor")).To(BeFalse())
+		Expect(IsValidAdminPassword("Harb0r")).To(BeFalse())
+	})
+
+	It("Should own generated secrets", func() {
+		h := &goharborv1alpha1.Harbor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "harbor",
+				Namespace: "ns",
+				UID:       "uid",
+			},
+			Spec: goharborv1alpha1.HarborSpec{
+				AdminPasswordSecret: "admin",
+			},
+		}
+
+		secret, err := r.NewAdminPasswordSecret(ctx, h, h.AppliedAdminPasswordSecretName(), "Harbor12345")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.GetName()).To(Equal("harbor-applied-admin-password"))
+		Expect(secret.GetNamespace()).To(Equal("ns"))
+		Expect(secret.StringData).To(HaveKeyWithValue(goharborv1alpha1.HarborAdminPasswordKey, "Harbor12345"))
+		Expect(secret.GetLabels()).To(HaveKeyWithValue(goharborv1alpha1.ComponentNameLabel, goharborv1alpha1.CoreName))
+		Expect(metav1.GetControllerOf(secret)).ToNot(BeNil())
+		Expect(metav1.GetControllerOf(secret).Name).To(Equal("harbor"))
+	})
+
+	Context("Applied password", func() {
+		var server *harborapitest.Server
+		var h *goharborv1alpha1.Harbor
+
+		BeforeEach(func() {
+			server = harborapitest.NewServer()
+			h = harborapitest.NewHarbor("ns")
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		setup := func(objects ...runtime.Object) {
+			r.Client = fake.NewFakeClientWithScheme(r.Scheme, append(objects, h, harborapitest.NewAdminPasswordSecret(h))...)
+		}
+
+		getAppliedSecret := func() (*corev1.Secret, error) {
+			secret := &corev1.Secret{}
+			err := r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: h.AppliedAdminPasswordSecretName()}, secret)
+
+			return secret, err
+		}
+
+		It("Should record the password once checked in Harbor", func() {
+			server.HandleJSON(http.MethodGet, "/users/current", http.StatusOK, nil)
+			setup()
+
+			Expect(r.RotateAdminPassword(ctx, h)).To(Succeed())
+
+			requests := server.Requests(http.MethodGet, "/users/current")
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Password).To(Equal(harborapitest.AdminPassword))
+
+			_, err := getAppliedSecret()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should ask for the current password when the secret changed before being recorded", func() {
+			server.HandleJSON(http.MethodGet, "/users/current", http.StatusUnauthorized, nil)
+			setup()
+
+			err := r.RotateAdminPassword(ctx, h)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("create secret harbor-applied-admin-password with the current password"))
+
+			_, err = getAppliedSecret()
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("Admin password secret watch", func() {
+		newHarbor := func(name, class string) *goharborv1alpha1.Harbor {
+			h := &goharborv1alpha1.Harbor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "ns",
+				},
+				Spec: goharborv1alpha1.HarborSpec{
+					AdminPasswordSecret: "admin",
+				},
+			}
+
+			if class != "" {
+				h.SetAnnotations(map[string]string{goharborv1alpha1.HarborClassAnnotation: class})
+			}
+
+			return h
+		}
+
+		It("Should only reconcile Harbors of the class of the operator", func() {
+			r.Config.ClassName = "internal"
+			r.Client = fake.NewFakeClientWithScheme(r.Scheme, newHarbor("internal", "internal"), newHarbor("other", "other"), newHarbor("default", ""))
+
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: "ns"}}
+
+			requests := r.adminPasswordSecretRequests(handler.MapObject{Meta: secret, Object: secret})
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Name).To(Equal("internal"))
+		})
+	})
+})
@@ -90,6 +90,15 @@ func (r *Reconciler) ApplyResource(ctx context.Context, harbor *goharborv1alpha1
 
 	span.SetTag("Resource.Operation", op)
 
+	applyTotal.WithLabelValues(r.resourceKind(result), string(op)).Inc()
+
+	switch op {
+	case controllerutil.OperationResultCreated:
+		r.resourceEvent(harbor, EventReasonResourceCreated, result)
+	case controllerutil.OperationResultUpdated:
+		r.resourceEvent(harbor, EventReasonResourceUpdated, result)
+	}
+
 	return result, nil
 }
 
@@ -178,7 +187,13 @@ func mutateService(serviceResource, result components.Resource) controllerutil.M
 
 		defer func() { serviceResult.Spec.ClusterIP = clusterIP }()
 
-		for _, port := range serviceResult.Spec.Ports {
+		// Node ports are not allowed with the ClusterIP type
+		nodePorts := serviceResult.Spec.Ports
+		if service.Spec.Type == "" || service.Spec.Type == corev1.ServiceTypeClusterIP {
+			nodePorts = nil
+		}
+
+		for _, port := range nodePorts {
 			port := port
 
 			defer func() {
@@ -226,8 +241,25 @@ func mutateDeployment(deploymentResource, result components.Resource) controller
 			return errors.Errorf("unexpected argument %+v", result)
 		}
 
+		// Keep the last restarts triggered by the operator
+		restarts := map[string]string{}
+
+		for _, annotation := range restartAnnotations {
+			if value, ok := deploymentResult.Spec.Template.GetAnnotations()[annotation]; ok {
+				restarts[annotation] = value
+			}
+		}
+
 		deployment.DeepCopyInto(deploymentResult)
 
+		if len(restarts) > 0 && deploymentResult.Spec.Template.Annotations == nil {
+			deploymentResult.Spec.Template.Annotations = map[string]string{}
+		}
+
+		for annotation, value := range restarts {
+			deploymentResult.Spec.Template.Annotations[annotation] = value
+		}
+
 		return nil
 	}
 }
@@ -255,26 +287,33 @@ func mutateConfigMap(configResource, result components.Resource) controllerutil.
 // +kubebuilder:rbac:groups="apps",resources="deployments",verbs=get;list;watch;update;patch;create
 
 func (r *Reconciler) ApplyComponent(ctx context.Context, harbor *goharborv1alpha1.Harbor, component *components.ComponentRunner) error {
-	service := func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
-		return r.ApplyResources(ctx, harbor, resources, func() components.Resource { return &corev1.Service{} }, mutateService)
-	}
 	configMap := func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
 		return r.ApplyResources(ctx, harbor, resources, func() components.Resource { return &corev1.ConfigMap{} }, mutateConfigMap)
 	}
 	ingress := func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
-		return r.ApplyResources(ctx, harbor, resources, func() components.Resource { return &netv1.Ingress{} }, mutateIngress)
+		mutate := mutateIngress
+		if r.IngressGVK().Version != netv1.SchemeGroupVersion.Version {
+			mutate = mutateUnstructured
+		}
+
+		return r.ApplyResources(ctx, harbor, resources, r.NewIngressObject, mutate)
 	}
 	secret := func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
 		return r.ApplyResources(ctx, harbor, resources, func() components.Resource { return &corev1.Secret{} }, mutateSecret)
 	}
 	certificate := func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
-		return r.ApplyResources(ctx, harbor, resources, func() components.Resource { return &certv1.Certificate{} }, mutateCertificate)
+		mutate := mutateCertificate
+		if r.CertificateGVK().Version != certv1.SchemeGroupVersion.Version {
+			mutate = mutateUnstructuredCertificate
+		}
+
+		return r.ApplyResources(ctx, harbor, resources, r.NewCertificateObject, mutate)
 	}
 	deployment := func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
 		return r.ApplyResources(ctx, harbor, resources, func() components.Resource { return &appsv1.Deployment{} }, mutateDeployment)
 	}
 
-	return component.ParallelRun(ctx, harbor, service, configMap, ingress, secret, certificate, deployment, true)
+	return component.ParallelRun(ctx, harbor, r.customizeServices(r.applyServices), configMap, r.convertIngresses(ingress), secret, r.convertCertificates(certificate), r.injectSidecars(deployment), true)
 }
 
 func (r *Reconciler) Apply(ctx context.Context, harbor *goharborv1alpha1.Harbor) error {
@@ -286,8 +325,15 @@ func (r *Reconciler) Apply(ctx context.Context, harbor *goharborv1alpha1.Harbor)
 		return errors.Wrap(err, "cannot get resources to manage")
 	}
 
+	r.warningEvents(harbor)
+
 	var g errgroup.Group
 
+	g.Go(func() error {
+		err := r.CheckReferencedSecrets(ctx, harbor)
+		return errors.Wrap(err, "cannot check referenced secrets")
+	})
+
 	if harbor.Spec.Components.Clair == nil {
 		g.Go(func() error {
 			err := r.DeleteComponent(ctx, harbor, goharborv1alpha1.ClairName)
@@ -302,6 +348,11 @@ func (r *Reconciler) Apply(ctx context.Context, harbor *goharborv1alpha1.Harbor)
 		})
 	}
 
+	g.Go(func() error {
+		err := r.ApplyExpose(ctx, harbor)
+		return errors.Wrap(err, "cannot expose components")
+	})
+
 	g.Go(func() error {
 		err = harborResource.ParallelRun(ctx, harbor, r.ApplyComponent)
 		return errors.Wrap(err, "cannot deploy component")
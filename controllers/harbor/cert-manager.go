@@ -0,0 +1,189 @@
+package harbor
+
+import (
+	"context"
+	"strings"
+
+	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+)
+
+const (
+	CertManagerV1       = "v1"
+	CertManagerV1Alpha2 = "v1alpha2"
+)
+
+// CertManagerVersions are the supported versions of cert-manager API, by order of preference.
+var CertManagerVersions = []string{CertManagerV1, CertManagerV1Alpha2}
+
+// v1alpha2 fields renamed in v1, the private key settings are moved to spec.privateKey
+var certificateV1Fields = map[string]string{
+	"organization": "organizations",
+	"uriSANs":      "uris",
+}
+
+// DetectCertManagerVersion returns the preferred cert-manager API version serving certificates, empty if none is served.
+func DetectCertManagerVersion(client discovery.DiscoveryInterface) (string, error) {
+	for _, version := range CertManagerVersions {
+		resources, err := client.ServerResourcesForGroupVersion(schema.GroupVersion{Group: certv1.SchemeGroupVersion.Group, Version: version}.String())
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return "", errors.Wrapf(err, "cannot discover cert-manager %s", version)
+		}
+
+		for _, resource := range resources.APIResources {
+			if resource.Kind == "Certificate" {
+				return version, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// CertificateGVK returns the kind of certificates rendered for the detected cert-manager version.
+func (r *Reconciler) CertificateGVK() schema.GroupVersionKind {
+	version := r.Config.CertManagerVersion
+	if version == "" {
+		version = certv1.SchemeGroupVersion.Version
+	}
+
+	return schema.GroupVersionKind{
+		Group:   certv1.SchemeGroupVersion.Group,
+		Version: version,
+		Kind:    "Certificate",
+	}
+}
+
+// NewCertificateObject returns an empty certificate of the kind served by cert-manager.
+func (r *Reconciler) NewCertificateObject() components.Resource {
+	gvk := r.CertificateGVK()
+	if gvk.Version == certv1.SchemeGroupVersion.Version {
+		return &certv1.Certificate{}
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(gvk)
+
+	return certificate
+}
+
+// ConvertCertificate returns the certificate rendered for the cert-manager version.
+// Only v1alpha2 is vendored, other versions are rendered as unstructured objects.
+func ConvertCertificate(certificate *certv1.Certificate, version string) (components.Resource, error) {
+	if version == "" || version == certv1.SchemeGroupVersion.Version {
+		return certificate, nil
+	}
+
+	if version != CertManagerV1 {
+		return nil, errors.Errorf("unsupported cert-manager version %s", version)
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(certificate)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert certificate")
+	}
+
+	spec, _ := content["spec"].(map[string]interface{})
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+
+	for oldName, name := range certificateV1Fields {
+		if value, ok := spec[oldName]; ok {
+			delete(spec, oldName)
+			spec[name] = value
+		}
+	}
+
+	if organizations, ok := spec["organizations"]; ok {
+		delete(spec, "organizations")
+		spec["subject"] = map[string]interface{}{"organizations": organizations}
+	}
+
+	privateKey := map[string]interface{}{}
+
+	if algorithm, ok := spec["keyAlgorithm"].(string); ok {
+		// rsa and ecdsa are uppercase in v1
+		privateKey["algorithm"] = strings.ToUpper(algorithm)
+	}
+
+	if encoding, ok := spec["keyEncoding"].(string); ok {
+		// pkcs1 and pkcs8 are uppercase in v1
+		privateKey["encoding"] = strings.ToUpper(encoding)
+	}
+
+	if size, ok := spec["keySize"]; ok {
+		privateKey["size"] = size
+	}
+
+	delete(spec, "keyAlgorithm")
+	delete(spec, "keyEncoding")
+	delete(spec, "keySize")
+
+	if len(privateKey) > 0 {
+		spec["privateKey"] = privateKey
+	}
+
+	delete(content, "status")
+	content["spec"] = spec
+
+	result := &unstructured.Unstructured{Object: content}
+	result.SetGroupVersionKind(certv1.SchemeGroupVersion.WithKind("Certificate").GroupKind().WithVersion(version))
+
+	return result, nil
+}
+
+// convertCertificates wraps the run of component certificates, so they are rendered for the cert-manager version.
+func (r *Reconciler) convertCertificates(run components.ComponentRun) components.ComponentRun {
+	return func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
+		if len(resources) > 0 && !r.Config.CertManager {
+			return errors.New("cert-manager is not available")
+		}
+
+		converted := make([]components.Resource, len(resources))
+
+		for i, resource := range resources {
+			certificate, ok := resource.(*certv1.Certificate)
+			if !ok {
+				return errors.Errorf("unexpected certificate %+v", resource)
+			}
+
+			result, err := ConvertCertificate(certificate, r.Config.CertManagerVersion)
+			if err != nil {
+				return err
+			}
+
+			converted[i] = result
+		}
+
+		return run(ctx, harbor, converted)
+	}
+}
+
+func mutateUnstructuredCertificate(certificateResource, result components.Resource) controllerutil.MutateFn {
+	certificateResult, ok := result.(*unstructured.Unstructured)
+	certificate := certificateResource.(*unstructured.Unstructured)
+
+	return func() error {
+		if !ok {
+			return errors.Errorf("unexpected argument %+v", result)
+		}
+
+		certificateResult.Object["spec"] = certificate.DeepCopy().Object["spec"]
+
+		return nil
+	}
+}
@@ -0,0 +1,111 @@
+package harbor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+func discoveryServer(versions ...string) *httptest.Server {
+	return apiDiscoveryServer("cert-manager.io", "certificates", "Certificate", versions...)
+}
+
+// apiDiscoveryServer serves the resource of the group in the given versions.
+func apiDiscoveryServer(group, resource, kind string, versions ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		for _, version := range versions {
+			if req.URL.Path == fmt.Sprintf("/apis/%s/%s", group, version) {
+				_, _ = fmt.Fprintf(w, `{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"%s/%s","resources":[{"name":"%s","namespaced":true,"kind":"%s","verbs":["get","list","watch"]}]}`, group, version, resource, kind)
+				return
+			}
+		}
+
+		http.NotFound(w, req)
+	}))
+}
+
+var _ = Describe("cert-manager", func() {
+	detect := func(served ...string) string {
+		server := discoveryServer(served...)
+		defer server.Close()
+
+		client, err := discovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+		Expect(err).ToNot(HaveOccurred())
+
+		version, err := DetectCertManagerVersion(client)
+		Expect(err).ToNot(HaveOccurred())
+
+		return version
+	}
+
+	It("Should prefer v1", func() {
+		Expect(detect(CertManagerV1, CertManagerV1Alpha2)).To(Equal(CertManagerV1))
+	})
+
+	It("Should fall back to v1alpha2", func() {
+		Expect(detect(CertManagerV1Alpha2)).To(Equal(CertManagerV1Alpha2))
+	})
+
+	It("Should detect missing cert-manager", func() {
+		Expect(detect()).To(BeEmpty())
+	})
+
+	It("Should render certificates for the version", func() {
+		r := &Reconciler{}
+		Expect(r.CertificateGVK().Version).To(Equal(CertManagerV1Alpha2))
+		Expect(r.NewCertificateObject()).To(BeAssignableToTypeOf(&certv1.Certificate{}))
+
+		r.Config.CertManagerVersion = CertManagerV1
+		Expect(r.CertificateGVK().Version).To(Equal(CertManagerV1))
+		Expect(r.NewCertificateObject()).To(BeAssignableToTypeOf(&unstructured.Unstructured{}))
+	})
+
+	It("Should convert certificates to v1", func() {
+		certificate := &certv1.Certificate{
+			Spec: certv1.CertificateSpec{
+				CommonName:   "harbor",
+				Organization: []string{"Harbor Operator"},
+				SecretName:   "harbor-certificate",
+				DNSNames:     []string{"harbor.example.com"},
+				KeySize:      4096,
+				KeyAlgorithm: certv1.RSAKeyAlgorithm,
+				KeyEncoding:  certv1.PKCS1,
+				IssuerRef:    cmmeta.ObjectReference{Name: "issuer"},
+			},
+		}
+		certificate.SetName("harbor-registry")
+
+		result, err := ConvertCertificate(certificate, CertManagerV1Alpha2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeIdenticalTo(certificate))
+
+		result, err = ConvertCertificate(certificate, CertManagerV1)
+		Expect(err).ToNot(HaveOccurred())
+
+		u, ok := result.(*unstructured.Unstructured)
+		Expect(ok).To(BeTrue())
+		Expect(u.GetAPIVersion()).To(Equal("cert-manager.io/v1"))
+		Expect(u.GetKind()).To(Equal("Certificate"))
+		Expect(u.GetName()).To(Equal("harbor-registry"))
+
+		spec := u.Object["spec"].(map[string]interface{})
+		Expect(spec).To(HaveKeyWithValue("commonName", "harbor"))
+		Expect(spec).To(HaveKeyWithValue("secretName", "harbor-certificate"))
+		Expect(spec).To(HaveKeyWithValue("subject", map[string]interface{}{"organizations": []interface{}{"Harbor Operator"}}))
+		Expect(spec).To(HaveKeyWithValue("privateKey", map[string]interface{}{"algorithm": "RSA", "encoding": "PKCS1", "size": int64(4096)}))
+		Expect(spec).ToNot(HaveKey("keySize"))
+		Expect(spec).ToNot(HaveKey("organization"))
+
+		_, err = ConvertCertificate(certificate, "v2")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,402 @@
+package harbor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/certificates"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+)
+
+const (
+	EventReasonCertificateIssued  = "CertificateIssued"
+	EventReasonCertificateRenewed = "CertificateRenewed"
+)
+
+const (
+	DefaultCertificateDuration   = 365 * 24 * time.Hour
+	CertificateAuthorityDuration = 10 * DefaultCertificateDuration
+)
+
+var (
+	// CertificateKeySize is the size of RSA keys generated in SelfSigned mode
+	CertificateKeySize = certificates.DefaultKeySize
+)
+
+// SelfSignedCertificate is a certificate issued by the operator in SelfSigned mode.
+type SelfSignedCertificate struct {
+	SecretName string
+	Component  string
+	CommonName string
+	DNSNames   []string
+}
+
+// SelfSignedCertificates returns the certificates issued by the CA of the Harbor.
+// Token and notary certificates are issued in SelfSigned mode, internal TLS ones whatever the mode.
+func SelfSignedCertificates(harbor *goharborv1alpha1.Harbor) []SelfSignedCertificate {
+	if harbor.GetCertificatesMode() != goharborv1alpha1.CertificatesModeSelfSigned {
+		return InternalTLSCertificates(harbor)
+	}
+
+	result := []SelfSignedCertificate{{
+		SecretName: harbor.TokenCertificateSecretName(),
+		Component:  goharborv1alpha1.CertificateName,
+		CommonName: harbor.TokenCertificateSecretName(),
+	}}
+
+	if harbor.Spec.Components.Notary != nil {
+		signer := harbor.NormalizeComponentName(goharborv1alpha1.NotarySignerName)
+
+		result = append(result, SelfSignedCertificate{
+			SecretName: harbor.NotaryCertificateSecretName(),
+			Component:  goharborv1alpha1.NotaryName,
+			CommonName: signer,
+			DNSNames:   []string{signer},
+		})
+	}
+
+	return append(result, InternalTLSCertificates(harbor)...)
+}
+
+// InternalTLSComponents returns the enabled components serving HTTPS with internal TLS.
+func InternalTLSComponents(harbor *goharborv1alpha1.Harbor) []string {
+	if !harbor.IsInternalTLSEnabled() {
+		return nil
+	}
+
+	components := []string{goharborv1alpha1.CoreName, goharborv1alpha1.JobServiceName, goharborv1alpha1.RegistryName, goharborv1alpha1.PortalName}
+
+	if harbor.Spec.Components.ChartMuseum != nil {
+		components = append(components, goharborv1alpha1.ChartMuseumName)
+	}
+
+	if harbor.Spec.Components.Clair != nil {
+		components = append(components, goharborv1alpha1.ClairName)
+	}
+
+	return components
+}
+
+// InternalTLSCertificates returns the serving certificates of components, valid for the names of their service.
+func InternalTLSCertificates(harbor *goharborv1alpha1.Harbor) []SelfSignedCertificate {
+	components := InternalTLSComponents(harbor)
+	result := make([]SelfSignedCertificate, 0, len(components))
+
+	for _, component := range components {
+		service := harbor.NormalizeComponentName(component)
+
+		result = append(result, SelfSignedCertificate{
+			SecretName: harbor.InternalTLSSecretName(component),
+			Component:  component,
+			CommonName: service,
+			DNSNames: []string{
+				service,
+				fmt.Sprintf("%s.%s", service, harbor.GetNamespace()),
+				fmt.Sprintf("%s.%s.svc", service, harbor.GetNamespace()),
+			},
+		})
+	}
+
+	return result
+}
+
+// CertificatesConsumers returns the components reading certificates, in restart order.
+func CertificatesConsumers(harbor *goharborv1alpha1.Harbor) []string {
+	consumers := []string{goharborv1alpha1.CoreName, goharborv1alpha1.RegistryName}
+
+	if harbor.Spec.Components.Notary != nil {
+		consumers = append(consumers, goharborv1alpha1.NotarySignerName, goharborv1alpha1.NotaryServerName)
+	}
+
+	for _, component := range InternalTLSComponents(harbor) {
+		if component != goharborv1alpha1.CoreName && component != goharborv1alpha1.RegistryName {
+			consumers = append(consumers, component)
+		}
+	}
+
+	return consumers
+}
+
+// GetCertificateDuration returns the validity of certificates issued in SelfSigned mode.
+func GetCertificateDuration(harbor *goharborv1alpha1.Harbor) time.Duration {
+	if harbor.Spec.Certificates == nil || harbor.Spec.Certificates.Duration == nil {
+		return DefaultCertificateDuration
+	}
+
+	return harbor.Spec.Certificates.Duration.Duration
+}
+
+// NewCertificateSecret returns a TLS secret holding the key pair, with the certificate of the CA, owned by the Harbor.
+func (r *Reconciler) NewCertificateSecret(ctx context.Context, harbor *goharborv1alpha1.Harbor, name, component string, keyPair, ca *certificates.KeyPair) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: harbor.GetNamespace(),
+			Labels: map[string]string{
+				goharborv1alpha1.OperatorNameLabel:    r.GetName(),
+				goharborv1alpha1.OperatorVersionLabel: r.GetVersion(),
+				goharborv1alpha1.ComponentNameLabel:   component,
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       keyPair.CertificatePEM(),
+			corev1.TLSPrivateKeyKey: keyPair.KeyPEM(),
+			cmmeta.TLSCAKey:         ca.CertificatePEM(),
+		},
+	}
+
+	r.MutateAnnotations(ctx, secret)
+
+	err := controllerutil.SetControllerReference(harbor, secret, r.Scheme)
+
+	return secret, errors.Wrap(err, "cannot set controller reference")
+}
+
+// getKeyPair returns the key pair of the TLS secret, nil if the secret does not exist or is invalid.
+func (r *Reconciler) getKeyPair(ctx context.Context, harbor *goharborv1alpha1.Harbor, name string) (*certificates.KeyPair, *corev1.Secret, error) {
+	secret := &corev1.Secret{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: harbor.GetNamespace(), Name: name}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+
+		return nil, nil, errors.Wrapf(err, "cannot get secret %s", name)
+	}
+
+	keyPair, err := certificates.Parse(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		logger.Get(ctx).Info("invalid certificate, issuing a new one", "Secret.Name", name, "error", err.Error())
+
+		return nil, secret, nil
+	}
+
+	return keyPair, secret, nil
+}
+
+// writeCertificateSecret creates the TLS secret, or replaces its content if it exists.
+// The time of the replacement is recorded in the renewal annotation of the secret, in the same update as the certificate.
+func (r *Reconciler) writeCertificateSecret(ctx context.Context, harbor *goharborv1alpha1.Harbor, current *corev1.Secret, name, component string, keyPair, ca *certificates.KeyPair, now time.Time) error {
+	secret, err := r.NewCertificateSecret(ctx, harbor, name, component, keyPair, ca)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		err = r.Client.Create(ctx, secret)
+
+		return errors.Wrapf(err, "cannot create secret %s", name)
+	}
+
+	current.Data = secret.Data
+
+	if current.Annotations == nil {
+		current.Annotations = map[string]string{}
+	}
+
+	current.Annotations[goharborv1alpha1.CertificatesRenewalAnnotation] = now.UTC().Format(time.RFC3339)
+
+	err = r.Client.Update(ctx, current)
+
+	return errors.Wrapf(err, "cannot update secret %s", name)
+}
+
+// GetDefaultCertificatesMode returns the certificates mode of Harbors without mode, SelfSigned when cert-manager is disabled.
+func (r *Reconciler) GetDefaultCertificatesMode() goharborv1alpha1.CertificatesMode {
+	if r.Config.CertManager {
+		return goharborv1alpha1.CertificatesModeCertManager
+	}
+
+	return goharborv1alpha1.CertificatesModeSelfSigned
+}
+
+// DefaultCertificatesMode writes the default certificates mode in the spec of a Harbor without mode.
+// The mode is persisted so that it never changes with the availability of cert-manager, which would reissue certificates.
+func (r *Reconciler) DefaultCertificatesMode(ctx context.Context, harbor *goharborv1alpha1.Harbor) error {
+	if harbor.Spec.Certificates != nil && harbor.Spec.Certificates.Mode != "" {
+		return nil
+	}
+
+	if harbor.Spec.Certificates == nil {
+		harbor.Spec.Certificates = &goharborv1alpha1.CertificatesSpec{}
+	}
+
+	harbor.Spec.Certificates.Mode = r.GetDefaultCertificatesMode()
+
+	logger.Get(ctx).Info("setting default certificates mode", "Mode", harbor.Spec.Certificates.Mode)
+
+	err := r.Client.Update(ctx, harbor)
+
+	return errors.Wrap(err, "cannot set certificates mode")
+}
+
+// EnsureCertificates prepares certificates used between components according to the certificates mode.
+// In SelfSigned mode, or when internal TLS is enabled, a CA is generated and certificates are issued,
+// then renewed when less than a third of their lifetime remains.
+func (r *Reconciler) EnsureCertificates(ctx context.Context, result *ctrl.Result, harbor *goharborv1alpha1.Harbor) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ensureCertificates")
+	defer span.Finish()
+
+	mode := harbor.GetCertificatesMode()
+
+	switch mode {
+	case goharborv1alpha1.CertificatesModeCertManager:
+		if !r.Config.CertManager {
+			return errors.Errorf("cert-manager is disabled, use %s or %s certificates mode", goharborv1alpha1.CertificatesModeSelfSigned, goharborv1alpha1.CertificatesModeProvided)
+		}
+
+		return r.issueSelfSignedCertificates(ctx, result, harbor)
+	case goharborv1alpha1.CertificatesModeProvided:
+	case goharborv1alpha1.CertificatesModeSelfSigned:
+	default:
+		return errors.Errorf("unsupported certificates mode %s", mode)
+	}
+
+	if r.Config.CertManager {
+		// Certificates issued before the mode changed would override the secrets
+		for _, component := range []string{goharborv1alpha1.RegistryName, goharborv1alpha1.NotaryName} {
+			_, err := r.DeleteResourceCollection(ctx, harbor, component, r.CertificateGVK())
+			if err != nil {
+				return errors.Wrapf(err, "cannot delete certificates of %s", component)
+			}
+		}
+	}
+
+	return r.issueSelfSignedCertificates(ctx, result, harbor)
+}
+
+func (r *Reconciler) issueSelfSignedCertificates(ctx context.Context, result *ctrl.Result, harbor *goharborv1alpha1.Harbor) error {
+	selfSignedCertificates := SelfSignedCertificates(harbor)
+	if len(selfSignedCertificates) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	caName := harbor.NormalizeComponentName(goharborv1alpha1.CertificateAuthorityName)
+
+	ca, caSecret, err := r.getKeyPair(ctx, harbor, caName)
+	if err != nil {
+		return err
+	}
+
+	if ca == nil || ca.NeedsRenewal(now) {
+		ca, err = certificates.NewCA(caName, CertificateKeySize, CertificateAuthorityDuration)
+		if err != nil {
+			return errors.Wrap(err, "cannot generate certificate authority")
+		}
+
+		err = r.writeCertificateSecret(ctx, harbor, caSecret, caName, goharborv1alpha1.CertificateName, ca, ca, now)
+		if err != nil {
+			return err
+		}
+
+		r.Recorder.Eventf(harbor, corev1.EventTypeNormal, EventReasonCertificateIssued, "certificate authority %s issued", caName)
+	}
+
+	requeueBefore(result, ca.RenewalTime().Sub(now))
+
+	for _, certificate := range selfSignedCertificates {
+		keyPair, secret, err := r.getKeyPair(ctx, harbor, certificate.SecretName)
+		if err != nil {
+			return err
+		}
+
+		if keyPair == nil || keyPair.NeedsRenewal(now) || !keyPair.IsIssuedBy(ca) {
+			keyPair, err = ca.Issue(certificate.CommonName, certificate.DNSNames, CertificateKeySize, GetCertificateDuration(harbor))
+			if err != nil {
+				return errors.Wrapf(err, "cannot issue certificate %s", certificate.SecretName)
+			}
+
+			err = r.writeCertificateSecret(ctx, harbor, secret, certificate.SecretName, certificate.Component, keyPair, ca, now)
+			if err != nil {
+				return err
+			}
+
+			if secret == nil {
+				r.Recorder.Eventf(harbor, corev1.EventTypeNormal, EventReasonCertificateIssued, "certificate %s issued", certificate.SecretName)
+			} else {
+				logger.Get(ctx).Info("certificate renewed", "Secret.Name", certificate.SecretName)
+				r.Recorder.Eventf(harbor, corev1.EventTypeNormal, EventReasonCertificateRenewed, "certificate %s renewed", certificate.SecretName)
+			}
+		}
+
+		requeueBefore(result, keyPair.RenewalTime().Sub(now))
+	}
+
+	return nil
+}
+
+// GetSecretRenewalTime returns the time recorded in the renewal annotation of the certificate secret,
+// nil if the certificate was never renewed.
+func GetSecretRenewalTime(secret *corev1.Secret) *metav1.Time {
+	value, ok := secret.GetAnnotations()[goharborv1alpha1.CertificatesRenewalAnnotation]
+	if !ok {
+		return nil
+	}
+
+	renewalTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+
+	result := metav1.NewTime(renewalTime)
+
+	return &result
+}
+
+// GetCertificatesRenewalTime returns the last renewal time recorded on the certificate secrets issued by the operator.
+func (r *Reconciler) GetCertificatesRenewalTime(ctx context.Context, harbor *goharborv1alpha1.Harbor) (*metav1.Time, error) {
+	var result *metav1.Time
+
+	for _, certificate := range SelfSignedCertificates(harbor) {
+		secret := &corev1.Secret{}
+
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: harbor.GetNamespace(), Name: certificate.SecretName}, secret)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, errors.Wrapf(err, "cannot get secret %s", certificate.SecretName)
+		}
+
+		renewalTime := GetSecretRenewalTime(secret)
+		if renewalTime != nil && (result == nil || result.Before(renewalTime)) {
+			result = renewalTime
+		}
+	}
+
+	return result, nil
+}
+
+// RestartCertificatesConsumers restarts consumers of certificates one after the other after a renewal.
+// The renewal time is read from the certificate secrets rather than the status, which may fail to be updated.
+func (r *Reconciler) RestartCertificatesConsumers(ctx context.Context, result *ctrl.Result, harbor *goharborv1alpha1.Harbor) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "restartCertificatesConsumers")
+	defer span.Finish()
+
+	renewalTime, err := r.GetCertificatesRenewalTime(ctx, harbor)
+	if err != nil {
+		return err
+	}
+
+	if renewalTime != nil {
+		harbor.Status.CertificatesRenewalTime = renewalTime
+	}
+
+	return r.RestartDeployments(ctx, result, harbor, goharborv1alpha1.CertificatesRenewalAnnotation, renewalTime, CertificatesConsumers(harbor), "renewed certificates")
+}
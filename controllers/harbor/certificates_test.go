@@ -0,0 +1,117 @@
+package harbor
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/certificates"
+)
+
+var _ = Describe("Certificates", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var h *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		r, ctx = setupTest(context.TODO())
+		r.Name = "harbor-operator"
+		r.Version = "test"
+
+		h = &goharborv1alpha1.Harbor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "harbor",
+				Namespace: "ns",
+				UID:       "uid",
+			},
+		}
+	})
+
+	It("Should default to cert-manager", func() {
+		Expect(h.GetCertificatesMode()).To(Equal(goharborv1alpha1.CertificatesModeCertManager))
+		Expect(h.TokenCertificateSecretName()).To(Equal("harbor-certificate"))
+		Expect(h.NotaryCertificateSecretName()).To(Equal("harbor-notary-certificate"))
+		Expect(GetCertificateDuration(h)).To(Equal(DefaultCertificateDuration))
+	})
+
+	It("Should default to SelfSigned mode without cert-manager", func() {
+		r.Config.CertManager = true
+		Expect(r.GetDefaultCertificatesMode()).To(Equal(goharborv1alpha1.CertificatesModeCertManager))
+
+		r.Config.CertManager = false
+		Expect(r.GetDefaultCertificatesMode()).To(Equal(goharborv1alpha1.CertificatesModeSelfSigned))
+	})
+
+	It("Should use provided secrets", func() {
+		h.Spec.Certificates = &goharborv1alpha1.CertificatesSpec{
+			Mode:                   goharborv1alpha1.CertificatesModeProvided,
+			TokenSecretName:        "token",
+			NotarySignerSecretName: "signer",
+		}
+		h.Spec.Components.Notary = &goharborv1alpha1.NotaryComponent{}
+
+		Expect(h.TokenCertificateSecretName()).To(Equal("token"))
+		Expect(h.NotaryCertificateSecretName()).To(Equal("signer"))
+		Expect(ReferencedSecrets(h)).To(ContainElement("token"))
+		Expect(ReferencedSecrets(h)).To(ContainElement("signer"))
+	})
+
+	It("Should issue the notary certificate only with notary", func() {
+		h.Spec.Certificates = &goharborv1alpha1.CertificatesSpec{
+			Mode:     goharborv1alpha1.CertificatesModeSelfSigned,
+			Duration: &metav1.Duration{Duration: time.Hour},
+		}
+
+		Expect(SelfSignedCertificates(h)).To(HaveLen(1))
+		Expect(CertificatesConsumers(h)).To(Equal([]string{goharborv1alpha1.CoreName, goharborv1alpha1.RegistryName}))
+		Expect(GetCertificateDuration(h)).To(Equal(time.Hour))
+
+		h.Spec.Components.Notary = &goharborv1alpha1.NotaryComponent{}
+
+		certificates := SelfSignedCertificates(h)
+		Expect(certificates).To(HaveLen(2))
+		Expect(certificates[1].SecretName).To(Equal("harbor-notary-certificate"))
+		Expect(certificates[1].DNSNames).To(ConsistOf("harbor-notary-signer"))
+		Expect(CertificatesConsumers(h)).To(ContainElement(goharborv1alpha1.NotarySignerName))
+	})
+
+	It("Should store certificates in TLS secrets", func() {
+		ca, err := certificates.NewCA("harbor-ca", 1024, time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+
+		leaf, err := ca.Issue("harbor-certificate", nil, 1024, time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+
+		secret, err := r.NewCertificateSecret(ctx, h, "harbor-certificate", goharborv1alpha1.CertificateName, leaf, ca)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+		Expect(secret.Data).To(HaveKeyWithValue(corev1.TLSCertKey, leaf.CertificatePEM()))
+		Expect(secret.Data).To(HaveKeyWithValue(corev1.TLSPrivateKeyKey, leaf.KeyPEM()))
+		Expect(secret.Data).To(HaveKeyWithValue("ca.crt", ca.CertificatePEM()))
+		Expect(metav1.GetControllerOf(secret)).ToNot(BeNil())
+		Expect(GetSecretRenewalTime(secret)).To(BeNil())
+	})
+
+	It("Should read the renewal time of certificate secrets", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					goharborv1alpha1.CertificatesRenewalAnnotation: "2020-03-01T10:00:00Z",
+				},
+			},
+		}
+
+		renewalTime := GetSecretRenewalTime(secret)
+		Expect(renewalTime).ToNot(BeNil())
+		Expect(renewalTime.UTC()).To(Equal(time.Date(2020, time.March, 1, 10, 0, 0, 0, time.UTC)))
+
+		secret.Annotations[goharborv1alpha1.CertificatesRenewalAnnotation] = "invalid"
+		Expect(GetSecretRenewalTime(secret)).To(BeNil())
+	})
+})
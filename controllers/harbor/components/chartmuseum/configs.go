@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -61,7 +62,7 @@ func (c *ChartMuseum) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap {
 				configName: config,
 			},
 			Data: map[string]string{
-				"PORT":      fmt.Sprintf("%d", port),
+				"PORT":      fmt.Sprintf("%d", internaltls.Port(c.harbor, port, tlsPort)),
 				"CHART_URL": fmt.Sprintf("%s/chartrepo", c.harbor.Spec.PublicURL),
 			},
 		},
@@ -69,7 +70,7 @@ func (c *ChartMuseum) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap {
 }
 
 func (c *ChartMuseum) GetConfigMapsCheckSum() string {
-	value := fmt.Sprintf("%s\n%d\n%x", c.harbor.Spec.PublicURL, port, config)
+	value := fmt.Sprintf("%s\n%d\n%x", c.harbor.Spec.PublicURL, internaltls.Port(c.harbor, port, tlsPort), config)
 	sum := sha256.New().Sum([]byte(value))
 
 	// todo get generation of the secret
@@ -10,6 +10,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/proxy"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/redis"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/trusted-ca"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -23,11 +27,13 @@ const (
 	initImage  = "hairyhenderson/gomplate"
 	configPath = "/etc/chartmuseum/"
 	port       = 8080 // https://github.com/helm/chartmuseum/blob/969515a51413e1f1840fb99509401aa3c63deccd/pkg/config/vars.go#L135
+	tlsPort    = 9443 // ChartMuseum listens on this port when internal TLS is enabled
 )
 
 func (c *ChartMuseum) GetDeployments(ctx context.Context) []*appsv1.Deployment { // nolint:funlen
 	operatorName := application.GetName(ctx)
 	harborName := c.harbor.GetName()
+	containerPort := internaltls.Port(c.harbor, port, tlsPort)
 
 	volumes := []corev1.Volume{{
 		Name: "chartmuseum",
@@ -87,7 +93,9 @@ func (c *ChartMuseum) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 		}}
 	}
 
-	if c.harbor.Spec.Components.ChartMuseum.CacheSecret != "" {
+	if cache := c.harbor.Spec.Components.ChartMuseum.Cache; cache != nil {
+		initEnv = redis.ConfigEnvVars("CACHE_REDIS_", cache)
+	} else if c.harbor.Spec.Components.ChartMuseum.CacheSecret != "" {
 		initEnv = []corev1.EnvVar{
 			{
 				Name: "CACHE_URL",
@@ -156,7 +164,7 @@ func (c *ChartMuseum) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 									},
 								},
 							},
-						}, volumes...),
+						}, append(append(volumes, internaltls.Volumes(c.harbor, goharborv1alpha1.ChartMuseumName)...), trustedca.Volumes(c.harbor)...)...),
 						InitContainers: []corev1.Container{
 							{
 								Name:            "configuration",
@@ -185,17 +193,17 @@ func (c *ChartMuseum) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 								Image: c.harbor.Spec.Components.ChartMuseum.GetImage(),
 								Ports: []corev1.ContainerPort{
 									{
-										ContainerPort: port,
+										ContainerPort: int32(containerPort),
 									},
 								},
 								Command: []string{"/home/chart/chartm"},
 								Args:    []string{"-c", path.Join(configPath, configName)},
 
-								VolumeMounts: append(volumeMounts, corev1.VolumeMount{
+								VolumeMounts: append(append(volumeMounts, corev1.VolumeMount{
 									MountPath: path.Join(configPath, configName),
 									Name:      "config",
 									SubPath:   configName,
-								}),
+								}), append(internaltls.VolumeMounts(c.harbor), trustedca.VolumeMounts(c.harbor)...)...),
 
 								Env: append([]corev1.EnvVar{
 									{
@@ -210,7 +218,7 @@ func (c *ChartMuseum) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 											},
 										},
 									},
-								}, envs...),
+								}, append(append(envs, tlsEnvs(c.harbor)...), proxy.EnvVars(c.harbor)...)...),
 
 								EnvFrom: append(envFroms, corev1.EnvFromSource{
 									ConfigMapRef: &corev1.ConfigMapEnvSource{
@@ -225,16 +233,18 @@ func (c *ChartMuseum) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 								LivenessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/health",
-											Port: intstr.FromInt(port),
+											Path:   "/health",
+											Port:   intstr.FromInt(containerPort),
+											Scheme: internaltls.ProbeScheme(c.harbor),
 										},
 									},
 								},
 								ReadinessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/health",
-											Port: intstr.FromInt(port),
+											Path:   "/health",
+											Port:   intstr.FromInt(containerPort),
+											Scheme: internaltls.ProbeScheme(c.harbor),
 										},
 									},
 								},
@@ -249,3 +259,20 @@ func (c *ChartMuseum) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 		},
 	}
 }
+
+// tlsEnvs configures ChartMuseum to serve its TLS secret, it does not read Harbor's INTERNAL_TLS_* variables.
+func tlsEnvs(harbor *goharborv1alpha1.Harbor) []corev1.EnvVar {
+	if !harbor.IsInternalTLSEnabled() {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name:  "TLS_CERT",
+			Value: internaltls.CertificatePath,
+		}, {
+			Name:  "TLS_KEY",
+			Value: internaltls.KeyPath,
+		},
+	}
+}
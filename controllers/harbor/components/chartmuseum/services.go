@@ -8,6 +8,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/mesh"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -33,8 +35,9 @@ func (c *ChartMuseum) GetServices(ctx context.Context) []*corev1.Service {
 			Spec: corev1.ServiceSpec{
 				Ports: []corev1.ServicePort{
 					{
-						Port:       PublicPort,
-						TargetPort: intstr.FromInt(port),
+						Name:       mesh.PortName(c.harbor, mesh.HTTPProtocol(c.harbor), ""),
+						Port:       int32(internaltls.PublicPort(c.harbor, PublicPort)),
+						TargetPort: intstr.FromInt(internaltls.Port(c.harbor, port, tlsPort)),
 					},
 				},
 				Selector: map[string]string{
@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -44,6 +45,21 @@ func (c *Clair) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap {
 	operatorName := application.GetName(ctx)
 	harborName := c.harbor.Name
 
+	data := map[string]string{
+		"SCANNER_CLAIR_URL":                   fmt.Sprintf("http://%s", c.harbor.NormalizeComponentName(goharborv1alpha1.ClairName)),
+		"SCANNER_LOG_LEVEL":                   "debug",
+		"SCANNER_STORE_REDIS_POOL_MAX_ACTIVE": "5",
+		"SCANNER_STORE_REDIS_POOL_MAX_IDLE":   "5",
+		"SCANNER_STORE_REDIS_SCAN_JOB_TTL":    "1h",
+		"SCANNER_API_SERVER_ADDR":             fmt.Sprintf(":%d", internaltls.Port(c.harbor, adapterPort, adapterTLSPort)),
+	}
+
+	// Clair itself is only called by the adapter, in the same pod
+	if c.harbor.IsInternalTLSEnabled() {
+		data["SCANNER_API_SERVER_TLS_CERTIFICATE"] = internaltls.CertificatePath
+		data["SCANNER_API_SERVER_TLS_KEY"] = internaltls.KeyPath
+	}
+
 	return []*corev1.ConfigMap{
 		{
 			ObjectMeta: metav1.ObjectMeta{
@@ -60,20 +76,13 @@ func (c *Clair) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap {
 			},
 			// https://github.com/goharbor/harbor-scanner-clair#configuration
 			// https://github.com/goharbor/harbor/blob/master/make/photon/prepare/templates/clair/clair_env.jinja
-			Data: map[string]string{
-				"SCANNER_CLAIR_URL":                   fmt.Sprintf("http://%s", c.harbor.NormalizeComponentName(goharborv1alpha1.ClairName)),
-				"SCANNER_LOG_LEVEL":                   "debug",
-				"SCANNER_STORE_REDIS_POOL_MAX_ACTIVE": "5",
-				"SCANNER_STORE_REDIS_POOL_MAX_IDLE":   "5",
-				"SCANNER_STORE_REDIS_SCAN_JOB_TTL":    "1h",
-				"SCANNER_API_SERVER_ADDR":             fmt.Sprintf(":%d", adapterPort),
-			},
+			Data: data,
 		},
 	}
 }
 
 func (c *Clair) GetConfigMapsCheckSum() string {
-	value := fmt.Sprintf("%d\n%+v\n%x", adapterPort, c.harbor.Spec.Components.Clair.VulnerabilitySources, config)
+	value := fmt.Sprintf("%d\n%+v\n%+v\n%x", adapterPort, c.harbor.IsInternalTLSEnabled(), c.harbor.Spec.Components.Clair.VulnerabilitySources, config)
 	sum := sha256.New().Sum([]byte(value))
 
 	return fmt.Sprintf("%x", sum)
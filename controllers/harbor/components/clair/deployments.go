@@ -12,6 +12,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/proxy"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/redis"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/trusted-ca"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 	"github.com/goharbor/harbor-operator/pkg/factories/logger"
 )
@@ -21,8 +25,12 @@ const (
 	apiPort         = 6060 // https://github.com/quay/clair/blob/c39101e9b8206401d8b9cb631f3aee47a24ab889/cmd/clair/config.go#L64
 	healthPort      = 6061 // https://github.com/quay/clair/blob/c39101e9b8206401d8b9cb631f3aee47a24ab889/cmd/clair/config.go#L63
 	adapterPort     = 8080
+	adapterTLSPort  = 8443
 	clairConfigPath = "/etc/clair"
 
+	// https://github.com/goharbor/harbor-scanner-clair#configuration
+	adapterRedisNamespace = "harbor.scanner.clair:store"
+
 	livenessProbeInitialDelay = 300 * time.Second
 )
 
@@ -31,6 +39,42 @@ var (
 	varFalse                   = false
 )
 
+// adapterRedisEnvVars returns the environment variables of the Redis server storing scan reports.
+func (c *Clair) adapterRedisEnvVars() []corev1.EnvVar {
+	if spec := c.harbor.Spec.Components.Clair.Adapter.Redis; spec != nil {
+		return append(redis.URLEnvVars("SCANNER_STORE_REDIS_URL", spec), corev1.EnvVar{
+			Name:  "SCANNER_STORE_REDIS_NAMESPACE",
+			Value: adapterRedisNamespace,
+		})
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name: "SCANNER_STORE_REDIS_URL",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					Key:      goharborv1alpha1.HarborClairAdapterBrokerURLKey,
+					Optional: &varFalse,
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: c.harbor.Spec.Components.Clair.Adapter.RedisSecret,
+					},
+				},
+			},
+		}, {
+			Name: "SCANNER_STORE_REDIS_NAMESPACE",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					Key:      goharborv1alpha1.HarborClairAdapterBrokerNamespaceKey,
+					Optional: &varFalse,
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: c.harbor.Spec.Components.Clair.Adapter.RedisSecret,
+					},
+				},
+			},
+		},
+	}
+}
+
 func (c *Clair) GetDeployments(ctx context.Context) []*appsv1.Deployment { // nolint:funlen
 	operatorName := application.GetName(ctx)
 	harborName := c.harbor.GetName()
@@ -40,6 +84,8 @@ func (c *Clair) GetDeployments(ctx context.Context) []*appsv1.Deployment { // no
 		logger.Get(ctx).Error(err, "invalid vulnerability sources")
 	}
 
+	adapterContainerPort := internaltls.Port(c.harbor, adapterPort, adapterTLSPort)
+
 	return []*appsv1.Deployment{
 		{
 			ObjectMeta: metav1.ObjectMeta{
@@ -76,7 +122,7 @@ func (c *Clair) GetDeployments(ctx context.Context) []*appsv1.Deployment { // no
 					Spec: corev1.PodSpec{
 						NodeSelector:                 c.harbor.Spec.Components.Clair.NodeSelector,
 						AutomountServiceAccountToken: &varFalse,
-						Volumes: []corev1.Volume{
+						Volumes: append([]corev1.Volume{
 							{
 								Name: "config-template",
 								VolumeSource: corev1.VolumeSource{
@@ -96,7 +142,7 @@ func (c *Clair) GetDeployments(ctx context.Context) []*appsv1.Deployment { // no
 								Name:         "config",
 								VolumeSource: corev1.VolumeSource{},
 							},
-						},
+						}, append(internaltls.Volumes(c.harbor, goharborv1alpha1.ClairName), trustedca.Volumes(c.harbor)...)...),
 						InitContainers: []corev1.Container{
 							{
 								Name:       "configuration",
@@ -144,17 +190,9 @@ func (c *Clair) GetDeployments(ctx context.Context) []*appsv1.Deployment { // no
 									},
 								},
 
-								Env: []corev1.EnvVar{
-									{ // // https://github.com/goharbor/harbor/blob/master/make/photon/prepare/templates/clair/clair_env.jinja
-										Name:  "HTTP_PROXY",
-										Value: "",
-									}, {
-										Name:  "HTTPS_PROXY",
-										Value: "",
-									}, {
-										Name:  "NO_PROXY",
-										Value: "",
-									}, { // https://github.com/goharbor/harbor/blob/master/make/photon/prepare/templates/clair/postgres_env.jinja
+								// https://github.com/goharbor/harbor/blob/master/make/photon/prepare/templates/clair/clair_env.jinja
+								Env: append([]corev1.EnvVar{
+									{ // https://github.com/goharbor/harbor/blob/master/make/photon/prepare/templates/clair/postgres_env.jinja
 										Name: "POSTGRES_PASSWORD",
 										ValueFrom: &corev1.EnvVarSource{
 											SecretKeyRef: &corev1.SecretKeySelector{
@@ -166,7 +204,7 @@ func (c *Clair) GetDeployments(ctx context.Context) []*appsv1.Deployment { // no
 											},
 										},
 									},
-								},
+								}, proxy.EnvVars(c.harbor)...),
 								Command:         []string{"/home/clair/clair"},
 								Args:            []string{"-config", path.Join(clairConfigPath, configKey)},
 								ImagePullPolicy: corev1.PullAlways,
@@ -186,47 +224,23 @@ func (c *Clair) GetDeployments(ctx context.Context) []*appsv1.Deployment { // no
 										},
 									},
 								},
-								VolumeMounts: []corev1.VolumeMount{
+								VolumeMounts: append([]corev1.VolumeMount{
 									{
 										MountPath: path.Join(clairConfigPath, configKey),
 										Name:      "config",
 										SubPath:   configKey,
 									},
-								},
+								}, trustedca.VolumeMounts(c.harbor)...),
 							}, {
 								Name:  "clair-adapter",
 								Image: c.harbor.Spec.Components.Clair.Adapter.GetImage(),
 								Ports: []corev1.ContainerPort{
 									{
-										ContainerPort: adapterPort,
+										ContainerPort: int32(adapterContainerPort),
 									},
 								},
 
-								Env: []corev1.EnvVar{
-									{
-										Name: "SCANNER_STORE_REDIS_URL",
-										ValueFrom: &corev1.EnvVarSource{
-											SecretKeyRef: &corev1.SecretKeySelector{
-												Key:      goharborv1alpha1.HarborClairAdapterBrokerURLKey,
-												Optional: &varFalse,
-												LocalObjectReference: corev1.LocalObjectReference{
-													Name: c.harbor.Spec.Components.Clair.Adapter.RedisSecret,
-												},
-											},
-										},
-									}, {
-										Name: "SCANNER_STORE_REDIS_NAMESPACE",
-										ValueFrom: &corev1.EnvVarSource{
-											SecretKeyRef: &corev1.SecretKeySelector{
-												Key:      goharborv1alpha1.HarborClairAdapterBrokerNamespaceKey,
-												Optional: &varFalse,
-												LocalObjectReference: corev1.LocalObjectReference{
-													Name: c.harbor.Spec.Components.Clair.Adapter.RedisSecret,
-												},
-											},
-										},
-									},
-								},
+								Env: append(c.adapterRedisEnvVars(), internaltls.EnvVars(c.harbor)...),
 								EnvFrom: []corev1.EnvFromSource{
 									{
 										Prefix: "clair_db_",
@@ -250,8 +264,9 @@ func (c *Clair) GetDeployments(ctx context.Context) []*appsv1.Deployment { // no
 								LivenessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/probe/healthy",
-											Port: intstr.FromInt(adapterPort),
+											Path:   "/probe/healthy",
+											Port:   intstr.FromInt(adapterContainerPort),
+											Scheme: internaltls.ProbeScheme(c.harbor),
 										},
 									},
 									InitialDelaySeconds: int32(livenessProbeInitialDelay.Seconds()),
@@ -259,18 +274,19 @@ func (c *Clair) GetDeployments(ctx context.Context) []*appsv1.Deployment { // no
 								ReadinessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/probe/healthy",
-											Port: intstr.FromInt(adapterPort),
+											Path:   "/probe/healthy",
+											Port:   intstr.FromInt(adapterContainerPort),
+											Scheme: internaltls.ProbeScheme(c.harbor),
 										},
 									},
 								},
-								VolumeMounts: []corev1.VolumeMount{
+								VolumeMounts: append([]corev1.VolumeMount{
 									{
 										MountPath: path.Join(clairConfigPath, configKey),
 										Name:      "config",
 										SubPath:   configKey,
 									},
-								},
+								}, internaltls.VolumeMounts(c.harbor)...),
 							},
 						},
 						Priority: c.Option.GetPriority(),
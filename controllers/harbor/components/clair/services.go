@@ -8,14 +8,23 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/mesh"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
 const (
-	PublicPort        = 80
-	AdapterPublicPort = 8080
+	PublicPort = 80
+
+	adapterPublicPort    = 8080
+	adapterTLSPublicPort = 8443
 )
 
+// AdapterPublicPort returns the port of the service exposing the adapter.
+func AdapterPublicPort(harbor *goharborv1alpha1.Harbor) int {
+	return internaltls.Port(harbor, adapterPublicPort, adapterTLSPublicPort)
+}
+
 func (c *Clair) GetServices(ctx context.Context) []*corev1.Service {
 	operatorName := application.GetName(ctx)
 	harborName := c.harbor.Name
@@ -35,16 +44,16 @@ func (c *Clair) GetServices(ctx context.Context) []*corev1.Service {
 			Spec: corev1.ServiceSpec{
 				Ports: []corev1.ServicePort{
 					{
-						Name:       "api",
+						Name:       mesh.PortName(c.harbor, mesh.ProtocolHTTP, "api"),
 						Port:       PublicPort,
 						TargetPort: intstr.FromInt(apiPort),
 					}, {
-						Name: "healthcheck",
+						Name: mesh.PortName(c.harbor, mesh.ProtocolHTTP, "healthcheck"),
 						Port: healthPort,
 					}, {
-						Name:       "adapter",
-						Port:       AdapterPublicPort,
-						TargetPort: intstr.FromInt(adapterPort),
+						Name:       mesh.PortName(c.harbor, mesh.HTTPProtocol(c.harbor), "adapter"),
+						Port:       int32(AdapterPublicPort(c.harbor)),
+						TargetPort: intstr.FromInt(internaltls.Port(c.harbor, adapterPort, adapterTLSPort)),
 					},
 				},
 				Selector: map[string]string{
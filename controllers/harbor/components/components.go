@@ -19,6 +19,7 @@ import (
 	harbor_clair "github.com/goharbor/harbor-operator/controllers/harbor/components/clair"
 	harbor_core "github.com/goharbor/harbor-operator/controllers/harbor/components/harbor-core"
 	harbor_jobservice "github.com/goharbor/harbor-operator/controllers/harbor/components/jobservice"
+	harbor_nginx "github.com/goharbor/harbor-operator/controllers/harbor/components/nginx"
 	harbor_notary "github.com/goharbor/harbor-operator/controllers/harbor/components/notary"
 	harbor_portal "github.com/goharbor/harbor-operator/controllers/harbor/components/portal"
 	harbor_registry "github.com/goharbor/harbor-operator/controllers/harbor/components/registry"
@@ -39,6 +40,7 @@ type Components struct {
 	ChartMuseum *ComponentRunner
 	Clair       *ComponentRunner
 	Notary      *ComponentRunner
+	Nginx       *ComponentRunner
 }
 
 type Component interface {
@@ -111,6 +113,14 @@ func GetComponents(ctx context.Context, harbor *goharborv1alpha1.Harbor) (*Compo
 		}))
 	}
 
+	if harbor.IsExposeProxyEnabled() {
+		harborResource.Nginx = &ComponentRunner{}
+
+		g.Go(harborResource.Nginx.getInitFunc(ctx, harbor, NginxPriority, goharborv1alpha1.NginxName, func(ctx context.Context, harbor *goharborv1alpha1.Harbor, option *Option) (Component, error) {
+			return harbor_nginx.New(ctx, harbor, option)
+		}))
+	}
+
 	err := g.Wait()
 
 	return harborResource, errors.Wrap(err, "cannot get resources")
@@ -137,7 +147,7 @@ func (c *ComponentRunner) getInitFunc(ctx context.Context, harbor *goharborv1alp
 
 		options := c.getOption(harbor, componentPriority)
 
-		ctx := withComponent(ctx, name)
+		ctx := WithComponent(ctx, name)
 
 		span, ctx := opentracing.StartSpanFromContext(ctx, "init", opentracing.Tags{
 			"component": name,
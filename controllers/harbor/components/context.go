@@ -13,7 +13,7 @@ func ComponentName(ctx context.Context) string {
 	return ctx.Value(&componentContext).(string)
 }
 
-func withComponent(ctx context.Context, name string) context.Context {
+func WithComponent(ctx context.Context, name string) context.Context {
 	return context.WithValue(ctx, &componentContext, name)
 }
 
@@ -0,0 +1,159 @@
+package expose
+
+import (
+	"strconv"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+)
+
+const (
+	// IngressClassAnnotation selects the ingress controller, the vendored Ingress API has no ingressClassName field
+	IngressClassAnnotation = "kubernetes.io/ingress.class"
+
+	// Long enough to push or pull large layers
+	requestTimeoutSeconds = "900"
+
+	// https://doc.traefik.io/traefik/routing/providers/kubernetes-ingress/#on-service
+	traefikServersSchemeAnnotation = "traefik.ingress.kubernetes.io/service.serversscheme"
+	// https://projectcontour.io/docs/main/config/upstream-tls/
+	contourUpstreamTLSAnnotation = "projectcontour.io/upstream-protocol.tls"
+)
+
+// IngressAnnotations returns the annotations of the ingress of the component:
+// the preset of the ingress controller, then the ingress class, then the annotations of spec.expose.ingress.
+// secure is whether the ingress terminates TLS.
+func IngressAnnotations(harbor *goharborv1alpha1.Harbor, componentName string, secure bool) map[string]string {
+	annotations := map[string]string{}
+
+	for key, value := range presetAnnotations(harbor, componentName, secure) {
+		annotations[key] = value
+	}
+
+	if harbor.Spec.Expose != nil && harbor.Spec.Expose.Ingress != nil {
+		spec := harbor.Spec.Expose.Ingress
+
+		if spec.IngressClassName != "" {
+			annotations[IngressClassAnnotation] = spec.IngressClassName
+		}
+
+		for key, value := range spec.Annotations {
+			annotations[key] = value
+		}
+	}
+
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	return annotations
+}
+
+// ServiceAnnotations returns the annotations of the service of the component,
+// so that traefik and contour forward requests over TLS to components serving TLS.
+// nginx reads the backend protocol from the annotations of the ingress.
+func ServiceAnnotations(harbor *goharborv1alpha1.Harbor, componentName string) map[string]string {
+	if !harbor.IsIngressEnabled() || !isUpstreamTLS(harbor, componentName) {
+		return nil
+	}
+
+	switch harbor.GetIngressController() {
+	case goharborv1alpha1.IngressControllerTraefik:
+		return map[string]string{
+			traefikServersSchemeAnnotation: "https",
+		}
+	case goharborv1alpha1.IngressControllerContour:
+		return map[string]string{
+			contourUpstreamTLSAnnotation: strconv.Itoa(internaltls.HTTPSPort),
+		}
+	default:
+		return nil
+	}
+}
+
+// isUpstreamTLS returns whether the service of the component, routed by ingresses, serves TLS.
+func isUpstreamTLS(harbor *goharborv1alpha1.Harbor, componentName string) bool {
+	switch componentName {
+	case goharborv1alpha1.CoreName, goharborv1alpha1.RegistryName, goharborv1alpha1.PortalName:
+		return harbor.IsInternalTLSEnabled()
+	case goharborv1alpha1.NotaryServerName:
+		return NotaryServesTLS(harbor)
+	default:
+		return false
+	}
+}
+
+func presetAnnotations(harbor *goharborv1alpha1.Harbor, componentName string, secure bool) map[string]string {
+	switch harbor.GetIngressController() {
+	case goharborv1alpha1.IngressControllerNginx:
+		return nginxAnnotations(harbor, componentName)
+	case goharborv1alpha1.IngressControllerTraefik:
+		return traefikAnnotations(secure)
+	case goharborv1alpha1.IngressControllerContour:
+		return contourAnnotations(secure)
+	default:
+		if componentName == goharborv1alpha1.NotaryName {
+			return nil
+		}
+
+		// Most clusters run ingress-nginx
+		return internaltls.IngressAnnotations(harbor)
+	}
+}
+
+// https://kubernetes.github.io/ingress-nginx/user-guide/nginx-configuration/annotations/
+func nginxAnnotations(harbor *goharborv1alpha1.Harbor, componentName string) map[string]string {
+	annotations := map[string]string{
+		"nginx.ingress.kubernetes.io/proxy-body-size":    "0",
+		"nginx.ingress.kubernetes.io/proxy-read-timeout": requestTimeoutSeconds,
+		"nginx.ingress.kubernetes.io/proxy-send-timeout": requestTimeoutSeconds,
+	}
+
+	switch componentName {
+	case goharborv1alpha1.CoreName, goharborv1alpha1.RegistryName:
+		// Stream layers instead of buffering them on the controller
+		annotations["nginx.ingress.kubernetes.io/proxy-request-buffering"] = "off"
+		annotations["nginx.ingress.kubernetes.io/proxy-buffering"] = "off"
+	case goharborv1alpha1.NotaryName:
+		if NotaryServesTLS(harbor) {
+			// Notary clients authenticate notary server with the public certificate, served by notary server itself
+			// https://kubernetes.github.io/ingress-nginx/user-guide/tls/#ssl-passthrough
+			annotations["nginx.ingress.kubernetes.io/ssl-passthrough"] = "true"
+			annotations["nginx.ingress.kubernetes.io/backend-protocol"] = "HTTPS"
+		}
+
+		return annotations
+	}
+
+	for key, value := range internaltls.IngressAnnotations(harbor) {
+		annotations[key] = value
+	}
+
+	return annotations
+}
+
+// https://doc.traefik.io/traefik/routing/providers/kubernetes-ingress/#annotations
+// Traefik cannot pass TLS through with ingresses, the scheme of backends serving TLS is set on their service.
+func traefikAnnotations(secure bool) map[string]string {
+	if !secure {
+		return nil
+	}
+
+	return map[string]string{
+		"traefik.ingress.kubernetes.io/router.tls": "true",
+	}
+}
+
+// https://projectcontour.io/docs/main/config/annotations/
+// Contour cannot pass TLS through with ingresses, the protocol of backends serving TLS is set on their service.
+func contourAnnotations(secure bool) map[string]string {
+	annotations := map[string]string{
+		"projectcontour.io/response-timeout": requestTimeoutSeconds + "s",
+	}
+
+	if secure {
+		annotations["ingress.kubernetes.io/force-ssl-redirect"] = "true"
+	}
+
+	return annotations
+}
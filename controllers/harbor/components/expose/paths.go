@@ -0,0 +1,303 @@
+package expose
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+	netv1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+)
+
+const (
+	// ServicePort is the port of the services of exposed components, without internal TLS
+	ServicePort = 80
+)
+
+// Host is a public host of Harbor, reached with one or several names.
+type Host struct {
+	Names []string
+	// TLS is whether clients reach the host over https
+	TLS   bool
+	Paths []Path
+}
+
+// Path routes the requests whose path starts with Prefix to the service of a component.
+type Path struct {
+	// Component is the component exposing the path
+	Component   string
+	Prefix      string
+	ServiceName string
+	ServicePort int
+}
+
+// Hosts returns the public hosts of Harbor and the paths routed to enabled components,
+// whatever the type of exposure.
+func Hosts(harbor *goharborv1alpha1.Harbor) []Host {
+	host := newHost(harbor.Spec.PublicURL)
+	host.Names = append(host.Names, harbor.Spec.AdditionalHostnames...)
+	host.Paths = harborPaths(harbor)
+
+	hosts := []Host{host}
+
+	if harbor.Spec.Components.Notary != nil {
+		notary := newHost(harbor.Spec.Components.Notary.PublicURL)
+
+		notary.Paths = []Path{{
+			Component:   goharborv1alpha1.NotaryName,
+			Prefix:      "/",
+			ServiceName: harbor.NormalizeComponentName(goharborv1alpha1.NotaryServerName),
+			ServicePort: NotaryServerPort(harbor),
+		}}
+
+		hosts = append(hosts, notary)
+	}
+
+	return hosts
+}
+
+// NotaryServesTLS returns whether notary server serves TLS with the public certificate,
+// for ingress controllers passing TLS through to notary or forwarding requests over TLS.
+func NotaryServesTLS(harbor *goharborv1alpha1.Harbor) bool {
+	if harbor.Spec.Components.Notary == nil || !harbor.IsIngressEnabled() || !newHost(harbor.Spec.Components.Notary.PublicURL).TLS {
+		return false
+	}
+
+	if harbor.PublicTLSSecretName() == "" {
+		return false
+	}
+
+	switch harbor.GetIngressController() {
+	case goharborv1alpha1.IngressControllerNginx, goharborv1alpha1.IngressControllerTraefik, goharborv1alpha1.IngressControllerContour:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotaryServerPort returns the port of the service of notary server, HTTPSPort when it serves TLS.
+func NotaryServerPort(harbor *goharborv1alpha1.Harbor) int {
+	if NotaryServesTLS(harbor) {
+		return internaltls.HTTPSPort
+	}
+
+	return ServicePort
+}
+
+// InternalHost returns the host of the internal endpoint, with the paths of the public url, nil if there is no internal endpoint.
+func InternalHost(harbor *goharborv1alpha1.Harbor) *Host {
+	if harbor.Spec.InternalEndpoint == nil {
+		return nil
+	}
+
+	host := newHost(harbor.Spec.InternalEndpoint.URL)
+	host.Paths = harborPaths(harbor)
+
+	return &host
+}
+
+// harborPaths returns the paths of the host of the public url.
+func harborPaths(harbor *goharborv1alpha1.Harbor) []Path {
+	var paths []Path
+
+	corePort := internaltls.PublicPort(harbor, ServicePort)
+	coreService := harbor.NormalizeComponentName(goharborv1alpha1.CoreName)
+
+	if harbor.Spec.Components.Core != nil {
+		for _, prefix := range []string{"/api", "/c", "/service"} {
+			paths = append(paths, Path{
+				Component:   goharborv1alpha1.CoreName,
+				Prefix:      prefix,
+				ServiceName: coreService,
+				ServicePort: corePort,
+			})
+		}
+	}
+
+	if harbor.Spec.Components.Registry != nil {
+		paths = append(paths, Path{
+			Component:   goharborv1alpha1.RegistryName,
+			Prefix:      "/v2",
+			ServiceName: harbor.NormalizeComponentName(goharborv1alpha1.RegistryName),
+			ServicePort: internaltls.PublicPort(harbor, ServicePort),
+		})
+	}
+
+	if harbor.Spec.Components.ChartMuseum != nil {
+		// Core proxies chart repositories to chartmuseum
+		paths = append(paths, Path{
+			Component:   goharborv1alpha1.ChartMuseumName,
+			Prefix:      "/chartrepo",
+			ServiceName: coreService,
+			ServicePort: corePort,
+		})
+	}
+
+	if harbor.Spec.Components.Portal != nil {
+		paths = append(paths, Path{
+			Component:   goharborv1alpha1.PortalName,
+			Prefix:      "/",
+			ServiceName: harbor.NormalizeComponentName(goharborv1alpha1.PortalName),
+			ServicePort: internaltls.PublicPort(harbor, ServicePort),
+		})
+	}
+
+	return paths
+}
+
+func newHost(publicURL string) Host {
+	u, err := url.Parse(publicURL)
+	if err != nil {
+		panic(errors.Wrap(err, "invalid url"))
+	}
+
+	return Host{
+		Names: []string{u.Hostname()},
+		TLS:   u.Scheme == "https",
+	}
+}
+
+// Ingresses returns the ingresses of the paths of the component: the public one and the one of the internal endpoint.
+// They are empty when components are not exposed with ingresses.
+func Ingresses(harbor *goharborv1alpha1.Harbor, componentName string, labels map[string]string) []*netv1.Ingress {
+	result := []*netv1.Ingress{}
+
+	if ingress := Ingress(harbor, componentName, labels); ingress != nil {
+		result = append(result, ingress)
+	}
+
+	if ingress := InternalIngress(harbor, componentName, labels); ingress != nil {
+		result = append(result, ingress)
+	}
+
+	return result
+}
+
+// InternalIngress returns the ingress of the paths of the component on the internal endpoint, nil if there is none.
+func InternalIngress(harbor *goharborv1alpha1.Harbor, componentName string, labels map[string]string) *netv1.Ingress {
+	host := InternalHost(harbor)
+	if host == nil || !harbor.IsIngressEnabled() {
+		return nil
+	}
+
+	ingress := newIngress(harbor, []Host{*host}, componentName, harbor.InternalEndpointTLSSecretName())
+	if ingress == nil {
+		return nil
+	}
+
+	ingress.SetName(harbor.NormalizeComponentName(fmt.Sprintf("%s-internal", componentName)))
+	ingress.SetLabels(copyLabels(labels))
+
+	if class := harbor.Spec.InternalEndpoint.IngressClassName; class != "" {
+		if ingress.Annotations == nil {
+			ingress.Annotations = map[string]string{}
+		}
+
+		ingress.Annotations[IngressClassAnnotation] = class
+	}
+
+	return ingress
+}
+
+// Ingress returns the ingress of the paths of the component, nil when components are not exposed with ingresses.
+func Ingress(harbor *goharborv1alpha1.Harbor, componentName string, labels map[string]string) *netv1.Ingress {
+	if !harbor.IsIngressEnabled() {
+		return nil
+	}
+
+	ingress := newIngress(harbor, Hosts(harbor), componentName, harbor.PublicTLSSecretName())
+	if ingress == nil {
+		return nil
+	}
+
+	ingress.SetName(harbor.NormalizeComponentName(componentName))
+	ingress.SetLabels(copyLabels(labels))
+
+	return ingress
+}
+
+func newIngress(harbor *goharborv1alpha1.Harbor, hosts []Host, componentName string, tlsSecretName string) *netv1.Ingress {
+	var rules []netv1.IngressRule
+
+	var tlsHosts []string
+
+	for _, host := range hosts {
+		var paths []netv1.HTTPIngressPath
+
+		for _, path := range host.Paths {
+			if path.Component != componentName {
+				continue
+			}
+
+			paths = append(paths, netv1.HTTPIngressPath{
+				Path: path.Prefix,
+				Backend: netv1.IngressBackend{
+					ServiceName: path.ServiceName,
+					ServicePort: intstr.FromInt(path.ServicePort),
+				},
+			})
+		}
+
+		if len(paths) == 0 {
+			continue
+		}
+
+		if host.TLS {
+			tlsHosts = append(tlsHosts, host.Names...)
+		}
+
+		for _, name := range host.Names {
+			rules = append(rules, netv1.IngressRule{
+				Host: name,
+				IngressRuleValue: netv1.IngressRuleValue{
+					HTTP: &netv1.HTTPIngressRuleValue{
+						Paths: paths,
+					},
+				},
+			})
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var tls []netv1.IngressTLS
+	if len(tlsHosts) > 0 {
+		tls = []netv1.IngressTLS{
+			{
+				Hosts:      tlsHosts,
+				SecretName: tlsSecretName,
+			},
+		}
+	}
+
+	return &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   harbor.Namespace,
+			Annotations: IngressAnnotations(harbor, componentName, len(tls) > 0),
+		},
+		Spec: netv1.IngressSpec{
+			TLS:   tls,
+			Rules: rules,
+		},
+	}
+}
+
+// copyLabels returns a copy of the labels, as labels of resources are mutated before they are applied.
+func copyLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(labels))
+	for key, value := range labels {
+		result[key] = value
+	}
+
+	return result
+}
@@ -4,8 +4,61 @@ import (
 	"context"
 
 	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/expose"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
+// PublicHosts returns the hosts exposed with TLS, which are covered by the public certificate.
+// The host of the internal endpoint is covered when its ingresses use the public TLS secret.
+func PublicHosts(harbor *goharborv1alpha1.Harbor) []string {
+	hosts := []string{}
+
+	for _, host := range expose.Hosts(harbor) {
+		if host.TLS {
+			hosts = append(hosts, host.Names...)
+		}
+	}
+
+	if host := expose.InternalHost(harbor); host != nil && host.TLS && harbor.InternalEndpointTLSSecretName() == harbor.PublicTLSSecretName() {
+		hosts = append(hosts, host.Names...)
+	}
+
+	return hosts
+}
+
 func (c *HarborCore) GetCertificates(ctx context.Context) []*certv1.Certificate {
-	return []*certv1.Certificate{}
+	if !c.harbor.IsPublicCertificateManaged() {
+		return []*certv1.Certificate{}
+	}
+
+	hosts := PublicHosts(c.harbor)
+	if len(hosts) == 0 {
+		return []*certv1.Certificate{}
+	}
+
+	operatorName := application.GetName(ctx)
+	harborName := c.harbor.Name
+
+	return []*certv1.Certificate{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      c.harbor.NormalizeComponentName(goharborv1alpha1.PublicCertificateName),
+				Namespace: c.harbor.Namespace,
+				Labels: map[string]string{
+					"app":      goharborv1alpha1.CoreName,
+					"harbor":   harborName,
+					"operator": operatorName,
+				},
+			},
+			Spec: certv1.CertificateSpec{
+				CommonName: hosts[0],
+				SecretName: c.harbor.PublicTLSSecretName(),
+				DNSNames:   hosts,
+				IssuerRef:  c.harbor.Spec.CertificateIssuerRef,
+			},
+		},
+	}
 }
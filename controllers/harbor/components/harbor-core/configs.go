@@ -14,7 +14,9 @@ import (
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
 	"github.com/goharbor/harbor-operator/controllers/harbor/components/clair"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
 	"github.com/goharbor/harbor-operator/controllers/harbor/components/notary"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/registry"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 	"github.com/markbates/pkger"
 	"github.com/pkg/errors"
@@ -48,6 +50,12 @@ func (c *HarborCore) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap { //
 	operatorName := application.GetName(ctx)
 	harborName := c.harbor.Name
 
+	chartCacheDriver := "memory"
+	if c.harbor.Spec.Components.Core.Redis != nil {
+		// Sessions and the chart cache read the url set in the deployment
+		chartCacheDriver = "redis"
+	}
+
 	return []*corev1.ConfigMap{
 		{
 			ObjectMeta: metav1.ObjectMeta{
@@ -70,7 +78,7 @@ func (c *HarborCore) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap { //
 
 				"AUTH_MODE":                      "db_auth",
 				"CFG_EXPIRATION":                 "5",
-				"CHART_CACHE_DRIVER":             "memory",
+				"CHART_CACHE_DRIVER":             chartCacheDriver,
 				"EXT_ENDPOINT":                   c.harbor.Spec.PublicURL,
 				"LOG_LEVEL":                      "debug",
 				"MAX_JOB_WORKERS":                fmt.Sprintf("%d", c.harbor.Spec.Components.JobService.WorkerCount),
@@ -80,20 +88,19 @@ func (c *HarborCore) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap { //
 				"SYNC_QUOTA":                     "true",
 				"SYNC_REGISTRY":                  "false",
 
-				"_REDIS_URL":                    "", // For session purpose
 				"ADMIRAL_URL":                   "NA",
-				"CHART_REPOSITORY_URL":          fmt.Sprintf("http://%s", c.harbor.NormalizeComponentName(goharborv1alpha1.ChartMuseumName)),
+				"CHART_REPOSITORY_URL":          internaltls.URL(c.harbor, goharborv1alpha1.ChartMuseumName),
 				"CLAIR_HEALTH_CHECK_SERVER_URL": fmt.Sprintf("http://%s:6061", c.harbor.NormalizeComponentName(goharborv1alpha1.ClairName)),
 				"CLAIR_URL":                     fmt.Sprintf("http://%s", c.harbor.NormalizeComponentName(goharborv1alpha1.ClairName)),
-				"CLAIR_ADAPTER_URL":             fmt.Sprintf("http://%s:%d", c.harbor.NormalizeComponentName(goharborv1alpha1.ClairName), clair.AdapterPublicPort),
-				"CORE_LOCAL_URL":                fmt.Sprintf("http://%s", c.harbor.NormalizeComponentName(goharborv1alpha1.CoreName)),
-				"CORE_URL":                      fmt.Sprintf("http://%s", c.harbor.NormalizeComponentName(goharborv1alpha1.CoreName)),
-				"JOBSERVICE_URL":                fmt.Sprintf("http://%s", c.harbor.NormalizeComponentName(goharborv1alpha1.JobServiceName)),
-				"NOTARY_URL":                    fmt.Sprintf("http://%s", c.harbor.NormalizeComponentName(notary.NotaryServerName)),
-				"PORTAL_URL":                    fmt.Sprintf("http://%s", c.harbor.NormalizeComponentName(goharborv1alpha1.PortalName)),
-				"REGISTRY_URL":                  fmt.Sprintf("http://%s", c.harbor.NormalizeComponentName(goharborv1alpha1.RegistryName)),
-				"REGISTRYCTL_URL":               fmt.Sprintf("http://%s:8080", c.harbor.NormalizeComponentName(goharborv1alpha1.RegistryName)),
-				"TOKEN_SERVICE_URL":             fmt.Sprintf("http://%s/service/token", c.harbor.NormalizeComponentName(goharborv1alpha1.CoreName)),
+				"CLAIR_ADAPTER_URL":             fmt.Sprintf("%s:%d", internaltls.URL(c.harbor, goharborv1alpha1.ClairName), clair.AdapterPublicPort(c.harbor)),
+				"CORE_LOCAL_URL":                internaltls.URL(c.harbor, goharborv1alpha1.CoreName),
+				"CORE_URL":                      internaltls.URL(c.harbor, goharborv1alpha1.CoreName),
+				"JOBSERVICE_URL":                internaltls.URL(c.harbor, goharborv1alpha1.JobServiceName),
+				"NOTARY_URL":                    notary.URL(c.harbor),
+				"PORTAL_URL":                    internaltls.URL(c.harbor, goharborv1alpha1.PortalName),
+				"REGISTRY_URL":                  internaltls.URL(c.harbor, goharborv1alpha1.RegistryName),
+				"REGISTRYCTL_URL":               fmt.Sprintf("%s:%d", internaltls.URL(c.harbor, goharborv1alpha1.RegistryName), registry.ControllerPublicPort(c.harbor)),
+				"TOKEN_SERVICE_URL":             fmt.Sprintf("%s/service/token", internaltls.URL(c.harbor, goharborv1alpha1.CoreName)),
 
 				"DATABASE_TYPE":             "postgresql",
 				"POSTGRESQL_MAX_IDLE_CONNS": fmt.Sprintf("%d", maxIdleConns),
@@ -108,7 +115,7 @@ func (c *HarborCore) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap { //
 }
 
 func (c *HarborCore) GetConfigMapsCheckSum() string {
-	value := fmt.Sprintf("%s\n%+v\n%x", c.harbor.Spec.PublicURL, c.harbor.Spec.Components.Clair != nil, config)
+	value := fmt.Sprintf("%s\n%+v\n%+v\n%+v\n%+v\n%x", c.harbor.Spec.PublicURL, c.harbor.Spec.Components.Clair != nil, c.harbor.IsInternalTLSEnabled(), c.harbor.Spec.Components.Core.Redis != nil, c.harbor.Spec.ReadOnly, config)
 	sum := sha256.New().Sum([]byte(value))
 
 	// todo get generation of the secret
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"strconv"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -12,6 +13,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/proxy"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/redis"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/trusted-ca"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -29,19 +34,48 @@ const (
 	keyFileName    = "key"
 	configFileName = "app.conf"
 	port           = 8080 // https://github.com/goharbor/harbor/blob/2fb1cc89d9ef9313842cc68b4b7c36be73681505/src/common/const.go#L127
+	tlsPort        = 8443 // Core listens on this port when internal TLS is enabled
 
 	healthCheckPeriod = 90 * time.Second
+
+	redisURLName        = "_REDIS_URL_CORE"
+	sessionRedisURLName = "_REDIS_URL"
+	sessionPoolSize     = 100
 )
 
+// redisEnvVars returns the environment variables of the Redis server storing sessions and the chart cache.
+// Sessions are stored in memory without server.
+// Beego reads the session url as <address>,<pool size>,<password>,<database>, newer releases read the url of the server.
+func (c *HarborCore) redisEnvVars() []corev1.EnvVar {
+	spec := c.harbor.Spec.Components.Core.Redis
+	if spec == nil {
+		return []corev1.EnvVar{{
+			Name: sessionRedisURLName,
+		}}
+	}
+
+	password := ""
+	if spec.PasswordSecret != "" {
+		password = fmt.Sprintf("$(%s)", redis.PasswordEnvName(redisURLName))
+	}
+
+	return append(redis.URLEnvVars(redisURLName, spec), corev1.EnvVar{
+		Name:  sessionRedisURLName,
+		Value: fmt.Sprintf("%s,%d,%s,%d", spec.Hosts[0], sessionPoolSize, password, spec.Database),
+	})
+}
+
 func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment { // nolint:funlen
 	operatorName := application.GetName(ctx)
 	harborName := c.harbor.GetName()
 
-	cacheEnv := corev1.EnvVar{
+	cacheEnv := []corev1.EnvVar{{
 		Name: "_REDIS_URL_REG",
-	}
-	if len(c.harbor.Spec.Components.Registry.CacheSecret) > 0 {
-		cacheEnv.ValueFrom = &corev1.EnvVarSource{
+	}}
+	if cache := c.harbor.Spec.Components.Registry.Cache; cache != nil {
+		cacheEnv = redis.URLEnvVars("_REDIS_URL_REG", cache)
+	} else if len(c.harbor.Spec.Components.Registry.CacheSecret) > 0 {
+		cacheEnv[0].ValueFrom = &corev1.EnvVarSource{
 			SecretKeyRef: &corev1.SecretKeySelector{
 				Key:      goharborv1alpha1.HarborRegistryURLKey,
 				Optional: &varTrue,
@@ -52,6 +86,8 @@ func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 		}
 	}
 
+	containerPort := internaltls.Port(c.harbor, port, tlsPort)
+
 	return []*appsv1.Deployment{
 		{
 			ObjectMeta: metav1.ObjectMeta{
@@ -75,9 +111,10 @@ func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 				Template: corev1.PodTemplateSpec{
 					ObjectMeta: metav1.ObjectMeta{
 						Annotations: map[string]string{
-							"configuration/checksum": c.GetConfigMapsCheckSum(),
-							"secret/checksum":        c.GetSecretsCheckSum(),
-							"operator/version":       application.GetVersion(ctx),
+							"configuration/checksum":            c.GetConfigMapsCheckSum(),
+							"secret/checksum":                   c.GetSecretsCheckSum(),
+							"operator/version":                  application.GetVersion(ctx),
+							goharborv1alpha1.ReadOnlyAnnotation: strconv.FormatBool(c.harbor.Spec.ReadOnly),
 						},
 						Labels: map[string]string{
 							"app":      goharborv1alpha1.CoreName,
@@ -88,7 +125,7 @@ func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 					Spec: corev1.PodSpec{
 						NodeSelector:                 c.harbor.Spec.Components.Core.NodeSelector,
 						AutomountServiceAccountToken: &varFalse,
-						Volumes: []corev1.Volume{
+						Volumes: append([]corev1.Volume{
 							{
 								Name: "config",
 								VolumeSource: corev1.VolumeSource{
@@ -121,7 +158,7 @@ func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 								Name: "certificate",
 								VolumeSource: corev1.VolumeSource{
 									Secret: &corev1.SecretVolumeSource{
-										SecretName: c.harbor.NormalizeComponentName(goharborv1alpha1.CertificateName),
+										SecretName: c.harbor.TokenCertificateSecretName(),
 									},
 								},
 							}, {
@@ -130,7 +167,7 @@ func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 									EmptyDir: &corev1.EmptyDirVolumeSource{},
 								},
 							},
-						},
+						}, append(internaltls.Volumes(c.harbor, goharborv1alpha1.CoreName), trustedca.Volumes(c.harbor)...)...),
 						InitContainers: []corev1.Container{
 							{
 								Name:            "configuration",
@@ -154,7 +191,7 @@ func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 								Env: []corev1.EnvVar{
 									{
 										Name:  "PORT",
-										Value: fmt.Sprintf("%d", port),
+										Value: fmt.Sprintf("%d", containerPort),
 									},
 								},
 							},
@@ -165,12 +202,12 @@ func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 								Image: c.harbor.Spec.Components.Core.GetImage(),
 								Ports: []corev1.ContainerPort{
 									{
-										ContainerPort: int32(port),
+										ContainerPort: int32(containerPort),
 									},
 								},
 
 								// https://github.com/goharbor/harbor/blob/master/make/photon/prepare/templates/core/env.jinja
-								Env: []corev1.EnvVar{
+								Env: append(append([]corev1.EnvVar{
 									{
 										Name: "CORE_SECRET",
 										ValueFrom: &corev1.EnvVarSource{
@@ -315,8 +352,7 @@ func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 											},
 										},
 									},
-									cacheEnv,
-								},
+								}, append(cacheEnv, c.redisEnvVars()...)...), append(internaltls.EnvVars(c.harbor), proxy.EnvVars(c.harbor)...)...),
 								EnvFrom: []corev1.EnvFromSource{
 									{
 										ConfigMapRef: &corev1.ConfigMapEnvSource{
@@ -331,8 +367,9 @@ func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 								LivenessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/api/ping",
-											Port: intstr.FromInt(port),
+											Path:   "/api/ping",
+											Port:   intstr.FromInt(containerPort),
+											Scheme: internaltls.ProbeScheme(c.harbor),
 										},
 									},
 									PeriodSeconds: int32(healthCheckPeriod.Seconds()),
@@ -340,12 +377,13 @@ func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 								ReadinessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/api/ping",
-											Port: intstr.FromInt(port),
+											Path:   "/api/ping",
+											Port:   intstr.FromInt(containerPort),
+											Scheme: internaltls.ProbeScheme(c.harbor),
 										},
 									},
 								},
-								VolumeMounts: []corev1.VolumeMount{
+								VolumeMounts: append([]corev1.VolumeMount{
 									{
 										Name:      "config",
 										ReadOnly:  true,
@@ -366,7 +404,7 @@ func (c *HarborCore) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 										ReadOnly:  false,
 										MountPath: path.Join(coreConfigPath, "token"),
 									},
-								},
+								}, append(internaltls.VolumeMounts(c.harbor), trustedca.VolumeMounts(c.harbor)...)...),
 							},
 						},
 						Priority: c.Option.GetPriority(),
@@ -8,6 +8,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/expose"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/mesh"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -22,8 +25,9 @@ func (c *HarborCore) GetServices(ctx context.Context) []*corev1.Service {
 	return []*corev1.Service{
 		{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      c.harbor.NormalizeComponentName(goharborv1alpha1.CoreName),
-				Namespace: c.harbor.Namespace,
+				Name:        c.harbor.NormalizeComponentName(goharborv1alpha1.CoreName),
+				Namespace:   c.harbor.Namespace,
+				Annotations: expose.ServiceAnnotations(c.harbor, goharborv1alpha1.CoreName),
 				Labels: map[string]string{
 					"app":      goharborv1alpha1.CoreName,
 					"harbor":   harborName,
@@ -33,8 +37,9 @@ func (c *HarborCore) GetServices(ctx context.Context) []*corev1.Service {
 			Spec: corev1.ServiceSpec{
 				Ports: []corev1.ServicePort{
 					{
-						Port:       PublicPort,
-						TargetPort: intstr.FromInt(port),
+						Name:       mesh.PortName(c.harbor, mesh.HTTPProtocol(c.harbor), ""),
+						Port:       int32(internaltls.PublicPort(c.harbor, PublicPort)),
+						TargetPort: intstr.FromInt(internaltls.Port(c.harbor, port, tlsPort)),
 					},
 				},
 				Selector: map[string]string{
@@ -0,0 +1,140 @@
+package internaltls
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+const (
+	// HTTPSPort is the port of services exposing components with internal TLS
+	HTTPSPort = 443
+
+	// CertificatesPath is where the TLS secret of the component is mounted
+	CertificatesPath = "/etc/harbor/ssl"
+	// TrustedCertificatesPath is where Harbor images look for certificates to trust at startup
+	TrustedCertificatesPath = "/harbor_cust_cert"
+
+	VolumeName = "internal-tls"
+
+	// https://kubernetes.github.io/ingress-nginx/user-guide/nginx-configuration/annotations/#backend-protocol
+	ingressBackendProtocolAnnotation = "nginx.ingress.kubernetes.io/backend-protocol"
+)
+
+var (
+	CertificatePath = path.Join(CertificatesPath, corev1.TLSCertKey)
+	KeyPath         = path.Join(CertificatesPath, corev1.TLSPrivateKeyKey)
+	CAPath          = path.Join(CertificatesPath, "ca.crt")
+)
+
+// Scheme returns the scheme of URLs between components.
+func Scheme(harbor *goharborv1alpha1.Harbor) string {
+	if harbor.IsInternalTLSEnabled() {
+		return "https"
+	}
+
+	return "http"
+}
+
+// ProbeScheme returns the scheme of the probes of components.
+func ProbeScheme(harbor *goharborv1alpha1.Harbor) corev1.URIScheme {
+	if harbor.IsInternalTLSEnabled() {
+		return corev1.URISchemeHTTPS
+	}
+
+	return corev1.URISchemeHTTP
+}
+
+// Port returns httpsPort with internal TLS, httpPort otherwise.
+func Port(harbor *goharborv1alpha1.Harbor, httpPort, httpsPort int) int {
+	if harbor.IsInternalTLSEnabled() {
+		return httpsPort
+	}
+
+	return httpPort
+}
+
+// PublicPort returns the port of the service of a component, HTTPSPort with internal TLS.
+func PublicPort(harbor *goharborv1alpha1.Harbor, httpPort int) int {
+	return Port(harbor, httpPort, HTTPSPort)
+}
+
+// URL returns the URL of the service of the component, with the default port of the scheme.
+func URL(harbor *goharborv1alpha1.Harbor, componentName string) string {
+	return fmt.Sprintf("%s://%s", Scheme(harbor), harbor.NormalizeComponentName(componentName))
+}
+
+// EnvVars returns the environment variables configuring internal TLS in Harbor images.
+func EnvVars(harbor *goharborv1alpha1.Harbor) []corev1.EnvVar {
+	if !harbor.IsInternalTLSEnabled() {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name:  "INTERNAL_TLS_ENABLED",
+			Value: "true",
+		}, {
+			Name:  "INTERNAL_TLS_CERT_PATH",
+			Value: CertificatePath,
+		}, {
+			Name:  "INTERNAL_TLS_KEY_PATH",
+			Value: KeyPath,
+		}, {
+			Name:  "INTERNAL_TLS_TRUST_CA_PATH",
+			Value: CAPath,
+		},
+	}
+}
+
+// Volumes returns the volume of the TLS secret of the component.
+func Volumes(harbor *goharborv1alpha1.Harbor, componentName string) []corev1.Volume {
+	if !harbor.IsInternalTLSEnabled() {
+		return nil
+	}
+
+	return []corev1.Volume{
+		{
+			Name: VolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: harbor.InternalTLSSecretName(componentName),
+				},
+			},
+		},
+	}
+}
+
+// VolumeMounts returns the mounts of the TLS secret, the CA being also trusted by Harbor images.
+func VolumeMounts(harbor *goharborv1alpha1.Harbor) []corev1.VolumeMount {
+	if !harbor.IsInternalTLSEnabled() {
+		return nil
+	}
+
+	return []corev1.VolumeMount{
+		{
+			Name:      VolumeName,
+			MountPath: CertificatesPath,
+			ReadOnly:  true,
+		}, {
+			Name:      VolumeName,
+			MountPath: path.Join(TrustedCertificatesPath, "harbor-internal-ca.crt"),
+			SubPath:   "ca.crt",
+			ReadOnly:  true,
+		},
+	}
+}
+
+// IngressAnnotations returns the annotations of ingresses forwarding to components.
+func IngressAnnotations(harbor *goharborv1alpha1.Harbor) map[string]string {
+	if !harbor.IsInternalTLSEnabled() {
+		return nil
+	}
+
+	return map[string]string{
+		ingressBackendProtocolAnnotation: "HTTPS",
+	}
+}
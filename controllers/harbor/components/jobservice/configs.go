@@ -11,6 +11,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/registry"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 	"github.com/markbates/pkger"
 	"github.com/pkg/errors"
@@ -64,7 +66,7 @@ func (j *JobService) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap {
 				configName: config,
 			},
 			Data: map[string]string{
-				"REGISTRY_CONTROLLER_URL":          fmt.Sprintf("http://%s:8080", j.harbor.NormalizeComponentName(goharborv1alpha1.RegistryName)),
+				"REGISTRY_CONTROLLER_URL":          fmt.Sprintf("%s:%d", internaltls.URL(j.harbor, goharborv1alpha1.RegistryName), registry.ControllerPublicPort(j.harbor)),
 				"JOBSERVICE_WEBHOOK_JOB_MAX_RETRY": fmt.Sprintf("%d", hookMaxRetry),
 				"JOB_SERVICE_POOL_WORKERS":         fmt.Sprintf("%d", j.harbor.Spec.Components.JobService.WorkerCount),
 			},
@@ -73,7 +75,7 @@ func (j *JobService) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap {
 }
 
 func (j *JobService) GetConfigMapsCheckSum() string {
-	value := fmt.Sprintf("%d\n%d\n%x", hookMaxRetry, j.harbor.Spec.Components.JobService.WorkerCount, config)
+	value := fmt.Sprintf("%d\n%d\n%t\n%x", hookMaxRetry, j.harbor.Spec.Components.JobService.WorkerCount, j.harbor.IsInternalTLSEnabled(), config)
 	sum := sha256.New().Sum([]byte(value))
 
 	return fmt.Sprintf("%x", sum)
@@ -11,6 +11,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/proxy"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/redis"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/trusted-ca"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -23,11 +27,51 @@ const (
 	initImage  = "hairyhenderson/gomplate"
 	configPath = "/etc/jobservice/"
 	port       = 8080
+	tlsPort    = 8443 // JobService listens on this port when internal TLS is enabled
+
+	redisNamespace = "harbor_job_service_namespace"
 )
 
+// redisEnvVars returns the environment variables of the Redis server of the job queue.
+func (j *JobService) redisEnvVars() []corev1.EnvVar {
+	if spec := j.harbor.Spec.Components.JobService.Redis; spec != nil {
+		return append(redis.URLEnvVars("JOB_SERVICE_POOL_REDIS_URL", spec), corev1.EnvVar{
+			Name:  "JOB_SERVICE_POOL_REDIS_NAMESPACE",
+			Value: redisNamespace,
+		})
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name: "JOB_SERVICE_POOL_REDIS_URL",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					Key:      goharborv1alpha1.HarborJobServiceBrokerURLKey,
+					Optional: &varFalse,
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: j.harbor.Spec.Components.JobService.RedisSecret,
+					},
+				},
+			},
+		}, {
+			Name: "JOB_SERVICE_POOL_REDIS_NAMESPACE",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					Key:      goharborv1alpha1.HarborJobServiceBrokerNamespaceKey,
+					Optional: &varFalse,
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: j.harbor.Spec.Components.JobService.RedisSecret,
+					},
+				},
+			},
+		},
+	}
+}
+
 func (j *JobService) GetDeployments(ctx context.Context) []*appsv1.Deployment { // nolint:funlen
 	operatorName := application.GetName(ctx)
 	harborName := j.harbor.GetName()
+	containerPort := internaltls.Port(j.harbor, port, tlsPort)
 
 	return []*appsv1.Deployment{
 		{
@@ -65,7 +109,7 @@ func (j *JobService) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 					Spec: corev1.PodSpec{
 						NodeSelector:                 j.harbor.Spec.Components.JobService.NodeSelector,
 						AutomountServiceAccountToken: &varFalse,
-						Volumes: []corev1.Volume{
+						Volumes: append([]corev1.Volume{
 							{
 								Name: "config",
 								VolumeSource: corev1.VolumeSource{
@@ -86,7 +130,7 @@ func (j *JobService) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 									EmptyDir: &corev1.EmptyDirVolumeSource{},
 								},
 							},
-						},
+						}, append(internaltls.Volumes(j.harbor, goharborv1alpha1.JobServiceName), trustedca.Volumes(j.harbor)...)...),
 						InitContainers: []corev1.Container{
 							{
 								Name:            "configuration",
@@ -107,15 +151,15 @@ func (j *JobService) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 										ReadOnly:  false,
 									},
 								},
-								Env: []corev1.EnvVar{
+								Env: append([]corev1.EnvVar{
 									{
 										Name:  "PORT",
-										Value: fmt.Sprintf("%d", port),
+										Value: fmt.Sprintf("%d", containerPort),
 									}, {
 										Name:  "LOGS_DIR",
 										Value: logsDirectory,
 									},
-								},
+								}, internaltls.EnvVars(j.harbor)...),
 							},
 						},
 						Containers: []corev1.Container{
@@ -124,12 +168,12 @@ func (j *JobService) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 								Image: j.harbor.Spec.Components.JobService.GetImage(),
 								Ports: []corev1.ContainerPort{
 									{
-										ContainerPort: port,
+										ContainerPort: int32(containerPort),
 									},
 								},
 
 								// https://github.com/goharbor/harbor/blob/master/make/photon/prepare/templates/jobservice/env.jinja
-								Env: []corev1.EnvVar{
+								Env: append(append([]corev1.EnvVar{
 									{
 										Name: "CORE_SECRET",
 										ValueFrom: &corev1.EnvVarSource{
@@ -163,30 +207,8 @@ func (j *JobService) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 												},
 											},
 										},
-									}, {
-										Name: "JOB_SERVICE_POOL_REDIS_URL",
-										ValueFrom: &corev1.EnvVarSource{
-											SecretKeyRef: &corev1.SecretKeySelector{
-												Key:      goharborv1alpha1.HarborJobServiceBrokerURLKey,
-												Optional: &varFalse,
-												LocalObjectReference: corev1.LocalObjectReference{
-													Name: j.harbor.Spec.Components.JobService.RedisSecret,
-												},
-											},
-										},
-									}, {
-										Name: "JOB_SERVICE_POOL_REDIS_NAMESPACE",
-										ValueFrom: &corev1.EnvVarSource{
-											SecretKeyRef: &corev1.SecretKeySelector{
-												Key:      goharborv1alpha1.HarborJobServiceBrokerNamespaceKey,
-												Optional: &varFalse,
-												LocalObjectReference: corev1.LocalObjectReference{
-													Name: j.harbor.Spec.Components.JobService.RedisSecret,
-												},
-											},
-										},
 									},
-								},
+								}, j.redisEnvVars()...), append(internaltls.EnvVars(j.harbor), proxy.EnvVars(j.harbor)...)...),
 								EnvFrom: []corev1.EnvFromSource{
 									{
 										ConfigMapRef: &corev1.ConfigMapEnvSource{
@@ -203,20 +225,22 @@ func (j *JobService) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 								LivenessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/api/v1/stats",
-											Port: intstr.FromInt(port),
+											Path:   "/api/v1/stats",
+											Port:   intstr.FromInt(containerPort),
+											Scheme: internaltls.ProbeScheme(j.harbor),
 										},
 									},
 								},
 								ReadinessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/api/v1/stats",
-											Port: intstr.FromInt(port),
+											Path:   "/api/v1/stats",
+											Port:   intstr.FromInt(containerPort),
+											Scheme: internaltls.ProbeScheme(j.harbor),
 										},
 									},
 								},
-								VolumeMounts: []corev1.VolumeMount{
+								VolumeMounts: append([]corev1.VolumeMount{
 									{
 										MountPath: path.Join(configPath, configName),
 										Name:      "config",
@@ -225,7 +249,7 @@ func (j *JobService) GetDeployments(ctx context.Context) []*appsv1.Deployment {
 										MountPath: logsDirectory,
 										Name:      "logs",
 									},
-								},
+								}, append(internaltls.VolumeMounts(j.harbor), trustedca.VolumeMounts(j.harbor)...)...),
 							},
 						},
 						Priority: j.Option.GetPriority(),
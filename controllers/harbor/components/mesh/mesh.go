@@ -0,0 +1,30 @@
+package mesh
+
+import (
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+)
+
+const (
+	ProtocolHTTP = "http"
+	ProtocolTLS  = "tls"
+)
+
+// PortName returns the name of a service port, prefixed with its protocol in a service mesh so the mesh detects it.
+// https://istio.io/latest/docs/ops/configuration/traffic-management/protocol-selection/
+func PortName(harbor *goharborv1alpha1.Harbor, protocol, name string) string {
+	if !harbor.IsServiceMeshEnabled() {
+		return name
+	}
+
+	if name == "" {
+		return protocol
+	}
+
+	return protocol + "-" + name
+}
+
+// HTTPProtocol returns the protocol of the HTTP ports of components, https with internal TLS.
+func HTTPProtocol(harbor *goharborv1alpha1.Harbor) string {
+	return internaltls.Scheme(harbor)
+}
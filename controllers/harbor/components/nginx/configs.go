@@ -0,0 +1,138 @@
+package nginx
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sync"
+	"text/template"
+
+	"github.com/markbates/pkger"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/expose"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+)
+
+const (
+	nginxConfigName = "nginx.conf"
+	nginxConfigPath = "/etc/nginx/nginx.conf"
+
+	// certificatesPath is where the public TLS secret is mounted
+	certificatesPath = "/etc/nginx/cert"
+)
+
+var (
+	once        sync.Once
+	nginxConfig *template.Template
+)
+
+func InitConfigMaps() {
+	file, err := pkger.Open("/assets/templates/nginx/nginx.conf")
+	if err != nil {
+		panic(errors.Wrapf(err, "cannot open Nginx configuration template %s", "/assets/templates/nginx/nginx.conf"))
+	}
+	defer file.Close()
+
+	content, err := ioutil.ReadAll(file)
+	if err != nil {
+		panic(errors.Wrapf(err, "cannot read Nginx configuration template %s", "/assets/templates/nginx/nginx.conf"))
+	}
+
+	nginxConfig, err = template.New("nginx.conf").Parse(string(content))
+	if err != nil {
+		panic(errors.Wrapf(err, "cannot parse Nginx configuration template %s", "/assets/templates/nginx/nginx.conf"))
+	}
+}
+
+type configLocation struct {
+	Prefix string
+	URL    string
+}
+
+type configHost struct {
+	Names     []string
+	TLS       bool
+	Locations []configLocation
+}
+
+// getConfig renders the nginx configuration routing the paths of each public host to the services of components.
+func (n *Nginx) getConfig() []byte {
+	once.Do(InitConfigMaps)
+
+	hosts := []configHost{}
+
+	for _, host := range expose.Hosts(n.harbor) {
+		locations := make([]configLocation, 0, len(host.Paths))
+
+		for _, p := range host.Paths {
+			scheme := internaltls.Scheme(n.harbor)
+			if p.Component == goharborv1alpha1.NotaryName {
+				// Notary server does not serve TLS
+				scheme = "http"
+			}
+
+			locations = append(locations, configLocation{
+				Prefix: p.Prefix,
+				URL:    fmt.Sprintf("%s://%s:%d", scheme, p.ServiceName, p.ServicePort),
+			})
+		}
+
+		hosts = append(hosts, configHost{
+			Names:     host.Names,
+			TLS:       host.TLS,
+			Locations: locations,
+		})
+	}
+
+	var config bytes.Buffer
+
+	err := nginxConfig.Execute(&config, map[string]interface{}{
+		"Hosts":           hosts,
+		"HTTPPort":        port,
+		"HTTPSPort":       tlsPort,
+		"CertificatePath": path.Join(certificatesPath, corev1.TLSCertKey),
+		"KeyPath":         path.Join(certificatesPath, corev1.TLSPrivateKeyKey),
+		"IPv6":            n.harbor.Spec.Expose.Service.IsIPv6Enabled(),
+	})
+	if err != nil {
+		panic(errors.Wrap(err, "cannot render Nginx configuration"))
+	}
+
+	return config.Bytes()
+}
+
+func (n *Nginx) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap {
+	operatorName := application.GetName(ctx)
+	harborName := n.harbor.Name
+
+	return []*corev1.ConfigMap{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      n.harbor.NormalizeComponentName(goharborv1alpha1.NginxName),
+				Namespace: n.harbor.Namespace,
+				Labels: map[string]string{
+					"app":      goharborv1alpha1.NginxName,
+					"harbor":   harborName,
+					"operator": operatorName,
+				},
+			},
+			BinaryData: map[string][]byte{
+				nginxConfigName: n.getConfig(),
+			},
+		},
+	}
+}
+
+func (n *Nginx) GetConfigMapsCheckSum() string {
+	sum := sha256.New().Sum(n.getConfig())
+
+	return fmt.Sprintf("%x", sum)
+}
@@ -0,0 +1,162 @@
+package nginx
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/expose"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+)
+
+const (
+	// Ports of the nginx-photon image, which does not run as root
+	port    = 8080
+	tlsPort = 8443
+)
+
+var (
+	revisionHistoryLimit int32 = 0 // nolint:golint
+	varFalse                   = false
+)
+
+// isTLSEnabled returns whether a public host is reached over https, so the proxy terminates TLS.
+func (n *Nginx) isTLSEnabled() bool {
+	for _, host := range expose.Hosts(n.harbor) {
+		if host.TLS {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (n *Nginx) GetDeployments(ctx context.Context) []*appsv1.Deployment { // nolint:funlen
+	operatorName := application.GetName(ctx)
+	harborName := n.harbor.GetName()
+
+	volumes := []corev1.Volume{
+		{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: n.harbor.NormalizeComponentName(goharborv1alpha1.NginxName),
+					},
+				},
+			},
+		},
+	}
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "config",
+			MountPath: nginxConfigPath,
+			SubPath:   nginxConfigName,
+			ReadOnly:  true,
+		},
+	}
+
+	if n.isTLSEnabled() {
+		volumes = append(volumes, corev1.Volume{
+			Name: "certificate",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: n.harbor.PublicTLSSecretName(),
+				},
+			},
+		})
+
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "certificate",
+			MountPath: certificatesPath,
+			ReadOnly:  true,
+		})
+	}
+
+	var replicas *int32
+	if n.harbor.Spec.Expose.Service != nil {
+		replicas = n.harbor.Spec.Expose.Service.Replicas
+	}
+
+	return []*appsv1.Deployment{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      n.harbor.NormalizeComponentName(goharborv1alpha1.NginxName),
+				Namespace: n.harbor.Namespace,
+				Labels: map[string]string{
+					"app":      goharborv1alpha1.NginxName,
+					"harbor":   harborName,
+					"operator": operatorName,
+				},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app":      goharborv1alpha1.NginxName,
+						"harbor":   harborName,
+						"operator": operatorName,
+					},
+				},
+				Replicas: replicas,
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							"configuration/checksum": n.GetConfigMapsCheckSum(),
+							"secret/checksum":        "",
+							"operator/version":       application.GetVersion(ctx),
+						},
+						Labels: map[string]string{
+							"app":      goharborv1alpha1.NginxName,
+							"harbor":   harborName,
+							"operator": operatorName,
+						},
+					},
+					Spec: corev1.PodSpec{
+						AutomountServiceAccountToken: &varFalse,
+						Volumes:                      volumes,
+						Containers: []corev1.Container{
+							{
+								Name:  "nginx",
+								Image: n.harbor.Spec.Expose.Service.GetImage(),
+								Ports: []corev1.ContainerPort{
+									{
+										Name:          "http",
+										ContainerPort: port,
+									}, {
+										Name:          "https",
+										ContainerPort: tlsPort,
+									},
+								},
+
+								VolumeMounts:    volumeMounts,
+								ImagePullPolicy: corev1.PullAlways,
+								LivenessProbe: &corev1.Probe{
+									Handler: corev1.Handler{
+										TCPSocket: &corev1.TCPSocketAction{
+											Port: intstr.FromInt(port),
+										},
+									},
+								},
+								ReadinessProbe: &corev1.Probe{
+									Handler: corev1.Handler{
+										TCPSocket: &corev1.TCPSocketAction{
+											Port: intstr.FromInt(port),
+										},
+									},
+								},
+							},
+						},
+						Priority: n.Option.GetPriority(),
+					},
+				},
+				RevisionHistoryLimit: &revisionHistoryLimit,
+				Paused:               n.harbor.Spec.Paused,
+			},
+		},
+	}
+}
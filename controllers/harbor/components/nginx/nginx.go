@@ -0,0 +1,25 @@
+package nginx
+
+import (
+	"context"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+// Nginx is the proxy routing requests to components, when they are exposed with a LoadBalancer or NodePort service,
+// or with TLS passthrough of a gateway.
+type Nginx struct {
+	harbor *goharborv1alpha1.Harbor
+	Option Option
+}
+
+type Option interface {
+	GetPriority() *int32
+}
+
+func New(ctx context.Context, harbor *goharborv1alpha1.Harbor, opt Option) (*Nginx, error) {
+	return &Nginx{
+		harbor: harbor,
+		Option: opt,
+	}, nil
+}
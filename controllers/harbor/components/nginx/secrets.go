@@ -0,0 +1,11 @@
+package nginx
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func (*Nginx) GetSecrets(ctx context.Context) []*corev1.Secret {
+	return []*corev1.Secret{}
+}
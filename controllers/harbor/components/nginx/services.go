@@ -0,0 +1,81 @@
+package nginx
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+)
+
+const (
+	PublicPort    = 80
+	PublicTLSPort = 443
+)
+
+// GetServices returns the service of the proxy, of the type of exposure.
+// It is a ClusterIP service reached by gateways with TLS passthrough.
+func (n *Nginx) GetServices(ctx context.Context) []*corev1.Service {
+	operatorName := application.GetName(ctx)
+	harborName := n.harbor.Name
+
+	spec := corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{
+			{
+				Name:       "http",
+				Port:       PublicPort,
+				TargetPort: intstr.FromInt(port),
+			}, {
+				Name:       "https",
+				Port:       PublicTLSPort,
+				TargetPort: intstr.FromInt(tlsPort),
+			},
+		},
+		Selector: map[string]string{
+			"app":      goharborv1alpha1.NginxName,
+			"harbor":   harborName,
+			"operator": operatorName,
+		},
+	}
+
+	var annotations map[string]string
+
+	if service := n.harbor.Spec.Expose.Service; service != nil {
+		annotations = service.Annotations
+		spec.ExternalTrafficPolicy = service.ExternalTrafficPolicy
+	}
+
+	switch n.harbor.GetExposeType() {
+	case goharborv1alpha1.ExposeTypeLoadBalancer:
+		spec.Type = corev1.ServiceTypeLoadBalancer
+
+		if n.harbor.Spec.Expose.Service != nil {
+			spec.LoadBalancerIP = n.harbor.Spec.Expose.Service.LoadBalancerIP
+		}
+	case goharborv1alpha1.ExposeTypeNodePort:
+		spec.Type = corev1.ServiceTypeNodePort
+	default:
+		spec.Type = corev1.ServiceTypeClusterIP
+		// Only valid for services reached from outside the cluster
+		spec.ExternalTrafficPolicy = ""
+	}
+
+	return []*corev1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      n.harbor.NormalizeComponentName(goharborv1alpha1.NginxName),
+				Namespace: n.harbor.Namespace,
+				Labels: map[string]string{
+					"app":      goharborv1alpha1.NginxName,
+					"harbor":   harborName,
+					"operator": operatorName,
+				},
+				Annotations: annotations,
+			},
+			Spec: spec,
+		},
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/ovh/configstore"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 	"github.com/goharbor/harbor-operator/pkg/factories/logger"
 )
@@ -14,7 +15,7 @@ import (
 const (
 	defaultKeyAlgorithm   = certv1.RSAKeyAlgorithm
 	defaultKeySize        = 4096
-	notaryCertificateName = "notary-certificate"
+	notaryCertificateName = goharborv1alpha1.NotaryCertificateName
 )
 
 var (
@@ -28,6 +29,11 @@ type certificateEncryption struct {
 }
 
 func (n *Notary) GetCertificates(ctx context.Context) []*certv1.Certificate {
+	// Certificates are issued by the operator or provided by the user
+	if n.harbor.GetCertificatesMode() != goharborv1alpha1.CertificatesModeCertManager {
+		return []*certv1.Certificate{}
+	}
+
 	operatorName := application.GetName(ctx)
 	harborName := n.harbor.Name
 
@@ -8,7 +8,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -68,7 +67,7 @@ func (n *Notary) GetDeployments(ctx context.Context) []*appsv1.Deployment { // n
 					Spec: corev1.PodSpec{
 						NodeSelector:                 n.harbor.Spec.Components.Notary.Server.NodeSelector,
 						AutomountServiceAccountToken: &varFalse,
-						Volumes: []corev1.Volume{
+						Volumes: append([]corev1.Volume{
 							{
 								Name: "config-template",
 								VolumeSource: corev1.VolumeSource{
@@ -91,18 +90,18 @@ func (n *Notary) GetDeployments(ctx context.Context) []*appsv1.Deployment { // n
 								Name: "notary-certificate",
 								VolumeSource: corev1.VolumeSource{
 									Secret: &corev1.SecretVolumeSource{
-										SecretName: n.harbor.NormalizeComponentName(notaryCertificateName),
+										SecretName: n.harbor.NotaryCertificateSecretName(),
 									},
 								},
 							}, {
 								Name: "token-certificate",
 								VolumeSource: corev1.VolumeSource{
 									Secret: &corev1.SecretVolumeSource{
-										SecretName: n.harbor.NormalizeComponentName(goharborv1alpha1.CertificateName),
+										SecretName: n.harbor.TokenCertificateSecretName(),
 									},
 								},
 							},
-						},
+						}, serverTLSVolumes(n.harbor)...),
 						InitContainers: []corev1.Container{
 							{
 								Name:  "init-db",
@@ -149,7 +148,7 @@ func (n *Notary) GetDeployments(ctx context.Context) []*appsv1.Deployment { // n
 										},
 									},
 								},
-								Env: []corev1.EnvVar{
+								Env: append([]corev1.EnvVar{
 									{
 										Name:  "core_public_url",
 										Value: n.harbor.Spec.PublicURL,
@@ -163,7 +162,7 @@ func (n *Notary) GetDeployments(ctx context.Context) []*appsv1.Deployment { // n
 										Name:  "notary_signer_key_algorithm",
 										Value: notarySignerKeyAlgorithm,
 									},
-								},
+								}, serverTLSEnvVars(n.harbor)...),
 							},
 						},
 						Containers: []corev1.Container{
@@ -176,7 +175,7 @@ func (n *Notary) GetDeployments(ctx context.Context) []*appsv1.Deployment { // n
 									"/etc/notary/server-config.json",
 								},
 								ImagePullPolicy: corev1.PullAlways,
-								VolumeMounts: []corev1.VolumeMount{
+								VolumeMounts: append([]corev1.VolumeMount{
 									{
 										Name:      "token-certificate",
 										MountPath: "/etc/ssl/notary/auth-token.crt",
@@ -190,7 +189,7 @@ func (n *Notary) GetDeployments(ctx context.Context) []*appsv1.Deployment { // n
 										MountPath: "/etc/notary/server-config.json",
 										SubPath:   serverConfigKey,
 									},
-								},
+								}, serverTLSVolumeMounts(n.harbor)...),
 							},
 						},
 						Priority: n.Option.GetPriority(),
@@ -258,7 +257,7 @@ func (n *Notary) GetDeployments(ctx context.Context) []*appsv1.Deployment { // n
 								Name: "notary-certificate",
 								VolumeSource: corev1.VolumeSource{
 									Secret: &corev1.SecretVolumeSource{
-										SecretName: n.harbor.NormalizeComponentName(notaryCertificateName),
+										SecretName: n.harbor.NotaryCertificateSecretName(),
 									},
 								},
 							},
@@ -7,8 +7,8 @@ import (
 )
 
 const (
-	NotaryServerName = "notary-server"
-	NotarySignerName = "notary-signer"
+	NotaryServerName = goharborv1alpha1.NotaryServerName
+	NotarySignerName = goharborv1alpha1.NotarySignerName
 )
 
 type Notary struct {
@@ -7,6 +7,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/expose"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/mesh"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -22,8 +25,9 @@ func (n *Notary) GetServices(ctx context.Context) []*corev1.Service {
 		{
 			// https://github.com/goharbor/harbor-helm/blob/master/templates/notary/notary-svc.yaml
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      n.harbor.NormalizeComponentName(NotaryServerName),
-				Namespace: n.harbor.Namespace,
+				Name:        n.harbor.NormalizeComponentName(NotaryServerName),
+				Namespace:   n.harbor.Namespace,
+				Annotations: expose.ServiceAnnotations(n.harbor, goharborv1alpha1.NotaryServerName),
 				Labels: map[string]string{
 					"app":      NotaryServerName,
 					"harbor":   harborName,
@@ -33,8 +37,8 @@ func (n *Notary) GetServices(ctx context.Context) []*corev1.Service {
 			Spec: corev1.ServiceSpec{
 				Ports: []corev1.ServicePort{
 					{
-						Name:       NotaryServerName,
-						Port:       PublicPort,
+						Name:       mesh.PortName(n.harbor, serverProtocol(n.harbor), NotaryServerName),
+						Port:       int32(expose.NotaryServerPort(n.harbor)),
 						TargetPort: intstr.FromInt(notaryServerPort),
 					},
 				},
@@ -59,7 +63,7 @@ func (n *Notary) GetServices(ctx context.Context) []*corev1.Service {
 			Spec: corev1.ServiceSpec{
 				Ports: []corev1.ServicePort{
 					{
-						Name:       NotarySignerName,
+						Name:       mesh.PortName(n.harbor, mesh.ProtocolTLS, NotarySignerName),
 						Port:       PublicPort,
 						TargetPort: intstr.FromInt(notarySignerPort),
 					},
@@ -0,0 +1,85 @@
+package notary
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/expose"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/mesh"
+)
+
+const (
+	serverTLSVolumeName = "public-certificate"
+	serverTLSPath       = "/etc/ssl/notary/server"
+)
+
+// URL returns the URL of notary server for core,
+// the public one when notary server serves the public certificate, which is not valid for the name of the service.
+func URL(harbor *goharborv1alpha1.Harbor) string {
+	if expose.NotaryServesTLS(harbor) {
+		return harbor.Spec.Components.Notary.PublicURL
+	}
+
+	return fmt.Sprintf("http://%s", harbor.NormalizeComponentName(NotaryServerName))
+}
+
+// serverProtocol returns the protocol of the port of notary server, tls when it serves the public certificate.
+func serverProtocol(harbor *goharborv1alpha1.Harbor) string {
+	if expose.NotaryServesTLS(harbor) {
+		return mesh.ProtocolTLS
+	}
+
+	return mesh.ProtocolHTTP
+}
+
+// serverTLSVolumes returns the volume of the public TLS secret, served by notary server behind ingress controllers.
+func serverTLSVolumes(harbor *goharborv1alpha1.Harbor) []corev1.Volume {
+	if !expose.NotaryServesTLS(harbor) {
+		return nil
+	}
+
+	return []corev1.Volume{
+		{
+			Name: serverTLSVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: harbor.PublicTLSSecretName(),
+				},
+			},
+		},
+	}
+}
+
+func serverTLSVolumeMounts(harbor *goharborv1alpha1.Harbor) []corev1.VolumeMount {
+	if !expose.NotaryServesTLS(harbor) {
+		return nil
+	}
+
+	return []corev1.VolumeMount{
+		{
+			Name:      serverTLSVolumeName,
+			MountPath: serverTLSPath,
+			ReadOnly:  true,
+		},
+	}
+}
+
+// serverTLSEnvVars returns the variables enabling TLS in the configuration template of notary server.
+func serverTLSEnvVars(harbor *goharborv1alpha1.Harbor) []corev1.EnvVar {
+	if !expose.NotaryServesTLS(harbor) {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name:  "notary_server_tls_cert_file",
+			Value: path.Join(serverTLSPath, corev1.TLSCertKey),
+		}, {
+			Name:  "notary_server_tls_key_file",
+			Value: path.Join(serverTLSPath, corev1.TLSPrivateKeyKey),
+		},
+	}
+}
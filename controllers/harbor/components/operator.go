@@ -8,4 +8,5 @@ const (
 	ClairPriority       = 80
 	NotaryPriority      = 80
 	PortalPriority      = 75
+	NginxPriority       = 90
 )
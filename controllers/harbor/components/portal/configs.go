@@ -1,11 +1,107 @@
 package portal
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"text/template"
 
+	"github.com/markbates/pkger"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+)
+
+const (
+	nginxConfigName = "nginx.conf"
+	nginxConfigPath = "/etc/nginx/nginx.conf"
 )
 
-func (*Portal) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap {
-	return []*corev1.ConfigMap{}
+var (
+	once        sync.Once
+	nginxConfig *template.Template
+)
+
+func InitConfigMaps() {
+	file, err := pkger.Open("/assets/templates/portal/nginx.conf")
+	if err != nil {
+		panic(errors.Wrapf(err, "cannot open Portal configuration template %s", "/assets/templates/portal/nginx.conf"))
+	}
+	defer file.Close()
+
+	content, err := ioutil.ReadAll(file)
+	if err != nil {
+		panic(errors.Wrapf(err, "cannot read Portal configuration template %s", "/assets/templates/portal/nginx.conf"))
+	}
+
+	nginxConfig, err = template.New("nginx.conf").Parse(string(content))
+	if err != nil {
+		panic(errors.Wrapf(err, "cannot parse Portal configuration template %s", "/assets/templates/portal/nginx.conf"))
+	}
+}
+
+// isConfigEnabled returns whether the portal uses the nginx configuration of the operator, to serve HTTPS or listen on IPv6 addresses.
+func (p *Portal) isConfigEnabled() bool {
+	return p.harbor.IsInternalTLSEnabled() || p.harbor.Spec.Components.Portal.Service.IsIPv6Enabled()
+}
+
+func (p *Portal) getConfig() []byte {
+	once.Do(InitConfigMaps)
+
+	var config bytes.Buffer
+
+	err := nginxConfig.Execute(&config, map[string]interface{}{
+		"TLS":  p.harbor.IsInternalTLSEnabled(),
+		"Port": internaltls.Port(p.harbor, port, tlsPort),
+		"IPv6": p.harbor.Spec.Components.Portal.Service.IsIPv6Enabled(),
+	})
+	if err != nil {
+		panic(errors.Wrap(err, "cannot render Portal configuration"))
+	}
+
+	return config.Bytes()
+}
+
+// GetConfigMaps returns the nginx configuration of the portal, the image default one is used without internal TLS nor IPv6.
+func (p *Portal) GetConfigMaps(ctx context.Context) []*corev1.ConfigMap {
+	if !p.isConfigEnabled() {
+		return []*corev1.ConfigMap{}
+	}
+
+	operatorName := application.GetName(ctx)
+	harborName := p.harbor.Name
+
+	return []*corev1.ConfigMap{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      p.harbor.NormalizeComponentName(goharborv1alpha1.PortalName),
+				Namespace: p.harbor.Namespace,
+				Labels: map[string]string{
+					"app":      goharborv1alpha1.PortalName,
+					"harbor":   harborName,
+					"operator": operatorName,
+				},
+			},
+			BinaryData: map[string][]byte{
+				nginxConfigName: p.getConfig(),
+			},
+		},
+	}
+}
+
+func (p *Portal) GetConfigMapsCheckSum() string {
+	if !p.isConfigEnabled() {
+		return ""
+	}
+
+	sum := sha256.New().Sum(p.getConfig())
+
+	return fmt.Sprintf("%x", sum)
 }
@@ -9,11 +9,13 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
 const (
-	port = 8080
+	port    = 8080
+	tlsPort = 8443 // Port of the nginx configuration used with internal TLS
 )
 
 var (
@@ -24,6 +26,30 @@ var (
 func (p *Portal) GetDeployments(ctx context.Context) []*appsv1.Deployment { // nolint:funlen
 	operatorName := application.GetName(ctx)
 	harborName := p.harbor.GetName()
+	containerPort := internaltls.Port(p.harbor, port, tlsPort)
+
+	volumes := internaltls.Volumes(p.harbor, goharborv1alpha1.PortalName)
+	volumeMounts := internaltls.VolumeMounts(p.harbor)
+
+	if p.isConfigEnabled() {
+		volumes = append(volumes, corev1.Volume{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: p.harbor.NormalizeComponentName(goharborv1alpha1.PortalName),
+					},
+				},
+			},
+		})
+
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "config",
+			MountPath: nginxConfigPath,
+			SubPath:   nginxConfigName,
+			ReadOnly:  true,
+		})
+	}
 
 	return []*appsv1.Deployment{
 		{
@@ -48,7 +74,7 @@ func (p *Portal) GetDeployments(ctx context.Context) []*appsv1.Deployment { // n
 				Template: corev1.PodTemplateSpec{
 					ObjectMeta: metav1.ObjectMeta{
 						Annotations: map[string]string{
-							"configuration/checksum": "",
+							"configuration/checksum": p.GetConfigMapsCheckSum(),
 							"secret/checksum":        "",
 							"operator/version":       application.GetVersion(ctx),
 						},
@@ -61,30 +87,34 @@ func (p *Portal) GetDeployments(ctx context.Context) []*appsv1.Deployment { // n
 					Spec: corev1.PodSpec{
 						NodeSelector:                 p.harbor.Spec.Components.Portal.NodeSelector,
 						AutomountServiceAccountToken: &varFalse,
+						Volumes:                      volumes,
 						Containers: []corev1.Container{
 							{
 								Name:  "portal",
 								Image: p.harbor.Spec.Components.Portal.GetImage(),
 								Ports: []corev1.ContainerPort{
 									{
-										ContainerPort: port,
+										ContainerPort: int32(containerPort),
 									},
 								},
 
+								VolumeMounts:    volumeMounts,
 								ImagePullPolicy: corev1.PullAlways,
 								LivenessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/",
-											Port: intstr.FromInt(port),
+											Path:   "/",
+											Port:   intstr.FromInt(containerPort),
+											Scheme: internaltls.ProbeScheme(p.harbor),
 										},
 									},
 								},
 								ReadinessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/",
-											Port: intstr.FromInt(port),
+											Path:   "/",
+											Port:   intstr.FromInt(containerPort),
+											Scheme: internaltls.ProbeScheme(p.harbor),
 										},
 									},
 								},
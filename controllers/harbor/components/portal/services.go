@@ -8,6 +8,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/expose"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/mesh"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -22,8 +25,9 @@ func (p *Portal) GetServices(ctx context.Context) []*corev1.Service {
 	return []*corev1.Service{
 		{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      p.harbor.NormalizeComponentName(goharborv1alpha1.PortalName),
-				Namespace: p.harbor.Namespace,
+				Name:        p.harbor.NormalizeComponentName(goharborv1alpha1.PortalName),
+				Namespace:   p.harbor.Namespace,
+				Annotations: expose.ServiceAnnotations(p.harbor, goharborv1alpha1.PortalName),
 				Labels: map[string]string{
 					"app":      goharborv1alpha1.PortalName,
 					"harbor":   harborName,
@@ -33,8 +37,9 @@ func (p *Portal) GetServices(ctx context.Context) []*corev1.Service {
 			Spec: corev1.ServiceSpec{
 				Ports: []corev1.ServicePort{
 					{
-						Port:       PublicPort,
-						TargetPort: intstr.FromInt(port),
+						Name:       mesh.PortName(p.harbor, mesh.HTTPProtocol(p.harbor), ""),
+						Port:       int32(internaltls.PublicPort(p.harbor, PublicPort)),
+						TargetPort: intstr.FromInt(internaltls.Port(p.harbor, port, tlsPort)),
 					},
 				},
 				Selector: map[string]string{
@@ -0,0 +1,60 @@
+package proxy
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+// defaultNoProxy are hosts never reached through the proxy
+// https://github.com/goharbor/harbor/blob/master/make/harbor.yml.tmpl
+var defaultNoProxy = []string{"127.0.0.1", "localhost", ".local", ".internal", ".svc"}
+
+// components are the components with a service, always excluded from the proxy
+var components = []string{
+	goharborv1alpha1.CoreName,
+	goharborv1alpha1.JobServiceName,
+	goharborv1alpha1.PortalName,
+	goharborv1alpha1.RegistryName,
+	goharborv1alpha1.ChartMuseumName,
+	goharborv1alpha1.ClairName,
+	goharborv1alpha1.NotaryServerName,
+	goharborv1alpha1.NotarySignerName,
+}
+
+// NoProxy returns the hosts reached without proxy, the services of components followed by the ones of the spec.
+func NoProxy(harbor *goharborv1alpha1.Harbor) []string {
+	noProxy := append([]string{}, defaultNoProxy...)
+
+	for _, component := range components {
+		noProxy = append(noProxy, harbor.NormalizeComponentName(component))
+	}
+
+	if harbor.Spec.Proxy != nil {
+		noProxy = append(noProxy, harbor.Spec.Proxy.NoProxy...)
+	}
+
+	return noProxy
+}
+
+// EnvVars returns the proxy environment variables of components with outbound traffic.
+func EnvVars(harbor *goharborv1alpha1.Harbor) []corev1.EnvVar {
+	if harbor.Spec.Proxy == nil {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name:  "HTTP_PROXY",
+			Value: harbor.Spec.Proxy.HTTPProxy,
+		}, {
+			Name:  "HTTPS_PROXY",
+			Value: harbor.Spec.Proxy.HTTPSProxy,
+		}, {
+			Name:  "NO_PROXY",
+			Value: strings.Join(NoProxy(harbor), ","),
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+const (
+	schemeRedis    = "redis"
+	schemeTLS      = "rediss"
+	schemeSentinel = "redis+sentinel"
+)
+
+var varFalse = false
+
+// URL returns the url of the Redis server, as understood by Harbor components.
+// The password is read from the passwordEnvName environment variable, which must be declared before the url in the container.
+// https://kubernetes.io/docs/tasks/inject-data-application/define-interdependent-environment-variables/
+func URL(spec *goharborv1alpha1.RedisSpec, passwordEnvName string) string {
+	scheme := schemeRedis
+	if spec.TLS {
+		scheme = schemeTLS
+	}
+
+	path := fmt.Sprintf("%d", spec.Database)
+
+	if spec.IsSentinel() {
+		scheme = schemeSentinel
+		path = fmt.Sprintf("%s/%d", spec.SentinelMasterSet, spec.Database)
+	}
+
+	userInfo := ""
+	if spec.PasswordSecret != "" {
+		userInfo = fmt.Sprintf(":$(%s)@", passwordEnvName)
+	}
+
+	return fmt.Sprintf("%s://%s%s/%s", scheme, userInfo, strings.Join(spec.Hosts, ","), path)
+}
+
+// PasswordEnvVars returns the environment variable holding the password of the Redis server, none without password.
+func PasswordEnvVars(name string, spec *goharborv1alpha1.RedisSpec) []corev1.EnvVar {
+	if spec.PasswordSecret == "" {
+		return nil
+	}
+
+	return []corev1.EnvVar{{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				Key:      spec.GetPasswordKey(),
+				Optional: &varFalse,
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: spec.PasswordSecret,
+				},
+			},
+		},
+	}}
+}
+
+// PasswordEnvName returns the name of the environment variable holding the password of the url named name.
+func PasswordEnvName(name string) string {
+	return fmt.Sprintf("%s_PASSWORD", name)
+}
+
+// URLEnvVars returns the environment variable named name holding the url of the Redis server, preceded by the one of its password.
+func URLEnvVars(name string, spec *goharborv1alpha1.RedisSpec) []corev1.EnvVar {
+	passwordEnvName := PasswordEnvName(name)
+
+	return append(PasswordEnvVars(passwordEnvName, spec), corev1.EnvVar{
+		Name:  name,
+		Value: URL(spec, passwordEnvName),
+	})
+}
+
+// ConfigEnvVars returns the environment variables read by configuration templates, so the url is not parsed:
+// <prefix>ADDR, <prefix>SENTINEL_MASTER_SET, <prefix>PASSWORD, <prefix>DB and <prefix>TLS.
+func ConfigEnvVars(prefix string, spec *goharborv1alpha1.RedisSpec) []corev1.EnvVar {
+	return append([]corev1.EnvVar{
+		{
+			Name:  prefix + "ADDR",
+			Value: strings.Join(spec.Hosts, ","),
+		}, {
+			Name:  prefix + "SENTINEL_MASTER_SET",
+			Value: spec.SentinelMasterSet,
+		}, {
+			Name:  prefix + "DB",
+			Value: fmt.Sprintf("%d", spec.Database),
+		}, {
+			Name:  prefix + "TLS",
+			Value: fmt.Sprintf("%t", spec.TLS),
+		},
+	}, PasswordEnvVars(prefix+"PASSWORD", spec)...)
+}
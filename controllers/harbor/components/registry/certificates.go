@@ -23,6 +23,11 @@ type certificateEncryption struct {
 }
 
 func (r *Registry) GetCertificates(ctx context.Context) []*certv1.Certificate {
+	// Certificates are issued by the operator or provided by the user
+	if r.harbor.GetCertificatesMode() != goharborv1alpha1.CertificatesModeCertManager {
+		return []*certv1.Certificate{}
+	}
+
 	operatorName := application.GetName(ctx)
 	harborName := r.harbor.Name
 
@@ -11,6 +11,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/proxy"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/redis"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/trusted-ca"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -19,6 +23,10 @@ const (
 	apiPort     = 5000 // https://github.com/docker/distribution/blob/749f6afb4572201e3c37325d0ffedb6f32be8950/contrib/compose/docker-compose.yml#L15
 	metricsPort = 5001 // https://github.com/docker/distribution/blob/b12bd4004afc203f1cbd2072317c8fda30b89710/cmd/registry/config-dev.yml#L34
 	ctlAPIPort  = 8080 // https://github.com/goharbor/harbor/blob/2fb1cc89d9ef9313842cc68b4b7c36be73681505/src/common/const.go#L134
+
+	// Ports used when internal TLS is enabled
+	apiTLSPort    = 5443
+	ctlAPITLSPort = 8443
 )
 
 var (
@@ -29,15 +37,37 @@ var (
 	varTrue                     = true
 )
 
+// httpHost returns the url of generated urls, such as upload locations.
+// It is empty when Harbor is reached with several names, so urls are derived from requests.
+func (r *Registry) httpHost() string {
+	if len(r.harbor.Spec.AdditionalHostnames) > 0 || r.harbor.Spec.InternalEndpoint != nil {
+		return ""
+	}
+
+	return r.harbor.Spec.PublicURL
+}
+
+// tokenRealm returns the url of the token service clients are sent to.
+func (r *Registry) tokenRealm() string {
+	endpoint := r.harbor.Spec.PublicURL
+	if r.harbor.Spec.InternalEndpoint != nil && r.harbor.Spec.InternalEndpoint.TokenRealm {
+		endpoint = r.harbor.Spec.InternalEndpoint.URL
+	}
+
+	return fmt.Sprintf("%s/service/token", endpoint)
+}
+
 func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { // nolint:funlen
 	operatorName := application.GetName(ctx)
 	harborName := r.harbor.GetName()
 
-	cacheEnv := corev1.EnvVar{
+	cacheEnv := []corev1.EnvVar{{
 		Name: "REDIS_URL",
-	}
-	if len(r.harbor.Spec.Components.Registry.CacheSecret) > 0 {
-		cacheEnv.ValueFrom = &corev1.EnvVarSource{
+	}}
+	if cache := r.harbor.Spec.Components.Registry.Cache; cache != nil {
+		cacheEnv = redis.ConfigEnvVars("REDIS_", cache)
+	} else if len(r.harbor.Spec.Components.Registry.CacheSecret) > 0 {
+		cacheEnv[0].ValueFrom = &corev1.EnvVarSource{
 			SecretKeyRef: &corev1.SecretKeySelector{
 				Key:      goharborv1alpha1.HarborRegistryURLKey,
 				Optional: &varTrue,
@@ -57,6 +87,9 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 		}
 	}
 
+	apiContainerPort := internaltls.Port(r.harbor, apiPort, apiTLSPort)
+	ctlAPIContainerPort := internaltls.Port(r.harbor, ctlAPIPort, ctlAPITLSPort)
+
 	return []*appsv1.Deployment{
 		{
 			ObjectMeta: metav1.ObjectMeta{
@@ -93,7 +126,7 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 					Spec: corev1.PodSpec{
 						NodeSelector:                 r.harbor.Spec.Components.Registry.NodeSelector,
 						AutomountServiceAccountToken: &varFalse,
-						Volumes: []corev1.Volume{
+						Volumes: append([]corev1.Volume{
 							{
 								Name: "config",
 								VolumeSource: corev1.VolumeSource{
@@ -115,11 +148,11 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 								Name: "certificate",
 								VolumeSource: corev1.VolumeSource{
 									Secret: &corev1.SecretVolumeSource{
-										SecretName: r.harbor.NormalizeComponentName(goharborv1alpha1.CertificateName),
+										SecretName: r.harbor.TokenCertificateSecretName(),
 									},
 								},
 							},
-						},
+						}, append(internaltls.Volumes(r.harbor, goharborv1alpha1.RegistryName), trustedca.Volumes(r.harbor)...)...),
 						InitContainers: []corev1.Container{
 							{
 								Name:            "configuration",
@@ -148,25 +181,24 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 										ReadOnly:  false,
 									},
 								},
-								Env: []corev1.EnvVar{
+								Env: append(append([]corev1.EnvVar{
 									{
 										Name:  "STORAGE_CONFIG",
 										Value: "/opt/configuration/storage",
 									}, {
-										Name:  "CORE_HOSTNAME",
-										Value: r.harbor.NormalizeComponentName(goharborv1alpha1.CoreName),
+										Name:  "CORE_URL",
+										Value: internaltls.URL(r.harbor, goharborv1alpha1.CoreName),
 									}, {
 										Name:  "METRICS_ADDRESS",
 										Value: fmt.Sprintf(":%d", metricsPort),
 									}, {
 										Name:  "API_ADDRESS",
-										Value: fmt.Sprintf(":%d", apiPort),
+										Value: fmt.Sprintf(":%d", apiContainerPort),
 									}, {
 										Name:  "REGISTRYCTL_PORT",
-										Value: fmt.Sprintf("%d", ctlAPIPort),
+										Value: fmt.Sprintf("%d", ctlAPIContainerPort),
 									},
-									cacheEnv,
-								},
+								}, cacheEnv...), internaltls.EnvVars(r.harbor)...),
 							},
 						},
 						Containers: []corev1.Container{
@@ -175,10 +207,10 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 								Image: r.harbor.Spec.Components.Registry.Controller.GetImage(),
 								Ports: []corev1.ContainerPort{
 									{
-										ContainerPort: ctlAPIPort,
+										ContainerPort: int32(ctlAPIContainerPort),
 									},
 								},
-								Env: []corev1.EnvVar{
+								Env: append([]corev1.EnvVar{
 									{
 										Name: "CORE_SECRET",
 										ValueFrom: &corev1.EnvVarSource{
@@ -203,10 +235,10 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 										},
 									}, {
 										Name:  "REGISTRY_HTTP_HOST",
-										Value: r.harbor.Spec.PublicURL,
+										Value: r.httpHost(),
 									}, {
 										Name:  "REGISTRY_AUTH_TOKEN_REALM",
-										Value: fmt.Sprintf("%s/service/token", r.harbor.Spec.PublicURL),
+										Value: r.tokenRealm(),
 									}, {
 										Name:  "REGISTRY_LOG_FIELDS_OPERATOR",
 										Value: operatorName,
@@ -214,25 +246,27 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 										Name:  "REGISTRY_LOG_FIELDS_HARBOR",
 										Value: harborName,
 									},
-								},
+								}, append(internaltls.EnvVars(r.harbor), proxy.EnvVars(r.harbor)...)...),
 								ImagePullPolicy: corev1.PullAlways,
 								LivenessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/api/health",
-											Port: intstr.FromInt(ctlAPIPort),
+											Path:   "/api/health",
+											Port:   intstr.FromInt(ctlAPIContainerPort),
+											Scheme: internaltls.ProbeScheme(r.harbor),
 										},
 									},
 								},
 								ReadinessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
-											Path: "/api/health",
-											Port: intstr.FromInt(ctlAPIPort),
+											Path:   "/api/health",
+											Port:   intstr.FromInt(ctlAPIContainerPort),
+											Scheme: internaltls.ProbeScheme(r.harbor),
 										},
 									},
 								},
-								VolumeMounts: []corev1.VolumeMount{
+								VolumeMounts: append([]corev1.VolumeMount{
 									{
 										MountPath: path.Join(registryConfigPath, defaultRegistryConfigName),
 										Name:      "config",
@@ -246,7 +280,7 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 										Name:      "certificate",
 										SubPath:   "tls.crt",
 									},
-								},
+								}, append(internaltls.VolumeMounts(r.harbor), trustedca.VolumeMounts(r.harbor)...)...),
 								Command: []string{"/home/harbor/harbor_registryctl"},
 								Args:    []string{"-c", path.Join(registryCtlConfigPath, registryCtlConfigName)},
 							}, {
@@ -254,18 +288,18 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 								Image: r.harbor.Spec.Components.Registry.GetImage(),
 								Ports: []corev1.ContainerPort{
 									{
-										ContainerPort: apiPort,
+										ContainerPort: int32(apiContainerPort),
 									}, {
 										ContainerPort: metricsPort,
 									},
 								},
-								Env: []corev1.EnvVar{
+								Env: append([]corev1.EnvVar{
 									{
 										Name:  "REGISTRY_HTTP_HOST",
-										Value: r.harbor.Spec.PublicURL,
+										Value: r.httpHost(),
 									}, {
 										Name:  "REGISTRY_AUTH_TOKEN_REALM",
-										Value: fmt.Sprintf("%s/service/token", r.harbor.Spec.PublicURL),
+										Value: r.tokenRealm(),
 									}, {
 										Name:  "REGISTRY_LOG_FIELDS_OPERATOR",
 										Value: operatorName,
@@ -273,14 +307,14 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 										Name:  "REGISTRY_LOG_FIELDS_HARBOR",
 										Value: harborName,
 									},
-								},
+								}, append(internaltls.EnvVars(r.harbor), proxy.EnvVars(r.harbor)...)...),
 								ImagePullPolicy: corev1.PullAlways,
 								LivenessProbe: &corev1.Probe{
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
 											Path:   "/",
-											Port:   intstr.FromInt(apiPort),
-											Scheme: corev1.URISchemeHTTP,
+											Port:   intstr.FromInt(apiContainerPort),
+											Scheme: internaltls.ProbeScheme(r.harbor),
 										},
 									},
 								},
@@ -288,12 +322,12 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 									Handler: corev1.Handler{
 										HTTPGet: &corev1.HTTPGetAction{
 											Path:   "/",
-											Port:   intstr.FromInt(apiPort),
-											Scheme: corev1.URISchemeHTTP,
+											Port:   intstr.FromInt(apiContainerPort),
+											Scheme: internaltls.ProbeScheme(r.harbor),
 										},
 									},
 								},
-								VolumeMounts: []corev1.VolumeMount{
+								VolumeMounts: append([]corev1.VolumeMount{
 									{
 										MountPath: path.Join(registryConfigPath, registryConfigName),
 										Name:      "config",
@@ -303,7 +337,7 @@ func (r *Registry) GetDeployments(ctx context.Context) []*appsv1.Deployment { //
 										Name:      "certificate",
 										SubPath:   "tls.crt",
 									},
-								},
+								}, append(internaltls.VolumeMounts(r.harbor), trustedca.VolumeMounts(r.harbor)...)...),
 								Command: []string{"/usr/bin/registry"},
 								Args:    []string{"serve", path.Join(registryConfigPath, registryConfigName)},
 							},
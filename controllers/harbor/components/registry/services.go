@@ -8,6 +8,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/expose"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/mesh"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 )
 
@@ -15,6 +18,11 @@ const (
 	PublicPort = 80
 )
 
+// ControllerPublicPort returns the port of the service exposing the registry controller.
+func ControllerPublicPort(harbor *goharborv1alpha1.Harbor) int {
+	return internaltls.Port(harbor, ctlAPIPort, ctlAPITLSPort)
+}
+
 func (r *Registry) GetServices(ctx context.Context) []*corev1.Service {
 	operatorName := application.GetName(ctx)
 	harborName := r.harbor.Name
@@ -22,8 +30,9 @@ func (r *Registry) GetServices(ctx context.Context) []*corev1.Service {
 	return []*corev1.Service{
 		{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      r.harbor.NormalizeComponentName(goharborv1alpha1.RegistryName),
-				Namespace: r.harbor.Namespace,
+				Name:        r.harbor.NormalizeComponentName(goharborv1alpha1.RegistryName),
+				Namespace:   r.harbor.Namespace,
+				Annotations: expose.ServiceAnnotations(r.harbor, goharborv1alpha1.RegistryName),
 				Labels: map[string]string{
 					"app":      goharborv1alpha1.RegistryName,
 					"harbor":   harborName,
@@ -33,15 +42,15 @@ func (r *Registry) GetServices(ctx context.Context) []*corev1.Service {
 			Spec: corev1.ServiceSpec{
 				Ports: []corev1.ServicePort{
 					{
-						Name:       "registry",
-						TargetPort: intstr.FromInt(apiPort),
-						Port:       PublicPort,
+						Name:       mesh.PortName(r.harbor, mesh.HTTPProtocol(r.harbor), "registry"),
+						TargetPort: intstr.FromInt(internaltls.Port(r.harbor, apiPort, apiTLSPort)),
+						Port:       int32(internaltls.PublicPort(r.harbor, PublicPort)),
 					}, {
-						Name: "registry-debug",
+						Name: mesh.PortName(r.harbor, mesh.ProtocolHTTP, "registry-debug"),
 						Port: metricsPort,
 					}, {
-						Name: "controller",
-						Port: ctlAPIPort,
+						Name: mesh.PortName(r.harbor, mesh.HTTPProtocol(r.harbor), "controller"),
+						Port: int32(ControllerPublicPort(r.harbor)),
 					},
 				},
 				Selector: map[string]string{
@@ -27,6 +27,7 @@ func (r *Components) ParallelRun(ctx context.Context, harbor *goharborv1alpha1.H
 	g.Go(run.getRunFunc(ctx, harbor, r.ChartMuseum, goharborv1alpha1.ChartMuseumName))
 	g.Go(run.getRunFunc(ctx, harbor, r.Clair, goharborv1alpha1.ClairName))
 	g.Go(run.getRunFunc(ctx, harbor, r.Notary, goharborv1alpha1.NotaryName))
+	g.Go(run.getRunFunc(ctx, harbor, r.Nginx, goharborv1alpha1.NginxName))
 
 	return g.Wait()
 }
@@ -37,7 +38,7 @@ func (r Run) getRunFunc(ctx context.Context, harbor *goharborv1alpha1.Harbor, ru
 			return nil
 		}
 
-		ctx := withComponent(ctx, name)
+		ctx := WithComponent(ctx, name)
 
 		span, ctx := opentracing.StartSpanFromContext(ctx, "run", opentracing.Tags{
 			"component": name,
@@ -0,0 +1,78 @@
+package trustedca
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	internaltls "github.com/goharbor/harbor-operator/controllers/harbor/components/internal-tls"
+)
+
+const (
+	VolumeName = "trusted-ca-bundle"
+
+	// bundleName is the name of the bundle among certificates trusted by Harbor images at startup
+	bundleName = "trusted-ca-bundle.crt"
+)
+
+var BundlePath = path.Join(internaltls.TrustedCertificatesPath, bundleName)
+
+// Key returns the key of the bundle in the referenced config map or secret, empty without bundle.
+func Key(harbor *goharborv1alpha1.Harbor) string {
+	bundle := harbor.Spec.TrustedCABundle
+
+	switch {
+	case bundle == nil:
+		return ""
+	case bundle.ConfigMapRef != nil:
+		return bundle.ConfigMapRef.Key
+	case bundle.SecretRef != nil:
+		return bundle.SecretRef.Key
+	default:
+		return ""
+	}
+}
+
+// Volumes returns the volume of the config map or secret referenced by spec.trustedCABundle.
+func Volumes(harbor *goharborv1alpha1.Harbor) []corev1.Volume {
+	if Key(harbor) == "" {
+		return nil
+	}
+
+	volume := corev1.Volume{
+		Name: VolumeName,
+	}
+
+	bundle := harbor.Spec.TrustedCABundle
+	if bundle.ConfigMapRef != nil {
+		volume.ConfigMap = &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: bundle.ConfigMapRef.LocalObjectReference,
+			Optional:             bundle.ConfigMapRef.Optional,
+		}
+	} else {
+		volume.Secret = &corev1.SecretVolumeSource{
+			SecretName: bundle.SecretRef.Name,
+			Optional:   bundle.SecretRef.Optional,
+		}
+	}
+
+	return []corev1.Volume{volume}
+}
+
+// VolumeMounts returns the mount of the bundle among certificates trusted by Harbor images.
+func VolumeMounts(harbor *goharborv1alpha1.Harbor) []corev1.VolumeMount {
+	key := Key(harbor)
+	if key == "" {
+		return nil
+	}
+
+	return []corev1.VolumeMount{
+		{
+			Name:      VolumeName,
+			MountPath: BundlePath,
+			SubPath:   key,
+			ReadOnly:  true,
+		},
+	}
+}
@@ -72,6 +72,8 @@ func (r *Reconciler) CreateResource(ctx context.Context, harbor *goharborv1alpha
 
 	logger.Get(ctx).Info("resource created")
 
+	r.resourceEvent(harbor, EventReasonResourceCreated, resource)
+
 	return nil
 }
 
@@ -97,7 +99,7 @@ func (r *Reconciler) CreateResources(ctx context.Context, harbor *goharborv1alph
 // +kubebuilder:rbac:groups="networking.k8s.io",resources="ingresses",verbs=create
 
 func (r *Reconciler) CreateComponent(ctx context.Context, harbor *goharborv1alpha1.Harbor, component *components.ComponentRunner) error {
-	return component.ParallelRun(ctx, harbor, r.CreateResources, r.CreateResources, r.CreateResources, r.CreateResources, r.CreateResources, r.CreateResources, true)
+	return component.ParallelRun(ctx, harbor, r.customizeServices(r.CreateResources), r.CreateResources, r.convertIngresses(r.CreateResources), r.CreateResources, r.convertCertificates(r.CreateResources), r.injectSidecars(r.CreateResources), true)
 }
 
 func (r *Reconciler) Create(ctx context.Context, harbor *goharborv1alpha1.Harbor) error {
@@ -3,14 +3,14 @@ package harbor
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
-	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	netv1 "k8s.io/api/networking/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -30,18 +30,10 @@ var (
 			Group:   corev1.SchemeGroupVersion.Group,
 			Version: corev1.SchemeGroupVersion.Version,
 			Kind:    "ConfigMap",
-		}, {
-			Group:   netv1.SchemeGroupVersion.Group,
-			Version: netv1.SchemeGroupVersion.Version,
-			Kind:    "Ingress",
 		}, {
 			Group:   corev1.SchemeGroupVersion.Group,
 			Version: corev1.SchemeGroupVersion.Version,
 			Kind:    "Secret",
-		}, {
-			Group:   certv1.SchemeGroupVersion.Group,
-			Version: certv1.SchemeGroupVersion.Version,
-			Kind:    "Certificate",
 		}, {
 			Group:   appsv1.SchemeGroupVersion.Group,
 			Version: appsv1.SchemeGroupVersion.Version,
@@ -57,7 +49,7 @@ var (
 // +kubebuilder:rbac:groups="cert-manager.io",resources="certificates",verbs=delete
 // +kubebuilder:rbac:groups="networking.k8s.io",resources="ingresses",verbs=delete
 
-func (r *Reconciler) DeleteResourceCollection(ctx context.Context, harbor *goharborv1alpha1.Harbor, componentName string, gvk schema.GroupVersionKind) error {
+func (r *Reconciler) DeleteResourceCollection(ctx context.Context, harbor *goharborv1alpha1.Harbor, componentName string, gvk schema.GroupVersionKind) (int, error) {
 	u := &unstructured.UnstructuredList{}
 	u.SetGroupVersionKind(gvk)
 
@@ -72,19 +64,25 @@ func (r *Reconciler) DeleteResourceCollection(ctx context.Context, harbor *gohar
 
 	err := r.Client.List(ctx, u, inNamespace, matchingLabel, client.Limit(limit))
 
-	if apierrors.IsNotFound(err) {
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
 		logger.Get(ctx).Info("Cannot list resource to delete, endpoint not found", "GVK.Group", gvk.Group, "GVK.Version", gvk.Version, "GVK.Kind", gvk.Kind)
-		return nil
+		return 0, nil
 	}
 
 	countToDelete := len(u.Items)
 	if countToDelete == 0 {
-		return nil
+		return 0, nil
 	}
 
 	count := 0
 	err = u.EachListItem(func(object runtime.Object) error {
 		err := r.Client.Delete(ctx, object)
+		if err == nil {
+			if accessor, e := meta.Accessor(object); e == nil {
+				r.Recorder.Eventf(harbor, corev1.EventTypeNormal, EventReasonResourceDeleted, "%s %s", gvk.Kind, accessor.GetName())
+			}
+		}
+
 		err = client.IgnoreNotFound(err)
 		if err == nil {
 			count++
@@ -95,14 +93,30 @@ func (r *Reconciler) DeleteResourceCollection(ctx context.Context, harbor *gohar
 	logger.Get(ctx).Info(fmt.Sprintf("%d/%d resources deleted", count, countToDelete), "GVK.Group", gvk.Group, "GVK.Version", gvk.Version, "GVK.Kind", gvk.Kind)
 
 	if err != nil {
-		return errors.Wrap(err, "cannot delete object")
+		return count, errors.Wrap(err, "cannot delete object")
 	}
 
 	if limit == countToDelete {
-		return errors.New("some resource to delete may remain")
+		return count, errors.New("some resource to delete may remain")
 	}
 
-	return nil
+	return count, nil
+}
+
+// gvksToDelete returns the kinds of resources managed for components, with ingresses of the served version
+// when ingresses are served and certificates when cert-manager is available.
+func (r *Reconciler) gvksToDelete() []schema.GroupVersionKind {
+	kinds := append([]schema.GroupVersionKind{}, gvkToDelete...)
+
+	if !r.Config.IngressDisabled {
+		kinds = append(kinds, r.IngressGVK())
+	}
+
+	if !r.Config.CertManager {
+		return kinds
+	}
+
+	return append(kinds, r.CertificateGVK())
 }
 
 func (r *Reconciler) DeleteComponent(ctx context.Context, harbor *goharborv1alpha1.Harbor, componentName string) error {
@@ -113,14 +127,31 @@ func (r *Reconciler) DeleteComponent(ctx context.Context, harbor *goharborv1alph
 
 	l.Info("Deleting component")
 
-	for _, gvk := range gvkToDelete {
+	var deleted int32
+
+	for _, gvk := range r.gvksToDelete() {
 		gvk := gvk
 
 		g.Go(func() error {
-			err := r.DeleteResourceCollection(ctx, harbor, componentName, gvk)
+			count, err := r.DeleteResourceCollection(ctx, harbor, componentName, gvk)
+			atomic.AddInt32(&deleted, int32(count))
+
 			return errors.Wrapf(err, "deletecollection failed for %s", gvk.String())
 		})
 	}
 
-	return g.Wait()
+	err := g.Wait()
+
+	// Absent components are deleted at each reconciliation, only actual deletions and failures are counted
+	if err == nil && deleted == 0 {
+		return nil
+	}
+
+	deleteComponentTotal.WithLabelValues(componentName, errorResultLabel(err)).Inc()
+
+	if err == nil {
+		r.Recorder.Eventf(harbor, corev1.EventTypeNormal, EventReasonComponentDeleted, "component %s deleted", componentName)
+	}
+
+	return err
 }
@@ -0,0 +1,66 @@
+package harbor
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+)
+
+const (
+	EventReasonResourceCreated  = "ResourceCreated"
+	EventReasonResourceUpdated  = "ResourceUpdated"
+	EventReasonResourceDeleted  = "ResourceDeleted"
+	EventReasonComponentDeleted = "ComponentDeleted"
+	EventReasonSecretNotFound   = "SecretNotFound"
+	EventReasonSpecWarning      = "SpecWarning"
+)
+
+// +kubebuilder:rbac:groups="",resources="events",verbs=create;patch
+
+// resourceKind returns the kind of the resource.
+// Resources built by components do not set their TypeMeta, so fallback to the scheme.
+func (r *Reconciler) resourceKind(resource runtime.Object) string {
+	gvk, err := apiutil.GVKForObject(resource, r.Scheme)
+	if err != nil {
+		return resource.GetObjectKind().GroupVersionKind().Kind
+	}
+
+	return gvk.Kind
+}
+
+func (r *Reconciler) resourceEvent(harbor *goharborv1alpha1.Harbor, reason string, resource components.Resource) {
+	r.Recorder.Eventf(harbor, corev1.EventTypeNormal, reason, "%s %s", r.resourceKind(resource), resource.GetName())
+}
+
+// conditionEvent records a transition of an Harbor condition.
+// Transitions to True are Normal events, other transitions are Warning events.
+func (r *Reconciler) conditionEvent(harbor *goharborv1alpha1.Harbor, condition goharborv1alpha1.HarborCondition) {
+	eventType := corev1.EventTypeWarning
+	if condition.Status == corev1.ConditionTrue {
+		eventType = corev1.EventTypeNormal
+	}
+
+	message := fmt.Sprintf("%s is now %s", condition.Type, condition.Status)
+
+	if condition.Reason != "" {
+		message = fmt.Sprintf("%s: %s", message, condition.Reason)
+	}
+
+	if condition.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, condition.Message)
+	}
+
+	r.Recorder.Event(harbor, eventType, string(condition.Type), message)
+}
+
+// warningEvents records the warnings of the Harbor spec, the validating webhook being unable to return them.
+func (r *Reconciler) warningEvents(harbor *goharborv1alpha1.Harbor) {
+	for _, warning := range harbor.Warnings() {
+		r.Recorder.Event(harbor, corev1.EventTypeWarning, EventReasonSpecWarning, warning)
+	}
+}
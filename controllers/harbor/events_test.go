@@ -0,0 +1,68 @@
+package harbor
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+var _ = Describe("events", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var recorder *record.FakeRecorder
+
+	BeforeEach(func() {
+		r, ctx = setupTest(context.TODO())
+
+		recorder = record.NewFakeRecorder(10)
+		r.Recorder = recorder
+	})
+
+	Describe("Condition transitions", func() {
+		var h *goharborv1alpha1.Harbor
+
+		BeforeEach(func() {
+			h = &goharborv1alpha1.Harbor{}
+		})
+
+		It("Should be recorded once per transition", func() {
+			Expect(r.UpdateCondition(ctx, h, goharborv1alpha1.ReadyConditionType, corev1.ConditionFalse, "reason")).To(Succeed())
+			Expect(recorder.Events).To(Receive(Equal("Warning Ready Ready is now False: reason")))
+
+			Expect(r.UpdateCondition(ctx, h, goharborv1alpha1.ReadyConditionType, corev1.ConditionFalse, "reason")).To(Succeed())
+			Expect(recorder.Events).ToNot(Receive())
+
+			Expect(r.UpdateCondition(ctx, h, goharborv1alpha1.ReadyConditionType, corev1.ConditionTrue)).To(Succeed())
+			Expect(recorder.Events).To(Receive(Equal("Normal Ready Ready is now True")))
+		})
+	})
+
+	Describe("Referenced secrets", func() {
+		It("Should be deduplicated", func() {
+			h := &goharborv1alpha1.Harbor{
+				Spec: goharborv1alpha1.HarborSpec{
+					AdminPasswordSecret: "admin",
+					Components: goharborv1alpha1.HarborComponents{
+						Core: &goharborv1alpha1.CoreComponent{
+							DatabaseSecret: "database",
+						},
+						Clair: &goharborv1alpha1.ClairComponent{
+							DatabaseSecret: "database",
+							Adapter: goharborv1alpha1.ClairAdapterComponent{
+								RedisSecret: "redis",
+							},
+						},
+					},
+				},
+			}
+
+			Expect(ReferencedSecrets(h)).To(ConsistOf("admin", "database", "redis"))
+		})
+	})
+})
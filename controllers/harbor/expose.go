@@ -0,0 +1,395 @@
+package harbor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/expose"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/nginx"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+)
+
+const (
+	routeTLSTerminationEdge       = "edge"
+	routeTLSTerminationReencrypt  = "reencrypt"
+	routeInsecurePolicyRedirect   = "Redirect"
+	routeInsecurePolicyAllow      = "Allow"
+	gatewayPathMatchPathPrefix    = "PathPrefix"
+	internalTLSCertificateAuthKey = "ca.crt"
+)
+
+var (
+	// RouteGVK is the kind of OpenShift routes.
+	RouteGVK = schema.GroupVersionKind{
+		Group:   "route.openshift.io",
+		Version: "v1",
+		Kind:    "Route",
+	}
+
+	// HTTPRouteGVK is the kind of Gateway API HTTP routes.
+	HTTPRouteGVK = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1",
+		Kind:    "HTTPRoute",
+	}
+
+	// TLSRouteGVK is the kind of Gateway API TLS routes, still experimental.
+	TLSRouteGVK = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1alpha2",
+		Kind:    "TLSRoute",
+	}
+
+	// ingressComponents are the components exposing paths with an ingress.
+	ingressComponents = []string{
+		goharborv1alpha1.CoreName,
+		goharborv1alpha1.RegistryName,
+		goharborv1alpha1.PortalName,
+		goharborv1alpha1.ChartMuseumName,
+		goharborv1alpha1.NotaryName,
+	}
+)
+
+func newExposeObject(ctx context.Context, harbor *goharborv1alpha1.Harbor, gvk schema.GroupVersionKind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	result := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": spec,
+	}}
+	result.SetGroupVersionKind(gvk)
+	result.SetName(name)
+	result.SetNamespace(harbor.GetNamespace())
+	result.SetLabels(map[string]string{
+		"app":      goharborv1alpha1.ExposeName,
+		"harbor":   harbor.GetName(),
+		"operator": application.GetName(ctx),
+	})
+
+	return result
+}
+
+// Routes returns the OpenShift routes of the paths of components, one per path and host name.
+// destinationCA is the certificate authority of internal TLS, trusted by the router to reencrypt requests.
+func Routes(ctx context.Context, harbor *goharborv1alpha1.Harbor, destinationCA string) []*unstructured.Unstructured {
+	var routes []*unstructured.Unstructured
+
+	for _, host := range expose.Hosts(harbor) {
+		for i, hostname := range host.Names {
+			for _, path := range host.Paths {
+				name := harbor.NormalizeComponentName(path.Component)
+				if suffix := strings.Trim(path.Prefix, "/"); suffix != "" {
+					name += "-" + suffix
+				}
+
+				// Additional host names
+				if i > 0 {
+					name = fmt.Sprintf("%s-%d", name, i)
+				}
+
+				routes = append(routes, newExposeObject(ctx, harbor, RouteGVK, name, routeSpec(harbor, host, hostname, path, destinationCA)))
+			}
+		}
+	}
+
+	return routes
+}
+
+func routeSpec(harbor *goharborv1alpha1.Harbor, host expose.Host, hostname string, path expose.Path, destinationCA string) map[string]interface{} {
+	// Services expose a single port, so the router reaches it without port
+	spec := map[string]interface{}{
+		"host": hostname,
+		"path": path.Prefix,
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   path.ServiceName,
+			"weight": int64(100), // nolint:mnd
+		},
+	}
+
+	tls := map[string]interface{}{}
+
+	if host.TLS {
+		tls["termination"] = routeTLSTerminationEdge
+		tls["insecureEdgeTerminationPolicy"] = routeInsecurePolicyRedirect
+	}
+
+	// Notary server does not serve TLS
+	if harbor.IsInternalTLSEnabled() && path.Component != goharborv1alpha1.NotaryName {
+		tls["termination"] = routeTLSTerminationReencrypt
+		tls["destinationCACertificate"] = destinationCA
+
+		if !host.TLS {
+			tls["insecureEdgeTerminationPolicy"] = routeInsecurePolicyAllow
+		}
+	}
+
+	if len(tls) > 0 {
+		spec["tls"] = tls
+	}
+
+	return spec
+}
+
+func gatewayParentRefs(harbor *goharborv1alpha1.Harbor) []interface{} {
+	var parentRefs []interface{}
+
+	if harbor.Spec.Expose == nil || harbor.Spec.Expose.Gateway == nil {
+		return parentRefs
+	}
+
+	for _, ref := range harbor.Spec.Expose.Gateway.ParentRefs {
+		parentRef := map[string]interface{}{
+			"name": ref.Name,
+		}
+
+		if ref.Namespace != "" {
+			parentRef["namespace"] = ref.Namespace
+		}
+
+		if ref.SectionName != "" {
+			parentRef["sectionName"] = ref.SectionName
+		}
+
+		parentRefs = append(parentRefs, parentRef)
+	}
+
+	return parentRefs
+}
+
+func gatewayHostnames(host expose.Host) []interface{} {
+	hostnames := make([]interface{}, len(host.Names))
+	for i, name := range host.Names {
+		hostnames[i] = name
+	}
+
+	return hostnames
+}
+
+func gatewayBackendRefs(name string, port int) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"name": name,
+			"port": int64(port),
+		},
+	}
+}
+
+// GatewayRoutes returns the Gateway API routes of the public hosts, one per host.
+// HTTP routes forward the paths of components to their services.
+// With TLS passthrough, connections are forwarded to the nginx proxy, with TLS routes for hosts using https.
+func GatewayRoutes(ctx context.Context, harbor *goharborv1alpha1.Harbor) []*unstructured.Unstructured {
+	var routes []*unstructured.Unstructured
+
+	passthrough := harbor.IsExposeProxyEnabled()
+	proxyName := harbor.NormalizeComponentName(goharborv1alpha1.NginxName)
+
+	for i, host := range expose.Hosts(harbor) {
+		name := harbor.GetName()
+		if i > 0 {
+			name = harbor.NormalizeComponentName(host.Paths[0].Component)
+		}
+
+		var rules []interface{}
+
+		if passthrough {
+			rules = []interface{}{
+				map[string]interface{}{
+					"backendRefs": gatewayBackendRefs(proxyName, nginx.PublicPort),
+				},
+			}
+
+			if host.TLS {
+				routes = append(routes, newExposeObject(ctx, harbor, TLSRouteGVK, name, map[string]interface{}{
+					"parentRefs": gatewayParentRefs(harbor),
+					"hostnames":  gatewayHostnames(host),
+					"rules": []interface{}{
+						map[string]interface{}{
+							"backendRefs": gatewayBackendRefs(proxyName, nginx.PublicTLSPort),
+						},
+					},
+				}))
+			}
+		} else {
+			for _, path := range host.Paths {
+				rules = append(rules, map[string]interface{}{
+					"matches": []interface{}{
+						map[string]interface{}{
+							"path": map[string]interface{}{
+								"type":  gatewayPathMatchPathPrefix,
+								"value": path.Prefix,
+							},
+						},
+					},
+					"backendRefs": gatewayBackendRefs(path.ServiceName, path.ServicePort),
+				})
+			}
+		}
+
+		routes = append(routes, newExposeObject(ctx, harbor, HTTPRouteGVK, name, map[string]interface{}{
+			"parentRefs": gatewayParentRefs(harbor),
+			"hostnames":  gatewayHostnames(host),
+			"rules":      rules,
+		}))
+	}
+
+	return routes
+}
+
+// getInternalTLSCertificateAuthority returns the certificate authority of internal TLS, from the secret of core.
+func (r *Reconciler) getInternalTLSCertificateAuthority(ctx context.Context, harbor *goharborv1alpha1.Harbor) (string, error) {
+	if !harbor.IsInternalTLSEnabled() {
+		return "", nil
+	}
+
+	secretName := harbor.InternalTLSSecretName(goharborv1alpha1.CoreName)
+	secret := &corev1.Secret{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: harbor.GetNamespace(), Name: secretName}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", errors.Errorf("internal TLS secret %s not found", secretName)
+		}
+
+		return "", errors.Wrapf(err, "cannot get internal TLS secret %s", secretName)
+	}
+
+	return string(secret.Data[internalTLSCertificateAuthKey]), nil
+}
+
+// exposeResources returns the routes to apply by kind, empty for kinds not used by the type of exposure.
+func (r *Reconciler) exposeResources(ctx context.Context, harbor *goharborv1alpha1.Harbor) (map[schema.GroupVersionKind][]components.Resource, error) {
+	resources := map[schema.GroupVersionKind][]components.Resource{
+		RouteGVK:           {},
+		HTTPRouteGVK:       {},
+		TLSRouteGVK:        {},
+		VirtualServiceGVK:  {},
+		DestinationRuleGVK: {},
+	}
+
+	var routes []*unstructured.Unstructured
+
+	switch harbor.GetExposeType() {
+	case goharborv1alpha1.ExposeTypeRoute:
+		ca, err := r.getInternalTLSCertificateAuthority(ctx, harbor)
+		if err != nil {
+			return nil, err
+		}
+
+		routes = Routes(ctx, harbor, ca)
+	case goharborv1alpha1.ExposeTypeGateway:
+		routes = GatewayRoutes(ctx, harbor)
+	case goharborv1alpha1.ExposeTypeIngress:
+		if harbor.IsServiceMeshEnabled() {
+			routes = append(VirtualServices(ctx, harbor), DestinationRules(ctx, harbor)...)
+		}
+	}
+
+	for _, route := range routes {
+		gvk := route.GroupVersionKind()
+		resources[gvk] = append(resources[gvk], route)
+	}
+
+	return resources, nil
+}
+
+// +kubebuilder:rbac:groups="route.openshift.io",resources="routes",verbs=get;list;watch;update;patch;create;delete
+// +kubebuilder:rbac:groups="route.openshift.io",resources="routes/custom-host",verbs=create
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources="httproutes",verbs=get;list;watch;update;patch;create;delete
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources="tlsroutes",verbs=get;list;watch;update;patch;create;delete
+// +kubebuilder:rbac:groups="networking.istio.io",resources="virtualservices",verbs=get;list;watch;update;patch;create;delete
+// +kubebuilder:rbac:groups="networking.istio.io",resources="destinationrules",verbs=get;list;watch;update;patch;create;delete
+
+// ApplyExpose applies the routes of the type of exposure and deletes the resources of other types:
+// ingresses of components, routes, and the nginx proxy.
+func (r *Reconciler) ApplyExpose(ctx context.Context, harbor *goharborv1alpha1.Harbor) error {
+	ctx = components.WithComponent(ctx, goharborv1alpha1.ExposeName)
+
+	resources, err := r.exposeResources(ctx, harbor)
+	if err != nil {
+		return errors.Wrap(err, "cannot get routes")
+	}
+
+	var g errgroup.Group
+
+	for gvk, routes := range resources {
+		gvk, routes := gvk, routes
+
+		g.Go(func() error {
+			if len(routes) == 0 {
+				_, err := r.DeleteResourceCollection(ctx, harbor, goharborv1alpha1.ExposeName, gvk)
+				return errors.Wrapf(err, "cannot delete %s", gvk.Kind)
+			}
+
+			err := r.ApplyResources(ctx, harbor, routes, func() components.Resource {
+				result := &unstructured.Unstructured{}
+				result.SetGroupVersionKind(gvk)
+
+				return result
+			}, mutateUnstructured)
+
+			return errors.Wrapf(err, "cannot apply %s", gvk.Kind)
+		})
+	}
+
+	if !harbor.IsIngressEnabled() {
+		for _, componentName := range ingressComponents {
+			componentName := componentName
+
+			g.Go(func() error {
+				_, err := r.DeleteResourceCollection(ctx, harbor, componentName, r.IngressGVK())
+				return errors.Wrapf(err, "cannot delete ingresses of %s", componentName)
+			})
+		}
+	}
+
+	if harbor.IsIngressEnabled() && harbor.Spec.InternalEndpoint == nil {
+		for _, componentName := range ingressComponents {
+			componentName := componentName
+
+			g.Go(func() error {
+				err := r.deleteInternalIngress(ctx, harbor, componentName)
+				return errors.Wrapf(err, "cannot delete internal ingress of %s", componentName)
+			})
+		}
+	}
+
+	if !harbor.IsExposeProxyEnabled() {
+		g.Go(func() error {
+			err := r.DeleteComponent(ctx, harbor, goharborv1alpha1.NginxName)
+			return errors.Wrap(err, "cannot delete nginx")
+		})
+	}
+
+	return g.Wait()
+}
+
+// deleteInternalIngress deletes the ingress of the component on the internal endpoint, once the endpoint is removed.
+func (r *Reconciler) deleteInternalIngress(ctx context.Context, harbor *goharborv1alpha1.Harbor, componentName string) error {
+	ingress := &unstructured.Unstructured{}
+	ingress.SetGroupVersionKind(r.IngressGVK())
+	ingress.SetName(harbor.NormalizeComponentName(fmt.Sprintf("%s-internal", componentName)))
+	ingress.SetNamespace(harbor.GetNamespace())
+
+	err := r.Client.Delete(ctx, ingress)
+	if err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	r.resourceEvent(harbor, EventReasonResourceDeleted, ingress)
+
+	return nil
+}
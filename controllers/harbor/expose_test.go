@@ -0,0 +1,328 @@
+package harbor
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/expose"
+	harbor_core "github.com/goharbor/harbor-operator/controllers/harbor/components/harbor-core"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/notary"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/registry"
+)
+
+var _ = Describe("Ingresses", func() {
+	var ctx context.Context
+	var h *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		_, ctx, h = setupHarborTest(context.TODO())
+
+		h.Spec.Components = goharborv1alpha1.HarborComponents{
+			Registry: &goharborv1alpha1.RegistryComponent{},
+		}
+	})
+
+	It("Should not be annotated by default", func() {
+		Expect(h.GetIngressController()).To(Equal(goharborv1alpha1.IngressControllerDefault))
+		Expect(expose.IngressAnnotations(h, goharborv1alpha1.CoreName, true)).To(BeEmpty())
+
+		h.Spec.InternalTLS = &goharborv1alpha1.InternalTLSSpec{Enabled: true}
+		Expect(expose.IngressAnnotations(h, goharborv1alpha1.CoreName, true)).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/backend-protocol", "HTTPS"))
+		Expect(expose.IngressAnnotations(h, goharborv1alpha1.NotaryName, true)).To(BeEmpty())
+	})
+
+	It("Should apply the nginx preset", func() {
+		h.Spec.Expose = &goharborv1alpha1.ExposeSpec{
+			Ingress: &goharborv1alpha1.IngressSpec{
+				Controller: goharborv1alpha1.IngressControllerNginx,
+			},
+		}
+
+		annotations := expose.IngressAnnotations(h, goharborv1alpha1.RegistryName, true)
+		Expect(annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/proxy-body-size", "0"))
+		Expect(annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/proxy-request-buffering", "off"))
+
+		annotations = expose.IngressAnnotations(h, goharborv1alpha1.PortalName, true)
+		Expect(annotations).ToNot(HaveKey("nginx.ingress.kubernetes.io/proxy-request-buffering"))
+	})
+
+	It("Should override presets with the class and annotations of the spec", func() {
+		h.Spec.Expose = &goharborv1alpha1.ExposeSpec{
+			Ingress: &goharborv1alpha1.IngressSpec{
+				IngressClassName: "internal",
+				Controller:       goharborv1alpha1.IngressControllerContour,
+				Annotations: map[string]string{
+					"projectcontour.io/response-timeout": "1h",
+				},
+			},
+		}
+
+		r, err := registry.New(ctx, h, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		ingresses := r.GetIngresses(ctx)
+		Expect(ingresses).To(HaveLen(1))
+		Expect(ingresses[0].GetAnnotations()).To(Equal(map[string]string{
+			"kubernetes.io/ingress.class":              "internal",
+			"projectcontour.io/response-timeout":       "1h",
+			"ingress.kubernetes.io/force-ssl-redirect": "true",
+		}))
+	})
+
+	It("Should route traefik over TLS", func() {
+		h.Spec.Expose = &goharborv1alpha1.ExposeSpec{
+			Ingress: &goharborv1alpha1.IngressSpec{
+				Controller: goharborv1alpha1.IngressControllerTraefik,
+			},
+		}
+
+		Expect(expose.IngressAnnotations(h, goharborv1alpha1.NotaryName, true)).To(HaveKeyWithValue("traefik.ingress.kubernetes.io/router.tls", "true"))
+		Expect(expose.IngressAnnotations(h, goharborv1alpha1.NotaryName, false)).To(BeEmpty())
+	})
+
+	It("Should forward requests over TLS to services with internal TLS", func() {
+		h.Spec.Expose = &goharborv1alpha1.ExposeSpec{
+			Ingress: &goharborv1alpha1.IngressSpec{
+				Controller: goharborv1alpha1.IngressControllerTraefik,
+			},
+		}
+
+		r, err := registry.New(ctx, h, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.GetServices(ctx)[0].GetAnnotations()).To(BeEmpty())
+
+		h.Spec.InternalTLS = &goharborv1alpha1.InternalTLSSpec{Enabled: true}
+		Expect(r.GetServices(ctx)[0].GetAnnotations()).To(HaveKeyWithValue("traefik.ingress.kubernetes.io/service.serversscheme", "https"))
+
+		h.Spec.Expose.Ingress.Controller = goharborv1alpha1.IngressControllerContour
+		Expect(r.GetServices(ctx)[0].GetAnnotations()).To(HaveKeyWithValue("projectcontour.io/upstream-protocol.tls", "443"))
+		Expect(expose.ServiceAnnotations(h, goharborv1alpha1.JobServiceName)).To(BeEmpty())
+	})
+
+	It("Should pass TLS through to notary server", func() {
+		h.Spec.TLSSecretName = "public-tls"
+		h.Spec.Components.Notary = &goharborv1alpha1.NotaryComponent{
+			PublicURL: "https://notary.example.com",
+		}
+
+		Expect(expose.NotaryServesTLS(h)).To(BeFalse())
+		Expect(notary.URL(h)).To(Equal("http://harbor-notary-server"))
+
+		h.Spec.Expose = &goharborv1alpha1.ExposeSpec{
+			Ingress: &goharborv1alpha1.IngressSpec{
+				Controller: goharborv1alpha1.IngressControllerNginx,
+			},
+		}
+
+		Expect(expose.NotaryServesTLS(h)).To(BeTrue())
+		Expect(notary.URL(h)).To(Equal("https://notary.example.com"))
+		Expect(expose.IngressAnnotations(h, goharborv1alpha1.NotaryName, true)).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/ssl-passthrough", "true"))
+
+		n, err := notary.New(ctx, h, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		ingresses := n.GetIngresses(ctx)
+		Expect(ingresses).To(HaveLen(1))
+		Expect(ingresses[0].Spec.Rules[0].HTTP.Paths[0].Backend.ServicePort.IntValue()).To(Equal(443))
+		Expect(n.GetServices(ctx)[0].Spec.Ports[0].Port).To(BeEquivalentTo(443))
+
+		h.Spec.Expose.Ingress.Controller = goharborv1alpha1.IngressControllerContour
+		Expect(n.GetServices(ctx)[0].GetAnnotations()).To(HaveKeyWithValue("projectcontour.io/upstream-protocol.tls", "443"))
+
+		h.Spec.Components.Notary.PublicURL = "http://notary.example.com"
+		Expect(expose.NotaryServesTLS(h)).To(BeFalse())
+		Expect(n.GetServices(ctx)[0].Spec.Ports[0].Port).To(BeEquivalentTo(80))
+	})
+})
+
+var _ = Describe("Exposure", func() {
+	var ctx context.Context
+	var h *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		_, ctx, h = setupHarborTest(context.TODO())
+
+		h.Spec.Components = goharborv1alpha1.HarborComponents{
+			Core:     &goharborv1alpha1.CoreComponent{},
+			Registry: &goharborv1alpha1.RegistryComponent{},
+			Portal:   &goharborv1alpha1.PortalComponent{},
+			Notary: &goharborv1alpha1.NotaryComponent{
+				PublicURL: "https://notary.example.com",
+			},
+		}
+	})
+
+	It("Should render ingresses from the paths of components", func() {
+		Expect(h.GetExposeType()).To(Equal(goharborv1alpha1.ExposeTypeIngress))
+
+		ingress := expose.Ingress(h, goharborv1alpha1.CoreName, nil)
+		Expect(ingress).ToNot(BeNil())
+		Expect(ingress.Spec.TLS).To(HaveLen(1))
+		Expect(ingress.Spec.Rules).To(HaveLen(1))
+		Expect(ingress.Spec.Rules[0].Host).To(Equal("harbor.example.com"))
+
+		prefixes := []string{}
+		for _, path := range ingress.Spec.Rules[0].HTTP.Paths {
+			prefixes = append(prefixes, path.Path)
+			Expect(path.Backend.ServiceName).To(Equal("harbor-core"))
+		}
+		Expect(prefixes).To(Equal([]string{"/api", "/c", "/service"}))
+
+		Expect(expose.Ingress(h, goharborv1alpha1.ChartMuseumName, nil)).To(BeNil())
+
+		h.Spec.Expose = &goharborv1alpha1.ExposeSpec{Type: goharborv1alpha1.ExposeTypeNodePort}
+		Expect(expose.Ingress(h, goharborv1alpha1.CoreName, nil)).To(BeNil())
+	})
+
+	It("Should route additional host names and the internal endpoint", func() {
+		h.Spec.PublicURL = "https://harbor.example.com:8443"
+		h.Spec.AdditionalHostnames = []string{"registry.example.com"}
+		h.Spec.InternalEndpoint = &goharborv1alpha1.InternalEndpointSpec{
+			URL:              "http://harbor.internal",
+			IngressClassName: "internal",
+		}
+
+		ingresses := expose.Ingresses(h, goharborv1alpha1.RegistryName, map[string]string{"app": "registry"})
+		Expect(ingresses).To(HaveLen(2))
+
+		Expect(ingresses[0].GetName()).To(Equal("harbor-registry"))
+		Expect(ingresses[0].Spec.Rules).To(HaveLen(2))
+		Expect(ingresses[0].Spec.Rules[0].Host).To(Equal("harbor.example.com"))
+		Expect(ingresses[0].Spec.Rules[1].Host).To(Equal("registry.example.com"))
+		Expect(ingresses[0].Spec.TLS[0].Hosts).To(Equal([]string{"harbor.example.com", "registry.example.com"}))
+
+		Expect(ingresses[1].GetName()).To(Equal("harbor-registry-internal"))
+		Expect(ingresses[1].Spec.Rules[0].Host).To(Equal("harbor.internal"))
+		Expect(ingresses[1].Spec.TLS).To(BeEmpty())
+		Expect(ingresses[1].GetAnnotations()).To(HaveKeyWithValue("kubernetes.io/ingress.class", "internal"))
+		Expect(ingresses[0].GetAnnotations()).ToNot(HaveKey("kubernetes.io/ingress.class"))
+
+		Expect(expose.Ingresses(h, goharborv1alpha1.NotaryName, nil)).To(HaveLen(1))
+
+		Expect(harbor_core.PublicHosts(h)).To(Equal([]string{"harbor.example.com", "registry.example.com", "notary.example.com"}))
+
+		h.Spec.InternalEndpoint.URL = "https://harbor.internal"
+		Expect(harbor_core.PublicHosts(h)).To(ContainElement("harbor.internal"))
+
+		h.Spec.InternalEndpoint.TLSSecretName = "internal-tls"
+		Expect(harbor_core.PublicHosts(h)).ToNot(ContainElement("harbor.internal"))
+	})
+
+	It("Should send registry clients to the token service of the internal endpoint", func() {
+		h.Spec.InternalEndpoint = &goharborv1alpha1.InternalEndpointSpec{
+			URL:        "https://harbor.internal",
+			TokenRealm: true,
+		}
+
+		r, err := registry.New(ctx, h, &components.Option{})
+		Expect(err).ToNot(HaveOccurred())
+
+		env := map[string]string{}
+		for _, e := range r.GetDeployments(ctx)[0].Spec.Template.Spec.Containers[0].Env {
+			env[e.Name] = e.Value
+		}
+
+		Expect(env).To(HaveKeyWithValue("REGISTRY_AUTH_TOKEN_REALM", "https://harbor.internal/service/token"))
+		Expect(env).To(HaveKeyWithValue("REGISTRY_HTTP_HOST", ""))
+	})
+
+	It("Should expose the nginx proxy with a LoadBalancer service", func() {
+		h.Spec.Expose = &goharborv1alpha1.ExposeSpec{
+			Type: goharborv1alpha1.ExposeTypeLoadBalancer,
+			Service: &goharborv1alpha1.ExposeServiceSpec{
+				Annotations:           map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+				LoadBalancerIP:        "10.0.0.10",
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+			},
+		}
+
+		c, err := components.GetComponents(ctx, h)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.Nginx).ToNot(BeNil())
+
+		services := c.Nginx.Component.GetServices(ctx)
+		Expect(services).To(HaveLen(1))
+		Expect(services[0].Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(services[0].Spec.LoadBalancerIP).To(Equal("10.0.0.10"))
+		Expect(services[0].Spec.ExternalTrafficPolicy).To(Equal(corev1.ServiceExternalTrafficPolicyTypeLocal))
+		Expect(services[0].GetAnnotations()).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-internal", "true"))
+
+		config := string(c.Nginx.Component.GetConfigMaps(ctx)[0].BinaryData["nginx.conf"])
+		Expect(config).To(ContainSubstring("server_name harbor.example.com;"))
+		Expect(config).To(ContainSubstring("proxy_pass http://harbor-core:80;"))
+		Expect(config).To(ContainSubstring("proxy_pass http://harbor-notary-server:80;"))
+		Expect(config).To(ContainSubstring("ssl_certificate /etc/nginx/cert/tls.crt;"))
+
+		Expect(c.Core.Component.GetIngresses(ctx)).To(BeEmpty())
+
+		h.Spec.Expose.Type = goharborv1alpha1.ExposeTypeIngress
+		c, err = components.GetComponents(ctx, h)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.Nginx).To(BeNil())
+	})
+
+	It("Should render a route per path", func() {
+		h.Spec.Expose = &goharborv1alpha1.ExposeSpec{Type: goharborv1alpha1.ExposeTypeRoute}
+
+		routes := map[string]map[string]interface{}{}
+		for _, route := range Routes(ctx, h, "") {
+			Expect(route.GroupVersionKind()).To(Equal(RouteGVK))
+			routes[route.GetName()] = route.Object["spec"].(map[string]interface{})
+		}
+
+		Expect(routes).To(HaveLen(6))
+		Expect(routes).To(HaveKey("harbor-core-api"))
+		Expect(routes["harbor-registry-v2"]).To(HaveKeyWithValue("path", "/v2"))
+		Expect(routes["harbor-portal"]).To(HaveKeyWithValue("host", "harbor.example.com"))
+		Expect(routes["harbor-notary"]).To(HaveKeyWithValue("host", "notary.example.com"))
+		Expect(routes["harbor-notary"]["tls"]).To(HaveKeyWithValue("termination", "edge"))
+
+		h.Spec.InternalTLS = &goharborv1alpha1.InternalTLSSpec{Enabled: true}
+
+		for _, route := range Routes(ctx, h, "ca") {
+			tls := route.Object["spec"].(map[string]interface{})["tls"]
+
+			if route.GetName() == "harbor-notary" {
+				Expect(tls).To(HaveKeyWithValue("termination", "edge"))
+			} else {
+				Expect(tls).To(HaveKeyWithValue("termination", "reencrypt"))
+				Expect(tls).To(HaveKeyWithValue("destinationCACertificate", "ca"))
+			}
+		}
+	})
+
+	It("Should render gateway routes", func() {
+		h.Spec.Expose = &goharborv1alpha1.ExposeSpec{
+			Type: goharborv1alpha1.ExposeTypeGateway,
+			Gateway: &goharborv1alpha1.ExposeGatewaySpec{
+				ParentRefs: []goharborv1alpha1.GatewayParentReference{{Name: "public", Namespace: "gateways"}},
+			},
+		}
+
+		routes := GatewayRoutes(ctx, h)
+		Expect(routes).To(HaveLen(2))
+		Expect(routes[0].GroupVersionKind()).To(Equal(HTTPRouteGVK))
+		Expect(routes[0].GetName()).To(Equal("harbor"))
+		Expect(routes[0].Object["spec"]).To(HaveKeyWithValue("hostnames", []interface{}{"harbor.example.com"}))
+		Expect(routes[0].Object["spec"]).To(HaveKeyWithValue("parentRefs", []interface{}{
+			map[string]interface{}{"name": "public", "namespace": "gateways"},
+		}))
+		Expect(routes[0].Object["spec"].(map[string]interface{})["rules"]).To(HaveLen(5))
+		Expect(routes[1].GetName()).To(Equal("harbor-notary"))
+
+		h.Spec.Expose.Gateway.TLSPassthrough = true
+		Expect(h.IsExposeProxyEnabled()).To(BeTrue())
+
+		kinds := []string{}
+		for _, route := range GatewayRoutes(ctx, h) {
+			kinds = append(kinds, route.GetKind())
+		}
+		Expect(kinds).To(Equal([]string{"TLSRoute", "HTTPRoute", "TLSRoute", "HTTPRoute"}))
+	})
+})
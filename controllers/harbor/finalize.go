@@ -0,0 +1,336 @@
+package harbor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/backup"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+)
+
+const (
+	FinalBackupRequeueWait = 10 * time.Second
+	FinalBackupName        = "final-backup"
+)
+
+var (
+	pvcGVK = corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim")
+
+	// gvkToRetain lists kinds holding data which should survive the Harbor with the Retain deletion policy
+	gvkToRetain = []schema.GroupVersionKind{
+		pvcGVK,
+		corev1.SchemeGroupVersion.WithKind("Secret"),
+	}
+)
+
+func hasFinalizer(harbor *goharborv1alpha1.Harbor) bool {
+	for _, finalizer := range harbor.GetFinalizers() {
+		if finalizer == goharborv1alpha1.HarborFinalizer {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeFinalizer(harbor *goharborv1alpha1.Harbor) {
+	finalizers := []string{}
+
+	for _, finalizer := range harbor.GetFinalizers() {
+		if finalizer != goharborv1alpha1.HarborFinalizer {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+
+	harbor.SetFinalizers(finalizers)
+}
+
+func deletionPolicy(harbor *goharborv1alpha1.Harbor) goharborv1alpha1.DeletionPolicy {
+	if harbor.Spec.DeletionPolicy == "" {
+		return goharborv1alpha1.DeletionPolicyDelete
+	}
+
+	return harbor.Spec.DeletionPolicy
+}
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=update;patch
+
+// AddFinalizer ensures the finalizer is registered on the Harbor, so Finalize is called before the Harbor is removed.
+func (r *Reconciler) AddFinalizer(ctx context.Context, harbor *goharborv1alpha1.Harbor) error {
+	if hasFinalizer(harbor) {
+		return nil
+	}
+
+	harbor.SetFinalizers(append(harbor.GetFinalizers(), goharborv1alpha1.HarborFinalizer))
+
+	err := r.Client.Update(ctx, harbor)
+
+	return errors.Wrap(err, "cannot add finalizer")
+}
+
+// Finalize runs the final backup if any, releases resources according to the deletion policy
+// and finally removes the finalizer.
+func (r *Reconciler) Finalize(ctx context.Context, result *ctrl.Result, harbor *goharborv1alpha1.Harbor) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "finalize")
+	defer span.Finish()
+
+	if !hasFinalizer(harbor) {
+		return nil
+	}
+
+	if harbor.Spec.FinalBackup != nil {
+		rolledOut, err := r.ApplyReadOnly(ctx, result, harbor)
+		if err != nil {
+			result.RequeueAfter = FinalBackupRequeueWait
+
+			err := r.UpdateCondition(ctx, harbor, goharborv1alpha1.FinalizedConditionType, corev1.ConditionFalse, "read-only-failed", err.Error())
+			if err != nil {
+				return errors.Wrapf(err, "value=%s", corev1.ConditionFalse)
+			}
+
+			return r.UpdateStatus(ctx, result, harbor)
+		}
+
+		if !rolledOut {
+			// Backup jobs would race with writes until core runs in read-only mode
+			result.RequeueAfter = FinalBackupRequeueWait
+
+			err := r.UpdateCondition(ctx, harbor, goharborv1alpha1.FinalizedConditionType, corev1.ConditionFalse, "waiting-read-only", "waiting for harbor to roll out in read-only mode")
+			if err != nil {
+				return errors.Wrapf(err, "value=%s", corev1.ConditionFalse)
+			}
+
+			return r.UpdateStatus(ctx, result, harbor)
+		}
+
+		done, err := r.RunFinalBackup(ctx, harbor)
+		if err != nil {
+			result.RequeueAfter = FinalBackupRequeueWait
+
+			err := r.UpdateCondition(ctx, harbor, goharborv1alpha1.FinalizedConditionType, corev1.ConditionFalse, "backup-failed", err.Error())
+			if err != nil {
+				return errors.Wrapf(err, "value=%s", corev1.ConditionFalse)
+			}
+
+			return r.UpdateStatus(ctx, result, harbor)
+		}
+
+		if !done {
+			result.RequeueAfter = FinalBackupRequeueWait
+
+			err := r.UpdateCondition(ctx, harbor, goharborv1alpha1.FinalizedConditionType, corev1.ConditionFalse, "backup-running", "waiting for final backup completion")
+			if err != nil {
+				return errors.Wrapf(err, "value=%s", corev1.ConditionFalse)
+			}
+
+			return r.UpdateStatus(ctx, result, harbor)
+		}
+	}
+
+	policy := deletionPolicy(harbor)
+
+	err := r.ReleaseResources(ctx, harbor, policy)
+	if err != nil {
+		result.Requeue = true
+
+		err := r.UpdateCondition(ctx, harbor, goharborv1alpha1.FinalizedConditionType, corev1.ConditionFalse, "release-failed", err.Error())
+		if err != nil {
+			return errors.Wrapf(err, "value=%s", corev1.ConditionFalse)
+		}
+
+		return r.UpdateStatus(ctx, result, harbor)
+	}
+
+	err = r.UpdateCondition(ctx, harbor, goharborv1alpha1.FinalizedConditionType, corev1.ConditionTrue, "released", fmt.Sprintf("resources released with %s policy", policy))
+	if err != nil {
+		return errors.Wrapf(err, "value=%s", corev1.ConditionTrue)
+	}
+
+	err = r.UpdateStatus(ctx, result, harbor)
+	if err != nil || result.Requeue {
+		return err
+	}
+
+	removeFinalizer(harbor)
+
+	err = r.Client.Update(ctx, harbor)
+	if err != nil {
+		result.Requeue = true
+
+		return errors.Wrap(err, "cannot remove finalizer")
+	}
+
+	logger.Get(ctx).Info("finalizer removed", "DeletionPolicy", policy)
+
+	return nil
+}
+
+// +kubebuilder:rbac:groups="",resources="persistentvolumeclaims",verbs=get;list;update;patch
+// +kubebuilder:rbac:groups="",resources="configmaps",verbs=update;patch
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=update;patch
+// +kubebuilder:rbac:groups="",resources="services",verbs=update;patch
+// +kubebuilder:rbac:groups="apps",resources="deployments",verbs=update;patch
+// +kubebuilder:rbac:groups="cert-manager.io",resources="certificates",verbs=update;patch
+// +kubebuilder:rbac:groups="networking.k8s.io",resources="ingresses",verbs=update;patch
+
+// ReleaseResources removes the Harbor owner reference from resources to keep, according to the policy,
+// so they are not garbage collected with the Harbor.
+func (r *Reconciler) ReleaseResources(ctx context.Context, harbor *goharborv1alpha1.Harbor, policy goharborv1alpha1.DeletionPolicy) error {
+	var kinds []schema.GroupVersionKind
+
+	switch policy {
+	case goharborv1alpha1.DeletionPolicyDelete:
+		return nil
+	case goharborv1alpha1.DeletionPolicyRetain:
+		kinds = gvkToRetain
+
+		if r.Config.CertManager {
+			kinds = append(append([]schema.GroupVersionKind{}, gvkToRetain...), r.CertificateGVK())
+		}
+	case goharborv1alpha1.DeletionPolicyOrphan:
+		kinds = append([]schema.GroupVersionKind{pvcGVK}, r.gvksToDelete()...)
+	default:
+		return errors.Errorf("unsupported deletion policy %s", policy)
+	}
+
+	for _, gvk := range kinds {
+		err := r.OrphanResources(ctx, harbor, gvk)
+		if err != nil {
+			return errors.Wrapf(err, "cannot orphan %s", gvk.Kind)
+		}
+	}
+
+	return nil
+}
+
+// OrphanResources removes the Harbor owner reference from all resources of the given kind.
+func (r *Reconciler) OrphanResources(ctx context.Context, harbor *goharborv1alpha1.Harbor, gvk schema.GroupVersionKind) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk)
+
+	err := r.Client.List(ctx, list, client.InNamespace(harbor.GetNamespace()))
+	if err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			// Kind not served by the cluster
+			return nil
+		}
+
+		return errors.Wrap(err, "cannot list resources")
+	}
+
+	for i := range list.Items {
+		item := &list.Items[i]
+
+		owners := item.GetOwnerReferences()
+		references := make([]metav1.OwnerReference, 0, len(owners))
+
+		for _, owner := range owners {
+			if owner.UID != harbor.GetUID() {
+				references = append(references, owner)
+			}
+		}
+
+		if len(references) == len(owners) {
+			continue
+		}
+
+		patch := client.MergeFrom(item.DeepCopy())
+
+		item.SetOwnerReferences(references)
+
+		err := r.Client.Patch(ctx, item, patch)
+		if err != nil {
+			return errors.Wrapf(err, "cannot release %s", item.GetName())
+		}
+
+		logger.Get(ctx).Info("resource released", "GVK.Kind", gvk.Kind, "Resource.Name", item.GetName())
+	}
+
+	return nil
+}
+
+// ApplyReadOnly switches the Harbor to read-only and returns whether core pods run in read-only mode.
+// A deleted Harbor is only finalized by Reconcile, so the new generation is applied here.
+func (r *Reconciler) ApplyReadOnly(ctx context.Context, result *ctrl.Result, harbor *goharborv1alpha1.Harbor) (bool, error) {
+	err := backup.SetHarborReadOnly(ctx, r.Client, harbor, true)
+	if err != nil {
+		return false, errors.Wrap(err, "cannot switch harbor to read-only")
+	}
+
+	rolledOut, err := backup.IsHarborRolledOut(ctx, r.Client, harbor)
+	if err != nil || rolledOut {
+		return rolledOut, errors.Wrap(err, "cannot check harbor rollout")
+	}
+
+	err = r.UpdateAppliedStatus(ctx, result, harbor)
+	if err != nil {
+		return false, errors.Wrapf(err, "type=%s", goharborv1alpha1.AppliedConditionType)
+	}
+
+	err = r.UpdateReadyStatus(ctx, result, harbor)
+
+	return false, errors.Wrapf(err, "type=%s", goharborv1alpha1.ReadyConditionType)
+}
+
+// +kubebuilder:rbac:groups="batch",resources="jobs",verbs=get;list;watch;create
+
+// RunFinalBackup creates the final backup jobs if needed and returns whether they completed successfully.
+// Jobs are not owned by the Harbor, so they are kept as a record of the backup.
+func (r *Reconciler) RunFinalBackup(ctx context.Context, harbor *goharborv1alpha1.Harbor) (bool, error) {
+	progress, err := backup.RunJobs(ctx, r.Client, r.Scheme, nil, r.GetFinalBackupJobs(ctx, harbor))
+	if err != nil {
+		return false, errors.Wrap(err, "cannot run final backup jobs")
+	}
+
+	if progress.IsFailed() {
+		return false, errors.Errorf("%s, remove finalBackup from the spec to skip it", progress.FailureMessage())
+	}
+
+	return progress.IsCompleted(), nil
+}
+
+// GetFinalBackup returns the backup described by the finalBackup of the Harbor.
+func GetFinalBackup(harbor *goharborv1alpha1.Harbor) *goharborv1alpha1.HarborBackup {
+	return &goharborv1alpha1.HarborBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      harbor.NormalizeComponentName(FinalBackupName),
+			Namespace: harbor.GetNamespace(),
+		},
+		Spec: goharborv1alpha1.HarborBackupSpec{
+			HarborName: harbor.GetName(),
+			Target:     *harbor.Spec.FinalBackup.Target.DeepCopy(),
+			Images:     harbor.Spec.FinalBackup.Images,
+		},
+	}
+}
+
+// GetFinalBackupJobs returns the jobs saving databases, the core secret key and storage of the Harbor,
+// as a HarborBackup would.
+func (r *Reconciler) GetFinalBackupJobs(ctx context.Context, harbor *goharborv1alpha1.Harbor) []*batchv1.Job {
+	finalBackup := GetFinalBackup(harbor)
+	items := backup.Items(harbor, finalBackup.Spec.Images)
+
+	jobs := make([]*batchv1.Job, len(items))
+
+	for i, item := range items {
+		jobs[i] = backup.GetJob(backup.BackupDirection, finalBackup.GetName(), finalBackup, harbor, item)
+		jobs[i].Labels[goharborv1alpha1.OperatorNameLabel] = r.GetName()
+		jobs[i].Labels[goharborv1alpha1.OperatorVersionLabel] = r.GetVersion()
+	}
+
+	return jobs
+}
@@ -0,0 +1,123 @@
+package harbor
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+var _ = Describe("finalize", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var h *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		r, ctx = setupTest(context.TODO())
+
+		h = &goharborv1alpha1.Harbor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "harbor",
+				Namespace:  "ns",
+				Finalizers: []string{"other", goharborv1alpha1.HarborFinalizer},
+			},
+		}
+	})
+
+	It("Should remove only the operator finalizer", func() {
+		Expect(hasFinalizer(h)).To(BeTrue())
+
+		removeFinalizer(h)
+
+		Expect(hasFinalizer(h)).To(BeFalse())
+		Expect(h.GetFinalizers()).To(ConsistOf("other"))
+	})
+
+	It("Should default to Delete policy", func() {
+		Expect(deletionPolicy(h)).To(Equal(goharborv1alpha1.DeletionPolicyDelete))
+
+		h.Spec.DeletionPolicy = goharborv1alpha1.DeletionPolicyRetain
+
+		Expect(deletionPolicy(h)).To(Equal(goharborv1alpha1.DeletionPolicyRetain))
+	})
+
+	It("Should not release any resource with Delete policy", func() {
+		Expect(r.ReleaseResources(ctx, h, goharborv1alpha1.DeletionPolicyDelete)).To(Succeed())
+	})
+
+	It("Should back up databases and the secret key in final backup jobs", func() {
+		h.Spec.Components.Core = &goharborv1alpha1.CoreComponent{DatabaseSecret: "core-database"}
+		h.Spec.FinalBackup = &goharborv1alpha1.FinalBackupJob{
+			Target: goharborv1alpha1.BackupTarget{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "backups"},
+			},
+		}
+
+		jobs := r.GetFinalBackupJobs(ctx, h)
+		Expect(jobs).To(HaveLen(2))
+
+		names := []string{}
+		for _, job := range jobs {
+			names = append(names, job.GetName())
+			Expect(job.GetNamespace()).To(Equal("ns"))
+			Expect(job.GetOwnerReferences()).To(BeEmpty())
+			Expect(job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("backups"))
+		}
+		Expect(names).To(ConsistOf("harbor-final-backup-core-database", "harbor-final-backup-core-secretkey"))
+
+		container := jobs[0].Spec.Template.Spec.Containers[0]
+		Expect(container.Command[0]).To(Equal("pg_dump"))
+		Expect(container.Env[0].ValueFrom.SecretKeyRef.Name).To(Equal("core-database"))
+		Expect(container.VolumeMounts[0].SubPath).To(Equal("harbor-final-backup"))
+	})
+
+	It("Should run final backup jobs once core runs in read-only mode", func() {
+		h.SetGeneration(2)
+		h.Spec.ReadOnly = true
+		h.Spec.Components.Core = &goharborv1alpha1.CoreComponent{DatabaseSecret: "core-database"}
+		h.Spec.FinalBackup = &goharborv1alpha1.FinalBackupJob{
+			Target: goharborv1alpha1.BackupTarget{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "backups"},
+			},
+		}
+		h.Status.ObservedGeneration = 2
+		h.Status.Conditions = []goharborv1alpha1.HarborCondition{
+			{Type: goharborv1alpha1.AppliedConditionType, Status: corev1.ConditionTrue},
+			{Type: goharborv1alpha1.ReadyConditionType, Status: corev1.ConditionTrue},
+		}
+
+		core := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      h.NormalizeComponentName(goharborv1alpha1.CoreName),
+				Namespace: "ns",
+			},
+		}
+		core.Spec.Template.Annotations = map[string]string{goharborv1alpha1.ReadOnlyAnnotation: "true"}
+		core.Status.Replicas = 1
+		core.Status.UpdatedReplicas = 1
+		core.Status.AvailableReplicas = 1
+
+		r.Client = fake.NewFakeClientWithScheme(r.Scheme, h, core)
+
+		var result ctrl.Result
+		Expect(r.Finalize(ctx, &result, h)).To(Succeed())
+		Expect(result.RequeueAfter).To(Equal(FinalBackupRequeueWait))
+		Expect(h.GetFinalizers()).To(ContainElement(goharborv1alpha1.HarborFinalizer))
+
+		condition := r.GetCondition(ctx, h, goharborv1alpha1.FinalizedConditionType)
+		Expect(condition.Reason).To(Equal("backup-running"))
+
+		jobs := &batchv1.JobList{}
+		Expect(r.Client.List(ctx, jobs)).To(Succeed())
+		Expect(jobs.Items).To(HaveLen(2))
+	})
+})
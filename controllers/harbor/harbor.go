@@ -5,15 +5,18 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
-	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	netv1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
 	"github.com/goharbor/harbor-operator/pkg/factories/logger"
@@ -27,6 +30,11 @@ type Config struct {
 	ClassName            string
 	ConcurrentReconciles int
 	WatchChildren        bool
+	CertManager          bool
+	CertManagerVersion   string
+	IngressVersion       string
+	// IngressDisabled is set when networking.k8s.io ingresses are not served, components cannot be exposed with ingresses
+	IngressDisabled bool
 }
 
 // Reconciler reconciles a Harbor object
@@ -40,6 +48,7 @@ type Reconciler struct {
 	Scheme *runtime.Scheme
 
 	RestConfig *rest.Config
+	Recorder   record.EventRecorder
 
 	Config Config
 }
@@ -56,20 +65,107 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.Client = mgr.GetClient()
 	r.Scheme = mgr.GetScheme()
 	r.RestConfig = mgr.GetConfig()
+	r.Recorder = mgr.GetEventRecorderFor(r.GetName())
 
-	return ctrl.NewControllerManagedBy(mgr).
+	if r.Config.CertManager {
+		err := r.setupCertManager()
+		if err != nil {
+			return err
+		}
+	}
+
+	err := r.setupIngress()
+	if err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		WithEventFilter(r.GetEventFilter()).
 		For(&goharborv1alpha1.Harbor{}).
-		Owns(&appsv1.Deployment{}).
-		Owns(&certv1.Certificate{}).
+		Owns(&appsv1.Deployment{})
+
+	if r.Config.CertManager {
+		builder = builder.Owns(r.NewCertificateObject())
+	}
+
+	if !r.Config.IngressDisabled {
+		builder = builder.Owns(r.NewIngressObject())
+	}
+
+	c, err := builder.
 		Owns(&corev1.ConfigMap{}).
-		Owns(&netv1.Ingress{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.Service{}).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: r.Config.ConcurrentReconciles,
 		}).
-		Complete(r)
+		Build(r)
+	if err != nil {
+		return err
+	}
+
+	// Admin password secrets are neither owned nor annotated with the class, so the event filter would drop them:
+	// they are watched apart, and Harbors of other classes are skipped by adminPasswordSecretRequests.
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(r.adminPasswordSecretRequests),
+	})
+
+	return errors.Wrap(err, "cannot watch admin password secrets")
+}
+
+// setupCertManager detects the version of cert-manager API, cert-manager is disabled if it is not installed.
+func (r *Reconciler) setupCertManager() error {
+	client, err := discovery.NewDiscoveryClientForConfig(r.RestConfig)
+	if err != nil {
+		return errors.Wrap(err, "cannot create discovery client")
+	}
+
+	version, err := DetectCertManagerVersion(client)
+	if err != nil {
+		return errors.Wrap(err, "cannot detect cert-manager version")
+	}
+
+	if version == "" {
+		r.Log.Info("cert-manager certificates are not served, only SelfSigned and Provided certificates modes are available")
+
+		r.Config.CertManager = false
+
+		return nil
+	}
+
+	r.Log.Info("cert-manager detected", "Version", version)
+
+	r.Config.CertManagerVersion = version
+
+	return nil
+}
+
+// setupIngress detects the version of networking.k8s.io Ingress API, v1 being rendered when served.
+// Ingresses are disabled if none is served.
+func (r *Reconciler) setupIngress() error {
+	client, err := discovery.NewDiscoveryClientForConfig(r.RestConfig)
+	if err != nil {
+		return errors.Wrap(err, "cannot create discovery client")
+	}
+
+	version, err := DetectIngressVersion(client)
+	if err != nil {
+		return errors.Wrap(err, "cannot detect ingress version")
+	}
+
+	if version == "" {
+		r.Log.Info("networking.k8s.io ingresses are not served, components cannot be exposed with the Ingress type")
+
+		r.Config.IngressDisabled = true
+
+		return nil
+	}
+
+	r.Log.Info("ingress version detected", "Version", version)
+
+	r.Config.IngressVersion = version
+
+	return nil
 }
 
 func New(ctx context.Context, name, version string, config *Config) (*Reconciler, error) {
@@ -3,7 +3,10 @@ package harbor
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
@@ -12,6 +15,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
 )
 
 const (
@@ -64,9 +68,19 @@ func (r *Reconciler) GetHealth(ctx context.Context, harbor *goharborv1alpha1.Har
 	span, ctx := opentracing.StartSpanFromContext(ctx, "check")
 	defer span.Finish()
 
+	start := time.Now()
+	defer func() {
+		healthCheckDuration.WithLabelValues(harbor.GetNamespace(), harbor.GetName()).Observe(time.Since(start).Seconds())
+	}()
+
+	if harbor.IsInternalTLSEnabled() {
+		// The apiserver proxy does not verify certificates of services, core is called with its CA trusted
+		return r.getHealthWithInternalTLS(ctx, harbor)
+	}
+
 	config := rest.CopyConfig(r.RestConfig)
 	config.APIPath = "api"
-	config = rest.AddUserAgent(config, fmt.Sprintf("%s(%s)", r.GetName(), r.GetVersion()))
+	config = rest.AddUserAgent(config, harborapi.UserAgent(r.GetName(), r.GetVersion()))
 	config.NegotiatedSerializer = serializer.NewCodecFactory(r.Scheme)
 	config.GroupVersion = &corev1.SchemeGroupVersion
 
@@ -85,12 +99,71 @@ func (r *Reconciler) GetHealth(ctx context.Context, harbor *goharborv1alpha1.Har
 		SubResource("proxy").
 		Suffix(HarborHealthEndpoint).
 		DoRaw()
+
+	return decodeHealth(harbor, result, err)
+}
+
+func (r *Reconciler) getHealthWithInternalTLS(ctx context.Context, harbor *goharborv1alpha1.Harbor) (*APIHealth, error) {
+	client, err := harborapi.NewHTTPClient(ctx, r.Client, harbor)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get http client")
+	}
+
+	u, err := harborapi.GetURL(harbor)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get core url")
+	}
+
+	u.Path = HarborHealthEndpoint
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create health request")
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", harborapi.UserAgent(r.GetName(), r.GetVersion()))
+
+	res, err := client.Do(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get health response")
 	}
+	defer res.Body.Close()
 
-	health := &APIHealth{}
-	err = json.Unmarshal(result, health)
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read health response")
+	}
+
+	if res.StatusCode != http.StatusOK {
+		err = errors.Errorf("unexpected health status code %d", res.StatusCode)
+	}
+
+	return decodeHealth(harbor, body, err)
+}
+
+// decodeHealth decodes the health response of core, or returns the error of the request.
+// Within the service mesh, the sidecar answers itself when core is not reachable, with "no healthy upstream" for instance.
+// Such responses report core as unhealthy instead of failing the reconciliation.
+func decodeHealth(harbor *goharborv1alpha1.Harbor, body []byte, responseErr error) (*APIHealth, error) {
+	if responseErr == nil {
+		health := &APIHealth{}
+
+		err := json.Unmarshal(body, health)
+		if err == nil || !harbor.IsServiceMeshEnabled() {
+			return health, errors.Wrap(err, "unexpected health response")
+		}
+	} else if !harbor.IsServiceMeshEnabled() || len(body) == 0 {
+		return nil, errors.Wrap(responseErr, "cannot get health response")
+	}
 
-	return health, errors.Wrap(err, "unexpected health response")
+	return &APIHealth{
+		Status: UnhealthyStatus,
+		Components: []ComponentHealth{{
+			Name:   goharborv1alpha1.CoreName,
+			Status: UnhealthyStatus,
+			Error:  strings.TrimSpace(string(body)),
+		}},
+	}, nil
 }
@@ -0,0 +1,207 @@
+package harbor
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	netv1 "k8s.io/api/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+)
+
+const (
+	IngressV1      = "v1"
+	IngressV1Beta1 = "v1beta1"
+
+	// pathTypePrefix matches paths of v1beta1 ingresses the same way most controllers do
+	pathTypePrefix = "Prefix"
+)
+
+// IngressVersions are the supported versions of networking.k8s.io Ingress API, by order of preference.
+var IngressVersions = []string{IngressV1, IngressV1Beta1}
+
+// DetectIngressVersion returns the preferred networking.k8s.io API version serving ingresses, empty if none is served.
+func DetectIngressVersion(client discovery.DiscoveryInterface) (string, error) {
+	for _, version := range IngressVersions {
+		resources, err := client.ServerResourcesForGroupVersion(schema.GroupVersion{Group: netv1.SchemeGroupVersion.Group, Version: version}.String())
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return "", errors.Wrapf(err, "cannot discover ingresses %s", version)
+		}
+
+		for _, resource := range resources.APIResources {
+			if resource.Kind == "Ingress" {
+				return version, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// IngressGVK returns the kind of ingresses rendered for the detected API version.
+func (r *Reconciler) IngressGVK() schema.GroupVersionKind {
+	version := r.Config.IngressVersion
+	if version == "" {
+		version = netv1.SchemeGroupVersion.Version
+	}
+
+	return schema.GroupVersionKind{
+		Group:   netv1.SchemeGroupVersion.Group,
+		Version: version,
+		Kind:    "Ingress",
+	}
+}
+
+// NewIngressObject returns an empty ingress of the kind served by the cluster.
+func (r *Reconciler) NewIngressObject() components.Resource {
+	gvk := r.IngressGVK()
+	if gvk.Version == netv1.SchemeGroupVersion.Version {
+		return &netv1.Ingress{}
+	}
+
+	ingress := &unstructured.Unstructured{}
+	ingress.SetGroupVersionKind(gvk)
+
+	return ingress
+}
+
+// ConvertIngress returns the ingress rendered for the API version.
+// Only v1beta1 is vendored, v1 ingresses are rendered as unstructured objects.
+func ConvertIngress(ingress *netv1.Ingress, version string) (components.Resource, error) {
+	if version == "" || version == netv1.SchemeGroupVersion.Version {
+		return ingress, nil
+	}
+
+	if version != IngressV1 {
+		return nil, errors.Errorf("unsupported ingress version %s", version)
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ingress)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert ingress")
+	}
+
+	spec := map[string]interface{}{}
+
+	if ingress.Spec.Backend != nil {
+		spec["defaultBackend"] = ingressV1Backend(*ingress.Spec.Backend)
+	}
+
+	if len(ingress.Spec.TLS) > 0 {
+		spec["tls"], _, _ = unstructured.NestedSlice(content, "spec", "tls")
+	}
+
+	rules := make([]interface{}, 0, len(ingress.Spec.Rules))
+
+	for _, rule := range ingress.Spec.Rules {
+		result := map[string]interface{}{}
+
+		if rule.Host != "" {
+			result["host"] = rule.Host
+		}
+
+		if rule.HTTP != nil {
+			paths := make([]interface{}, 0, len(rule.HTTP.Paths))
+
+			for _, path := range rule.HTTP.Paths {
+				paths = append(paths, map[string]interface{}{
+					"path":     path.Path,
+					"pathType": pathTypePrefix,
+					"backend":  ingressV1Backend(path.Backend),
+				})
+			}
+
+			result["http"] = map[string]interface{}{"paths": paths}
+		}
+
+		rules = append(rules, result)
+	}
+
+	if len(rules) > 0 {
+		spec["rules"] = rules
+	}
+
+	delete(content, "status")
+	content["spec"] = spec
+
+	result := &unstructured.Unstructured{Object: content}
+	result.SetGroupVersionKind(netv1.SchemeGroupVersion.WithKind("Ingress").GroupKind().WithVersion(version))
+
+	return result, nil
+}
+
+// ingressV1Backend returns the v1 backend, referencing the service port by number or by name.
+func ingressV1Backend(backend netv1.IngressBackend) map[string]interface{} {
+	port := map[string]interface{}{}
+
+	if backend.ServicePort.Type == intstr.String {
+		port["name"] = backend.ServicePort.StrVal
+	} else {
+		port["number"] = int64(backend.ServicePort.IntVal)
+	}
+
+	return map[string]interface{}{
+		"service": map[string]interface{}{
+			"name": backend.ServiceName,
+			"port": port,
+		},
+	}
+}
+
+// convertIngresses wraps the run of component ingresses, so they are rendered for the API version.
+func (r *Reconciler) convertIngresses(run components.ComponentRun) components.ComponentRun {
+	return func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
+		if len(resources) > 0 && r.Config.IngressDisabled {
+			return errors.New("networking.k8s.io ingresses are not served")
+		}
+
+		converted := make([]components.Resource, len(resources))
+
+		for i, resource := range resources {
+			ingress, ok := resource.(*netv1.Ingress)
+			if !ok {
+				return errors.Errorf("unexpected ingress %+v", resource)
+			}
+
+			result, err := ConvertIngress(ingress, r.Config.IngressVersion)
+			if err != nil {
+				return err
+			}
+
+			converted[i] = result
+		}
+
+		return run(ctx, harbor, converted)
+	}
+}
+
+// mutateUnstructured copies labels, annotations and spec of unstructured resources, such as ingresses or routes.
+func mutateUnstructured(resource, result components.Resource) controllerutil.MutateFn {
+	unstructuredResult, ok := result.(*unstructured.Unstructured)
+	desired := resource.(*unstructured.Unstructured)
+
+	return func() error {
+		if !ok {
+			return errors.Errorf("unexpected argument %+v", result)
+		}
+
+		// Presets and spec annotations are part of the desired state of ingresses
+		unstructuredResult.SetLabels(desired.GetLabels())
+		unstructuredResult.SetAnnotations(desired.GetAnnotations())
+		unstructuredResult.Object["spec"] = desired.DeepCopy().Object["spec"]
+
+		return nil
+	}
+}
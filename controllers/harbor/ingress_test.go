@@ -0,0 +1,136 @@
+package harbor
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	netv1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+)
+
+var _ = Describe("Ingress API", func() {
+	detect := func(served ...string) string {
+		server := apiDiscoveryServer("networking.k8s.io", "ingresses", "Ingress", served...)
+		defer server.Close()
+
+		client, err := discovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+		Expect(err).ToNot(HaveOccurred())
+
+		version, err := DetectIngressVersion(client)
+		Expect(err).ToNot(HaveOccurred())
+
+		return version
+	}
+
+	It("Should prefer v1", func() {
+		Expect(detect(IngressV1, IngressV1Beta1)).To(Equal(IngressV1))
+		Expect(detect(IngressV1Beta1)).To(Equal(IngressV1Beta1))
+		Expect(detect()).To(BeEmpty())
+	})
+
+	It("Should render ingresses for the version", func() {
+		r := &Reconciler{}
+		Expect(r.IngressGVK().Version).To(Equal(IngressV1Beta1))
+		Expect(r.NewIngressObject()).To(BeAssignableToTypeOf(&netv1.Ingress{}))
+		Expect(r.gvksToDelete()).To(ContainElement(r.IngressGVK()))
+
+		r.Config.IngressVersion = IngressV1
+		Expect(r.IngressGVK().Version).To(Equal(IngressV1))
+		Expect(r.NewIngressObject()).To(BeAssignableToTypeOf(&unstructured.Unstructured{}))
+	})
+
+	It("Should disable ingresses when none is served", func() {
+		server := apiDiscoveryServer("networking.k8s.io", "ingresses", "Ingress")
+		defer server.Close()
+
+		r := &Reconciler{
+			Log:        zap.LoggerTo(GinkgoWriter, true),
+			RestConfig: &rest.Config{Host: server.URL},
+		}
+
+		Expect(r.setupIngress()).To(Succeed())
+		Expect(r.Config.IngressDisabled).To(BeTrue())
+		Expect(r.gvksToDelete()).ToNot(ContainElement(r.IngressGVK()))
+
+		run := r.convertIngresses(func(context.Context, *goharborv1alpha1.Harbor, []components.Resource) error {
+			return nil
+		})
+
+		Expect(run(context.TODO(), &goharborv1alpha1.Harbor{}, nil)).To(Succeed())
+		Expect(run(context.TODO(), &goharborv1alpha1.Harbor{}, []components.Resource{&netv1.Ingress{}})).ToNot(Succeed())
+	})
+
+	It("Should convert ingresses to v1", func() {
+		ingress := &netv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "harbor-core",
+				Namespace:   "ns",
+				Annotations: map[string]string{"kubernetes.io/ingress.class": "nginx"},
+			},
+			Spec: netv1.IngressSpec{
+				TLS: []netv1.IngressTLS{{SecretName: "tls"}},
+				Rules: []netv1.IngressRule{{
+					Host: "harbor.example.com",
+					IngressRuleValue: netv1.IngressRuleValue{
+						HTTP: &netv1.HTTPIngressRuleValue{
+							Paths: []netv1.HTTPIngressPath{{
+								Path: "/api",
+								Backend: netv1.IngressBackend{
+									ServiceName: "harbor-core",
+									ServicePort: intstr.FromInt(80),
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+
+		result, err := ConvertIngress(ingress, IngressV1Beta1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeIdenticalTo(ingress))
+
+		result, err = ConvertIngress(ingress, IngressV1)
+		Expect(err).ToNot(HaveOccurred())
+
+		u, ok := result.(*unstructured.Unstructured)
+		Expect(ok).To(BeTrue())
+		Expect(u.GetAPIVersion()).To(Equal("networking.k8s.io/v1"))
+		Expect(u.GetKind()).To(Equal("Ingress"))
+		Expect(u.GetName()).To(Equal("harbor-core"))
+		Expect(u.GetAnnotations()).To(HaveKeyWithValue("kubernetes.io/ingress.class", "nginx"))
+
+		tls, _, _ := unstructured.NestedSlice(u.Object, "spec", "tls")
+		Expect(tls).To(Equal([]interface{}{map[string]interface{}{"secretName": "tls"}}))
+
+		rules, _, _ := unstructured.NestedSlice(u.Object, "spec", "rules")
+		Expect(rules).To(HaveLen(1))
+
+		paths, _, _ := unstructured.NestedSlice(rules[0].(map[string]interface{}), "http", "paths")
+		Expect(paths).To(Equal([]interface{}{
+			map[string]interface{}{
+				"path":     "/api",
+				"pathType": "Prefix",
+				"backend": map[string]interface{}{
+					"service": map[string]interface{}{
+						"name": "harbor-core",
+						"port": map[string]interface{}{"number": int64(80)},
+					},
+				},
+			},
+		}))
+
+		_, err = ConvertIngress(ingress, "v2")
+		Expect(err).To(HaveOccurred())
+	})
+})
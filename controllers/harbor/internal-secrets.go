@@ -0,0 +1,174 @@
+package harbor
+
+import (
+	"context"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	"github.com/sethvargo/go-password/password"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+)
+
+const (
+	EventReasonInternalSecretsRotated = "InternalSecretsRotated"
+)
+
+// coreDatabaseEncryptionKey is the key of the core secret used to encrypt passwords stored in the database.
+// Changing it makes those passwords unreadable: it must never be rotated.
+const coreDatabaseEncryptionKey = "secretKey"
+
+// InternalSecret is a generated key of a component secret, shared with other components.
+type InternalSecret struct {
+	Component string
+	Key       string
+
+	Length     int
+	NumDigits  int
+	NumSymbols int
+}
+
+// InternalSecrets are the rotated keys, with the length used by components to generate them.
+var InternalSecrets = []InternalSecret{
+	{Component: goharborv1alpha1.CoreName, Key: "secret", Length: 16, NumDigits: 5},
+	{Component: goharborv1alpha1.JobServiceName, Key: "secret", Length: 32, NumDigits: 10, NumSymbols: 10},
+	{Component: goharborv1alpha1.RegistryName, Key: "REGISTRY_HTTP_SECRET", Length: 15, NumDigits: 5, NumSymbols: 5},
+}
+
+// InternalSecretsConsumers returns the components reading internal secrets, in restart order.
+// Core is restarted first since other components authenticate against it.
+func InternalSecretsConsumers(harbor *goharborv1alpha1.Harbor) []string {
+	consumers := []string{}
+
+	if harbor.Spec.Components.Core != nil {
+		consumers = append(consumers, goharborv1alpha1.CoreName)
+	}
+
+	if harbor.Spec.Components.JobService != nil {
+		consumers = append(consumers, goharborv1alpha1.JobServiceName)
+	}
+
+	if harbor.Spec.Components.Registry != nil {
+		consumers = append(consumers, goharborv1alpha1.RegistryName)
+	}
+
+	if harbor.Spec.Components.ChartMuseum != nil {
+		consumers = append(consumers, goharborv1alpha1.ChartMuseumName)
+	}
+
+	return consumers
+}
+
+// NextInternalSecretsRotation returns the next scheduled rotation, zero without schedule.
+func NextInternalSecretsRotation(harbor *goharborv1alpha1.Harbor) (time.Time, error) {
+	if harbor.Spec.SecretRotation == nil {
+		return time.Time{}, nil
+	}
+
+	schedule, err := cron.ParseStandard(harbor.Spec.SecretRotation.Schedule)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid schedule %s", harbor.Spec.SecretRotation.Schedule)
+	}
+
+	last := harbor.GetCreationTimestamp().Time
+	if harbor.Status.InternalSecretsRotationTime != nil {
+		last = harbor.Status.InternalSecretsRotationTime.Time
+	}
+
+	return schedule.Next(last), nil
+}
+
+// IsInternalSecretsRotationRequested returns whether the rotate-internal-secrets annotation changed since the last rotation.
+func IsInternalSecretsRotationRequested(harbor *goharborv1alpha1.Harbor) bool {
+	request := harbor.GetAnnotations()[goharborv1alpha1.RotateInternalSecretsAnnotation]
+
+	return request != "" && request != harbor.Status.InternalSecretsRotationRequest
+}
+
+// RotateInternalSecrets regenerates internal secrets when the annotation changed or the schedule is reached.
+// Consumers are then restarted by RestartInternalSecretsConsumers.
+func (r *Reconciler) RotateInternalSecrets(ctx context.Context, result *ctrl.Result, harbor *goharborv1alpha1.Harbor) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "rotateInternalSecrets")
+	defer span.Finish()
+
+	next, err := NextInternalSecretsRotation(harbor)
+	if err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+
+	scheduled := !next.IsZero() && !next.After(now.Time)
+	if !scheduled && !IsInternalSecretsRotationRequested(harbor) {
+		if !next.IsZero() {
+			requeueBefore(result, next.Sub(now.Time))
+		}
+
+		return nil
+	}
+
+	for _, internalSecret := range InternalSecrets {
+		err := r.rotateInternalSecret(ctx, harbor, internalSecret)
+		if err != nil {
+			return err
+		}
+	}
+
+	harbor.Status.InternalSecretsRotationTime = &now
+	harbor.Status.InternalSecretsRotationRequest = harbor.GetAnnotations()[goharborv1alpha1.RotateInternalSecretsAnnotation]
+
+	logger.Get(ctx).Info("internal secrets rotated")
+	r.Recorder.Event(harbor, corev1.EventTypeNormal, EventReasonInternalSecretsRotated, "internal secrets regenerated")
+
+	return nil
+}
+
+func (r *Reconciler) rotateInternalSecret(ctx context.Context, harbor *goharborv1alpha1.Harbor, internalSecret InternalSecret) error {
+	if internalSecret.Component == goharborv1alpha1.CoreName && internalSecret.Key == coreDatabaseEncryptionKey {
+		return errors.Errorf("key %s of %s secret cannot be rotated", internalSecret.Key, internalSecret.Component)
+	}
+
+	name := harbor.NormalizeComponentName(internalSecret.Component)
+	secret := &corev1.Secret{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: harbor.GetNamespace(), Name: name}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Not generated yet
+			return nil
+		}
+
+		return errors.Wrapf(err, "cannot get secret %s", name)
+	}
+
+	value, err := password.Generate(internalSecret.Length, internalSecret.NumDigits, internalSecret.NumSymbols, false, true)
+	if err != nil {
+		return errors.Wrap(err, "cannot generate secret")
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	secret.Data[internalSecret.Key] = []byte(value)
+
+	err = r.Client.Update(ctx, secret)
+
+	return errors.Wrapf(err, "cannot update secret %s", name)
+}
+
+// RestartInternalSecretsConsumers restarts consumers of internal secrets one after the other after a rotation.
+func (r *Reconciler) RestartInternalSecretsConsumers(ctx context.Context, result *ctrl.Result, harbor *goharborv1alpha1.Harbor) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "restartInternalSecretsConsumers")
+	defer span.Finish()
+
+	return r.RestartDeployments(ctx, result, harbor, goharborv1alpha1.InternalSecretsRotationAnnotation, harbor.Status.InternalSecretsRotationTime, InternalSecretsConsumers(harbor), "internal secrets")
+}
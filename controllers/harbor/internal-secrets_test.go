@@ -0,0 +1,112 @@
+package harbor
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+var _ = Describe("Internal secrets", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var h *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		r, ctx = setupTest(context.TODO())
+
+		h = &goharborv1alpha1.Harbor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "harbor",
+				Namespace:         "ns",
+				CreationTimestamp: metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+		}
+	})
+
+	It("Should never rotate the database encryption key", func() {
+		for _, internalSecret := range InternalSecrets {
+			Expect(internalSecret.Key).ToNot(Equal(coreDatabaseEncryptionKey))
+		}
+
+		err := r.rotateInternalSecret(ctx, h, InternalSecret{Component: goharborv1alpha1.CoreName, Key: coreDatabaseEncryptionKey, Length: 16})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should restart core first", func() {
+		h.Spec.Components = goharborv1alpha1.HarborComponents{
+			Core:        &goharborv1alpha1.CoreComponent{},
+			Registry:    &goharborv1alpha1.RegistryComponent{},
+			JobService:  &goharborv1alpha1.JobServiceComponent{},
+			ChartMuseum: &goharborv1alpha1.ChartMuseumComponent{},
+			Portal:      &goharborv1alpha1.PortalComponent{},
+		}
+
+		Expect(InternalSecretsConsumers(h)).To(Equal([]string{
+			goharborv1alpha1.CoreName,
+			goharborv1alpha1.JobServiceName,
+			goharborv1alpha1.RegistryName,
+			goharborv1alpha1.ChartMuseumName,
+		}))
+
+		h.Spec.Components.ChartMuseum = nil
+		Expect(InternalSecretsConsumers(h)).ToNot(ContainElement(goharborv1alpha1.ChartMuseumName))
+	})
+
+	It("Should schedule rotations from the last one", func() {
+		next, err := NextInternalSecretsRotation(h)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(next.IsZero()).To(BeTrue())
+
+		h.Spec.SecretRotation = &goharborv1alpha1.SecretRotation{Schedule: "0 0 1 * *"}
+
+		next, err = NextInternalSecretsRotation(h)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(next).To(Equal(time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)))
+
+		last := metav1.NewTime(time.Date(2020, 5, 10, 0, 0, 0, 0, time.UTC))
+		h.Status.InternalSecretsRotationTime = &last
+
+		next, err = NextInternalSecretsRotation(h)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(next).To(Equal(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)))
+
+		h.Spec.SecretRotation.Schedule = "monthly"
+
+		_, err = NextInternalSecretsRotation(h)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should rotate when the annotation changes", func() {
+		Expect(IsInternalSecretsRotationRequested(h)).To(BeFalse())
+
+		h.SetAnnotations(map[string]string{goharborv1alpha1.RotateInternalSecretsAnnotation: "1"})
+		Expect(IsInternalSecretsRotationRequested(h)).To(BeTrue())
+
+		h.Status.InternalSecretsRotationRequest = "1"
+		Expect(IsInternalSecretsRotationRequested(h)).To(BeFalse())
+	})
+
+	It("Should keep the restart annotation when applying deployments", func() {
+		current := &appsv1.Deployment{}
+		current.Spec.Template.Annotations = map[string]string{
+			goharborv1alpha1.InternalSecretsRotationAnnotation: "2020-01-01T00:00:00Z",
+			"secret/checksum": "old",
+		}
+
+		desired := &appsv1.Deployment{}
+		desired.Spec.Template.Annotations = map[string]string{"secret/checksum": "new"}
+		desired.Spec.Template.Spec.Containers = []corev1.Container{{Name: "core"}}
+
+		Expect(mutateDeployment(desired, current)()).To(Succeed())
+		Expect(current.Spec.Template.Annotations).To(HaveKeyWithValue(goharborv1alpha1.InternalSecretsRotationAnnotation, "2020-01-01T00:00:00Z"))
+		Expect(current.Spec.Template.Annotations).To(HaveKeyWithValue("secret/checksum", "new"))
+	})
+})
@@ -0,0 +1,89 @@
+package harbor
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+	harbor_core "github.com/goharbor/harbor-operator/controllers/harbor/components/harbor-core"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+)
+
+var _ = Describe("Internal TLS", func() {
+	var ctx context.Context
+	var h *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		_, ctx, h = setupHarborTest(context.TODO())
+
+		h.Spec.Components = goharborv1alpha1.HarborComponents{
+			Core:     &goharborv1alpha1.CoreComponent{},
+			Registry: &goharborv1alpha1.RegistryComponent{},
+		}
+	})
+
+	It("Should be disabled by default", func() {
+		Expect(h.IsInternalTLSEnabled()).To(BeFalse())
+		Expect(InternalTLSCertificates(h)).To(BeEmpty())
+		Expect(SelfSignedCertificates(h)).To(BeEmpty())
+		u, err := harborapi.GetURL(h)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u.Scheme).To(Equal("http"))
+	})
+
+	It("Should issue a certificate per component, whatever the certificates mode", func() {
+		h.Spec.InternalTLS = &goharborv1alpha1.InternalTLSSpec{Enabled: true}
+
+		certificates := SelfSignedCertificates(h)
+		Expect(certificates).To(HaveLen(4))
+		Expect(certificates[0].SecretName).To(Equal("harbor-core-internal-tls"))
+		Expect(certificates[0].CommonName).To(Equal("harbor-core"))
+		Expect(certificates[0].DNSNames).To(ConsistOf("harbor-core", "harbor-core.ns", "harbor-core.ns.svc"))
+		u, err := harborapi.GetURL(h)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u.String()).To(Equal("https://harbor-core.ns.svc"))
+
+		h.Spec.Components.ChartMuseum = &goharborv1alpha1.ChartMuseumComponent{}
+		h.Spec.Components.Clair = &goharborv1alpha1.ClairComponent{}
+		h.Spec.Certificates = &goharborv1alpha1.CertificatesSpec{Mode: goharborv1alpha1.CertificatesModeSelfSigned}
+
+		Expect(InternalTLSCertificates(h)).To(HaveLen(6))
+		Expect(SelfSignedCertificates(h)).To(HaveLen(7))
+		Expect(CertificatesConsumers(h)).To(Equal([]string{
+			goharborv1alpha1.CoreName,
+			goharborv1alpha1.RegistryName,
+			goharborv1alpha1.JobServiceName,
+			goharborv1alpha1.PortalName,
+			goharborv1alpha1.ChartMuseumName,
+			goharborv1alpha1.ClairName,
+		}))
+	})
+
+	It("Should serve core over HTTPS", func() {
+		h.Spec.InternalTLS = &goharborv1alpha1.InternalTLSSpec{Enabled: true}
+
+		c, err := harbor_core.New(ctx, h, &components.Option{})
+		Expect(err).ToNot(HaveOccurred())
+
+		services := c.GetServices(ctx)
+		Expect(services).To(HaveLen(1))
+		Expect(services[0].Spec.Ports[0].Port).To(BeEquivalentTo(443))
+		Expect(services[0].Spec.Ports[0].TargetPort.IntValue()).To(Equal(8443))
+
+		container := c.GetDeployments(ctx)[0].Spec.Template.Spec.Containers[0]
+		Expect(container.Ports[0].ContainerPort).To(BeEquivalentTo(8443))
+		Expect(container.ReadinessProbe.HTTPGet.Scheme).To(Equal(corev1.URISchemeHTTPS))
+		Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "INTERNAL_TLS_ENABLED", Value: "true"}))
+
+		mountPaths := []string{}
+		for _, mount := range container.VolumeMounts {
+			mountPaths = append(mountPaths, mount.MountPath)
+		}
+		Expect(mountPaths).To(ContainElement("/harbor_cust_cert/harbor-internal-ca.crt"))
+	})
+})
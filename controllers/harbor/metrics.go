@@ -0,0 +1,172 @@
+package harbor
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "harbor_operator"
+	metricsSubsystem = "harbor"
+)
+
+const (
+	ReconcileResultSuccess = "success"
+	ReconcileResultRequeue = "requeue"
+	ReconcileResultError   = "error"
+)
+
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of Harbor reconciliation, by Harbor resource.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"namespace", "name"})
+
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "reconcile_total",
+		Help:      "Number of Harbor reconciliations, by Harbor resource and result.",
+	}, []string{"namespace", "name", "result"})
+
+	applyTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "apply_total",
+		Help:      "Number of resources applied, by kind and operation (created, updated, unchanged).",
+	}, []string{"kind", "operation"})
+
+	deleteComponentTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "delete_component_total",
+		Help:      "Number of deletions of components having resources, by component and result.",
+	}, []string{"component", "result"})
+
+	healthCheckDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "health_check_duration_seconds",
+		Help:      "Latency of Harbor health checks, by Harbor resource.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"namespace", "name"})
+
+	healthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "healthy",
+		Help:      "Whether an Harbor is healthy (1) or not (0), as reported by the health API.",
+	}, []string{"namespace", "name"})
+
+	componentHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "component_healthy",
+		Help:      "Whether an Harbor component is healthy (1) or not (0), as reported by the health API.",
+	}, []string{"namespace", "name", "component"})
+)
+
+// nolint:gochecknoinits
+func init() {
+	metrics.Registry.MustRegister(
+		reconcileDuration,
+		reconcileTotal,
+		applyTotal,
+		deleteComponentTotal,
+		healthCheckDuration,
+		healthy,
+		componentHealthy,
+	)
+}
+
+func reconcileResultLabel(result ctrl.Result, err error) string {
+	switch {
+	case err != nil:
+		return ReconcileResultError
+	case result.Requeue || result.RequeueAfter > 0:
+		return ReconcileResultRequeue
+	default:
+		return ReconcileResultSuccess
+	}
+}
+
+func errorResultLabel(err error) string {
+	if err != nil {
+		return ReconcileResultError
+	}
+
+	return ReconcileResultSuccess
+}
+
+// harborMetrics keeps track of exported component series per Harbor,
+// so they can be dropped when the Harbor resource is deleted.
+type harborMetrics struct {
+	lock       sync.Mutex
+	components map[types.NamespacedName]map[string]struct{}
+}
+
+var harborSeries = &harborMetrics{
+	components: map[types.NamespacedName]map[string]struct{}{},
+}
+
+// record exports the health of the Harbor and of each of its components.
+// When health is nil (health API unreachable), the Harbor is reported unhealthy
+// and components keep their last known value.
+func (m *harborMetrics) record(harbor types.NamespacedName, health *APIHealth) {
+	if health == nil || !health.IsHealthy() {
+		healthy.WithLabelValues(harbor.Namespace, harbor.Name).Set(0)
+	} else {
+		healthy.WithLabelValues(harbor.Namespace, harbor.Name).Set(1)
+	}
+
+	if health == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	known, ok := m.components[harbor]
+	if !ok {
+		known = map[string]struct{}{}
+		m.components[harbor] = known
+	}
+
+	for _, component := range health.Components {
+		value := 0.
+		if component.Status == HealthyStatus {
+			value = 1.
+		}
+
+		componentHealthy.WithLabelValues(harbor.Namespace, harbor.Name, component.Name).Set(value)
+
+		known[component.Name] = struct{}{}
+	}
+}
+
+// forget drops per-Harbor series once the resource is gone.
+func (m *harborMetrics) forget(harbor types.NamespacedName) {
+	reconcileDuration.DeleteLabelValues(harbor.Namespace, harbor.Name)
+	healthCheckDuration.DeleteLabelValues(harbor.Namespace, harbor.Name)
+	healthy.DeleteLabelValues(harbor.Namespace, harbor.Name)
+
+	for _, result := range []string{ReconcileResultSuccess, ReconcileResultRequeue, ReconcileResultError} {
+		reconcileTotal.DeleteLabelValues(harbor.Namespace, harbor.Name, result)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for component := range m.components[harbor] {
+		componentHealthy.DeleteLabelValues(harbor.Namespace, harbor.Name, component)
+	}
+
+	delete(m.components, harbor)
+}
@@ -0,0 +1,92 @@
+package harbor
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func countSeries(c prometheus.Collector) int {
+	ch := make(chan prometheus.Metric)
+
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+
+	count := 0
+	for range ch {
+		count++
+	}
+
+	return count
+}
+
+var _ = Describe("metrics", func() {
+	Describe("Reconcile result label", func() {
+		It("Should be error when an error occurred", func() {
+			Expect(reconcileResultLabel(ctrl.Result{Requeue: true}, errors.New("failure"))).To(Equal(ReconcileResultError))
+		})
+
+		It("Should be requeue when requeued", func() {
+			Expect(reconcileResultLabel(ctrl.Result{Requeue: true}, nil)).To(Equal(ReconcileResultRequeue))
+			Expect(reconcileResultLabel(ctrl.Result{RequeueAfter: time.Second}, nil)).To(Equal(ReconcileResultRequeue))
+		})
+
+		It("Should be success otherwise", func() {
+			Expect(reconcileResultLabel(ctrl.Result{}, nil)).To(Equal(ReconcileResultSuccess))
+		})
+	})
+
+	Describe("Health gauges", func() {
+		var name types.NamespacedName
+
+		BeforeEach(func() {
+			name = types.NamespacedName{Namespace: "metrics", Name: "harbor"}
+		})
+
+		AfterEach(func() {
+			harborSeries.forget(name)
+		})
+
+		It("Should export Harbor and components health", func() {
+			harborSeries.record(name, &APIHealth{
+				Status: UnhealthyStatus,
+				Components: []ComponentHealth{
+					{Name: "core", Status: HealthyStatus},
+					{Name: "registry", Status: UnhealthyStatus},
+				},
+			})
+
+			Expect(testutil.ToFloat64(healthy.WithLabelValues(name.Namespace, name.Name))).To(BeEquivalentTo(0))
+			Expect(testutil.ToFloat64(componentHealthy.WithLabelValues(name.Namespace, name.Name, "core"))).To(BeEquivalentTo(1))
+			Expect(testutil.ToFloat64(componentHealthy.WithLabelValues(name.Namespace, name.Name, "registry"))).To(BeEquivalentTo(0))
+		})
+
+		It("Should report unreachable Harbor as unhealthy", func() {
+			harborSeries.record(name, nil)
+
+			Expect(testutil.ToFloat64(healthy.WithLabelValues(name.Namespace, name.Name))).To(BeEquivalentTo(0))
+		})
+
+		It("Should drop series when forgotten", func() {
+			harborSeries.record(name, &APIHealth{
+				Status:     HealthyStatus,
+				Components: []ComponentHealth{{Name: "core", Status: HealthyStatus}},
+			})
+
+			Expect(countSeries(componentHealthy)).To(BeNumerically(">=", 1))
+
+			harborSeries.forget(name)
+
+			Expect(countSeries(componentHealthy)).To(BeZero())
+		})
+	})
+})
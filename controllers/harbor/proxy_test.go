@@ -0,0 +1,84 @@
+package harbor
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/jobservice"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/proxy"
+)
+
+var _ = Describe("Outbound traffic", func() {
+	var ctx context.Context
+	var h *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		_, ctx, h = setupHarborTest(context.TODO())
+
+		h.Spec.Components = goharborv1alpha1.HarborComponents{
+			JobService: &goharborv1alpha1.JobServiceComponent{},
+		}
+	})
+
+	It("Should exclude components from the proxy", func() {
+		Expect(proxy.EnvVars(h)).To(BeEmpty())
+
+		h.Spec.Proxy = &goharborv1alpha1.ProxySpec{
+			HTTPProxy:  "http://proxy.example.com:3128",
+			HTTPSProxy: "http://proxy.example.com:3128",
+			NoProxy:    []string{"registry.example.com"},
+		}
+
+		noProxy := proxy.NoProxy(h)
+		Expect(noProxy).To(ContainElement("localhost"))
+		Expect(noProxy).To(ContainElement("harbor-core"))
+		Expect(noProxy).To(ContainElement("harbor-notary-signer"))
+		Expect(noProxy[len(noProxy)-1]).To(Equal("registry.example.com"))
+	})
+
+	It("Should inject the proxy and the CA bundle in jobservice", func() {
+		h.Spec.Proxy = &goharborv1alpha1.ProxySpec{HTTPSProxy: "http://proxy.example.com:3128"}
+		h.Spec.TrustedCABundle = &goharborv1alpha1.TrustedCABundleSpec{
+			ConfigMapRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "bundle"},
+				Key:                  "ca-bundle.crt",
+			},
+		}
+
+		j, err := jobservice.New(ctx, h, &components.Option{})
+		Expect(err).ToNot(HaveOccurred())
+
+		pod := j.GetDeployments(ctx)[0].Spec.Template.Spec
+		Expect(pod.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy.example.com:3128"}))
+		Expect(pod.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "trusted-ca-bundle",
+			MountPath: "/harbor_cust_cert/trusted-ca-bundle.crt",
+			SubPath:   "ca-bundle.crt",
+			ReadOnly:  true,
+		}))
+
+		volumes := map[string]corev1.Volume{}
+		for _, volume := range pod.Volumes {
+			volumes[volume.Name] = volume
+		}
+		Expect(volumes).To(HaveKey("trusted-ca-bundle"))
+		Expect(volumes["trusted-ca-bundle"].ConfigMap.Name).To(Equal("bundle"))
+	})
+
+	It("Should check the secret of the CA bundle", func() {
+		h.Spec.TrustedCABundle = &goharborv1alpha1.TrustedCABundleSpec{
+			SecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "bundle"},
+				Key:                  "ca.crt",
+			},
+		}
+
+		Expect(ReferencedSecrets(h)).To(ContainElement("bundle"))
+	})
+})
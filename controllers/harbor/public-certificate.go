@@ -0,0 +1,106 @@
+package harbor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	harbor_core "github.com/goharbor/harbor-operator/controllers/harbor/components/harbor-core"
+)
+
+const certificateReadyConditionType = "Ready"
+
+// EnsurePublicCertificate reflects the Ready condition of the public certificate in the Harbor status.
+// The certificate is owned by the Harbor, so its changes trigger a new reconciliation.
+// When the certificate is no longer requested, because a TLS secret is specified, it is deleted.
+func (r *Reconciler) EnsurePublicCertificate(ctx context.Context, harbor *goharborv1alpha1.Harbor) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ensurePublicCertificate")
+	defer span.Finish()
+
+	name := harbor.NormalizeComponentName(goharborv1alpha1.PublicCertificateName)
+
+	if !harbor.IsPublicCertificateManaged() || len(harbor_core.PublicHosts(harbor)) == 0 {
+		r.RemoveCondition(ctx, harbor, goharborv1alpha1.PublicCertificateReadyConditionType)
+
+		if !r.Config.CertManager {
+			return nil
+		}
+
+		certificate := r.NewCertificateObject()
+		certificate.SetName(name)
+		certificate.SetNamespace(harbor.GetNamespace())
+
+		err := r.Client.Delete(ctx, certificate)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "cannot delete certificate %s", name)
+		}
+
+		return nil
+	}
+
+	if !r.Config.CertManager {
+		return r.UpdateCondition(ctx, harbor, goharborv1alpha1.PublicCertificateReadyConditionType, corev1.ConditionFalse, "CertManagerDisabled", "cert-manager is required to request the public certificate")
+	}
+
+	certificate := r.NewCertificateObject()
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: harbor.GetNamespace(), Name: name}, certificate)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.UpdateCondition(ctx, harbor, goharborv1alpha1.PublicCertificateReadyConditionType, corev1.ConditionFalse, "NotFound", fmt.Sprintf("certificate %s not found", name))
+		}
+
+		return errors.Wrapf(err, "cannot get certificate %s", name)
+	}
+
+	status, reason, message, err := certificateReadyCondition(certificate)
+	if err != nil {
+		return errors.Wrapf(err, "cannot read status of certificate %s", name)
+	}
+
+	return r.UpdateCondition(ctx, harbor, goharborv1alpha1.PublicCertificateReadyConditionType, status, reason, message)
+}
+
+// certificateReadyCondition returns the Ready condition of a certificate, whatever its cert-manager version.
+func certificateReadyCondition(certificate runtime.Object) (corev1.ConditionStatus, string, string, error) {
+	var content map[string]interface{}
+
+	if u, ok := certificate.(*unstructured.Unstructured); ok {
+		content = u.Object
+	} else {
+		var err error
+
+		content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(certificate)
+		if err != nil {
+			return corev1.ConditionUnknown, "", "", errors.Wrap(err, "cannot convert certificate")
+		}
+	}
+
+	conditions, _, err := unstructured.NestedSlice(content, "status", "conditions")
+	if err != nil {
+		return corev1.ConditionUnknown, "", "", errors.Wrap(err, "invalid conditions")
+	}
+
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok || condition["type"] != certificateReadyConditionType {
+			continue
+		}
+
+		status, _ := condition["status"].(string)
+		reason, _ := condition["reason"].(string)
+		message, _ := condition["message"].(string)
+
+		return corev1.ConditionStatus(status), reason, message, nil
+	}
+
+	return corev1.ConditionUnknown, "Pending", "certificate not issued yet", nil
+}
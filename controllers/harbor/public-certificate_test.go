@@ -0,0 +1,113 @@
+package harbor
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	harbor_core "github.com/goharbor/harbor-operator/controllers/harbor/components/harbor-core"
+)
+
+var _ = Describe("Public certificate", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var h *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		r, ctx, h = setupHarborTest(context.TODO())
+
+		h.Spec.CertificateIssuerRef = cmmeta.ObjectReference{Name: "letsencrypt", Kind: "ClusterIssuer"}
+	})
+
+	It("Should be requested without TLS secret", func() {
+		Expect(h.IsPublicCertificateManaged()).To(BeTrue())
+		Expect(h.PublicTLSSecretName()).To(Equal("harbor-public-certificate"))
+
+		h.Spec.TLSSecretName = "tls"
+		Expect(h.IsPublicCertificateManaged()).To(BeFalse())
+		Expect(h.PublicTLSSecretName()).To(Equal("tls"))
+
+		h.Spec.TLSSecretName = ""
+		h.Spec.CertificateIssuerRef = cmmeta.ObjectReference{}
+		Expect(h.IsPublicCertificateManaged()).To(BeFalse())
+		Expect(h.PublicTLSSecretName()).To(BeEmpty())
+	})
+
+	It("Should cover https hosts", func() {
+		Expect(harbor_core.PublicHosts(h)).To(Equal([]string{"harbor.example.com"}))
+
+		h.Spec.Components.Notary = &goharborv1alpha1.NotaryComponent{PublicURL: "https://notary.example.com:443"}
+		Expect(harbor_core.PublicHosts(h)).To(Equal([]string{"harbor.example.com", "notary.example.com"}))
+
+		h.Spec.PublicURL = "http://harbor.example.com"
+		Expect(harbor_core.PublicHosts(h)).To(Equal([]string{"notary.example.com"}))
+	})
+
+	It("Should be rendered by core", func() {
+		h.Spec.Components.Notary = &goharborv1alpha1.NotaryComponent{PublicURL: "https://notary.example.com"}
+
+		c, err := harbor_core.New(ctx, h, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		certificates := c.GetCertificates(ctx)
+		Expect(certificates).To(HaveLen(1))
+		Expect(certificates[0].GetName()).To(Equal("harbor-public-certificate"))
+		Expect(certificates[0].Spec.SecretName).To(Equal("harbor-public-certificate"))
+		Expect(certificates[0].Spec.DNSNames).To(ConsistOf("harbor.example.com", "notary.example.com"))
+		Expect(certificates[0].Spec.IssuerRef.Name).To(Equal("letsencrypt"))
+
+		h.Spec.TLSSecretName = "tls"
+		Expect(c.GetCertificates(ctx)).To(BeEmpty())
+	})
+
+	It("Should read readiness of certificates", func() {
+		certificate := &certv1.Certificate{}
+
+		status, reason, _, err := certificateReadyCondition(certificate)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(corev1.ConditionUnknown))
+		Expect(reason).To(Equal("Pending"))
+
+		certificate.Status.Conditions = []certv1.CertificateCondition{{
+			Type:    certv1.CertificateConditionReady,
+			Status:  cmmeta.ConditionFalse,
+			Reason:  "Pending",
+			Message: "waiting for challenge",
+		}}
+
+		status, _, message, err := certificateReadyCondition(certificate)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(corev1.ConditionFalse))
+		Expect(message).To(Equal("waiting for challenge"))
+
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Issuing", "status": "False"},
+					map[string]interface{}{"type": "Ready", "status": "True", "reason": "Ready"},
+				},
+			},
+		}}
+
+		status, reason, _, err = certificateReadyCondition(u)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(corev1.ConditionTrue))
+		Expect(reason).To(Equal("Ready"))
+	})
+
+	It("Should surface readiness without cert-manager", func() {
+		Expect(r.EnsurePublicCertificate(ctx, h)).To(Succeed())
+		Expect(r.GetConditionStatus(ctx, h, goharborv1alpha1.PublicCertificateReadyConditionType)).To(Equal(corev1.ConditionFalse))
+
+		h.Spec.TLSSecretName = "tls"
+		Expect(r.EnsurePublicCertificate(ctx, h)).To(Succeed())
+		Expect(h.Status.Conditions).To(BeEmpty())
+	})
+})
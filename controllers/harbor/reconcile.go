@@ -3,6 +3,7 @@ package harbor
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/log"
@@ -10,6 +11,7 @@ import (
 	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -21,8 +23,9 @@ import (
 // +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=get;list;watch
 // +kubebuilder:rbac:groups=goharbor.io,resources=harbors/status,verbs=get;update;patch
 
-func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+func (r *Reconciler) Reconcile(req ctrl.Request) (result ctrl.Result, err error) {
 	ctx := context.TODO()
+	start := time.Now()
 	application.SetName(&ctx, r.GetName())
 	application.SetVersion(&ctx, r.GetVersion())
 
@@ -44,12 +47,14 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	// Fetch the Harbor instance
 	harbor := &goharborv1alpha1.Harbor{}
 
-	err := r.Client.Get(ctx, req.NamespacedName, harbor)
+	err = r.Client.Get(ctx, req.NamespacedName, harbor)
 	if err != nil {
 		if apierrs.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			reqLogger.Info("Harbor does not exists")
+			harborSeries.forget(req.NamespacedName)
+
 			return reconcile.Result{}, nil
 		}
 
@@ -57,11 +62,37 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return reconcile.Result{}, err
 	}
 
-	result := reconcile.Result{}
+	defer func() {
+		reconcileDuration.WithLabelValues(req.Namespace, req.Name).Observe(time.Since(start).Seconds())
+		reconcileTotal.WithLabelValues(req.Namespace, req.Name, reconcileResultLabel(result, err)).Inc()
+	}()
 
 	if !harbor.ObjectMeta.DeletionTimestamp.IsZero() {
 		reqLogger.Info("harbor is being deleted")
-		return result, nil
+
+		err = r.Finalize(ctx, &result, harbor)
+
+		return result, errors.Wrap(err, "cannot finalize")
+	}
+
+	err = r.AddFinalizer(ctx, harbor)
+	if err != nil {
+		return result, err
+	}
+
+	err = r.DefaultCertificatesMode(ctx, harbor)
+	if err != nil {
+		return result, err
+	}
+
+	err = r.EnsureAdminPasswordSecret(ctx, harbor)
+	if err != nil {
+		return result, errors.Wrap(err, "cannot generate admin password")
+	}
+
+	err = r.EnsureCertificates(ctx, &result, harbor)
+	if err != nil {
+		return result, errors.Wrap(err, "cannot prepare certificates")
 	}
 
 	var g errgroup.Group
@@ -81,7 +112,44 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return result, errors.Wrap(err, "cannot set status")
 	}
 
-	return result, r.UpdateStatus(ctx, &result, harbor)
+	publicCertificateErr := r.EnsurePublicCertificate(ctx, harbor)
+
+	var rotationErr, internalSecretsErr error
+
+	// The password can only be changed through the API of a running Harbor
+	if r.GetConditionStatus(ctx, harbor, goharborv1alpha1.ReadyConditionType) == corev1.ConditionTrue {
+		rotationErr = r.RotateAdminPassword(ctx, harbor)
+		if rotationErr != nil {
+			r.Recorder.Event(harbor, corev1.EventTypeWarning, EventReasonAdminPasswordRotationError, rotationErr.Error())
+		}
+
+		internalSecretsErr = r.RotateInternalSecrets(ctx, &result, harbor)
+	}
+
+	if internalSecretsErr == nil {
+		internalSecretsErr = r.RestartInternalSecretsConsumers(ctx, &result, harbor)
+	}
+
+	certificatesErr := r.RestartCertificatesConsumers(ctx, &result, harbor)
+
+	err = r.UpdateStatus(ctx, &result, harbor)
+	if err != nil {
+		return result, err
+	}
+
+	if publicCertificateErr != nil {
+		return result, errors.Wrap(publicCertificateErr, "cannot ensure public certificate")
+	}
+
+	if rotationErr != nil {
+		return result, errors.Wrap(rotationErr, "cannot rotate admin password")
+	}
+
+	if internalSecretsErr != nil {
+		return result, errors.Wrap(internalSecretsErr, "cannot rotate internal secrets")
+	}
+
+	return result, errors.Wrap(certificatesErr, "cannot restart certificates consumers")
 }
 
 func (r *Reconciler) UpdateAppliedStatus(ctx context.Context, result *ctrl.Result, harbor *goharborv1alpha1.Harbor) error {
@@ -148,6 +216,9 @@ func (r *Reconciler) UpdateReadyStatus(ctx context.Context, result *ctrl.Result,
 	// TODO do it asynchronously but do not
 	// forget to wait for completion before return
 	health, err := r.GetHealth(ctx, harbor)
+
+	harborSeries.record(types.NamespacedName{Namespace: harbor.GetNamespace(), Name: harbor.GetName()}, health)
+
 	if err != nil {
 		result.Requeue = true
 
@@ -0,0 +1,172 @@
+package harbor
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/redis"
+)
+
+var _ = Describe("Redis", func() {
+	var ctx context.Context
+	var h *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		_, ctx, h = setupHarborTest(context.TODO())
+
+		h.Spec.Components = goharborv1alpha1.HarborComponents{
+			Registry: &goharborv1alpha1.RegistryComponent{},
+			JobService: &goharborv1alpha1.JobServiceComponent{
+				Redis: &goharborv1alpha1.RedisSpec{
+					Hosts:             []string{"sentinel-0:26379", "sentinel-1:26379"},
+					SentinelMasterSet: "mymaster",
+					PasswordSecret:    "redis",
+					Database:          1,
+				},
+			},
+		}
+	})
+
+	env := func(envVars []corev1.EnvVar) map[string]corev1.EnvVar {
+		result := map[string]corev1.EnvVar{}
+		for _, e := range envVars {
+			result[e.Name] = e
+		}
+
+		return result
+	}
+
+	It("Should render urls of Redis servers", func() {
+		spec := &goharborv1alpha1.RedisSpec{Hosts: []string{"redis:6379"}}
+		Expect(redis.URL(spec, "PASSWORD")).To(Equal("redis://redis:6379/0"))
+
+		spec.TLS = true
+		spec.PasswordSecret = "redis"
+		spec.Database = 2
+		Expect(redis.URL(spec, "PASSWORD")).To(Equal("rediss://:$(PASSWORD)@redis:6379/2"))
+
+		Expect(redis.URL(h.Spec.Components.JobService.Redis, "PASSWORD")).To(Equal("redis+sentinel://:$(PASSWORD)@sentinel-0:26379,sentinel-1:26379/mymaster/1"))
+	})
+
+	It("Should declare the password before the url of jobservice", func() {
+		c, err := components.GetComponents(ctx, h)
+		Expect(err).ToNot(HaveOccurred())
+
+		envVars := c.JobService.Component.GetDeployments(ctx)[0].Spec.Template.Spec.Containers[0].Env
+
+		indexes := map[string]int{}
+		for i, e := range envVars {
+			indexes[e.Name] = i
+		}
+		Expect(indexes).To(HaveKey("JOB_SERVICE_POOL_REDIS_URL_PASSWORD"))
+		Expect(indexes["JOB_SERVICE_POOL_REDIS_URL_PASSWORD"]).To(BeNumerically("<", indexes["JOB_SERVICE_POOL_REDIS_URL"]))
+
+		password := env(envVars)["JOB_SERVICE_POOL_REDIS_URL_PASSWORD"]
+		Expect(password.ValueFrom.SecretKeyRef.Name).To(Equal("redis"))
+		Expect(password.ValueFrom.SecretKeyRef.Key).To(Equal(goharborv1alpha1.HarborRedisPasswordKey))
+
+		Expect(env(envVars)).To(HaveKeyWithValue("JOB_SERVICE_POOL_REDIS_NAMESPACE", corev1.EnvVar{
+			Name:  "JOB_SERVICE_POOL_REDIS_NAMESPACE",
+			Value: "harbor_job_service_namespace",
+		}))
+
+		Expect(ReferencedSecrets(h)).To(ContainElement("redis"))
+	})
+
+	It("Should pass the registry cache to the configuration template", func() {
+		h.Spec.Components.Registry.Cache = &goharborv1alpha1.RedisSpec{
+			Hosts:          []string{"redis:6379"},
+			PasswordSecret: "registry-redis",
+			PasswordKey:    "redis-password",
+			TLS:            true,
+		}
+
+		c, err := components.GetComponents(ctx, h)
+		Expect(err).ToNot(HaveOccurred())
+
+		envVars := env(c.Registry.Component.GetDeployments(ctx)[0].Spec.Template.Spec.InitContainers[0].Env)
+		Expect(envVars["REDIS_ADDR"].Value).To(Equal("redis:6379"))
+		Expect(envVars["REDIS_TLS"].Value).To(Equal("true"))
+		Expect(envVars["REDIS_PASSWORD"].ValueFrom.SecretKeyRef.Key).To(Equal("redis-password"))
+		Expect(envVars).ToNot(HaveKey("REDIS_URL"))
+	})
+
+	It("Should store core sessions and the chart cache on Redis", func() {
+		h.Spec.Components.Core = &goharborv1alpha1.CoreComponent{}
+		h.Spec.Components.JobService.WorkerCount = 3
+
+		c, err := components.GetComponents(ctx, h)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(c.Core.Component.GetConfigMaps(ctx)[0].Data).To(HaveKeyWithValue("CHART_CACHE_DRIVER", "memory"))
+		envVars := env(c.Core.Component.GetDeployments(ctx)[0].Spec.Template.Spec.Containers[0].Env)
+		Expect(envVars).To(HaveKeyWithValue("_REDIS_URL", corev1.EnvVar{Name: "_REDIS_URL"}))
+		Expect(envVars).ToNot(HaveKey("_REDIS_URL_CORE"))
+
+		h.Spec.Components.Core.Redis = &goharborv1alpha1.RedisSpec{
+			Hosts:          []string{"redis:6379"},
+			PasswordSecret: "core-redis",
+			Database:       3,
+		}
+
+		c, err = components.GetComponents(ctx, h)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(c.Core.Component.GetConfigMaps(ctx)[0].Data).To(HaveKeyWithValue("CHART_CACHE_DRIVER", "redis"))
+		Expect(c.Core.Component.GetConfigMaps(ctx)[0].Data).ToNot(HaveKey("_REDIS_URL"))
+
+		envVars = env(c.Core.Component.GetDeployments(ctx)[0].Spec.Template.Spec.Containers[0].Env)
+		Expect(envVars["_REDIS_URL"].Value).To(Equal("redis:6379,100,$(_REDIS_URL_CORE_PASSWORD),3"))
+		Expect(envVars["_REDIS_URL_CORE"].Value).To(Equal("redis://:$(_REDIS_URL_CORE_PASSWORD)@redis:6379/3"))
+		Expect(envVars["_REDIS_URL_CORE_PASSWORD"].ValueFrom.SecretKeyRef.Name).To(Equal("core-redis"))
+
+		Expect(ReferencedSecrets(h)).To(ContainElement("core-redis"))
+	})
+
+	It("Should warn about core replicas without Redis", func() {
+		replicas := int32(2)
+		h.Spec.Components.Core = &goharborv1alpha1.CoreComponent{
+			HarborDeployment: goharborv1alpha1.HarborDeployment{Replicas: &replicas},
+		}
+		Expect(h.Warnings()).To(HaveLen(1))
+		Expect(h.ValidateCreate()).To(Succeed())
+
+		h.Spec.Components.Core.Redis = &goharborv1alpha1.RedisSpec{Hosts: []string{"redis:6379"}}
+		Expect(h.Warnings()).To(BeEmpty())
+		Expect(h.ValidateCreate()).To(Succeed())
+
+		h.Spec.Components.Core.Redis.TLS = true
+		Expect(h.ValidateCreate()).ToNot(Succeed())
+	})
+
+	It("Should validate Redis references", func() {
+		Expect(h.ValidateCreate()).To(Succeed())
+
+		h.Spec.Components.JobService.RedisSecret = "jobservice-redis"
+		Expect(h.ValidateCreate()).ToNot(Succeed())
+
+		h.Spec.Components.JobService.Redis = nil
+		Expect(h.ValidateCreate()).To(Succeed())
+
+		h.Spec.Components.JobService.RedisSecret = ""
+		Expect(h.ValidateCreate()).ToNot(Succeed())
+
+		h.Spec.Components.JobService.Redis = &goharborv1alpha1.RedisSpec{Hosts: []string{"redis-0:6379", "redis-1:6379"}}
+		Expect(h.ValidateCreate()).ToNot(Succeed())
+
+		h.Spec.Components.JobService.Redis = &goharborv1alpha1.RedisSpec{Hosts: []string{"redis"}}
+		Expect(h.ValidateCreate()).ToNot(Succeed())
+
+		h.Spec.Components.JobService.Redis = &goharborv1alpha1.RedisSpec{Hosts: []string{"redis:6379"}}
+		h.Spec.Components.ChartMuseum = &goharborv1alpha1.ChartMuseumComponent{
+			Cache: &goharborv1alpha1.RedisSpec{Hosts: []string{"redis:6379"}, TLS: true},
+		}
+		Expect(h.ValidateCreate()).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,89 @@
+package harbor
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/rollout"
+)
+
+const (
+	EventReasonDeploymentRestarted = "DeploymentRestarted"
+)
+
+// restartAnnotations are set on pod templates by the operator to restart deployments,
+// they are kept when deployments are applied.
+var restartAnnotations = []string{
+	goharborv1alpha1.InternalSecretsRotationAnnotation,
+	goharborv1alpha1.CertificatesRenewalAnnotation,
+}
+
+// RestartDeployments restarts the deployments of components one after the other, in order, to read updated secrets.
+// A deployment is restarted by setting the time of the update in the annotation of its pod template,
+// once the previous one is rolled out.
+func (r *Reconciler) RestartDeployments(ctx context.Context, result *ctrl.Result, harbor *goharborv1alpha1.Harbor, annotation string, updateTime *metav1.Time, components []string, reason string) error {
+	if updateTime == nil {
+		return nil
+	}
+
+	value := updateTime.UTC().Format(time.RFC3339)
+
+	for _, component := range components {
+		name := harbor.NormalizeComponentName(component)
+		deployment := &appsv1.Deployment{}
+
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: harbor.GetNamespace(), Name: name}, deployment)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return errors.Wrapf(err, "cannot get deployment %s", name)
+		}
+
+		if deployment.Spec.Template.GetAnnotations()[annotation] != value {
+			if deployment.Spec.Template.Annotations == nil {
+				deployment.Spec.Template.Annotations = map[string]string{}
+			}
+
+			deployment.Spec.Template.Annotations[annotation] = value
+
+			err := r.Client.Update(ctx, deployment)
+			if err != nil {
+				return errors.Wrapf(err, "cannot update deployment %s", name)
+			}
+
+			logger.Get(ctx).Info("deployment restarted", "Deployment.Name", name)
+			r.Recorder.Eventf(harbor, corev1.EventTypeNormal, EventReasonDeploymentRestarted, "deployment %s restarted to read %s", name, reason)
+
+			requeueBefore(result, DefaultRequeueWait)
+
+			return nil
+		}
+
+		if !rollout.IsComplete(deployment) {
+			requeueBefore(result, DefaultRequeueWait)
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// requeueBefore reduces the requeue delay of the result to at most the given duration.
+func requeueBefore(result *ctrl.Result, after time.Duration) {
+	if result.RequeueAfter == 0 || after < result.RequeueAfter {
+		result.RequeueAfter = after
+	}
+}
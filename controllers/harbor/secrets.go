@@ -0,0 +1,103 @@
+package harbor
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+)
+
+// ReferencedSecrets returns the name of secrets referenced by the Harbor spec.
+// Those secrets are expected to be created by the user.
+func ReferencedSecrets(harbor *goharborv1alpha1.Harbor) []string {
+	secrets := []string{
+		harbor.Spec.AdminPasswordSecret,
+		harbor.Spec.TLSSecretName,
+	}
+
+	if harbor.GetCertificatesMode() == goharborv1alpha1.CertificatesModeProvided {
+		secrets = append(secrets, harbor.TokenCertificateSecretName())
+
+		if harbor.Spec.Components.Notary != nil {
+			secrets = append(secrets, harbor.NotaryCertificateSecretName())
+		}
+	}
+
+	components := harbor.Spec.Components
+
+	if components.Core != nil {
+		secrets = append(secrets, components.Core.DatabaseSecret, redisPasswordSecret(components.Core.Redis))
+	}
+
+	if components.Registry != nil {
+		secrets = append(secrets, components.Registry.StorageSecret, components.Registry.CacheSecret, redisPasswordSecret(components.Registry.Cache))
+	}
+
+	if components.JobService != nil {
+		secrets = append(secrets, components.JobService.RedisSecret, redisPasswordSecret(components.JobService.Redis))
+	}
+
+	if components.ChartMuseum != nil {
+		secrets = append(secrets, components.ChartMuseum.StorageSecret, components.ChartMuseum.CacheSecret, redisPasswordSecret(components.ChartMuseum.Cache))
+	}
+
+	if components.Clair != nil {
+		secrets = append(secrets, components.Clair.DatabaseSecret, components.Clair.Adapter.RedisSecret, redisPasswordSecret(components.Clair.Adapter.Redis))
+	}
+
+	if components.Notary != nil {
+		secrets = append(secrets, components.Notary.Signer.DatabaseSecret, components.Notary.Server.DatabaseSecret)
+	}
+
+	if bundle := harbor.Spec.TrustedCABundle; bundle != nil && bundle.ConfigMapRef == nil && bundle.SecretRef != nil {
+		secrets = append(secrets, bundle.SecretRef.Name)
+	}
+
+	result := make([]string, 0, len(secrets))
+	found := map[string]bool{}
+
+	for _, secret := range secrets {
+		if secret == "" || found[secret] {
+			continue
+		}
+
+		found[secret] = true
+
+		result = append(result, secret)
+	}
+
+	return result
+}
+
+func redisPasswordSecret(redis *goharborv1alpha1.RedisSpec) string {
+	if redis == nil {
+		return ""
+	}
+
+	return redis.PasswordSecret
+}
+
+// CheckReferencedSecrets emits a warning event for each secret referenced by the Harbor spec which does not exist.
+// Missing secrets do not prevent resources to be applied, pods will start once secrets are created.
+func (r *Reconciler) CheckReferencedSecrets(ctx context.Context, harbor *goharborv1alpha1.Harbor) error {
+	for _, name := range ReferencedSecrets(harbor) {
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: harbor.GetNamespace(), Name: name}, &corev1.Secret{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				logger.Get(ctx).Info("referenced secret not found", "Secret.Name", name)
+				r.Recorder.Eventf(harbor, corev1.EventTypeWarning, EventReasonSecretNotFound, "secret %s not found", name)
+
+				continue
+			}
+
+			return errors.Wrapf(err, "cannot get secret %s", name)
+		}
+	}
+
+	return nil
+}
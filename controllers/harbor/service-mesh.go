@@ -0,0 +1,166 @@
+package harbor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components/expose"
+)
+
+const (
+	// IstioSidecarInjectLabel requests the injection of the istio sidecar in pods.
+	IstioSidecarInjectLabel = "sidecar.istio.io/inject"
+
+	istioTLSModeIstioMutual = "ISTIO_MUTUAL"
+)
+
+var (
+	// VirtualServiceGVK is the kind of Istio virtual services.
+	VirtualServiceGVK = schema.GroupVersionKind{
+		Group:   "networking.istio.io",
+		Version: "v1beta1",
+		Kind:    "VirtualService",
+	}
+
+	// DestinationRuleGVK is the kind of Istio destination rules.
+	DestinationRuleGVK = schema.GroupVersionKind{
+		Group:   "networking.istio.io",
+		Version: "v1beta1",
+		Kind:    "DestinationRule",
+	}
+)
+
+func istioGateways(harbor *goharborv1alpha1.Harbor) []interface{} {
+	var gateways []interface{}
+
+	if harbor.Spec.Expose == nil || harbor.Spec.Expose.Istio == nil {
+		return gateways
+	}
+
+	for _, gateway := range harbor.Spec.Expose.Istio.Gateways {
+		gateways = append(gateways, gateway)
+	}
+
+	return gateways
+}
+
+// VirtualServices returns the Istio virtual services replacing ingresses, one per host.
+// The host of the internal endpoint is routed with the host of the public url.
+func VirtualServices(ctx context.Context, harbor *goharborv1alpha1.Harbor) []*unstructured.Unstructured {
+	var virtualServices []*unstructured.Unstructured
+
+	for i, host := range expose.Hosts(harbor) {
+		name := harbor.GetName()
+		if i > 0 {
+			name = harbor.NormalizeComponentName(host.Paths[0].Component)
+		}
+
+		names := host.Names
+		if internal := expose.InternalHost(harbor); i == 0 && internal != nil {
+			names = append(append([]string{}, names...), internal.Names...)
+		}
+
+		// Routes are evaluated in order, so longest prefixes come first
+		paths := append([]expose.Path{}, host.Paths...)
+		sort.SliceStable(paths, func(i, j int) bool {
+			return len(paths[i].Prefix) > len(paths[j].Prefix)
+		})
+
+		routes := make([]interface{}, 0, len(paths))
+
+		for _, path := range paths {
+			routes = append(routes, map[string]interface{}{
+				"match": []interface{}{
+					map[string]interface{}{
+						"uri": map[string]interface{}{
+							"prefix": path.Prefix,
+						},
+					},
+				},
+				"route": []interface{}{
+					map[string]interface{}{
+						"destination": map[string]interface{}{
+							"host": path.ServiceName,
+							"port": map[string]interface{}{
+								"number": int64(path.ServicePort),
+							},
+						},
+					},
+				},
+			})
+		}
+
+		spec := map[string]interface{}{
+			"hosts": gatewayHostnames(expose.Host{Names: names}),
+			"http":  routes,
+		}
+
+		if gateways := istioGateways(harbor); len(gateways) > 0 {
+			spec["gateways"] = gateways
+		}
+
+		virtualServices = append(virtualServices, newExposeObject(ctx, harbor, VirtualServiceGVK, name, spec))
+	}
+
+	return virtualServices
+}
+
+// DestinationRules returns the Istio destination rules of the services routed by virtual services, so the mesh handles mTLS.
+func DestinationRules(ctx context.Context, harbor *goharborv1alpha1.Harbor) []*unstructured.Unstructured {
+	var destinationRules []*unstructured.Unstructured
+
+	services := map[string]bool{}
+
+	for _, host := range expose.Hosts(harbor) {
+		for _, path := range host.Paths {
+			if services[path.ServiceName] {
+				continue
+			}
+
+			services[path.ServiceName] = true
+
+			destinationRules = append(destinationRules, newExposeObject(ctx, harbor, DestinationRuleGVK, path.ServiceName, map[string]interface{}{
+				"host": fmt.Sprintf("%s.%s.svc.cluster.local", path.ServiceName, harbor.GetNamespace()),
+				"trafficPolicy": map[string]interface{}{
+					"tls": map[string]interface{}{
+						"mode": istioTLSModeIstioMutual,
+					},
+				},
+			}))
+		}
+	}
+
+	return destinationRules
+}
+
+// injectSidecars wraps the run of component deployments, so the sidecar of the service mesh is injected in their pods.
+func (r *Reconciler) injectSidecars(run components.ComponentRun) components.ComponentRun {
+	return func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
+		if !harbor.IsServiceMeshEnabled() {
+			return run(ctx, harbor, resources)
+		}
+
+		for _, resource := range resources {
+			deployment, ok := resource.(*appsv1.Deployment)
+			if !ok {
+				return errors.Errorf("unexpected deployment %+v", resource)
+			}
+
+			if deployment.Spec.Template.Labels == nil {
+				deployment.Spec.Template.Labels = map[string]string{}
+			}
+
+			deployment.Spec.Template.Labels[IstioSidecarInjectLabel] = "true"
+		}
+
+		return run(ctx, harbor, resources)
+	}
+}
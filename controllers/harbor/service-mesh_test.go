@@ -0,0 +1,132 @@
+package harbor
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+)
+
+var _ = Describe("Service mesh", func() {
+	var ctx context.Context
+	var h *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		_, ctx, h = setupHarborTest(context.TODO())
+
+		h.Spec.ServiceMesh = goharborv1alpha1.ServiceMeshIstio
+		h.Spec.Components = goharborv1alpha1.HarborComponents{
+			Core:     &goharborv1alpha1.CoreComponent{},
+			Registry: &goharborv1alpha1.RegistryComponent{},
+			Portal:   &goharborv1alpha1.PortalComponent{},
+			Notary: &goharborv1alpha1.NotaryComponent{
+				PublicURL: "https://notary.example.com",
+			},
+		}
+	})
+
+	It("Should name ports after their protocol", func() {
+		c, err := components.GetComponents(ctx, h)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(c.Core.Component.GetServices(ctx)[0].Spec.Ports[0].Name).To(Equal("http"))
+		Expect(c.Core.Component.GetIngresses(ctx)).To(BeEmpty())
+
+		ports := map[string]string{}
+		for _, service := range c.Notary.Component.GetServices(ctx) {
+			ports[service.GetName()] = service.Spec.Ports[0].Name
+		}
+		Expect(ports).To(HaveKeyWithValue("harbor-notary-server", "http-notary-server"))
+		Expect(ports).To(HaveKeyWithValue("harbor-notary-signer", "tls-notary-signer"))
+
+		h.Spec.InternalTLS = &goharborv1alpha1.InternalTLSSpec{Enabled: true}
+		c, err = components.GetComponents(ctx, h)
+		Expect(err).ToNot(HaveOccurred())
+
+		ports = map[string]string{}
+		for _, port := range c.Registry.Component.GetServices(ctx)[0].Spec.Ports {
+			ports[port.Name] = port.Name
+		}
+		Expect(ports).To(HaveKey("https-registry"))
+		Expect(ports).To(HaveKey("http-registry-debug"))
+	})
+
+	It("Should render virtual services and destination rules instead of ingresses", func() {
+		h.Spec.InternalEndpoint = &goharborv1alpha1.InternalEndpointSpec{URL: "http://harbor.internal"}
+		h.Spec.Expose = &goharborv1alpha1.ExposeSpec{
+			Istio: &goharborv1alpha1.ExposeIstioSpec{
+				Gateways: []string{"istio-system/public"},
+			},
+		}
+
+		Expect(h.IsIngressEnabled()).To(BeFalse())
+
+		virtualServices := VirtualServices(ctx, h)
+		Expect(virtualServices).To(HaveLen(2))
+		Expect(virtualServices[0].GetName()).To(Equal("harbor"))
+		Expect(virtualServices[1].GetName()).To(Equal("harbor-notary"))
+
+		spec := virtualServices[0].Object["spec"].(map[string]interface{})
+		Expect(spec).To(HaveKeyWithValue("hosts", []interface{}{"harbor.example.com", "harbor.internal"}))
+		Expect(spec).To(HaveKeyWithValue("gateways", []interface{}{"istio-system/public"}))
+
+		routes := spec["http"].([]interface{})
+		Expect(routes).To(HaveLen(5))
+		Expect(routes[len(routes)-1]).To(HaveKeyWithValue("match", []interface{}{
+			map[string]interface{}{"uri": map[string]interface{}{"prefix": "/"}},
+		}))
+
+		hosts := []interface{}{}
+		for _, rule := range DestinationRules(ctx, h) {
+			Expect(rule.GroupVersionKind()).To(Equal(DestinationRuleGVK))
+			Expect(rule.Object["spec"].(map[string]interface{})["trafficPolicy"]).To(HaveKeyWithValue("tls", map[string]interface{}{"mode": "ISTIO_MUTUAL"}))
+			hosts = append(hosts, rule.Object["spec"].(map[string]interface{})["host"])
+		}
+		Expect(hosts).To(ConsistOf(
+			"harbor-core.ns.svc.cluster.local",
+			"harbor-registry.ns.svc.cluster.local",
+			"harbor-portal.ns.svc.cluster.local",
+			"harbor-notary-server.ns.svc.cluster.local",
+		))
+	})
+
+	It("Should inject sidecars in deployments", func() {
+		var result []components.Resource
+
+		run := (&Reconciler{}).injectSidecars(func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
+			result = resources
+			return nil
+		})
+
+		Expect(run(ctx, h, []components.Resource{&appsv1.Deployment{}})).To(Succeed())
+		Expect(result[0].(*appsv1.Deployment).Spec.Template.GetLabels()).To(HaveKeyWithValue(IstioSidecarInjectLabel, "true"))
+
+		h.Spec.ServiceMesh = ""
+		Expect(run(ctx, h, []components.Resource{&appsv1.Deployment{}})).To(Succeed())
+		Expect(result[0].(*appsv1.Deployment).Spec.Template.GetLabels()).ToNot(HaveKey(IstioSidecarInjectLabel))
+	})
+
+	It("Should report responses of the sidecar as unhealthy", func() {
+		health, err := decodeHealth(h, []byte("no healthy upstream"), errors.New("service unavailable"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(health.IsHealthy()).To(BeFalse())
+		Expect(health.GetUnhealthyComponents()).To(Equal([]string{goharborv1alpha1.CoreName}))
+
+		health, err = decodeHealth(h, []byte(`{"status":"healthy"}`), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(health.IsHealthy()).To(BeTrue())
+
+		_, err = decodeHealth(h, nil, errors.New("connection refused"))
+		Expect(err).To(HaveOccurred())
+
+		h.Spec.ServiceMesh = ""
+		_, err = decodeHealth(h, []byte("no healthy upstream"), errors.New("service unavailable"))
+		Expect(err).To(HaveOccurred())
+	})
+})
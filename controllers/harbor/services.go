@@ -0,0 +1,255 @@
+package harbor
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+)
+
+// serviceSpecs returns the customization of services by name.
+func serviceSpecs(harbor *goharborv1alpha1.Harbor) map[string]*goharborv1alpha1.ComponentServiceSpec {
+	specs := map[string]*goharborv1alpha1.ComponentServiceSpec{}
+
+	add := func(name string, deployment goharborv1alpha1.HarborDeployment) {
+		if deployment.Service != nil {
+			specs[harbor.NormalizeComponentName(name)] = deployment.Service
+		}
+	}
+
+	c := harbor.Spec.Components
+
+	if c.Core != nil {
+		add(goharborv1alpha1.CoreName, c.Core.HarborDeployment)
+	}
+
+	if c.Portal != nil {
+		add(goharborv1alpha1.PortalName, c.Portal.HarborDeployment)
+	}
+
+	if c.Registry != nil {
+		add(goharborv1alpha1.RegistryName, c.Registry.HarborDeployment)
+	}
+
+	if c.JobService != nil {
+		add(goharborv1alpha1.JobServiceName, c.JobService.HarborDeployment)
+	}
+
+	if c.ChartMuseum != nil {
+		add(goharborv1alpha1.ChartMuseumName, c.ChartMuseum.HarborDeployment)
+	}
+
+	if c.Clair != nil {
+		add(goharborv1alpha1.ClairName, c.Clair.HarborDeployment)
+	}
+
+	if c.Notary != nil {
+		add(goharborv1alpha1.NotaryServerName, c.Notary.Server.HarborDeployment)
+		add(goharborv1alpha1.NotarySignerName, c.Notary.Signer.HarborDeployment)
+	}
+
+	// Other settings of the proxy service are rendered by the nginx component
+	if harbor.Spec.Expose != nil && harbor.Spec.Expose.Service != nil {
+		specs[harbor.NormalizeComponentName(goharborv1alpha1.NginxName)] = &goharborv1alpha1.ComponentServiceSpec{
+			ServiceIPFamilies: harbor.Spec.Expose.Service.ServiceIPFamilies,
+		}
+	}
+
+	return specs
+}
+
+// CustomizeService returns the service with the customization of the spec.
+// IP families are not part of the vendored API, services with IP families are rendered as unstructured objects.
+func CustomizeService(service *corev1.Service, spec *goharborv1alpha1.ComponentServiceSpec) (components.Resource, error) {
+	if spec == nil {
+		return service, nil
+	}
+
+	result := service.DeepCopy()
+
+	if spec.Type != "" {
+		result.Spec.Type = spec.Type
+	}
+
+	if len(spec.Labels) > 0 {
+		labels := make(map[string]string, len(spec.Labels)+len(result.Labels))
+
+		for key, value := range spec.Labels {
+			labels[key] = value
+		}
+
+		for key, value := range result.Labels {
+			labels[key] = value
+		}
+
+		result.SetLabels(labels)
+	}
+
+	if len(spec.Annotations) > 0 {
+		if result.Annotations == nil {
+			result.Annotations = map[string]string{}
+		}
+
+		for key, value := range spec.Annotations {
+			result.Annotations[key] = value
+		}
+	}
+
+	if spec.SessionAffinity != "" {
+		result.Spec.SessionAffinity = spec.SessionAffinity
+	}
+
+	if spec.SessionAffinityConfig != nil {
+		result.Spec.SessionAffinityConfig = spec.SessionAffinityConfig.DeepCopy()
+	}
+
+	result.Spec.Ports = append(result.Spec.Ports, spec.ExtraPorts...)
+
+	if spec.IPFamilyPolicy == "" && len(spec.IPFamilies) == 0 {
+		return result, nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(result)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert service")
+	}
+
+	delete(content, "status")
+
+	if spec.IPFamilyPolicy != "" {
+		err = unstructured.SetNestedField(content, string(spec.IPFamilyPolicy), "spec", "ipFamilyPolicy")
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot set ip family policy")
+		}
+	}
+
+	if len(spec.IPFamilies) > 0 {
+		families := make([]string, len(spec.IPFamilies))
+		for i, family := range spec.IPFamilies {
+			families[i] = string(family)
+		}
+
+		err = unstructured.SetNestedStringSlice(content, families, "spec", "ipFamilies")
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot set ip families")
+		}
+	}
+
+	converted := &unstructured.Unstructured{Object: content}
+	converted.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+
+	return converted, nil
+}
+
+// customizeServices wraps the run of component services, so they are customized with the spec of their component.
+func (r *Reconciler) customizeServices(run components.ComponentRun) components.ComponentRun {
+	return func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
+		specs := serviceSpecs(harbor)
+		customized := make([]components.Resource, len(resources))
+
+		for i, resource := range resources {
+			service, ok := resource.(*corev1.Service)
+			if !ok {
+				return errors.Errorf("unexpected service %+v", resource)
+			}
+
+			result, err := CustomizeService(service, specs[service.GetName()])
+			if err != nil {
+				return errors.Wrap(err, service.GetName())
+			}
+
+			customized[i] = result
+		}
+
+		return run(ctx, harbor, customized)
+	}
+}
+
+// applyServices applies typed services and services rendered as unstructured objects.
+func (r *Reconciler) applyServices(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
+	var services, unstructuredServices []components.Resource
+
+	for _, resource := range resources {
+		if _, ok := resource.(*unstructured.Unstructured); ok {
+			unstructuredServices = append(unstructuredServices, resource)
+		} else {
+			services = append(services, resource)
+		}
+	}
+
+	err := r.ApplyResources(ctx, harbor, services, func() components.Resource { return &corev1.Service{} }, mutateService)
+	if err != nil {
+		return err
+	}
+
+	return r.ApplyResources(ctx, harbor, unstructuredServices, func() components.Resource {
+		service := &unstructured.Unstructured{}
+		service.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+
+		return service
+	}, mutateUnstructuredService)
+}
+
+// mutateUnstructuredService copies labels, annotations and spec of services rendered as unstructured objects,
+// keeping allocated cluster IPs and node ports.
+func mutateUnstructuredService(resource, result components.Resource) controllerutil.MutateFn {
+	unstructuredResult, ok := result.(*unstructured.Unstructured)
+	desired := resource.(*unstructured.Unstructured)
+
+	return func() error {
+		if !ok {
+			return errors.Errorf("unexpected argument %+v", result)
+		}
+
+		spec, _, err := unstructured.NestedMap(desired.DeepCopy().Object, "spec")
+		if err != nil {
+			return errors.Wrap(err, "invalid service spec")
+		}
+
+		// Immutable fields
+		for _, field := range []string{"clusterIP", "clusterIPs"} {
+			if value, found, _ := unstructured.NestedFieldNoCopy(unstructuredResult.Object, "spec", field); found {
+				spec[field] = value
+			}
+		}
+
+		if spec["type"] != string(corev1.ServiceTypeClusterIP) && spec["type"] != nil {
+			keepNodePorts(unstructuredResult, spec)
+		}
+
+		unstructuredResult.SetLabels(desired.GetLabels())
+		unstructuredResult.SetAnnotations(desired.GetAnnotations())
+		unstructuredResult.Object["spec"] = spec
+
+		return nil
+	}
+}
+
+// keepNodePorts sets the node ports allocated to the current ports in the desired spec, by port name.
+func keepNodePorts(current *unstructured.Unstructured, spec map[string]interface{}) {
+	currentPorts, _, _ := unstructured.NestedSlice(current.Object, "spec", "ports")
+
+	nodePorts := map[interface{}]interface{}{}
+
+	for _, port := range currentPorts {
+		if port, ok := port.(map[string]interface{}); ok && port["nodePort"] != nil {
+			nodePorts[port["name"]] = port["nodePort"]
+		}
+	}
+
+	ports, _ := spec["ports"].([]interface{})
+
+	for _, port := range ports {
+		if port, ok := port.(map[string]interface{}); ok && port["nodePort"] == nil {
+			if nodePort, ok := nodePorts[port["name"]]; ok {
+				port["nodePort"] = nodePort
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package harbor
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers/harbor/components"
+)
+
+var _ = Describe("Services", func() {
+	var ctx context.Context
+	var h *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		_, ctx, h = setupHarborTest(context.TODO())
+
+		h.Spec.Components = goharborv1alpha1.HarborComponents{
+			Registry: &goharborv1alpha1.RegistryComponent{},
+			Portal:   &goharborv1alpha1.PortalComponent{},
+		}
+	})
+
+	customize := func() []components.Resource {
+		c, err := components.GetComponents(ctx, h)
+		Expect(err).ToNot(HaveOccurred())
+
+		var result []components.Resource
+
+		run := (&Reconciler{}).customizeServices(func(ctx context.Context, harbor *goharborv1alpha1.Harbor, resources []components.Resource) error {
+			result = resources
+			return nil
+		})
+
+		Expect(run(ctx, h, c.Registry.GetServices(ctx))).To(Succeed())
+		Expect(result).To(HaveLen(1))
+
+		return result
+	}
+
+	It("Should customize the service of a component", func() {
+		h.Spec.Components.Registry.Service = &goharborv1alpha1.ComponentServiceSpec{
+			Type:            corev1.ServiceTypeLoadBalancer,
+			Annotations:     map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+			Labels:          map[string]string{"app": "other", "team": "registry"},
+			SessionAffinity: corev1.ServiceAffinityClientIP,
+			ExtraPorts:      []corev1.ServicePort{{Name: "extra", Port: 9000}},
+		}
+
+		service, ok := customize()[0].(*corev1.Service)
+		Expect(ok).To(BeTrue())
+		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(service.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityClientIP))
+		Expect(service.GetAnnotations()).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-internal", "true"))
+		Expect(service.GetLabels()).To(HaveKeyWithValue("app", goharborv1alpha1.RegistryName))
+		Expect(service.GetLabels()).To(HaveKeyWithValue("team", "registry"))
+		Expect(service.Spec.Ports[len(service.Spec.Ports)-1].Name).To(Equal("extra"))
+	})
+
+	It("Should render IP families", func() {
+		h.Spec.Components.Registry.Service = &goharborv1alpha1.ComponentServiceSpec{
+			ServiceIPFamilies: goharborv1alpha1.ServiceIPFamilies{
+				IPFamilyPolicy: goharborv1alpha1.IPFamilyPolicyRequireDualStack,
+				IPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			},
+		}
+
+		service, ok := customize()[0].(*unstructured.Unstructured)
+		Expect(ok).To(BeTrue())
+		Expect(service.GetKind()).To(Equal("Service"))
+		Expect(service.GetName()).To(Equal("harbor-registry"))
+		Expect(service.Object["spec"]).To(HaveKeyWithValue("ipFamilyPolicy", "RequireDualStack"))
+		Expect(service.Object["spec"]).To(HaveKeyWithValue("ipFamilies", []interface{}{"IPv6", "IPv4"}))
+
+		current := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"clusterIP":  "fd00::10",
+				"clusterIPs": []interface{}{"fd00::10", "10.0.0.10"},
+			},
+		}}
+		Expect(mutateUnstructuredService(service, current)()).To(Succeed())
+		Expect(current.Object["spec"]).To(HaveKeyWithValue("clusterIP", "fd00::10"))
+		Expect(current.Object["spec"]).To(HaveKeyWithValue("ipFamilyPolicy", "RequireDualStack"))
+		Expect(current.GetLabels()).To(HaveKeyWithValue("app", goharborv1alpha1.RegistryName))
+	})
+
+	It("Should keep node ports unless the service type is ClusterIP", func() {
+		current := &corev1.Service{
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeNodePort,
+				ClusterIP: "10.0.0.10",
+				Ports:     []corev1.ServicePort{{Name: "http", Port: 80, NodePort: 30080}},
+			},
+		}
+
+		desired := &corev1.Service{
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeNodePort,
+				Ports: []corev1.ServicePort{{Name: "http", Port: 80}},
+			},
+		}
+
+		Expect(mutateService(desired.DeepCopy(), current)()).To(Succeed())
+		Expect(current.Spec.ClusterIP).To(Equal("10.0.0.10"))
+		Expect(current.Spec.Ports[0].NodePort).To(BeEquivalentTo(30080))
+
+		desired.Spec.Type = corev1.ServiceTypeClusterIP
+
+		Expect(mutateService(desired.DeepCopy(), current)()).To(Succeed())
+		Expect(current.Spec.Ports[0].NodePort).To(BeZero())
+	})
+
+	It("Should listen on IPv6 addresses", func() {
+		h.Spec.Components.Portal.Service = &goharborv1alpha1.ComponentServiceSpec{
+			ServiceIPFamilies: goharborv1alpha1.ServiceIPFamilies{
+				IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol},
+			},
+		}
+
+		c, err := components.GetComponents(ctx, h)
+		Expect(err).ToNot(HaveOccurred())
+
+		configMaps := c.Portal.Component.GetConfigMaps(ctx)
+		Expect(configMaps).To(HaveLen(1))
+
+		config := string(configMaps[0].BinaryData["nginx.conf"])
+		Expect(config).To(ContainSubstring("listen [::]:8080;"))
+		Expect(config).ToNot(ContainSubstring("ssl_certificate"))
+	})
+})
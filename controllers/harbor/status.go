@@ -51,7 +51,8 @@ func (r *Reconciler) UpdateCondition(ctx context.Context, harbor *goharborv1alph
 		if condition.Type == conditionType {
 			now.DeepCopyInto(&condition.LastUpdateTime)
 
-			if condition.LastTransitionTime.IsZero() || condition.Status != status {
+			transition := condition.LastTransitionTime.IsZero() || condition.Status != status
+			if transition {
 				now.DeepCopyInto(&condition.LastTransitionTime)
 			}
 
@@ -61,6 +62,10 @@ func (r *Reconciler) UpdateCondition(ctx context.Context, harbor *goharborv1alph
 
 			harbor.Status.Conditions[i] = condition
 
+			if transition {
+				r.conditionEvent(harbor, condition)
+			}
+
 			return nil
 		}
 	}
@@ -76,9 +81,24 @@ func (r *Reconciler) UpdateCondition(ctx context.Context, harbor *goharborv1alph
 
 	harbor.Status.Conditions = append(harbor.Status.Conditions, condition)
 
+	r.conditionEvent(harbor, condition)
+
 	return nil
 }
 
+// RemoveCondition removes the condition from the status, when it is no longer relevant.
+func (r *Reconciler) RemoveCondition(ctx context.Context, harbor *goharborv1alpha1.Harbor, conditionType goharborv1alpha1.HarborConditionType) {
+	conditions := make([]goharborv1alpha1.HarborCondition, 0, len(harbor.Status.Conditions))
+
+	for _, condition := range harbor.Status.Conditions {
+		if condition.Type != conditionType {
+			conditions = append(conditions, condition)
+		}
+	}
+
+	harbor.Status.Conditions = conditions
+}
+
 // UpdateStatus applies current in-memory statuses to the remote resource
 // https://kubernetes.io/docs/tasks/access-kubernetes-api/custom-resources/custom-resource-definitions/#status-subresource
 func (r *Reconciler) UpdateStatus(ctx context.Context, result *ctrl.Result, harbor *goharborv1alpha1.Harbor) error {
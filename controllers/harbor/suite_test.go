@@ -7,11 +7,15 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	// +kubebuilder:scaffold:imports
 
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
 	"github.com/goharbor/harbor-operator/pkg/factories/logger"
 	"github.com/goharbor/harbor-operator/pkg/scheme"
 )
@@ -32,6 +36,25 @@ func setupTest(ctx context.Context) (*Reconciler, context.Context) {
 	Expect(err).ToNot(HaveOccurred(), "failed to initialize scheme")
 
 	return &Reconciler{
-		Scheme: s,
+		Scheme:   s,
+		Recorder: &record.FakeRecorder{},
 	}, ctx
 }
+
+// setupHarborTest returns a reconciler and a context identifying the operator,
+// with a Harbor named harbor in the ns namespace, served at https://harbor.example.com.
+func setupHarborTest(ctx context.Context) (*Reconciler, context.Context, *goharborv1alpha1.Harbor) {
+	r, ctx := setupTest(ctx)
+	application.SetName(&ctx, "harbor-operator")
+	application.SetVersion(&ctx, "test")
+
+	return r, ctx, &goharborv1alpha1.Harbor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "harbor",
+			Namespace: "ns",
+		},
+		Spec: goharborv1alpha1.HarborSpec{
+			PublicURL: "https://harbor.example.com",
+		},
+	}
+}
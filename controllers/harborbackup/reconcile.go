@@ -0,0 +1,262 @@
+package harborbackup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/backup"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborbackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="batch",resources="jobs",verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources="events",verbs=create;patch
+
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.TODO()
+	application.SetName(&ctx, r.GetName())
+	application.SetVersion(&ctx, r.GetVersion())
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "reconcile", opentracing.Tags{
+		"HarborBackup.Namespace": req.Namespace,
+		"HarborBackup.Name":      req.Name,
+	})
+	defer span.Finish()
+
+	reqLogger := r.Log.WithValues("Request", req.NamespacedName, "HarborBackup.Namespace", req.Namespace, "HarborBackup.Name", req.Name)
+
+	logger.Set(&ctx, reqLogger)
+
+	harborBackup := &goharborv1alpha1.HarborBackup{}
+
+	err := r.Client.Get(ctx, req.NamespacedName, harborBackup)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			reqLogger.Info("HarborBackup does not exists")
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if !harborBackup.ObjectMeta.DeletionTimestamp.IsZero() {
+		reqLogger.Info("HarborBackup is being deleted")
+		return reconcile.Result{}, nil
+	}
+
+	if conditions.IsTrue(harborBackup.Status.Conditions, goharborv1alpha1.CompletedConditionType) ||
+		conditions.IsTrue(harborBackup.Status.Conditions, goharborv1alpha1.FailedConditionType) {
+		return reconcile.Result{}, nil
+	}
+
+	result := reconcile.Result{}
+
+	err = r.RunBackup(ctx, &result, harborBackup)
+	if err != nil {
+		return result, errors.Wrap(err, "cannot run backup")
+	}
+
+	return result, reconciliation.UpdateStatus(ctx, r.Client, &result, harborBackup)
+}
+
+func (r *Reconciler) RunBackup(ctx context.Context, result *ctrl.Result, harborBackup *goharborv1alpha1.HarborBackup) error {
+	harbor := &goharborv1alpha1.Harbor{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: harborBackup.GetNamespace(), Name: harborBackup.Spec.HarborName}, harbor)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return r.Fail(ctx, harborBackup, "harbor-not-found", fmt.Sprintf("harbor %s not found", harborBackup.Spec.HarborName))
+		}
+
+		return errors.Wrap(err, "cannot get harbor")
+	}
+
+	if harborBackup.Status.StartTime == nil {
+		// Store the original read-only state before switching it,
+		// so it is restored once the backup is over
+		now := metav1.Now()
+		readOnly := harbor.Spec.ReadOnly
+
+		harborBackup.Status.StartTime = &now
+		harborBackup.Status.HarborReadOnly = &readOnly
+
+		result.Requeue = true
+
+		return r.UpdateCondition(ctx, harborBackup, goharborv1alpha1.CompletedConditionType, corev1.ConditionFalse, "started", "switching harbor to read-only")
+	}
+
+	err = backup.SetHarborReadOnly(ctx, r.Client, harbor, true)
+	if err != nil {
+		return errors.Wrap(err, "cannot switch harbor to read-only")
+	}
+
+	rolledOut, err := backup.IsHarborRolledOut(ctx, r.Client, harbor)
+	if err != nil {
+		return errors.Wrap(err, "cannot check harbor rollout")
+	}
+
+	if !rolledOut {
+		// Jobs would race with writes until core runs in read-only mode
+		result.RequeueAfter = DefaultRequeueWait
+
+		return r.UpdateCondition(ctx, harborBackup, goharborv1alpha1.CompletedConditionType, corev1.ConditionFalse, "waiting-read-only", "waiting for harbor to roll out in read-only mode")
+	}
+
+	items := backup.Items(harbor, harborBackup.Spec.Images)
+
+	err = r.SaveSecretKey(ctx, harborBackup, harbor)
+	if err != nil {
+		return errors.Wrap(err, "cannot save secret key")
+	}
+
+	jobs := make([]*batchv1.Job, len(items))
+	for i, item := range items {
+		jobs[i] = backup.GetJob(backup.BackupDirection, harborBackup.GetName(), harborBackup, harbor, item)
+	}
+
+	progress, err := backup.RunJobs(ctx, r.Client, r.Scheme, harborBackup, jobs)
+	if err != nil {
+		return errors.Wrap(err, "cannot run jobs")
+	}
+
+	switch {
+	case progress.IsFailed():
+		return r.Fail(ctx, harborBackup, "job-failed", progress.FailureMessage())
+	case !progress.IsCompleted():
+		result.RequeueAfter = DefaultRequeueWait
+
+		return r.UpdateCondition(ctx, harborBackup, goharborv1alpha1.CompletedConditionType, corev1.ConditionFalse, "running", fmt.Sprintf("%d/%d jobs completed", progress.Completed, progress.Total))
+	}
+
+	err = r.releaseHarbor(ctx, harborBackup)
+	if err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	harborBackup.Status.CompletionTime = &now
+
+	harborBackup.Status.Items = make([]string, len(items))
+	for i, item := range items {
+		harborBackup.Status.Items[i] = item.Name
+	}
+
+	return r.UpdateCondition(ctx, harborBackup, goharborv1alpha1.CompletedConditionType, corev1.ConditionTrue, "completed", fmt.Sprintf("%d items saved", len(items)))
+}
+
+// SaveSecretKey copies the core secret key in a secret owned by the backup, so it can be restored by the operator.
+func (r *Reconciler) SaveSecretKey(ctx context.Context, harborBackup *goharborv1alpha1.HarborBackup, harbor *goharborv1alpha1.Harbor) error {
+	if harbor.Spec.Components.Core == nil {
+		return nil
+	}
+
+	coreSecret, err := backup.GetCoreSecret(ctx, r.Client, harbor)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backup.SecretKeySecretName(harborBackup),
+			Namespace: harborBackup.GetNamespace(),
+			Labels: map[string]string{
+				"harbor": harbor.GetName(),
+				"backup": harborBackup.GetName(),
+			},
+		},
+		Data: map[string][]byte{
+			backup.CoreSecretKey: coreSecret.Data[backup.CoreSecretKey],
+		},
+	}
+
+	err = controllerutil.SetControllerReference(harborBackup, secret, r.Scheme)
+	if err != nil {
+		return errors.Wrap(err, "cannot set controller reference")
+	}
+
+	err = r.Client.Create(ctx, secret)
+	if err != nil && !apierrs.IsAlreadyExists(err) {
+		return errors.Wrap(err, "cannot create secret")
+	}
+
+	return nil
+}
+
+// releaseHarbor switches the Harbor back to its original read-only state.
+func (r *Reconciler) releaseHarbor(ctx context.Context, harborBackup *goharborv1alpha1.HarborBackup) error {
+	if harborBackup.Status.HarborReadOnly == nil || *harborBackup.Status.HarborReadOnly {
+		return nil
+	}
+
+	harbor := &goharborv1alpha1.Harbor{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: harborBackup.GetNamespace(), Name: harborBackup.Spec.HarborName}, harbor)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+
+		return errors.Wrap(err, "cannot get harbor")
+	}
+
+	err = backup.SetHarborReadOnly(ctx, r.Client, harbor, false)
+
+	return errors.Wrap(err, "cannot switch harbor back to read-write")
+}
+
+func (r *Reconciler) Fail(ctx context.Context, harborBackup *goharborv1alpha1.HarborBackup, reason, message string) error {
+	logger.Get(ctx).Info("backup failed", "Reason", reason, "Message", message)
+
+	err := r.releaseHarbor(ctx, harborBackup)
+	if err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	harborBackup.Status.CompletionTime = &now
+
+	err = r.UpdateCondition(ctx, harborBackup, goharborv1alpha1.CompletedConditionType, corev1.ConditionFalse, reason, message)
+	if err != nil {
+		return err
+	}
+
+	return r.UpdateCondition(ctx, harborBackup, goharborv1alpha1.FailedConditionType, corev1.ConditionTrue, reason, message)
+}
+
+func (r *Reconciler) UpdateCondition(ctx context.Context, harborBackup *goharborv1alpha1.HarborBackup, conditionType goharborv1alpha1.HarborConditionType, status corev1.ConditionStatus, reasons ...string) error {
+	updated, transition, err := conditions.Update(harborBackup.Status.Conditions, conditionType, status, reasons...)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update condition %s", conditionType)
+	}
+
+	harborBackup.Status.Conditions = updated
+
+	if transition && status == corev1.ConditionTrue {
+		eventType := corev1.EventTypeNormal
+		if conditionType == goharborv1alpha1.FailedConditionType {
+			eventType = corev1.EventTypeWarning
+		}
+
+		r.Recorder.Event(harborBackup, eventType, string(conditionType), conditions.Get(updated, conditionType).Message)
+	}
+
+	return nil
+}
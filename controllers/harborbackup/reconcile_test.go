@@ -0,0 +1,165 @@
+package harborbackup
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/backup"
+	"github.com/goharbor/harbor-operator/pkg/backup/backuptest"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+)
+
+var _ = Describe("Reconcile", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var harbor *goharborv1alpha1.Harbor
+	var harborBackup *goharborv1alpha1.HarborBackup
+	var req ctrl.Request
+
+	BeforeEach(func() {
+		harbor = backuptest.NewHarbor("ns")
+
+		harborBackup = &goharborv1alpha1.HarborBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nightly",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborBackupSpec{
+				HarborName: "harbor",
+				Target: goharborv1alpha1.BackupTarget{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "backups"},
+				},
+			},
+		}
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "nightly"}}
+	})
+
+	getBackup := func() *goharborv1alpha1.HarborBackup {
+		current := &goharborv1alpha1.HarborBackup{}
+		Expect(r.Client.Get(ctx, req.NamespacedName, current)).To(Succeed())
+
+		return current
+	}
+
+	getHarbor := func() *goharborv1alpha1.Harbor {
+		current := &goharborv1alpha1.Harbor{}
+		Expect(r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "harbor"}, current)).To(Succeed())
+
+		return current
+	}
+
+	listJobs := func() []batchv1.Job {
+		jobs := &batchv1.JobList{}
+		Expect(r.Client.List(ctx, jobs)).To(Succeed())
+
+		return jobs.Items
+	}
+
+	completeJobs := func(conditionType batchv1.JobConditionType) {
+		for _, job := range listJobs() {
+			job := job
+			job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue, Message: "exit code 1"}}
+			Expect(r.Client.Status().Update(ctx, &job)).To(Succeed())
+		}
+	}
+
+	It("Should record the read-only mode then wait for core to roll out in read-only mode", func() {
+		r, ctx = setupTest(context.TODO(), harbor, harborBackup, backuptest.NewCoreDeployment(harbor, false))
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(getBackup().Status.HarborReadOnly).ToNot(BeNil())
+		Expect(*getBackup().Status.HarborReadOnly).To(BeFalse())
+		Expect(getHarbor().Spec.ReadOnly).To(BeFalse())
+
+		result, err = r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(DefaultRequeueWait))
+		Expect(getHarbor().Spec.ReadOnly).To(BeTrue())
+		Expect(conditions.Get(getBackup().Status.Conditions, goharborv1alpha1.CompletedConditionType).Reason).To(Equal("waiting-read-only"))
+		Expect(listJobs()).To(BeEmpty())
+	})
+
+	It("Should run jobs once core runs in read-only mode and switch the Harbor back to read-write", func() {
+		readOnly := false
+		now := metav1.Now()
+		harbor.Spec.ReadOnly = true
+		harborBackup.Status.StartTime = &now
+		harborBackup.Status.HarborReadOnly = &readOnly
+
+		r, ctx = setupTest(context.TODO(), harbor, harborBackup, backuptest.NewCoreDeployment(harbor, true), backuptest.NewCoreSecret(harbor, "key"))
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(DefaultRequeueWait))
+		Expect(conditions.Get(getBackup().Status.Conditions, goharborv1alpha1.CompletedConditionType).Message).To(Equal("0/2 jobs completed"))
+
+		names := []string{}
+		for _, job := range listJobs() {
+			names = append(names, job.GetName())
+			Expect(job.GetOwnerReferences()).To(HaveLen(1))
+		}
+		Expect(names).To(ConsistOf("nightly-core-database", "nightly-core-secretkey"))
+
+		secret := &corev1.Secret{}
+		Expect(r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: backup.SecretKeySecretName(harborBackup)}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue(backup.CoreSecretKey, []byte("key")))
+
+		completeJobs(batchv1.JobComplete)
+
+		_, err = r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+
+		current := getBackup()
+		Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.CompletedConditionType)).To(BeTrue())
+		Expect(current.Status.CompletionTime).ToNot(BeNil())
+		Expect(current.Status.Items).To(ConsistOf(backup.CoreDatabaseItem, backup.CoreSecretKeyItem))
+		Expect(getHarbor().Spec.ReadOnly).To(BeFalse())
+	})
+
+	It("Should fail and switch the Harbor back to read-write when a job fails", func() {
+		readOnly := false
+		now := metav1.Now()
+		harbor.Spec.ReadOnly = true
+		harborBackup.Status.StartTime = &now
+		harborBackup.Status.HarborReadOnly = &readOnly
+
+		r, ctx = setupTest(context.TODO(), harbor, harborBackup, backuptest.NewCoreDeployment(harbor, true), backuptest.NewCoreSecret(harbor, "key"))
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+
+		completeJobs(batchv1.JobFailed)
+
+		_, err = r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+
+		current := getBackup()
+		Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.FailedConditionType)).To(BeTrue())
+		Expect(conditions.Get(current.Status.Conditions, goharborv1alpha1.FailedConditionType).Reason).To(Equal("job-failed"))
+		Expect(getHarbor().Spec.ReadOnly).To(BeFalse())
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+	})
+
+	It("Should fail when the Harbor does not exist", func() {
+		r, ctx = setupTest(context.TODO(), harborBackup)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conditions.Get(getBackup().Status.Conditions, goharborv1alpha1.FailedConditionType).Reason).To(Equal("harbor-not-found"))
+	})
+})
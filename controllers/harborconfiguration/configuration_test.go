@@ -0,0 +1,124 @@
+package harborconfiguration
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+)
+
+var _ = Describe("HarborConfiguration", func() {
+	var configuration *goharborv1alpha1.HarborConfiguration
+
+	BeforeEach(func() {
+		selfRegistration := false
+
+		configuration = &goharborv1alpha1.HarborConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "harbor",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborConfigurationSpec{
+				HarborName: "harbor",
+				Email: &goharborv1alpha1.EmailConfiguration{
+					Host: "smtp.example.com",
+					From: "harbor@example.com",
+				},
+				ProjectCreationRestriction: goharborv1alpha1.ProjectCreationAdminOnly,
+				RobotTokenDuration:         &metav1.Duration{Duration: 720 * time.Hour},
+				SelfRegistration:           &selfRegistration,
+			},
+		}
+	})
+
+	Context("Values", func() {
+		It("Should only manage set fields", func() {
+			configuration.Spec.Email = nil
+			configuration.Spec.SelfRegistration = nil
+
+			Expect(GetValues(configuration)).To(Equal(map[string]interface{}{
+				harborapi.ConfigProjectCreationRestriction: "adminonly",
+				harborapi.ConfigRobotTokenDuration:         int64(43200),
+			}))
+		})
+
+		It("Should use the default SMTP port", func() {
+			Expect(GetValues(configuration)).To(HaveKeyWithValue(harborapi.ConfigEmailPort, BeEquivalentTo(DefaultEmailPort)))
+		})
+	})
+
+	Context("Drift", func() {
+		var current map[string]harborapi.ConfigurationValue
+
+		BeforeEach(func() {
+			// As returned by Harbor, numbers are decoded as float64
+			Expect(json.Unmarshal([]byte(`{
+				"email_host": {"value": "smtp.example.com", "editable": true},
+				"email_port": {"value": 25, "editable": true},
+				"email_username": {"value": "", "editable": true},
+				"email_from": {"value": "harbor@example.com", "editable": true},
+				"email_ssl": {"value": false, "editable": true},
+				"email_insecure": {"value": false, "editable": true},
+				"email_identity": {"value": "", "editable": true},
+				"project_creation_restriction": {"value": "everyone", "editable": true},
+				"robot_token_duration": {"value": 43200, "editable": true},
+				"self_registration": {"value": true, "editable": true},
+				"token_expiration": {"value": 30, "editable": true}
+			}`), &current)).To(Succeed())
+		})
+
+		It("Should list drifted items", func() {
+			drifted, err := GetDriftedKeys(GetValues(configuration), current)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drifted).To(Equal([]string{harborapi.ConfigProjectCreationRestriction, harborapi.ConfigSelfRegistration}))
+		})
+
+		It("Should report missing items as drifted", func() {
+			delete(current, harborapi.ConfigEmailHost)
+
+			drifted, err := GetDriftedKeys(GetValues(configuration), current)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drifted).To(ContainElement(harborapi.ConfigEmailHost))
+		})
+	})
+
+	Context("CVE allowlist", func() {
+		var current *harborapi.CVEWhitelist
+
+		BeforeEach(func() {
+			configuration.Spec.CVEAllowlist = &goharborv1alpha1.CVEAllowlist{
+				Items: []string{"CVE-2019-10164", "CVE-2018-1000001"},
+			}
+
+			current = &harborapi.CVEWhitelist{
+				ID: 1,
+				Items: []harborapi.CVEWhitelistItem{
+					{CVEID: "CVE-2018-1000001"},
+					{CVEID: "CVE-2019-10164"},
+				},
+			}
+		})
+
+		It("Should not be managed if not set", func() {
+			configuration.Spec.CVEAllowlist = nil
+
+			Expect(GetCVEWhitelist(configuration)).To(BeNil())
+		})
+
+		It("Should ignore the order of items", func() {
+			Expect(IsCVEWhitelistInSync(GetCVEWhitelist(configuration), current)).To(BeTrue())
+		})
+
+		It("Should detect expiration changes", func() {
+			configuration.Spec.CVEAllowlist.ExpiresAt = &metav1.Time{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+			Expect(IsCVEWhitelistInSync(GetCVEWhitelist(configuration), current)).To(BeFalse())
+		})
+	})
+})
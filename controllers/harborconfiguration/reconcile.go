@@ -0,0 +1,381 @@
+package harborconfiguration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+const (
+	EventReasonConfigurationUpdated = "ConfigurationUpdated"
+	EventReasonConfigurationDrifted = "ConfigurationDrifted"
+)
+
+const (
+	// DefaultEmailPort is the SMTP port used when not set
+	DefaultEmailPort = 25
+
+	// CVEAllowlistKey identifies the CVE allowlist in drifted items
+	CVEAllowlistKey = "cve_allowlist"
+)
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborconfigurations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborconfigurations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="events",verbs=create;patch
+
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.TODO()
+	application.SetName(&ctx, r.GetName())
+	application.SetVersion(&ctx, r.GetVersion())
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "reconcile", opentracing.Tags{
+		"HarborConfiguration.Namespace": req.Namespace,
+		"HarborConfiguration.Name":      req.Name,
+	})
+	defer span.Finish()
+
+	reqLogger := r.Log.WithValues("Request", req.NamespacedName, "HarborConfiguration.Namespace", req.Namespace, "HarborConfiguration.Name", req.Name)
+
+	logger.Set(&ctx, reqLogger)
+
+	configuration := &goharborv1alpha1.HarborConfiguration{}
+
+	err := r.Client.Get(ctx, req.NamespacedName, configuration)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			reqLogger.Info("HarborConfiguration does not exists")
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	// The configuration is kept in Harbor when the resource is deleted
+	if !configuration.ObjectMeta.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	result := reconcile.Result{
+		RequeueAfter: reconciliation.DefaultResyncPeriod,
+	}
+
+	// Differences are expected when the spec changed since the last sync
+	specChanged := configuration.Status.ObservedGeneration != configuration.GetGeneration()
+
+	drifted, syncErr := r.Sync(ctx, configuration)
+	if syncErr != nil {
+		err = r.UpdateCondition(ctx, configuration, goharborv1alpha1.ReadyConditionType, corev1.ConditionFalse, "sync-failed", syncErr.Error())
+	} else {
+		err = r.UpdateCondition(ctx, configuration, goharborv1alpha1.ReadyConditionType, corev1.ConditionTrue, "synced", "configuration is applied")
+		if err == nil {
+			err = r.updateDriftCondition(ctx, configuration, specChanged, drifted)
+		}
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	configuration.Status.ObservedGeneration = configuration.GetGeneration()
+
+	err = reconciliation.UpdateStatus(ctx, r.Client, &result, configuration)
+	if err != nil {
+		return result, err
+	}
+
+	return result, errors.Wrap(syncErr, "cannot sync configuration")
+}
+
+func (r *Reconciler) updateDriftCondition(ctx context.Context, configuration *goharborv1alpha1.HarborConfiguration, specChanged bool, drifted []string) error {
+	if specChanged || len(drifted) == 0 {
+		return r.UpdateCondition(ctx, configuration, goharborv1alpha1.DriftedConditionType, corev1.ConditionFalse, "in-sync", "configuration matches the resource")
+	}
+
+	message := fmt.Sprintf("%s changed outside of the resource and reverted", strings.Join(drifted, ", "))
+
+	r.Recorder.Event(configuration, corev1.EventTypeWarning, EventReasonConfigurationDrifted, message)
+
+	return r.UpdateCondition(ctx, configuration, goharborv1alpha1.DriftedConditionType, corev1.ConditionTrue, "changed-outside", message)
+}
+
+// getClient returns a client for the Harbor, nil if the Harbor does not exist.
+func (r *Reconciler) getClient(ctx context.Context, configuration *goharborv1alpha1.HarborConfiguration) (*harborapi.Client, error) {
+	api, _, err := harborapi.NewFromName(ctx, r.Client, configuration.GetNamespace(), configuration.Spec.HarborName, harborapi.UserAgent(r.GetName(), r.GetVersion()))
+
+	return api, err
+}
+
+// GetValues returns the configuration items matching the spec, except the SMTP password.
+func GetValues(configuration *goharborv1alpha1.HarborConfiguration) map[string]interface{} {
+	values := map[string]interface{}{}
+
+	if email := configuration.Spec.Email; email != nil {
+		port := email.Port
+		if port == 0 {
+			port = DefaultEmailPort
+		}
+
+		values[harborapi.ConfigEmailHost] = email.Host
+		values[harborapi.ConfigEmailPort] = port
+		values[harborapi.ConfigEmailUsername] = email.Username
+		values[harborapi.ConfigEmailFrom] = email.From
+		values[harborapi.ConfigEmailSSL] = email.SSL
+		values[harborapi.ConfigEmailInsecure] = email.Insecure
+		values[harborapi.ConfigEmailIdentity] = email.Identity
+	}
+
+	if configuration.Spec.ProjectCreationRestriction != "" {
+		values[harborapi.ConfigProjectCreationRestriction] = string(configuration.Spec.ProjectCreationRestriction)
+	}
+
+	if configuration.Spec.RobotTokenDuration != nil {
+		values[harborapi.ConfigRobotTokenDuration] = int64(configuration.Spec.RobotTokenDuration.Minutes())
+	}
+
+	if configuration.Spec.SelfRegistration != nil {
+		values[harborapi.ConfigSelfRegistration] = *configuration.Spec.SelfRegistration
+	}
+
+	return values
+}
+
+// GetDriftedKeys returns the sorted keys whose value in Harbor differs from the desired one.
+func GetDriftedKeys(desired map[string]interface{}, current map[string]harborapi.ConfigurationValue) ([]string, error) {
+	drifted := []string{}
+
+	for key, value := range desired {
+		desiredJSON, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot encode %s", key)
+		}
+
+		currentValue, ok := current[key]
+		if ok {
+			currentJSON, err := json.Marshal(currentValue.Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot encode current %s", key)
+			}
+
+			if string(desiredJSON) == string(currentJSON) {
+				continue
+			}
+		}
+
+		drifted = append(drifted, key)
+	}
+
+	sort.Strings(drifted)
+
+	return drifted, nil
+}
+
+// GetCVEWhitelist returns the system CVE allowlist matching the spec, nil if not managed.
+func GetCVEWhitelist(configuration *goharborv1alpha1.HarborConfiguration) *harborapi.CVEWhitelist {
+	allowlist := configuration.Spec.CVEAllowlist
+	if allowlist == nil {
+		return nil
+	}
+
+	whitelist := &harborapi.CVEWhitelist{
+		Items: []harborapi.CVEWhitelistItem{},
+	}
+
+	for _, item := range allowlist.Items {
+		whitelist.Items = append(whitelist.Items, harborapi.CVEWhitelistItem{CVEID: item})
+	}
+
+	if allowlist.ExpiresAt != nil {
+		expiresAt := allowlist.ExpiresAt.Unix()
+		whitelist.ExpiresAt = &expiresAt
+	}
+
+	return whitelist
+}
+
+func getCVEIDs(whitelist *harborapi.CVEWhitelist) []string {
+	ids := make([]string, len(whitelist.Items))
+
+	for i, item := range whitelist.Items {
+		ids[i] = item.CVEID
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// IsCVEWhitelistInSync returns whether the current allowlist has the same CVEs and expiration as the desired one.
+func IsCVEWhitelistInSync(desired, current *harborapi.CVEWhitelist) bool {
+	if (desired.ExpiresAt == nil) != (current.ExpiresAt == nil) {
+		return false
+	}
+
+	if desired.ExpiresAt != nil && *desired.ExpiresAt != *current.ExpiresAt {
+		return false
+	}
+
+	return strings.Join(getCVEIDs(desired), ",") == strings.Join(getCVEIDs(current), ",")
+}
+
+// getEmailPassword returns the SMTP password from the secret, empty without secret.
+func (r *Reconciler) getEmailPassword(ctx context.Context, configuration *goharborv1alpha1.HarborConfiguration) (string, error) {
+	if configuration.Spec.Email == nil || configuration.Spec.Email.PasswordSecret == "" {
+		return "", nil
+	}
+
+	secretName := configuration.Spec.Email.PasswordSecret
+	secret := &corev1.Secret{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: configuration.GetNamespace(), Name: secretName}, secret)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot get secret %s", secretName)
+	}
+
+	password, ok := secret.Data[goharborv1alpha1.EmailPasswordKey]
+	if !ok {
+		return "", errors.Errorf("key %s not found in secret %s", goharborv1alpha1.EmailPasswordKey, secretName)
+	}
+
+	return string(password), nil
+}
+
+// HashPassword returns a hash of the password, to detect changes since Harbor does not return it.
+func HashPassword(password string) string {
+	if password == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(password))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Sync updates the configuration items differing in Harbor.
+// It returns the items whose value differed, sorted.
+func (r *Reconciler) Sync(ctx context.Context, configuration *goharborv1alpha1.HarborConfiguration) ([]string, error) {
+	api, err := r.getClient(ctx, configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	if api == nil {
+		return nil, errors.Errorf("harbor %s not found", configuration.Spec.HarborName)
+	}
+
+	password, err := r.getEmailPassword(ctx, configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := api.GetConfigurations(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get configurations")
+	}
+
+	desired := GetValues(configuration)
+
+	drifted, err := GetDriftedKeys(desired, current)
+	if err != nil {
+		return nil, err
+	}
+
+	update := map[string]interface{}{}
+	for _, key := range drifted {
+		update[key] = desired[key]
+	}
+
+	hash := HashPassword(password)
+	if configuration.Spec.Email != nil && hash != configuration.Status.EmailPasswordHash {
+		update[harborapi.ConfigEmailPassword] = password
+	}
+
+	if len(update) > 0 {
+		err = api.UpdateConfigurations(ctx, update)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot update configurations")
+		}
+
+		keys := make([]string, 0, len(update))
+		for key := range update {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		r.Recorder.Eventf(configuration, corev1.EventTypeNormal, EventReasonConfigurationUpdated, "%s updated", strings.Join(keys, ", "))
+	}
+
+	if configuration.Spec.Email != nil {
+		configuration.Status.EmailPasswordHash = hash
+	}
+
+	whitelistDrifted, err := r.syncCVEWhitelist(ctx, api, configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	if whitelistDrifted {
+		drifted = append(drifted, CVEAllowlistKey)
+	}
+
+	return drifted, nil
+}
+
+// syncCVEWhitelist updates the system CVE allowlist if it differs in Harbor.
+// It returns whether the allowlist differed.
+func (r *Reconciler) syncCVEWhitelist(ctx context.Context, api *harborapi.Client, configuration *goharborv1alpha1.HarborConfiguration) (bool, error) {
+	desired := GetCVEWhitelist(configuration)
+	if desired == nil {
+		return false, nil
+	}
+
+	current, err := api.GetSystemCVEWhitelist(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "cannot get CVE allowlist")
+	}
+
+	if IsCVEWhitelistInSync(desired, current) {
+		return false, nil
+	}
+
+	err = api.UpdateSystemCVEWhitelist(ctx, desired)
+	if err != nil {
+		return false, errors.Wrap(err, "cannot update CVE allowlist")
+	}
+
+	r.Recorder.Eventf(configuration, corev1.EventTypeNormal, EventReasonConfigurationUpdated, "%s updated", CVEAllowlistKey)
+
+	return true, nil
+}
+
+func (r *Reconciler) UpdateCondition(ctx context.Context, configuration *goharborv1alpha1.HarborConfiguration, conditionType goharborv1alpha1.HarborConditionType, status corev1.ConditionStatus, reasons ...string) error {
+	updated, _, err := conditions.Update(configuration.Status.Conditions, conditionType, status, reasons...)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update condition %s", conditionType)
+	}
+
+	configuration.Status.Conditions = updated
+
+	return nil
+}
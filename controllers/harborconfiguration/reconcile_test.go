@@ -0,0 +1,220 @@
+package harborconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/harborapi/harborapitest"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+var _ = Describe("Reconcile", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var server *harborapitest.Server
+	var harbor *goharborv1alpha1.Harbor
+	var configuration *goharborv1alpha1.HarborConfiguration
+	var emailPassword *corev1.Secret
+	var req ctrl.Request
+
+	BeforeEach(func() {
+		server = harborapitest.NewServer()
+		server.HandleJSON(http.MethodGet, "/configurations", http.StatusOK, map[string]harborapi.ConfigurationValue{
+			harborapi.ConfigEmailHost:                  {Value: "smtp.example.com", Editable: true},
+			harborapi.ConfigEmailPort:                  {Value: 25, Editable: true},
+			harborapi.ConfigEmailUsername:              {Value: "harbor", Editable: true},
+			harborapi.ConfigEmailFrom:                  {Value: "harbor@example.com", Editable: true},
+			harborapi.ConfigEmailSSL:                   {Value: false, Editable: true},
+			harborapi.ConfigEmailInsecure:              {Value: false, Editable: true},
+			harborapi.ConfigEmailIdentity:              {Value: "", Editable: true},
+			harborapi.ConfigProjectCreationRestriction: {Value: "everyone", Editable: true},
+		})
+		server.HandleJSON(http.MethodPut, "/configurations", http.StatusOK, nil)
+		server.HandleJSON(http.MethodGet, "/system/CVEWhitelist", http.StatusOK, &harborapi.CVEWhitelist{Items: []harborapi.CVEWhitelistItem{}})
+		server.HandleJSON(http.MethodPut, "/system/CVEWhitelist", http.StatusOK, nil)
+
+		harbor = harborapitest.NewHarbor("ns")
+
+		configuration = &goharborv1alpha1.HarborConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "harbor",
+				Namespace:  "ns",
+				Generation: 1,
+			},
+			Spec: goharborv1alpha1.HarborConfigurationSpec{
+				HarborName: "harbor",
+				Email: &goharborv1alpha1.EmailConfiguration{
+					Host:           "smtp.example.com",
+					Username:       "harbor",
+					PasswordSecret: "smtp",
+					From:           "harbor@example.com",
+				},
+				ProjectCreationRestriction: goharborv1alpha1.ProjectCreationAdminOnly,
+			},
+		}
+
+		emailPassword = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "smtp",
+				Namespace: "ns",
+			},
+			Data: map[string][]byte{
+				goharborv1alpha1.EmailPasswordKey: []byte("secret"),
+			},
+		}
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "harbor"}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	setup := func(objects ...runtime.Object) {
+		r, ctx = setupTest(context.TODO(), append(objects, harbor, harborapitest.NewAdminPasswordSecret(harbor))...)
+	}
+
+	getConfiguration := func() *goharborv1alpha1.HarborConfiguration {
+		current := &goharborv1alpha1.HarborConfiguration{}
+		Expect(r.Client.Get(ctx, req.NamespacedName, current)).To(Succeed())
+
+		return current
+	}
+
+	// getUpdates returns the configuration items sent to Harbor by each update.
+	getUpdates := func() []map[string]interface{} {
+		updates := []map[string]interface{}{}
+
+		for _, request := range server.Requests(http.MethodPut, "/configurations") {
+			update := map[string]interface{}{}
+			Expect(json.Unmarshal(request.Body, &update)).To(Succeed())
+
+			updates = append(updates, update)
+		}
+
+		return updates
+	}
+
+	It("Should only update differing items and the password", func() {
+		setup(configuration, emailPassword)
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(reconciliation.DefaultResyncPeriod))
+
+		updates := getUpdates()
+		Expect(updates).To(HaveLen(1))
+		Expect(updates[0]).To(Equal(map[string]interface{}{
+			harborapi.ConfigProjectCreationRestriction: "adminonly",
+			harborapi.ConfigEmailPassword:              "secret",
+		}))
+
+		current := getConfiguration()
+		Expect(current.Status.EmailPasswordHash).To(Equal(HashPassword("secret")))
+		Expect(current.Status.ObservedGeneration).To(Equal(int64(1)))
+		Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.ReadyConditionType)).To(BeTrue())
+		Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.DriftedConditionType)).To(BeFalse(), "spec changed since the last sync")
+		Expect(server.Requests(http.MethodGet, "/system/CVEWhitelist")).To(BeEmpty())
+	})
+
+	It("Should report items changed outside of the resource", func() {
+		configuration.Status.ObservedGeneration = 1
+		configuration.Status.EmailPasswordHash = HashPassword("secret")
+		setup(configuration, emailPassword)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+
+		updates := getUpdates()
+		Expect(updates).To(HaveLen(1))
+		Expect(updates[0]).To(Equal(map[string]interface{}{
+			harborapi.ConfigProjectCreationRestriction: "adminonly",
+		}))
+
+		condition := conditions.Get(getConfiguration().Status.Conditions, goharborv1alpha1.DriftedConditionType)
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring(harborapi.ConfigProjectCreationRestriction))
+
+		events := r.Recorder.(*record.FakeRecorder).Events
+		Expect(events).To(Receive(ContainSubstring(EventReasonConfigurationUpdated)))
+		Expect(events).To(Receive(ContainSubstring(EventReasonConfigurationDrifted)))
+	})
+
+	It("Should not update a configuration in sync", func() {
+		configuration.Spec.ProjectCreationRestriction = goharborv1alpha1.ProjectCreationEveryone
+		configuration.Status.ObservedGeneration = 1
+		configuration.Status.EmailPasswordHash = HashPassword("secret")
+		setup(configuration, emailPassword)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.Requests(http.MethodPut, "/configurations")).To(BeEmpty())
+		Expect(conditions.IsTrue(getConfiguration().Status.Conditions, goharborv1alpha1.DriftedConditionType)).To(BeFalse())
+	})
+
+	It("Should update the CVE allowlist", func() {
+		configuration.Spec.CVEAllowlist = &goharborv1alpha1.CVEAllowlist{
+			Items: []string{"CVE-2020-1234"},
+		}
+		setup(configuration, emailPassword)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+
+		requests := server.Requests(http.MethodPut, "/system/CVEWhitelist")
+		Expect(requests).To(HaveLen(1))
+
+		whitelist := &harborapi.CVEWhitelist{}
+		Expect(json.Unmarshal(requests[0].Body, whitelist)).To(Succeed())
+		Expect(whitelist.Items).To(ConsistOf(harborapi.CVEWhitelistItem{CVEID: "CVE-2020-1234"}))
+		Expect(whitelist.ExpiresAt).To(BeNil())
+	})
+
+	It("Should report a missing password secret", func() {
+		setup(configuration)
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(HaveOccurred())
+		Expect(server.Requests(http.MethodPut, "/configurations")).To(BeEmpty())
+
+		condition := conditions.Get(getConfiguration().Status.Conditions, goharborv1alpha1.ReadyConditionType)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("cannot get secret smtp"))
+	})
+
+	It("Should report a missing Harbor", func() {
+		r, ctx = setupTest(context.TODO(), configuration, emailPassword)
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(HaveOccurred())
+
+		condition := conditions.Get(getConfiguration().Status.Conditions, goharborv1alpha1.ReadyConditionType)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("harbor harbor not found"))
+	})
+
+	It("Should keep the configuration in Harbor when deleted", func() {
+		now := metav1.Now()
+		configuration.SetDeletionTimestamp(&now)
+		setup(configuration, emailPassword)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.Requests(http.MethodGet, "/configurations")).To(BeEmpty())
+		Expect(server.Requests(http.MethodPut, "/configurations")).To(BeEmpty())
+	})
+})
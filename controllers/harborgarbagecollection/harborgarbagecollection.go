@@ -0,0 +1,68 @@
+package harborgarbagecollection
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+)
+
+const (
+	DefaultRequeueWait = 10 * time.Second
+)
+
+type Config struct {
+	ConcurrentReconciles int
+}
+
+// Reconciler reconciles a HarborGarbageCollection object
+type Reconciler struct {
+	client.Client
+
+	Name    string
+	Version string
+
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	Config Config
+}
+
+func (r *Reconciler) GetVersion() string {
+	return r.Version
+}
+
+func (r *Reconciler) GetName() string {
+	return r.Name
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	r.Scheme = mgr.GetScheme()
+	r.Recorder = mgr.GetEventRecorderFor(r.GetName())
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&goharborv1alpha1.HarborGarbageCollection{}).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.Config.ConcurrentReconciles,
+		}).
+		Complete(r)
+}
+
+func New(ctx context.Context, name, version string, config *Config) (*Reconciler, error) {
+	return &Reconciler{
+		Name:    name,
+		Version: version,
+		Log:     logger.Get(ctx).WithName("controller").WithName("harborgarbagecollection"),
+		Config:  *config,
+	}, nil
+}
@@ -0,0 +1,317 @@
+package harborgarbagecollection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/backup"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+const (
+	EventReasonInvalidSchedule = "InvalidSchedule"
+	EventReasonStarted         = "GarbageCollectionStarted"
+	EventReasonSucceeded       = "GarbageCollectionSucceeded"
+	EventReasonFailed          = "GarbageCollectionFailed"
+)
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborgarbagecollections,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborgarbagecollections/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="events",verbs=create;patch
+
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.TODO()
+	application.SetName(&ctx, r.GetName())
+	application.SetVersion(&ctx, r.GetVersion())
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "reconcile", opentracing.Tags{
+		"HarborGarbageCollection.Namespace": req.Namespace,
+		"HarborGarbageCollection.Name":      req.Name,
+	})
+	defer span.Finish()
+
+	reqLogger := r.Log.WithValues("Request", req.NamespacedName, "HarborGarbageCollection.Namespace", req.Namespace, "HarborGarbageCollection.Name", req.Name)
+
+	logger.Set(&ctx, reqLogger)
+
+	gc := &goharborv1alpha1.HarborGarbageCollection{}
+
+	err := r.Client.Get(ctx, req.NamespacedName, gc)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			reqLogger.Info("HarborGarbageCollection does not exists")
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if !gc.ObjectMeta.DeletionTimestamp.IsZero() {
+		reqLogger.Info("HarborGarbageCollection is being deleted")
+		return reconcile.Result{}, nil
+	}
+
+	result := reconcile.Result{}
+
+	err = r.RunGarbageCollection(ctx, &result, gc)
+	if err != nil {
+		return result, errors.Wrap(err, "cannot run garbage collection")
+	}
+
+	return result, reconciliation.UpdateStatus(ctx, r.Client, &result, gc)
+}
+
+func (r *Reconciler) RunGarbageCollection(ctx context.Context, result *ctrl.Result, gc *goharborv1alpha1.HarborGarbageCollection) error {
+	if gc.Status.LastRun != nil && gc.Status.LastRun.CompletionTime == nil {
+		return r.WatchRun(ctx, result, gc)
+	}
+
+	next, err := NextSchedule(gc)
+	if err != nil {
+		// The resource is reconciled again once the schedule is fixed
+		r.Recorder.Event(gc, corev1.EventTypeWarning, EventReasonInvalidSchedule, err.Error())
+		return nil
+	}
+
+	if gc.Spec.Suspend {
+		return nil
+	}
+
+	now := time.Now()
+	if next.After(now) {
+		result.RequeueAfter = next.Sub(now)
+		return nil
+	}
+
+	return r.StartRun(ctx, result, gc)
+}
+
+// StartRun triggers a garbage collection in Harbor core, switching the Harbor to read-only first if required.
+func (r *Reconciler) StartRun(ctx context.Context, result *ctrl.Result, gc *goharborv1alpha1.HarborGarbageCollection) error {
+	harbor, err := r.getHarbor(ctx, gc)
+	if err != nil {
+		return err
+	}
+
+	if harbor == nil {
+		now := metav1.Now()
+		gc.Status.LastScheduleTime = &now
+		gc.Status.LastRun = &goharborv1alpha1.GarbageCollectionRun{
+			StartTime: &now,
+		}
+
+		return r.Complete(ctx, result, gc, goharborv1alpha1.GarbageCollectionFailed, fmt.Sprintf("harbor %s not found", gc.Spec.HarborName))
+	}
+
+	if gc.Spec.ReadOnly {
+		if gc.Status.HarborReadOnly == nil {
+			// Store the original read-only state before switching it,
+			// so it is restored once the garbage collection is over
+			readOnly := harbor.Spec.ReadOnly
+			gc.Status.HarborReadOnly = &readOnly
+
+			result.Requeue = true
+
+			return nil
+		}
+
+		err = backup.SetHarborReadOnly(ctx, r.Client, harbor, true)
+		if err != nil {
+			return errors.Wrap(err, "cannot switch harbor to read-only")
+		}
+
+		rolledOut, err := backup.IsHarborRolledOut(ctx, r.Client, harbor)
+		if err != nil {
+			return errors.Wrap(err, "cannot check harbor rollout")
+		}
+
+		if !rolledOut {
+			logger.Get(ctx).Info("waiting for harbor to roll out in read-only mode")
+
+			result.RequeueAfter = DefaultRequeueWait
+
+			return nil
+		}
+	}
+
+	api, err := harborapi.New(ctx, r.Client, harbor, harborapi.UserAgent(r.GetName(), r.GetVersion()))
+	if err != nil {
+		return errors.Wrap(err, "cannot get harbor client")
+	}
+
+	id, err := api.TriggerGC(ctx, gc.Spec.DeleteUntagged)
+	if err != nil {
+		if harborapi.IsConflict(err) {
+			logger.Get(ctx).Info("a garbage collection is already running")
+
+			result.RequeueAfter = DefaultRequeueWait
+
+			return nil
+		}
+
+		return errors.Wrap(err, "cannot trigger garbage collection")
+	}
+
+	now := metav1.Now()
+	gc.Status.LastScheduleTime = &now
+	gc.Status.LastRun = &goharborv1alpha1.GarbageCollectionRun{
+		ID:        id,
+		StartTime: &now,
+	}
+
+	result.RequeueAfter = DefaultRequeueWait
+
+	message := fmt.Sprintf("garbage collection %d started", id)
+	r.Recorder.Event(gc, corev1.EventTypeNormal, EventReasonStarted, message)
+
+	return r.UpdateCondition(ctx, gc, goharborv1alpha1.RunningConditionType, corev1.ConditionTrue, "started", message)
+}
+
+// WatchRun checks the execution of the running garbage collection and completes it once done.
+func (r *Reconciler) WatchRun(ctx context.Context, result *ctrl.Result, gc *goharborv1alpha1.HarborGarbageCollection) error {
+	harbor, err := r.getHarbor(ctx, gc)
+	if err != nil {
+		return err
+	}
+
+	if harbor == nil {
+		return r.Complete(ctx, result, gc, goharborv1alpha1.GarbageCollectionFailed, fmt.Sprintf("harbor %s not found", gc.Spec.HarborName))
+	}
+
+	api, err := harborapi.New(ctx, r.Client, harbor, harborapi.UserAgent(r.GetName(), r.GetVersion()))
+	if err != nil {
+		return errors.Wrap(err, "cannot get harbor client")
+	}
+
+	run := gc.Status.LastRun
+
+	job, err := api.GetGC(ctx, run.ID)
+	if err != nil {
+		if harborapi.IsNotFound(err) {
+			return r.Complete(ctx, result, gc, goharborv1alpha1.GarbageCollectionFailed, fmt.Sprintf("garbage collection %d not found", run.ID))
+		}
+
+		return errors.Wrapf(err, "cannot get garbage collection %d", run.ID)
+	}
+
+	if !job.IsDone() {
+		result.RequeueAfter = DefaultRequeueWait
+		return nil
+	}
+
+	log, err := api.GetGCLog(ctx, run.ID)
+	if err != nil {
+		logger.Get(ctx).Error(err, "cannot get garbage collection log")
+	} else if freed, ok := harborapi.ParseFreedBytes(log); ok {
+		run.FreedBytes = &freed
+	}
+
+	if job.Status != harborapi.GCStatusFinished {
+		return r.Complete(ctx, result, gc, goharborv1alpha1.GarbageCollectionFailed, fmt.Sprintf("garbage collection %d is %s", run.ID, job.Status))
+	}
+
+	return r.Complete(ctx, result, gc, goharborv1alpha1.GarbageCollectionSucceeded, fmt.Sprintf("garbage collection %d finished", run.ID))
+}
+
+// Complete records the result of the last run and switches the Harbor back to its original read-only state.
+func (r *Reconciler) Complete(ctx context.Context, result *ctrl.Result, gc *goharborv1alpha1.HarborGarbageCollection, runResult goharborv1alpha1.GarbageCollectionResult, message string) error {
+	err := r.releaseHarbor(ctx, gc)
+	if err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+
+	run := gc.Status.LastRun
+	run.CompletionTime = &now
+	run.Duration = &metav1.Duration{Duration: now.Sub(run.StartTime.Time)}
+	run.Result = runResult
+	run.Message = message
+
+	// Compute the next schedule
+	result.Requeue = true
+
+	err = r.UpdateCondition(ctx, gc, goharborv1alpha1.RunningConditionType, corev1.ConditionFalse, string(runResult), message)
+	if err != nil {
+		return err
+	}
+
+	if runResult == goharborv1alpha1.GarbageCollectionFailed {
+		r.Recorder.Event(gc, corev1.EventTypeWarning, EventReasonFailed, message)
+
+		return r.UpdateCondition(ctx, gc, goharborv1alpha1.FailedConditionType, corev1.ConditionTrue, string(runResult), message)
+	}
+
+	r.Recorder.Event(gc, corev1.EventTypeNormal, EventReasonSucceeded, message)
+
+	return r.UpdateCondition(ctx, gc, goharborv1alpha1.FailedConditionType, corev1.ConditionFalse, string(runResult), message)
+}
+
+// getHarbor returns the Harbor to clean, nil if it does not exist.
+func (r *Reconciler) getHarbor(ctx context.Context, gc *goharborv1alpha1.HarborGarbageCollection) (*goharborv1alpha1.Harbor, error) {
+	harbor := &goharborv1alpha1.Harbor{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: gc.GetNamespace(), Name: gc.Spec.HarborName}, harbor)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "cannot get harbor")
+	}
+
+	return harbor, nil
+}
+
+// releaseHarbor switches the Harbor back to its original read-only state.
+func (r *Reconciler) releaseHarbor(ctx context.Context, gc *goharborv1alpha1.HarborGarbageCollection) error {
+	if gc.Status.HarborReadOnly == nil {
+		return nil
+	}
+
+	readOnly := *gc.Status.HarborReadOnly
+
+	harbor, err := r.getHarbor(ctx, gc)
+	if err != nil {
+		return err
+	}
+
+	if harbor != nil && !readOnly {
+		err = backup.SetHarborReadOnly(ctx, r.Client, harbor, false)
+		if err != nil {
+			return errors.Wrap(err, "cannot switch harbor back to read-write")
+		}
+	}
+
+	gc.Status.HarborReadOnly = nil
+
+	return nil
+}
+
+func (r *Reconciler) UpdateCondition(ctx context.Context, gc *goharborv1alpha1.HarborGarbageCollection, conditionType goharborv1alpha1.HarborConditionType, status corev1.ConditionStatus, reasons ...string) error {
+	updated, _, err := conditions.Update(gc.Status.Conditions, conditionType, status, reasons...)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update condition %s", conditionType)
+	}
+
+	gc.Status.Conditions = updated
+
+	return nil
+}
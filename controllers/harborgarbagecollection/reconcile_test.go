@@ -0,0 +1,245 @@
+package harborgarbagecollection
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/backup/backuptest"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/harborapi/harborapitest"
+)
+
+var _ = Describe("Reconcile", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var server *harborapitest.Server
+	var harbor *goharborv1alpha1.Harbor
+	var gc *goharborv1alpha1.HarborGarbageCollection
+	var req ctrl.Request
+
+	BeforeEach(func() {
+		server = harborapitest.NewServer()
+		server.Handle(http.MethodPost, "/system/gc/schedule", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Location", "/api/system/gc/12")
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		harbor = backuptest.NewHarbor("ns")
+		harbor.Spec.AdminPasswordSecret = "admin-password"
+
+		gc = &goharborv1alpha1.HarborGarbageCollection{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "weekly",
+				Namespace:         "ns",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-8 * 24 * time.Hour)),
+			},
+			Spec: goharborv1alpha1.HarborGarbageCollectionSpec{
+				HarborName: "harbor",
+				Schedule:   "@weekly",
+			},
+		}
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "weekly"}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	setup := func() {
+		r, ctx = setupTest(context.TODO(), harbor, gc, harborapitest.NewAdminPasswordSecret(harbor), backuptest.NewCoreDeployment(harbor, harbor.Spec.ReadOnly))
+	}
+
+	getGC := func() *goharborv1alpha1.HarborGarbageCollection {
+		current := &goharborv1alpha1.HarborGarbageCollection{}
+		Expect(r.Client.Get(ctx, req.NamespacedName, current)).To(Succeed())
+
+		return current
+	}
+
+	getHarbor := func() *goharborv1alpha1.Harbor {
+		current := &goharborv1alpha1.Harbor{}
+		Expect(r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "harbor"}, current)).To(Succeed())
+
+		return current
+	}
+
+	startRun := func() {
+		now := metav1.Now()
+		gc.Status.LastScheduleTime = &now
+		gc.Status.LastRun = &goharborv1alpha1.GarbageCollectionRun{
+			ID:        12,
+			StartTime: &now,
+		}
+	}
+
+	Context("Schedule", func() {
+		It("Should wait for the next schedule", func() {
+			gc.CreationTimestamp = metav1.Now()
+			setup()
+
+			result, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 7*24*time.Hour))
+			Expect(server.Requests(http.MethodPost, "/system/gc/schedule")).To(BeEmpty())
+		})
+
+		It("Should trigger a garbage collection once scheduled", func() {
+			gc.Spec.DeleteUntagged = true
+			setup()
+
+			result, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(DefaultRequeueWait))
+
+			requests := server.Requests(http.MethodPost, "/system/gc/schedule")
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Host).To(Equal("harbor-core.ns.svc"))
+			Expect(requests[0].Password).To(Equal(harborapitest.AdminPassword))
+			Expect(string(requests[0].Body)).To(ContainSubstring(`"delete_untagged":true`))
+
+			current := getGC()
+			Expect(current.Status.LastScheduleTime).ToNot(BeNil())
+			Expect(current.Status.LastRun.ID).To(Equal(int64(12)))
+			Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.RunningConditionType)).To(BeTrue())
+		})
+
+		It("Should not trigger a suspended garbage collection", func() {
+			gc.Spec.Suspend = true
+			setup()
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPost, "/system/gc/schedule")).To(BeEmpty())
+		})
+
+		It("Should report an invalid schedule", func() {
+			gc.Spec.Schedule = "every week"
+			setup()
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPost, "/system/gc/schedule")).To(BeEmpty())
+			Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(EventReasonInvalidSchedule)))
+		})
+
+		It("Should wait while a garbage collection is already running", func() {
+			server.HandleJSON(http.MethodPost, "/system/gc/schedule", http.StatusConflict, nil)
+			setup()
+
+			result, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(DefaultRequeueWait))
+			Expect(getGC().Status.LastRun).To(BeNil())
+		})
+	})
+
+	Context("Read-only", func() {
+		BeforeEach(func() {
+			gc.Spec.ReadOnly = true
+		})
+
+		It("Should wait for core to roll out in read-only mode before triggering", func() {
+			setup()
+
+			result, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(getGC().Status.HarborReadOnly).ToNot(BeNil())
+			Expect(*getGC().Status.HarborReadOnly).To(BeFalse())
+			Expect(getHarbor().Spec.ReadOnly).To(BeFalse())
+
+			result, err = r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(DefaultRequeueWait))
+			Expect(getHarbor().Spec.ReadOnly).To(BeTrue())
+			Expect(server.Requests(http.MethodPost, "/system/gc/schedule")).To(BeEmpty())
+
+			Expect(r.Client.Update(ctx, backuptest.NewCoreDeployment(harbor, true))).To(Succeed())
+
+			_, err = r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPost, "/system/gc/schedule")).To(HaveLen(1))
+		})
+
+		It("Should switch the Harbor back to read-write once the garbage collection finished", func() {
+			server.HandleJSON(http.MethodGet, "/system/gc/12", http.StatusOK, &harborapi.GCJob{ID: 12, Status: harborapi.GCStatusFinished})
+			server.Handle(http.MethodGet, "/system/gc/12/log", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("2020-01-04T03:04:10Z [INFO] The GC job actual frees up 34 MB space."))
+			})
+
+			readOnly := false
+			harbor.Spec.ReadOnly = true
+			gc.Status.HarborReadOnly = &readOnly
+			startRun()
+			setup()
+
+			result, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(getHarbor().Spec.ReadOnly).To(BeFalse())
+
+			current := getGC()
+			Expect(current.Status.HarborReadOnly).To(BeNil())
+			Expect(current.Status.LastRun.Result).To(Equal(goharborv1alpha1.GarbageCollectionSucceeded))
+			Expect(current.Status.LastRun.CompletionTime).ToNot(BeNil())
+			Expect(current.Status.LastRun.FreedBytes).ToNot(BeNil())
+			Expect(*current.Status.LastRun.FreedBytes).To(Equal(int64(34 * 1024 * 1024)))
+			Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.FailedConditionType)).To(BeFalse())
+		})
+
+		It("Should keep the Harbor read-only if it was before the garbage collection", func() {
+			server.HandleJSON(http.MethodGet, "/system/gc/12", http.StatusOK, &harborapi.GCJob{ID: 12, Status: harborapi.GCStatusError})
+
+			readOnly := true
+			harbor.Spec.ReadOnly = true
+			gc.Status.HarborReadOnly = &readOnly
+			startRun()
+			setup()
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getHarbor().Spec.ReadOnly).To(BeTrue())
+
+			current := getGC()
+			Expect(current.Status.HarborReadOnly).To(BeNil())
+			Expect(current.Status.LastRun.Result).To(Equal(goharborv1alpha1.GarbageCollectionFailed))
+			Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.FailedConditionType)).To(BeTrue())
+		})
+	})
+
+	It("Should wait while the garbage collection runs", func() {
+		server.HandleJSON(http.MethodGet, "/system/gc/12", http.StatusOK, &harborapi.GCJob{ID: 12, Status: harborapi.GCStatusRunning})
+
+		startRun()
+		setup()
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(DefaultRequeueWait))
+		Expect(getGC().Status.LastRun.CompletionTime).To(BeNil())
+	})
+
+	It("Should fail the run when the Harbor does not exist", func() {
+		r, ctx = setupTest(context.TODO(), gc)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+
+		current := getGC()
+		Expect(current.Status.LastRun.Result).To(Equal(goharborv1alpha1.GarbageCollectionFailed))
+		Expect(current.Status.LastRun.Message).To(Equal("harbor harbor not found"))
+	})
+})
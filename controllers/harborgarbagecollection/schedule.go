@@ -0,0 +1,26 @@
+package harborgarbagecollection
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+// NextSchedule returns the next time a garbage collection should be triggered.
+// Missed schedules are not caught up: a single garbage collection is triggered for all of them.
+func NextSchedule(gc *goharborv1alpha1.HarborGarbageCollection) (time.Time, error) {
+	schedule, err := cron.ParseStandard(gc.Spec.Schedule)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid schedule %s", gc.Spec.Schedule)
+	}
+
+	last := gc.GetCreationTimestamp().Time
+	if gc.Status.LastScheduleTime != nil {
+		last = gc.Status.LastScheduleTime.Time
+	}
+
+	return schedule.Next(last), nil
+}
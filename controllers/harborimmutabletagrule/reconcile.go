@@ -0,0 +1,270 @@
+package harborimmutabletagrule
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+	"github.com/goharbor/harbor-operator/pkg/references"
+)
+
+const (
+	EventReasonRuleCreated = "RuleCreated"
+	EventReasonRuleUpdated = "RuleUpdated"
+	EventReasonRuleDeleted = "RuleDeleted"
+)
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborimmutabletagrules,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborimmutabletagrules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborprojects,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="events",verbs=create;patch
+
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.TODO()
+	application.SetName(&ctx, r.GetName())
+	application.SetVersion(&ctx, r.GetVersion())
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "reconcile", opentracing.Tags{
+		"HarborImmutableTagRule.Namespace": req.Namespace,
+		"HarborImmutableTagRule.Name":      req.Name,
+	})
+	defer span.Finish()
+
+	reqLogger := r.Log.WithValues("Request", req.NamespacedName, "HarborImmutableTagRule.Namespace", req.Namespace, "HarborImmutableTagRule.Name", req.Name)
+
+	logger.Set(&ctx, reqLogger)
+
+	rule := &goharborv1alpha1.HarborImmutableTagRule{}
+
+	err := r.Client.Get(ctx, req.NamespacedName, rule)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			reqLogger.Info("HarborImmutableTagRule does not exists")
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if !rule.ObjectMeta.DeletionTimestamp.IsZero() {
+		reqLogger.Info("HarborImmutableTagRule is being deleted")
+		return reconcile.Result{}, r.Finalize(ctx, rule)
+	}
+
+	updated, err := reconciliation.UpdateFinalizer(ctx, r.Client, rule, rule.Spec.DeletionPolicy == goharborv1alpha1.DeletionPolicyDelete)
+	if err != nil || updated {
+		return reconcile.Result{}, err
+	}
+
+	result := reconcile.Result{
+		RequeueAfter: reconciliation.DefaultResyncPeriod,
+	}
+
+	syncErr := r.Sync(ctx, rule)
+	if syncErr != nil {
+		err = r.UpdateCondition(ctx, rule, goharborv1alpha1.ReadyConditionType, corev1.ConditionFalse, "sync-failed", syncErr.Error())
+	} else {
+		err = r.UpdateCondition(ctx, rule, goharborv1alpha1.ReadyConditionType, corev1.ConditionTrue, "synced", fmt.Sprintf("rule %d is in sync", rule.Status.RuleID))
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	rule.Status.ObservedGeneration = rule.GetGeneration()
+
+	err = reconciliation.UpdateStatus(ctx, r.Client, &result, rule)
+	if err != nil {
+		return result, err
+	}
+
+	return result, errors.Wrap(syncErr, "cannot sync immutable tag rule")
+}
+
+// Finalize deletes the rule from Harbor then removes the finalizer.
+func (r *Reconciler) Finalize(ctx context.Context, rule *goharborv1alpha1.HarborImmutableTagRule) error {
+	if !reconciliation.HasFinalizer(rule) {
+		return nil
+	}
+
+	if rule.Status.RuleID != 0 {
+		api, err := r.getClient(ctx, rule)
+		if err != nil {
+			return err
+		}
+
+		// Without Harbor, there is nothing to delete
+		if api != nil {
+			err = api.DeleteImmutableTagRule(ctx, rule.Status.ProjectID, rule.Status.RuleID)
+			if err != nil && !harborapi.IsNotFound(err) {
+				return errors.Wrapf(err, "cannot delete rule %d", rule.Status.RuleID)
+			}
+
+			r.Recorder.Eventf(rule, corev1.EventTypeNormal, EventReasonRuleDeleted, "rule %d deleted", rule.Status.RuleID)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(rule, goharborv1alpha1.HarborFinalizer)
+
+	err := r.Client.Update(ctx, rule)
+
+	return errors.Wrap(err, "cannot remove finalizer")
+}
+
+// getClient returns a client for the Harbor, nil if the Harbor does not exist.
+func (r *Reconciler) getClient(ctx context.Context, rule *goharborv1alpha1.HarborImmutableTagRule) (*harborapi.Client, error) {
+	api, _, err := harborapi.NewFromName(ctx, r.Client, rule.GetNamespace(), rule.Spec.HarborName, harborapi.UserAgent(r.GetName(), r.GetVersion()))
+
+	return api, err
+}
+
+func getSelector(selector goharborv1alpha1.PatternSelector, matches, excludes string) []harborapi.Selector {
+	decoration := matches
+	if selector.Exclude {
+		decoration = excludes
+	}
+
+	return []harborapi.Selector{{
+		Kind:       harborapi.SelectorKindDoublestar,
+		Decoration: decoration,
+		Pattern:    selector.GetPattern(),
+	}}
+}
+
+// GetRule returns the rule of the project matching the spec.
+func GetRule(rule *goharborv1alpha1.HarborImmutableTagRule, projectID int64) *harborapi.ImmutableTagRule {
+	return &harborapi.ImmutableTagRule{
+		ProjectID:    projectID,
+		Disabled:     rule.Spec.Disabled,
+		Action:       harborapi.ImmutableAction,
+		Template:     harborapi.ImmutableTemplate,
+		TagSelectors: getSelector(rule.Spec.Tags, harborapi.SelectorMatches, harborapi.SelectorExcludes),
+		ScopeSelectors: map[string][]harborapi.Selector{
+			harborapi.ScopeSelectorRepository: getSelector(rule.Spec.Repositories, harborapi.SelectorRepoMatches, harborapi.SelectorRepoExcludes),
+		},
+	}
+}
+
+// HasSameSelectors returns whether both rules apply to the same tags.
+func HasSameSelectors(desired, current *harborapi.ImmutableTagRule) bool {
+	return reflect.DeepEqual(desired.TagSelectors, current.TagSelectors) &&
+		reflect.DeepEqual(desired.ScopeSelectors, current.ScopeSelectors)
+}
+
+// IsInSync returns whether the current rule matches the desired one.
+func IsInSync(desired, current *harborapi.ImmutableTagRule) bool {
+	return desired.Disabled == current.Disabled && HasSameSelectors(desired, current)
+}
+
+// Sync creates the rule in Harbor or updates it, then updates the status.
+func (r *Reconciler) Sync(ctx context.Context, rule *goharborv1alpha1.HarborImmutableTagRule) error {
+	api, err := r.getClient(ctx, rule)
+	if err != nil {
+		return err
+	}
+
+	if api == nil {
+		return errors.Errorf("harbor %s not found", rule.Spec.HarborName)
+	}
+
+	project, err := references.GetProject(ctx, r.Client, api, rule.GetNamespace(), rule.Spec.HarborName, rule.Spec.Project)
+	if err != nil {
+		return err
+	}
+
+	if rule.Status.ProjectID != project.ID {
+		// The rule of the previous project is left untouched
+		rule.Status.ProjectID = project.ID
+		rule.Status.RuleID = 0
+	}
+
+	desired := GetRule(rule, project.ID)
+
+	current, err := r.getRule(ctx, api, rule, desired)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		id, err := api.CreateImmutableTagRule(ctx, desired)
+		if err != nil {
+			return errors.Wrap(err, "cannot create rule")
+		}
+
+		r.Recorder.Eventf(rule, corev1.EventTypeNormal, EventReasonRuleCreated, "rule %d created", id)
+
+		rule.Status.RuleID = id
+
+		return nil
+	}
+
+	rule.Status.RuleID = current.ID
+
+	if IsInSync(desired, current) {
+		return nil
+	}
+
+	desired.Priority = current.Priority
+
+	err = api.UpdateImmutableTagRule(ctx, current.ID, desired)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update rule %d", current.ID)
+	}
+
+	r.Recorder.Eventf(rule, corev1.EventTypeNormal, EventReasonRuleUpdated, "rule %d updated", current.ID)
+
+	return nil
+}
+
+// getRule returns the rule in Harbor, nil if it does not exist yet.
+// Rules have no name: without ID, a rule with the same selectors is adopted.
+func (r *Reconciler) getRule(ctx context.Context, api *harborapi.Client, rule *goharborv1alpha1.HarborImmutableTagRule, desired *harborapi.ImmutableTagRule) (*harborapi.ImmutableTagRule, error) {
+	rules, err := api.ListImmutableTagRules(ctx, rule.Status.ProjectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot list rules of project %d", rule.Status.ProjectID)
+	}
+
+	for i, current := range rules {
+		if rule.Status.RuleID != 0 && current.ID == rule.Status.RuleID {
+			return &rules[i], nil
+		}
+	}
+
+	// The rule has been deleted from the portal
+	rule.Status.RuleID = 0
+
+	for i, current := range rules {
+		if HasSameSelectors(desired, &current) {
+			return &rules[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *Reconciler) UpdateCondition(ctx context.Context, rule *goharborv1alpha1.HarborImmutableTagRule, conditionType goharborv1alpha1.HarborConditionType, status corev1.ConditionStatus, reasons ...string) error {
+	updated, _, err := conditions.Update(rule.Status.Conditions, conditionType, status, reasons...)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update condition %s", conditionType)
+	}
+
+	rule.Status.Conditions = updated
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package harborimmutabletagrule
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/harborapi/harborapitest"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+var _ = Describe("Reconcile", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var server *harborapitest.Server
+	var harbor *goharborv1alpha1.Harbor
+	var rule *goharborv1alpha1.HarborImmutableTagRule
+	var req ctrl.Request
+
+	BeforeEach(func() {
+		server = harborapitest.NewServer()
+		server.HandleJSON(http.MethodGet, "/projects", http.StatusOK, []harborapi.Project{{ID: 1, Name: "library"}, {ID: 2, Name: "other"}})
+		server.HandleJSON(http.MethodGet, "/projects/1/immutabletagrules", http.StatusOK, []harborapi.ImmutableTagRule{})
+		server.HandleJSON(http.MethodGet, "/projects/2/immutabletagrules", http.StatusOK, []harborapi.ImmutableTagRule{})
+		server.Handle(http.MethodPost, "/projects/1/immutabletagrules", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Location", "/api/projects/1/immutabletagrules/6")
+			w.WriteHeader(http.StatusCreated)
+		})
+		server.Handle(http.MethodPost, "/projects/2/immutabletagrules", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Location", "/api/projects/2/immutabletagrules/8")
+			w.WriteHeader(http.StatusCreated)
+		})
+		server.HandleJSON(http.MethodPut, "/projects/1/immutabletagrules/6", http.StatusOK, nil)
+		server.HandleJSON(http.MethodDelete, "/projects/1/immutabletagrules/6", http.StatusOK, nil)
+
+		harbor = harborapitest.NewHarbor("ns")
+
+		rule = &goharborv1alpha1.HarborImmutableTagRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "releases",
+				Namespace:  "ns",
+				Finalizers: []string{goharborv1alpha1.HarborFinalizer},
+			},
+			Spec: goharborv1alpha1.HarborImmutableTagRuleSpec{
+				HarborName: "harbor",
+				Project:    goharborv1alpha1.ProjectReference{Name: "library"},
+				Tags: goharborv1alpha1.PatternSelector{
+					Pattern: "v*",
+				},
+				DeletionPolicy: goharborv1alpha1.DeletionPolicyDelete,
+			},
+		}
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "releases"}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	setup := func(objects ...runtime.Object) {
+		r, ctx = setupTest(context.TODO(), append(objects, harbor, harborapitest.NewAdminPasswordSecret(harbor))...)
+	}
+
+	getRule := func() *goharborv1alpha1.HarborImmutableTagRule {
+		current := &goharborv1alpha1.HarborImmutableTagRule{}
+		Expect(r.Client.Get(ctx, req.NamespacedName, current)).To(Succeed())
+
+		return current
+	}
+
+	// existingRule returns the rule in Harbor matching the spec, with the ID 6.
+	existingRule := func() harborapi.ImmutableTagRule {
+		existing := GetRule(rule, 1)
+		existing.ID = 6
+		existing.Priority = 3
+
+		return *existing
+	}
+
+	It("Should add the finalizer first", func() {
+		rule.SetFinalizers(nil)
+		setup(rule)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getRule().GetFinalizers()).To(ConsistOf(goharborv1alpha1.HarborFinalizer))
+		Expect(server.Requests(http.MethodPost, "/projects/1/immutabletagrules")).To(BeEmpty())
+	})
+
+	It("Should create the rule in the project", func() {
+		setup(rule)
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(reconciliation.DefaultResyncPeriod))
+
+		requests := server.Requests(http.MethodPost, "/projects/1/immutabletagrules")
+		Expect(requests).To(HaveLen(1))
+
+		created := &harborapi.ImmutableTagRule{}
+		Expect(json.Unmarshal(requests[0].Body, created)).To(Succeed())
+		Expect(created.TagSelectors).To(ConsistOf(harborapi.Selector{Kind: "doublestar", Decoration: "matches", Pattern: "v*"}))
+
+		current := getRule()
+		Expect(current.Status.ProjectID).To(Equal(int64(1)))
+		Expect(current.Status.RuleID).To(Equal(int64(6)))
+		Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.ReadyConditionType)).To(BeTrue())
+	})
+
+	It("Should adopt a rule with the same selectors", func() {
+		server.HandleJSON(http.MethodGet, "/projects/1/immutabletagrules", http.StatusOK, []harborapi.ImmutableTagRule{existingRule()})
+		setup(rule)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.Requests(http.MethodPost, "/projects/1/immutabletagrules")).To(BeEmpty())
+		Expect(server.Requests(http.MethodPut, "/projects/1/immutabletagrules/6")).To(BeEmpty())
+		Expect(getRule().Status.RuleID).To(Equal(int64(6)))
+	})
+
+	It("Should report a missing project", func() {
+		rule.Spec.Project.Name = "missing"
+		setup(rule)
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(HaveOccurred())
+
+		condition := conditions.Get(getRule().Status.Conditions, goharborv1alpha1.ReadyConditionType)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("project missing not found"))
+	})
+
+	Context("With an existing rule", func() {
+		BeforeEach(func() {
+			rule.Status.ProjectID = 1
+			rule.Status.RuleID = 6
+		})
+
+		It("Should update the rule keeping its priority", func() {
+			server.HandleJSON(http.MethodGet, "/projects/1/immutabletagrules", http.StatusOK, []harborapi.ImmutableTagRule{existingRule()})
+			rule.Spec.Disabled = true
+			setup(rule)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+
+			requests := server.Requests(http.MethodPut, "/projects/1/immutabletagrules/6")
+			Expect(requests).To(HaveLen(1))
+
+			updated := &harborapi.ImmutableTagRule{}
+			Expect(json.Unmarshal(requests[0].Body, updated)).To(Succeed())
+			Expect(updated.Disabled).To(BeTrue())
+			Expect(updated.Priority).To(Equal(3))
+		})
+
+		It("Should recreate a rule deleted through the portal", func() {
+			setup(rule)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPost, "/projects/1/immutabletagrules")).To(HaveLen(1))
+			Expect(getRule().Status.RuleID).To(Equal(int64(6)))
+		})
+
+		It("Should create the rule in the new project, leaving the previous one", func() {
+			server.HandleJSON(http.MethodGet, "/projects/1/immutabletagrules", http.StatusOK, []harborapi.ImmutableTagRule{existingRule()})
+			rule.Spec.Project.Name = "other"
+			setup(rule)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPost, "/projects/2/immutabletagrules")).To(HaveLen(1))
+			Expect(server.Requests(http.MethodDelete, "/projects/1/immutabletagrules/6")).To(BeEmpty())
+
+			current := getRule()
+			Expect(current.Status.ProjectID).To(Equal(int64(2)))
+			Expect(current.Status.RuleID).To(Equal(int64(8)))
+		})
+	})
+
+	Context("Deletion", func() {
+		BeforeEach(func() {
+			now := metav1.Now()
+			rule.SetDeletionTimestamp(&now)
+			rule.Status.ProjectID = 1
+			rule.Status.RuleID = 6
+		})
+
+		It("Should delete the rule then remove the finalizer", func() {
+			setup(rule)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodDelete, "/projects/1/immutabletagrules/6")).To(HaveLen(1))
+			Expect(getRule().GetFinalizers()).To(BeEmpty())
+		})
+
+		It("Should keep the finalizer when the deletion fails", func() {
+			server.HandleJSON(http.MethodDelete, "/projects/1/immutabletagrules/6", http.StatusInternalServerError, nil)
+			setup(rule)
+
+			_, err := r.Reconcile(req)
+			Expect(err).To(HaveOccurred())
+			Expect(getRule().GetFinalizers()).To(ConsistOf(goharborv1alpha1.HarborFinalizer))
+		})
+
+		It("Should remove the finalizer when the Harbor does not exist", func() {
+			r, ctx = setupTest(context.TODO(), rule)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodDelete, "/projects/1/immutabletagrules/6")).To(BeEmpty())
+			Expect(getRule().GetFinalizers()).To(BeEmpty())
+		})
+	})
+})
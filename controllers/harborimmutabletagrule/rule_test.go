@@ -0,0 +1,59 @@
+package harborimmutabletagrule
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+)
+
+var _ = Describe("HarborImmutableTagRule", func() {
+	var rule *goharborv1alpha1.HarborImmutableTagRule
+	var current *harborapi.ImmutableTagRule
+
+	BeforeEach(func() {
+		rule = &goharborv1alpha1.HarborImmutableTagRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "releases",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborImmutableTagRuleSpec{
+				HarborName: "harbor",
+				Project:    goharborv1alpha1.ProjectReference{HarborProject: "library"},
+				Tags: goharborv1alpha1.PatternSelector{
+					Pattern: "v*",
+				},
+			},
+		}
+
+		current = &harborapi.ImmutableTagRule{}
+
+		Expect(json.Unmarshal([]byte(`{
+			"id": 3, "project_id": 1, "priority": 0, "disabled": false, "action": "immutable", "template": "immutable_template",
+			"tag_selectors": [{"kind": "doublestar", "decoration": "matches", "pattern": "v*"}],
+			"scope_selectors": {"repository": [{"kind": "doublestar", "decoration": "repoMatches", "pattern": "**"}]}
+		}`), current)).To(Succeed())
+	})
+
+	It("Should be in sync", func() {
+		Expect(IsInSync(GetRule(rule, 1), current)).To(BeTrue())
+	})
+
+	It("Should detect rules disabled in the portal", func() {
+		current.Disabled = true
+
+		Expect(HasSameSelectors(GetRule(rule, 1), current)).To(BeTrue())
+		Expect(IsInSync(GetRule(rule, 1), current)).To(BeFalse())
+	})
+
+	It("Should not adopt rules with other selectors", func() {
+		rule.Spec.Repositories.Exclude = true
+
+		Expect(HasSameSelectors(GetRule(rule, 1), current)).To(BeFalse())
+	})
+})
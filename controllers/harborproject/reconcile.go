@@ -0,0 +1,302 @@
+package harborproject
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+const (
+	EventReasonProjectCreated = "ProjectCreated"
+	EventReasonProjectUpdated = "ProjectUpdated"
+	EventReasonProjectDeleted = "ProjectDeleted"
+	EventReasonQuotaUpdated   = "QuotaUpdated"
+)
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborprojects,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborprojects/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="events",verbs=create;patch
+
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.TODO()
+	application.SetName(&ctx, r.GetName())
+	application.SetVersion(&ctx, r.GetVersion())
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "reconcile", opentracing.Tags{
+		"HarborProject.Namespace": req.Namespace,
+		"HarborProject.Name":      req.Name,
+	})
+	defer span.Finish()
+
+	reqLogger := r.Log.WithValues("Request", req.NamespacedName, "HarborProject.Namespace", req.Namespace, "HarborProject.Name", req.Name)
+
+	logger.Set(&ctx, reqLogger)
+
+	project := &goharborv1alpha1.HarborProject{}
+
+	err := r.Client.Get(ctx, req.NamespacedName, project)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			reqLogger.Info("HarborProject does not exists")
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if !project.ObjectMeta.DeletionTimestamp.IsZero() {
+		reqLogger.Info("HarborProject is being deleted")
+		return reconcile.Result{}, r.Finalize(ctx, project)
+	}
+
+	updated, err := reconciliation.UpdateFinalizer(ctx, r.Client, project, project.Spec.DeletionPolicy == goharborv1alpha1.DeletionPolicyDelete)
+	if err != nil || updated {
+		return reconcile.Result{}, err
+	}
+
+	result := reconcile.Result{
+		RequeueAfter: reconciliation.DefaultResyncPeriod,
+	}
+
+	syncErr := r.Sync(ctx, project)
+	if syncErr != nil {
+		err = r.UpdateCondition(ctx, project, goharborv1alpha1.ReadyConditionType, corev1.ConditionFalse, "sync-failed", syncErr.Error())
+	} else {
+		err = r.UpdateCondition(ctx, project, goharborv1alpha1.ReadyConditionType, corev1.ConditionTrue, "synced", fmt.Sprintf("project %d is in sync", project.Status.ProjectID))
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	project.Status.ObservedGeneration = project.GetGeneration()
+
+	err = reconciliation.UpdateStatus(ctx, r.Client, &result, project)
+	if err != nil {
+		return result, err
+	}
+
+	return result, errors.Wrap(syncErr, "cannot sync project")
+}
+
+// Finalize deletes the project from Harbor then removes the finalizer.
+func (r *Reconciler) Finalize(ctx context.Context, project *goharborv1alpha1.HarborProject) error {
+	if !reconciliation.HasFinalizer(project) {
+		return nil
+	}
+
+	if project.Status.ProjectID != 0 {
+		api, err := r.getClient(ctx, project)
+		if err != nil {
+			return err
+		}
+
+		// Without Harbor, there is nothing to delete
+		if api != nil {
+			err = api.DeleteProject(ctx, project.Status.ProjectID)
+			if err != nil && !harborapi.IsNotFound(err) {
+				return errors.Wrapf(err, "cannot delete project %d", project.Status.ProjectID)
+			}
+
+			r.Recorder.Eventf(project, corev1.EventTypeNormal, EventReasonProjectDeleted, "project %d deleted", project.Status.ProjectID)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(project, goharborv1alpha1.HarborFinalizer)
+
+	err := r.Client.Update(ctx, project)
+
+	return errors.Wrap(err, "cannot remove finalizer")
+}
+
+// getClient returns a client for the Harbor hosting the project, nil if the Harbor does not exist.
+func (r *Reconciler) getClient(ctx context.Context, project *goharborv1alpha1.HarborProject) (*harborapi.Client, error) {
+	api, _, err := harborapi.NewFromName(ctx, r.Client, project.GetNamespace(), project.Spec.HarborName, harborapi.UserAgent(r.GetName(), r.GetVersion()))
+
+	return api, err
+}
+
+// GetMetadata returns the project metadata matching the spec.
+func GetMetadata(project *goharborv1alpha1.HarborProject) map[string]string {
+	metadata := map[string]string{
+		harborapi.ProjectPublicKey:            strconv.FormatBool(project.Spec.Public),
+		harborapi.ProjectAutoScanKey:          strconv.FormatBool(project.Spec.AutoScan),
+		harborapi.ProjectContentTrustKey:      strconv.FormatBool(project.Spec.ContentTrust),
+		harborapi.ProjectPreventVulnerableKey: strconv.FormatBool(project.Spec.PreventVulnerable),
+	}
+
+	if project.Spec.Severity != "" {
+		metadata[harborapi.ProjectSeverityKey] = project.Spec.Severity
+	}
+
+	return metadata
+}
+
+// GetStorageLimit returns the storage quota matching the spec, in bytes.
+func GetStorageLimit(project *goharborv1alpha1.HarborProject) int64 {
+	if project.Spec.StorageQuota == nil {
+		return harborapi.UnlimitedQuota
+	}
+
+	return project.Spec.StorageQuota.Value()
+}
+
+// IsMetadataInSync returns whether current metadata contains the desired ones.
+// Unset booleans are false in Harbor.
+func IsMetadataInSync(desired, current map[string]string) bool {
+	for key, value := range desired {
+		currentValue, ok := current[key]
+		if !ok && value == strconv.FormatBool(false) {
+			continue
+		}
+
+		if currentValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Sync creates the project in Harbor or updates its settings, then updates the status.
+func (r *Reconciler) Sync(ctx context.Context, project *goharborv1alpha1.HarborProject) error {
+	api, err := r.getClient(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	if api == nil {
+		return errors.Errorf("harbor %s not found", project.Spec.HarborName)
+	}
+
+	current, err := r.getProject(ctx, api, project)
+	if err != nil {
+		return err
+	}
+
+	metadata := GetMetadata(project)
+
+	switch {
+	case current == nil:
+		storageLimit := GetStorageLimit(project)
+
+		id, err := api.CreateProject(ctx, &harborapi.ProjectReq{
+			Name:         project.GetProjectName(),
+			Metadata:     metadata,
+			StorageLimit: &storageLimit,
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot create project")
+		}
+
+		r.Recorder.Eventf(project, corev1.EventTypeNormal, EventReasonProjectCreated, "project %d created", id)
+
+		current, err = api.GetProject(ctx, id)
+		if err != nil {
+			return errors.Wrapf(err, "cannot get project %d", id)
+		}
+	case !IsMetadataInSync(metadata, current.Metadata):
+		err = api.UpdateProject(ctx, current.ID, &harborapi.ProjectReq{
+			Metadata: metadata,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "cannot update project %d", current.ID)
+		}
+
+		r.Recorder.Eventf(project, corev1.EventTypeNormal, EventReasonProjectUpdated, "project %d updated", current.ID)
+	}
+
+	project.Status.ProjectID = current.ID
+	project.Status.RepositoryCount = current.RepoCount
+
+	return r.syncQuota(ctx, api, project)
+}
+
+// getProject returns the project in Harbor, nil if it does not exist yet.
+func (r *Reconciler) getProject(ctx context.Context, api *harborapi.Client, project *goharborv1alpha1.HarborProject) (*harborapi.Project, error) {
+	if project.Status.ProjectID != 0 {
+		current, err := api.GetProject(ctx, project.Status.ProjectID)
+		if err == nil {
+			return current, nil
+		}
+
+		if !harborapi.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "cannot get project %d", project.Status.ProjectID)
+		}
+
+		// The project has been deleted from the portal
+		project.Status.ProjectID = 0
+	}
+
+	current, err := api.GetProjectByName(ctx, project.GetProjectName())
+
+	return current, errors.Wrapf(err, "cannot get project %s", project.GetProjectName())
+}
+
+func (r *Reconciler) syncQuota(ctx context.Context, api *harborapi.Client, project *goharborv1alpha1.HarborProject) error {
+	quota, err := api.GetProjectQuota(ctx, project.Status.ProjectID)
+	if err != nil {
+		return errors.Wrap(err, "cannot get quota")
+	}
+
+	if quota == nil {
+		project.Status.Quota = nil
+		return nil
+	}
+
+	storageLimit := GetStorageLimit(project)
+
+	if quota.Hard[harborapi.QuotaStorageKey] != storageLimit {
+		if quota.Hard == nil {
+			quota.Hard = map[string]int64{}
+		}
+
+		quota.Hard[harborapi.QuotaStorageKey] = storageLimit
+
+		err = api.UpdateQuota(ctx, quota.ID, quota.Hard)
+		if err != nil {
+			return errors.Wrapf(err, "cannot update quota %d", quota.ID)
+		}
+
+		r.Recorder.Eventf(project, corev1.EventTypeNormal, EventReasonQuotaUpdated, "storage quota set to %d", storageLimit)
+	}
+
+	project.Status.Quota = &goharborv1alpha1.ProjectQuotaStatus{
+		Used: resource.NewQuantity(quota.Used[harborapi.QuotaStorageKey], resource.BinarySI),
+	}
+
+	if storageLimit != harborapi.UnlimitedQuota {
+		project.Status.Quota.Hard = resource.NewQuantity(storageLimit, resource.BinarySI)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) UpdateCondition(ctx context.Context, project *goharborv1alpha1.HarborProject, conditionType goharborv1alpha1.HarborConditionType, status corev1.ConditionStatus, reasons ...string) error {
+	updated, _, err := conditions.Update(project.Status.Conditions, conditionType, status, reasons...)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update condition %s", conditionType)
+	}
+
+	project.Status.Conditions = updated
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package harborproject
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/harborapi/harborapitest"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+var _ = Describe("Reconcile", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var server *harborapitest.Server
+	var harbor *goharborv1alpha1.Harbor
+	var project *goharborv1alpha1.HarborProject
+	var req ctrl.Request
+
+	BeforeEach(func() {
+		server = harborapitest.NewServer()
+		server.HandleJSON(http.MethodGet, "/projects", http.StatusOK, []harborapi.Project{})
+		server.Handle(http.MethodPost, "/projects", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Location", "/api/projects/3")
+			w.WriteHeader(http.StatusCreated)
+		})
+		server.HandleJSON(http.MethodGet, "/projects/3", http.StatusOK, &harborapi.Project{
+			ID:       3,
+			Name:     "library",
+			Metadata: map[string]string{harborapi.ProjectPublicKey: "true"},
+		})
+		server.HandleJSON(http.MethodPut, "/projects/3", http.StatusOK, nil)
+		server.HandleJSON(http.MethodDelete, "/projects/3", http.StatusOK, nil)
+		server.HandleJSON(http.MethodGet, "/quotas", http.StatusOK, []harborapi.Quota{{
+			ID:   4,
+			Hard: map[string]int64{harborapi.QuotaStorageKey: harborapi.UnlimitedQuota},
+			Used: map[string]int64{harborapi.QuotaStorageKey: 1024},
+		}})
+		server.HandleJSON(http.MethodPut, "/quotas/4", http.StatusOK, nil)
+
+		harbor = harborapitest.NewHarbor("ns")
+
+		project = &goharborv1alpha1.HarborProject{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "library",
+				Namespace:  "ns",
+				Finalizers: []string{goharborv1alpha1.HarborFinalizer},
+			},
+			Spec: goharborv1alpha1.HarborProjectSpec{
+				HarborName:     "harbor",
+				Public:         true,
+				DeletionPolicy: goharborv1alpha1.DeletionPolicyDelete,
+			},
+		}
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "library"}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	setup := func(objects ...runtime.Object) {
+		r, ctx = setupTest(context.TODO(), append(objects, harbor, harborapitest.NewAdminPasswordSecret(harbor))...)
+	}
+
+	getProject := func() *goharborv1alpha1.HarborProject {
+		current := &goharborv1alpha1.HarborProject{}
+		Expect(r.Client.Get(ctx, req.NamespacedName, current)).To(Succeed())
+
+		return current
+	}
+
+	It("Should add the finalizer first", func() {
+		project.SetFinalizers(nil)
+		setup(project)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getProject().GetFinalizers()).To(ConsistOf(goharborv1alpha1.HarborFinalizer))
+		Expect(server.Requests(http.MethodPost, "/projects")).To(BeEmpty())
+	})
+
+	It("Should not register the finalizer when the project is retained", func() {
+		project.SetFinalizers(nil)
+		project.Spec.DeletionPolicy = goharborv1alpha1.DeletionPolicyRetain
+		setup(project)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getProject().GetFinalizers()).To(BeEmpty())
+		Expect(server.Requests(http.MethodPost, "/projects")).To(HaveLen(1))
+	})
+
+	It("Should create the project through the core service", func() {
+		setup(project)
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(reconciliation.DefaultResyncPeriod))
+
+		requests := server.Requests(http.MethodPost, "/projects")
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Host).To(Equal("harbor-core.ns.svc"))
+		Expect(requests[0].Username).To(Equal(harborapi.AdminUsername))
+		Expect(requests[0].Password).To(Equal(harborapitest.AdminPassword))
+
+		created := &harborapi.ProjectReq{}
+		Expect(json.Unmarshal(requests[0].Body, created)).To(Succeed())
+		Expect(created.Name).To(Equal("library"))
+		Expect(created.Metadata).To(HaveKeyWithValue(harborapi.ProjectPublicKey, "true"))
+		Expect(created.StorageLimit).ToNot(BeNil())
+		Expect(*created.StorageLimit).To(Equal(harborapi.UnlimitedQuota))
+
+		current := getProject()
+		Expect(current.Status.ProjectID).To(Equal(int64(3)))
+		Expect(current.Status.Quota).ToNot(BeNil())
+		Expect(current.Status.Quota.Used.Value()).To(Equal(int64(1024)))
+		Expect(current.Status.Quota.Hard).To(BeNil())
+		Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.ReadyConditionType)).To(BeTrue())
+	})
+
+	Context("With an existing project", func() {
+		BeforeEach(func() {
+			project.Status.ProjectID = 3
+		})
+
+		It("Should not update a project in sync", func() {
+			setup(project)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPost, "/projects")).To(BeEmpty())
+			Expect(server.Requests(http.MethodPut, "/projects/3")).To(BeEmpty())
+			Expect(server.Requests(http.MethodPut, "/quotas/4")).To(BeEmpty())
+		})
+
+		It("Should update metadata and quota", func() {
+			project.Spec.AutoScan = true
+			quota := resource.MustParse("1Gi")
+			project.Spec.StorageQuota = &quota
+			setup(project)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+
+			requests := server.Requests(http.MethodPut, "/projects/3")
+			Expect(requests).To(HaveLen(1))
+
+			updated := &harborapi.ProjectReq{}
+			Expect(json.Unmarshal(requests[0].Body, updated)).To(Succeed())
+			Expect(updated.Metadata).To(HaveKeyWithValue(harborapi.ProjectAutoScanKey, "true"))
+
+			requests = server.Requests(http.MethodPut, "/quotas/4")
+			Expect(requests).To(HaveLen(1))
+			Expect(string(requests[0].Body)).To(ContainSubstring(`"storage":1073741824`))
+
+			current := getProject()
+			Expect(current.Status.Quota.Hard).ToNot(BeNil())
+			Expect(current.Status.Quota.Hard.Value()).To(Equal(int64(1073741824)))
+		})
+
+		It("Should recreate a project deleted through the portal", func() {
+			server.HandleJSON(http.MethodGet, "/projects/3", http.StatusNotFound, nil)
+			server.Handle(http.MethodPost, "/projects", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Location", "/api/projects/5")
+				w.WriteHeader(http.StatusCreated)
+			})
+			server.HandleJSON(http.MethodGet, "/projects/5", http.StatusOK, &harborapi.Project{ID: 5, Name: "library"})
+			setup(project)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPost, "/projects")).To(HaveLen(1))
+			Expect(getProject().Status.ProjectID).To(Equal(int64(5)))
+		})
+	})
+
+	It("Should report a missing Harbor", func() {
+		r, ctx = setupTest(context.TODO(), project)
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(HaveOccurred())
+
+		condition := conditions.Get(getProject().Status.Conditions, goharborv1alpha1.ReadyConditionType)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("harbor harbor not found"))
+	})
+
+	Context("Deletion", func() {
+		BeforeEach(func() {
+			now := metav1.Now()
+			project.SetDeletionTimestamp(&now)
+			project.Status.ProjectID = 3
+		})
+
+		It("Should delete the project then remove the finalizer", func() {
+			setup(project)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodDelete, "/projects/3")).To(HaveLen(1))
+			Expect(getProject().GetFinalizers()).To(BeEmpty())
+		})
+
+		It("Should keep the finalizer when the deletion fails", func() {
+			server.HandleJSON(http.MethodDelete, "/projects/3", http.StatusPreconditionFailed, nil)
+			setup(project)
+
+			_, err := r.Reconcile(req)
+			Expect(err).To(HaveOccurred())
+			Expect(getProject().GetFinalizers()).To(ConsistOf(goharborv1alpha1.HarborFinalizer))
+		})
+
+		It("Should remove the finalizer when the Harbor does not exist", func() {
+			r, ctx = setupTest(context.TODO(), project)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodDelete, "/projects/3")).To(BeEmpty())
+			Expect(getProject().GetFinalizers()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,47 @@
+package harborproject
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/scheme"
+)
+
+// These tests use Ginkgo (BDD-style Go testing framework). Refer to
+// http://onsi.github.io/ginkgo/ to learn more about Ginkgo.
+
+func TestHarborProject(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecsWithDefaultAndCustomReporters(t,
+		"HarborProject Controller Suite",
+		[]Reporter{envtest.NewlineReporter{}})
+}
+
+// setupTest returns a reconciler working on a fake cluster holding the given objects.
+func setupTest(ctx context.Context, objects ...runtime.Object) (*Reconciler, context.Context) {
+	log := zap.LoggerTo(GinkgoWriter, true)
+	logger.Set(&ctx, log)
+
+	s, err := scheme.New(ctx)
+	Expect(err).ToNot(HaveOccurred(), "failed to initialize scheme")
+
+	return &Reconciler{
+		Client:   fake.NewFakeClientWithScheme(s, objects...),
+		Name:     "harbor-operator",
+		Version:  "test",
+		Log:      log,
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(10),
+	}, ctx
+}
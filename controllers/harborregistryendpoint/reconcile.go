@@ -0,0 +1,304 @@
+package harborregistryendpoint
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+const (
+	EventReasonRegistryCreated = "RegistryCreated"
+	EventReasonRegistryUpdated = "RegistryUpdated"
+	EventReasonRegistryDeleted = "RegistryDeleted"
+)
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborregistryendpoints,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborregistryendpoints/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="events",verbs=create;patch
+
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.TODO()
+	application.SetName(&ctx, r.GetName())
+	application.SetVersion(&ctx, r.GetVersion())
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "reconcile", opentracing.Tags{
+		"HarborRegistryEndpoint.Namespace": req.Namespace,
+		"HarborRegistryEndpoint.Name":      req.Name,
+	})
+	defer span.Finish()
+
+	reqLogger := r.Log.WithValues("Request", req.NamespacedName, "HarborRegistryEndpoint.Namespace", req.Namespace, "HarborRegistryEndpoint.Name", req.Name)
+
+	logger.Set(&ctx, reqLogger)
+
+	endpoint := &goharborv1alpha1.HarborRegistryEndpoint{}
+
+	err := r.Client.Get(ctx, req.NamespacedName, endpoint)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			reqLogger.Info("HarborRegistryEndpoint does not exists")
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if !endpoint.ObjectMeta.DeletionTimestamp.IsZero() {
+		reqLogger.Info("HarborRegistryEndpoint is being deleted")
+		return reconcile.Result{}, r.Finalize(ctx, endpoint)
+	}
+
+	updated, err := reconciliation.UpdateFinalizer(ctx, r.Client, endpoint, endpoint.Spec.DeletionPolicy == goharborv1alpha1.DeletionPolicyDelete)
+	if err != nil || updated {
+		return reconcile.Result{}, err
+	}
+
+	result := reconcile.Result{
+		RequeueAfter: reconciliation.DefaultResyncPeriod,
+	}
+
+	syncErr := r.Sync(ctx, endpoint)
+	if syncErr != nil {
+		err = r.UpdateCondition(ctx, endpoint, goharborv1alpha1.ReadyConditionType, corev1.ConditionFalse, "sync-failed", syncErr.Error())
+	} else {
+		err = r.UpdateCondition(ctx, endpoint, goharborv1alpha1.ReadyConditionType, corev1.ConditionTrue, "synced", fmt.Sprintf("registry %d is in sync", endpoint.Status.RegistryID))
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	endpoint.Status.ObservedGeneration = endpoint.GetGeneration()
+
+	err = reconciliation.UpdateStatus(ctx, r.Client, &result, endpoint)
+	if err != nil {
+		return result, err
+	}
+
+	return result, errors.Wrap(syncErr, "cannot sync registry")
+}
+
+// Finalize deletes the registry from Harbor then removes the finalizer.
+func (r *Reconciler) Finalize(ctx context.Context, endpoint *goharborv1alpha1.HarborRegistryEndpoint) error {
+	if !reconciliation.HasFinalizer(endpoint) {
+		return nil
+	}
+
+	if endpoint.Status.RegistryID != 0 {
+		api, err := r.getClient(ctx, endpoint)
+		if err != nil {
+			return err
+		}
+
+		// Without Harbor, there is nothing to delete
+		if api != nil {
+			err = api.DeleteRegistry(ctx, endpoint.Status.RegistryID)
+			if err != nil && !harborapi.IsNotFound(err) {
+				return errors.Wrapf(err, "cannot delete registry %d", endpoint.Status.RegistryID)
+			}
+
+			r.Recorder.Eventf(endpoint, corev1.EventTypeNormal, EventReasonRegistryDeleted, "registry %d deleted", endpoint.Status.RegistryID)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(endpoint, goharborv1alpha1.HarborFinalizer)
+
+	err := r.Client.Update(ctx, endpoint)
+
+	return errors.Wrap(err, "cannot remove finalizer")
+}
+
+// getClient returns a client for the Harbor, nil if the Harbor does not exist.
+func (r *Reconciler) getClient(ctx context.Context, endpoint *goharborv1alpha1.HarborRegistryEndpoint) (*harborapi.Client, error) {
+	api, _, err := harborapi.NewFromName(ctx, r.Client, endpoint.GetNamespace(), endpoint.Spec.HarborName, harborapi.UserAgent(r.GetName(), r.GetVersion()))
+
+	return api, err
+}
+
+// GetCredential returns the credential of the registry from the credentials secret, nil without secret.
+func (r *Reconciler) GetCredential(ctx context.Context, endpoint *goharborv1alpha1.HarborRegistryEndpoint) (*harborapi.RegistryCredential, error) {
+	if endpoint.Spec.CredentialsSecret == "" {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: endpoint.GetNamespace(), Name: endpoint.Spec.CredentialsSecret}, secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get secret %s", endpoint.Spec.CredentialsSecret)
+	}
+
+	accessKey, ok := secret.Data[goharborv1alpha1.RegistryAccessKeyKey]
+	if !ok {
+		return nil, errors.Errorf("key %s not found in secret %s", goharborv1alpha1.RegistryAccessKeyKey, endpoint.Spec.CredentialsSecret)
+	}
+
+	return harborapi.NewBasicCredential(string(accessKey), string(secret.Data[goharborv1alpha1.RegistryAccessSecretKey])), nil
+}
+
+// HashCredential returns a hash of the credential, to detect changes since Harbor does not return secrets.
+func HashCredential(credential *harborapi.RegistryCredential) string {
+	if credential == nil {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s", credential.AccessKey, credential.AccessSecret)))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// GetRegistry returns the registry matching the spec.
+func GetRegistry(endpoint *goharborv1alpha1.HarborRegistryEndpoint, credential *harborapi.RegistryCredential) *harborapi.Registry {
+	return &harborapi.Registry{
+		Name:        endpoint.GetRegistryName(),
+		Description: endpoint.Spec.Description,
+		Type:        endpoint.Spec.Type,
+		URL:         endpoint.Spec.URL,
+		Credential:  credential,
+		Insecure:    endpoint.Spec.Insecure,
+	}
+}
+
+// IsInSync returns whether the current registry matches the desired one, except for the secret.
+func IsInSync(desired, current *harborapi.Registry) bool {
+	if desired.Name != current.Name || desired.Description != current.Description ||
+		desired.URL != current.URL || desired.Insecure != current.Insecure {
+		return false
+	}
+
+	var desiredKey, currentKey string
+
+	if desired.Credential != nil {
+		desiredKey = desired.Credential.AccessKey
+	}
+
+	if current.Credential != nil {
+		currentKey = current.Credential.AccessKey
+	}
+
+	return desiredKey == currentKey
+}
+
+// Sync creates the registry in Harbor or updates it, then updates the status.
+func (r *Reconciler) Sync(ctx context.Context, endpoint *goharborv1alpha1.HarborRegistryEndpoint) error {
+	api, err := r.getClient(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	if api == nil {
+		return errors.Errorf("harbor %s not found", endpoint.Spec.HarborName)
+	}
+
+	credential, err := r.GetCredential(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	desired := GetRegistry(endpoint, credential)
+	hash := HashCredential(credential)
+
+	current, err := r.getRegistry(ctx, api, endpoint)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		id, err := api.CreateRegistry(ctx, desired)
+		if err != nil {
+			return errors.Wrap(err, "cannot create registry")
+		}
+
+		r.Recorder.Eventf(endpoint, corev1.EventTypeNormal, EventReasonRegistryCreated, "registry %d created", id)
+
+		endpoint.Status.RegistryID = id
+		endpoint.Status.CredentialsHash = hash
+
+		return nil
+	}
+
+	endpoint.Status.RegistryID = current.ID
+
+	if current.Type != desired.Type {
+		return errors.Errorf("registry %d has type %s, the type cannot be changed", current.ID, current.Type)
+	}
+
+	if IsInSync(desired, current) && hash == endpoint.Status.CredentialsHash {
+		return nil
+	}
+
+	update := &harborapi.RegistryUpdate{
+		Name:        desired.Name,
+		Description: desired.Description,
+		URL:         desired.URL,
+		Insecure:    desired.Insecure,
+	}
+
+	if credential != nil {
+		update.CredentialType = credential.Type
+		update.AccessKey = credential.AccessKey
+		update.AccessSecret = credential.AccessSecret
+	}
+
+	err = api.UpdateRegistry(ctx, current.ID, update)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update registry %d", current.ID)
+	}
+
+	r.Recorder.Eventf(endpoint, corev1.EventTypeNormal, EventReasonRegistryUpdated, "registry %d updated", current.ID)
+
+	endpoint.Status.CredentialsHash = hash
+
+	return nil
+}
+
+// getRegistry returns the registry in Harbor, nil if it does not exist yet.
+func (r *Reconciler) getRegistry(ctx context.Context, api *harborapi.Client, endpoint *goharborv1alpha1.HarborRegistryEndpoint) (*harborapi.Registry, error) {
+	if endpoint.Status.RegistryID != 0 {
+		current, err := api.GetRegistry(ctx, endpoint.Status.RegistryID)
+		if err == nil {
+			return current, nil
+		}
+
+		if !harborapi.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "cannot get registry %d", endpoint.Status.RegistryID)
+		}
+
+		// The registry has been deleted from the portal
+		endpoint.Status.RegistryID = 0
+	}
+
+	current, err := api.GetRegistryByName(ctx, endpoint.GetRegistryName())
+
+	return current, errors.Wrapf(err, "cannot get registry %s", endpoint.GetRegistryName())
+}
+
+func (r *Reconciler) UpdateCondition(ctx context.Context, endpoint *goharborv1alpha1.HarborRegistryEndpoint, conditionType goharborv1alpha1.HarborConditionType, status corev1.ConditionStatus, reasons ...string) error {
+	updated, _, err := conditions.Update(endpoint.Status.Conditions, conditionType, status, reasons...)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update condition %s", conditionType)
+	}
+
+	endpoint.Status.Conditions = updated
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package harborregistryendpoint
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/harborapi/harborapitest"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+var _ = Describe("Reconcile", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var server *harborapitest.Server
+	var harbor *goharborv1alpha1.Harbor
+	var endpoint *goharborv1alpha1.HarborRegistryEndpoint
+	var credentials *corev1.Secret
+	var req ctrl.Request
+
+	BeforeEach(func() {
+		server = harborapitest.NewServer()
+		server.HandleJSON(http.MethodGet, "/registries", http.StatusOK, []harborapi.Registry{})
+		server.Handle(http.MethodPost, "/registries", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Location", "/api/registries/7")
+			w.WriteHeader(http.StatusCreated)
+		})
+		server.HandleJSON(http.MethodPut, "/registries/7", http.StatusOK, nil)
+		server.HandleJSON(http.MethodDelete, "/registries/7", http.StatusOK, nil)
+
+		harbor = harborapitest.NewHarbor("ns")
+
+		endpoint = &goharborv1alpha1.HarborRegistryEndpoint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "hub",
+				Namespace:  "ns",
+				Finalizers: []string{goharborv1alpha1.HarborFinalizer},
+			},
+			Spec: goharborv1alpha1.HarborRegistryEndpointSpec{
+				HarborName:        "harbor",
+				Type:              "docker-hub",
+				URL:               "https://hub.docker.com",
+				CredentialsSecret: "hub-credentials",
+				DeletionPolicy:    goharborv1alpha1.DeletionPolicyDelete,
+			},
+		}
+
+		credentials = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hub-credentials",
+				Namespace: "ns",
+			},
+			Data: map[string][]byte{
+				goharborv1alpha1.RegistryAccessKeyKey:    []byte("user"),
+				goharborv1alpha1.RegistryAccessSecretKey: []byte("password"),
+			},
+		}
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "hub"}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	setup := func(objects ...runtime.Object) {
+		r, ctx = setupTest(context.TODO(), append(objects, harbor, harborapitest.NewAdminPasswordSecret(harbor))...)
+	}
+
+	getEndpoint := func() *goharborv1alpha1.HarborRegistryEndpoint {
+		current := &goharborv1alpha1.HarborRegistryEndpoint{}
+		Expect(r.Client.Get(ctx, req.NamespacedName, current)).To(Succeed())
+
+		return current
+	}
+
+	// currentRegistry returns the registry in Harbor matching the spec, as created by the first reconciliation.
+	currentRegistry := func() *harborapi.Registry {
+		return &harborapi.Registry{
+			ID:         7,
+			Name:       "hub",
+			Type:       "docker-hub",
+			URL:        "https://hub.docker.com",
+			Credential: &harborapi.RegistryCredential{Type: "basic", AccessKey: "user", AccessSecret: "*****"},
+		}
+	}
+
+	It("Should add the finalizer first", func() {
+		endpoint.SetFinalizers(nil)
+		setup(endpoint, credentials)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getEndpoint().GetFinalizers()).To(ConsistOf(goharborv1alpha1.HarborFinalizer))
+		Expect(server.Requests(http.MethodPost, "/registries")).To(BeEmpty())
+	})
+
+	It("Should remove the finalizer when the registry must be kept", func() {
+		endpoint.Spec.DeletionPolicy = goharborv1alpha1.DeletionPolicyRetain
+		setup(endpoint, credentials)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getEndpoint().GetFinalizers()).To(BeEmpty())
+	})
+
+	It("Should create the registry with its credentials", func() {
+		setup(endpoint, credentials)
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(reconciliation.DefaultResyncPeriod))
+
+		requests := server.Requests(http.MethodPost, "/registries")
+		Expect(requests).To(HaveLen(1))
+
+		registry := &harborapi.Registry{}
+		Expect(json.Unmarshal(requests[0].Body, registry)).To(Succeed())
+		Expect(registry.Name).To(Equal("hub"))
+		Expect(registry.Credential).ToNot(BeNil())
+		Expect(registry.Credential.AccessKey).To(Equal("user"))
+		Expect(registry.Credential.AccessSecret).To(Equal("password"))
+
+		current := getEndpoint()
+		Expect(current.Status.RegistryID).To(Equal(int64(7)))
+		Expect(current.Status.CredentialsHash).ToNot(BeEmpty())
+		Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.ReadyConditionType)).To(BeTrue())
+	})
+
+	Context("With an existing registry", func() {
+		BeforeEach(func() {
+			server.HandleJSON(http.MethodGet, "/registries/7", http.StatusOK, currentRegistry())
+
+			endpoint.Status.RegistryID = 7
+			endpoint.Status.CredentialsHash = HashCredential(harborapi.NewBasicCredential("user", "password"))
+		})
+
+		It("Should not update a registry in sync", func() {
+			setup(endpoint, credentials)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPut, "/registries/7")).To(BeEmpty())
+			Expect(server.Requests(http.MethodPost, "/registries")).To(BeEmpty())
+		})
+
+		It("Should update the registry when the secret changes", func() {
+			credentials.Data[goharborv1alpha1.RegistryAccessSecretKey] = []byte("rotated")
+			setup(endpoint, credentials)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+
+			requests := server.Requests(http.MethodPut, "/registries/7")
+			Expect(requests).To(HaveLen(1))
+
+			update := &harborapi.RegistryUpdate{}
+			Expect(json.Unmarshal(requests[0].Body, update)).To(Succeed())
+			Expect(update.AccessSecret).To(Equal("rotated"))
+
+			Expect(getEndpoint().Status.CredentialsHash).To(Equal(HashCredential(harborapi.NewBasicCredential("user", "rotated"))))
+		})
+
+		It("Should revert changes made through the portal", func() {
+			registry := currentRegistry()
+			registry.URL = "https://other.example.com"
+			server.HandleJSON(http.MethodGet, "/registries/7", http.StatusOK, registry)
+			setup(endpoint, credentials)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPut, "/registries/7")).To(HaveLen(1))
+		})
+
+		It("Should recreate a registry deleted through the portal", func() {
+			server.HandleJSON(http.MethodGet, "/registries/7", http.StatusNotFound, nil)
+			setup(endpoint, credentials)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPost, "/registries")).To(HaveLen(1))
+			Expect(getEndpoint().Status.RegistryID).To(Equal(int64(7)))
+		})
+
+		It("Should report a type change", func() {
+			endpoint.Spec.Type = "harbor"
+			setup(endpoint, credentials)
+
+			_, err := r.Reconcile(req)
+			Expect(err).To(HaveOccurred())
+			Expect(server.Requests(http.MethodPut, "/registries/7")).To(BeEmpty())
+
+			condition := conditions.Get(getEndpoint().Status.Conditions, goharborv1alpha1.ReadyConditionType)
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("the type cannot be changed"))
+		})
+	})
+
+	It("Should report a missing credentials secret", func() {
+		setup(endpoint)
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(HaveOccurred())
+		Expect(server.Requests(http.MethodPost, "/registries")).To(BeEmpty())
+
+		condition := conditions.Get(getEndpoint().Status.Conditions, goharborv1alpha1.ReadyConditionType)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+	})
+
+	Context("Deletion", func() {
+		BeforeEach(func() {
+			now := metav1.Now()
+			endpoint.SetDeletionTimestamp(&now)
+			endpoint.Status.RegistryID = 7
+		})
+
+		It("Should delete the registry then remove the finalizer", func() {
+			setup(endpoint)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodDelete, "/registries/7")).To(HaveLen(1))
+			Expect(getEndpoint().GetFinalizers()).To(BeEmpty())
+		})
+
+		It("Should remove the finalizer when the registry is already deleted", func() {
+			server.HandleJSON(http.MethodDelete, "/registries/7", http.StatusNotFound, nil)
+			setup(endpoint)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getEndpoint().GetFinalizers()).To(BeEmpty())
+		})
+
+		It("Should keep the finalizer when the deletion fails", func() {
+			server.HandleJSON(http.MethodDelete, "/registries/7", http.StatusInternalServerError, nil)
+			setup(endpoint)
+
+			_, err := r.Reconcile(req)
+			Expect(err).To(HaveOccurred())
+			Expect(getEndpoint().GetFinalizers()).To(ConsistOf(goharborv1alpha1.HarborFinalizer))
+		})
+
+		It("Should remove the finalizer when the Harbor does not exist", func() {
+			r, ctx = setupTest(context.TODO(), endpoint)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodDelete, "/registries/7")).To(BeEmpty())
+			Expect(getEndpoint().GetFinalizers()).To(BeEmpty())
+		})
+	})
+})
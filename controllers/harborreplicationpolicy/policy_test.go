@@ -0,0 +1,112 @@
+package harborreplicationpolicy
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+)
+
+var _ = Describe("HarborReplicationPolicy", func() {
+	var policy *goharborv1alpha1.HarborReplicationPolicy
+
+	BeforeEach(func() {
+		policy = &goharborv1alpha1.HarborReplicationPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "docker-hub-library",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborReplicationPolicySpec{
+				HarborName:           "harbor",
+				RegistryEndpoint:     "docker-hub",
+				Direction:            goharborv1alpha1.ReplicationPull,
+				DestinationNamespace: "library",
+				Filters: goharborv1alpha1.ReplicationFilters{
+					Name:   "library/**",
+					Labels: []string{"approved"},
+				},
+				Trigger: goharborv1alpha1.ReplicationTrigger{
+					Type: goharborv1alpha1.ReplicationTriggerScheduled,
+					Cron: "0 0 2 * * *",
+				},
+			},
+		}
+	})
+
+	Context("Policy", func() {
+		It("Should pull from the registry", func() {
+			desired := GetPolicy(policy, 3)
+			Expect(desired.Name).To(Equal("docker-hub-library"))
+			Expect(desired.SourceRegistry).To(Equal(&harborapi.RegistryReference{ID: 3}))
+			Expect(desired.DestinationRegistry).To(BeNil())
+			Expect(desired.Enabled).To(BeTrue())
+			Expect(desired.Trigger.Type).To(Equal(harborapi.TriggerScheduled))
+			Expect(desired.Trigger.Settings.Cron).To(Equal("0 0 2 * * *"))
+			Expect(desired.Filters).To(ConsistOf(
+				harborapi.ReplicationFilter{Type: harborapi.FilterName, Value: "library/**"},
+				harborapi.ReplicationFilter{Type: harborapi.FilterLabel, Value: []string{"approved"}},
+			))
+		})
+
+		It("Should push to the registry", func() {
+			policy.Spec.Direction = goharborv1alpha1.ReplicationPush
+			policy.Spec.Trigger = goharborv1alpha1.ReplicationTrigger{}
+
+			desired := GetPolicy(policy, 3)
+			Expect(desired.SourceRegistry).To(BeNil())
+			Expect(desired.DestinationRegistry).To(Equal(&harborapi.RegistryReference{ID: 3}))
+			Expect(desired.Trigger.Type).To(Equal(harborapi.TriggerManual))
+			Expect(desired.Trigger.Settings).To(BeNil())
+		})
+	})
+
+	Context("Drift", func() {
+		var current *harborapi.ReplicationPolicy
+
+		BeforeEach(func() {
+			current = &harborapi.ReplicationPolicy{}
+
+			// As returned by Harbor, with the local registry and filters in another order
+			Expect(json.Unmarshal([]byte(`{
+				"id": 5,
+				"name": "docker-hub-library",
+				"description": "",
+				"src_registry": {"id": 3, "name": "docker-hub"},
+				"dest_registry": {"id": 0, "name": "Local"},
+				"dest_namespace": "library",
+				"trigger": {"type": "scheduled", "trigger_settings": {"cron": "0 0 2 * * *"}},
+				"filters": [{"type": "label", "value": ["approved"]}, {"type": "name", "value": "library/**"}],
+				"deletion": false,
+				"override": false,
+				"enabled": true
+			}`), current)).To(Succeed())
+		})
+
+		It("Should be in sync", func() {
+			inSync, err := IsInSync(GetPolicy(policy, 3), current)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inSync).To(BeTrue())
+		})
+
+		It("Should detect changes made in Harbor", func() {
+			current.Enabled = false
+
+			inSync, err := IsInSync(GetPolicy(policy, 3), current)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inSync).To(BeFalse())
+		})
+
+		It("Should detect filter changes", func() {
+			policy.Spec.Filters.Labels = append(policy.Spec.Filters.Labels, "signed")
+
+			inSync, err := IsInSync(GetPolicy(policy, 3), current)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inSync).To(BeFalse())
+		})
+	})
+})
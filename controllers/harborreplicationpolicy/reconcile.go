@@ -0,0 +1,391 @@
+package harborreplicationpolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+const (
+	EventReasonPolicyCreated      = "PolicyCreated"
+	EventReasonPolicyUpdated      = "PolicyUpdated"
+	EventReasonPolicyDeleted      = "PolicyDeleted"
+	EventReasonExecutionFailed    = "ExecutionFailed"
+	EventReasonExecutionSucceeded = "ExecutionSucceeded"
+)
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborreplicationpolicies,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborreplicationpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborregistryendpoints,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="events",verbs=create;patch
+
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.TODO()
+	application.SetName(&ctx, r.GetName())
+	application.SetVersion(&ctx, r.GetVersion())
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "reconcile", opentracing.Tags{
+		"HarborReplicationPolicy.Namespace": req.Namespace,
+		"HarborReplicationPolicy.Name":      req.Name,
+	})
+	defer span.Finish()
+
+	reqLogger := r.Log.WithValues("Request", req.NamespacedName, "HarborReplicationPolicy.Namespace", req.Namespace, "HarborReplicationPolicy.Name", req.Name)
+
+	logger.Set(&ctx, reqLogger)
+
+	policy := &goharborv1alpha1.HarborReplicationPolicy{}
+
+	err := r.Client.Get(ctx, req.NamespacedName, policy)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			reqLogger.Info("HarborReplicationPolicy does not exists")
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if !policy.ObjectMeta.DeletionTimestamp.IsZero() {
+		reqLogger.Info("HarborReplicationPolicy is being deleted")
+		return reconcile.Result{}, r.Finalize(ctx, policy)
+	}
+
+	updated, err := reconciliation.UpdateFinalizer(ctx, r.Client, policy, policy.Spec.DeletionPolicy == goharborv1alpha1.DeletionPolicyDelete)
+	if err != nil || updated {
+		return reconcile.Result{}, err
+	}
+
+	// The last execution is updated at each resync too
+	result := reconcile.Result{
+		RequeueAfter: reconciliation.DefaultResyncPeriod,
+	}
+
+	syncErr := r.Sync(ctx, policy)
+	if syncErr != nil {
+		err = r.UpdateCondition(ctx, policy, goharborv1alpha1.ReadyConditionType, corev1.ConditionFalse, "sync-failed", syncErr.Error())
+	} else {
+		err = r.UpdateCondition(ctx, policy, goharborv1alpha1.ReadyConditionType, corev1.ConditionTrue, "synced", fmt.Sprintf("policy %d is in sync", policy.Status.PolicyID))
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	policy.Status.ObservedGeneration = policy.GetGeneration()
+
+	err = reconciliation.UpdateStatus(ctx, r.Client, &result, policy)
+	if err != nil {
+		return result, err
+	}
+
+	return result, errors.Wrap(syncErr, "cannot sync replication policy")
+}
+
+// Finalize deletes the policy from Harbor then removes the finalizer.
+func (r *Reconciler) Finalize(ctx context.Context, policy *goharborv1alpha1.HarborReplicationPolicy) error {
+	if !reconciliation.HasFinalizer(policy) {
+		return nil
+	}
+
+	if policy.Status.PolicyID != 0 {
+		api, err := r.getClient(ctx, policy)
+		if err != nil {
+			return err
+		}
+
+		// Without Harbor, there is nothing to delete
+		if api != nil {
+			err = api.DeleteReplicationPolicy(ctx, policy.Status.PolicyID)
+			if err != nil && !harborapi.IsNotFound(err) {
+				return errors.Wrapf(err, "cannot delete policy %d", policy.Status.PolicyID)
+			}
+
+			r.Recorder.Eventf(policy, corev1.EventTypeNormal, EventReasonPolicyDeleted, "policy %d deleted", policy.Status.PolicyID)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(policy, goharborv1alpha1.HarborFinalizer)
+
+	err := r.Client.Update(ctx, policy)
+
+	return errors.Wrap(err, "cannot remove finalizer")
+}
+
+// getClient returns a client for the Harbor, nil if the Harbor does not exist.
+func (r *Reconciler) getClient(ctx context.Context, policy *goharborv1alpha1.HarborReplicationPolicy) (*harborapi.Client, error) {
+	api, _, err := harborapi.NewFromName(ctx, r.Client, policy.GetNamespace(), policy.Spec.HarborName, harborapi.UserAgent(r.GetName(), r.GetVersion()))
+
+	return api, err
+}
+
+// getRegistryID returns the ID in Harbor of the registry endpoint of the policy.
+func (r *Reconciler) getRegistryID(ctx context.Context, policy *goharborv1alpha1.HarborReplicationPolicy) (int64, error) {
+	endpoint := &goharborv1alpha1.HarborRegistryEndpoint{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: policy.GetNamespace(), Name: policy.Spec.RegistryEndpoint}, endpoint)
+	if err != nil {
+		return 0, errors.Wrapf(err, "cannot get registry endpoint %s", policy.Spec.RegistryEndpoint)
+	}
+
+	if endpoint.Spec.HarborName != policy.Spec.HarborName {
+		return 0, errors.Errorf("registry endpoint %s belongs to harbor %s", endpoint.GetName(), endpoint.Spec.HarborName)
+	}
+
+	if endpoint.Status.RegistryID == 0 {
+		return 0, errors.Errorf("registry endpoint %s is not created yet", endpoint.GetName())
+	}
+
+	return endpoint.Status.RegistryID, nil
+}
+
+var triggerTypes = map[goharborv1alpha1.ReplicationTriggerType]string{
+	goharborv1alpha1.ReplicationTriggerManual:     harborapi.TriggerManual,
+	goharborv1alpha1.ReplicationTriggerScheduled:  harborapi.TriggerScheduled,
+	goharborv1alpha1.ReplicationTriggerEventBased: harborapi.TriggerEventBased,
+}
+
+// GetPolicy returns the policy matching the spec.
+func GetPolicy(policy *goharborv1alpha1.HarborReplicationPolicy, registryID int64) *harborapi.ReplicationPolicy {
+	result := &harborapi.ReplicationPolicy{
+		Name:          policy.GetPolicyName(),
+		Description:   policy.Spec.Description,
+		DestinationNS: policy.Spec.DestinationNamespace,
+		Trigger: &harborapi.ReplicationTrigger{
+			Type: harborapi.TriggerManual,
+		},
+		Filters:  []harborapi.ReplicationFilter{},
+		Deletion: policy.Spec.Deletion,
+		Override: policy.Spec.Override,
+		Enabled:  !policy.Spec.Disabled,
+	}
+
+	registry := &harborapi.RegistryReference{ID: registryID}
+
+	if policy.Spec.Direction == goharborv1alpha1.ReplicationPull {
+		result.SourceRegistry = registry
+	} else {
+		result.DestinationRegistry = registry
+	}
+
+	if triggerType, ok := triggerTypes[policy.Spec.Trigger.Type]; ok {
+		result.Trigger.Type = triggerType
+	}
+
+	if policy.Spec.Trigger.Type == goharborv1alpha1.ReplicationTriggerScheduled {
+		result.Trigger.Settings = &harborapi.ReplicationTriggerSettings{
+			Cron: policy.Spec.Trigger.Cron,
+		}
+	}
+
+	filters := policy.Spec.Filters
+
+	if filters.Name != "" {
+		result.Filters = append(result.Filters, harborapi.ReplicationFilter{Type: harborapi.FilterName, Value: filters.Name})
+	}
+
+	if filters.Tag != "" {
+		result.Filters = append(result.Filters, harborapi.ReplicationFilter{Type: harborapi.FilterTag, Value: filters.Tag})
+	}
+
+	if len(filters.Labels) > 0 {
+		result.Filters = append(result.Filters, harborapi.ReplicationFilter{Type: harborapi.FilterLabel, Value: filters.Labels})
+	}
+
+	if filters.Resource != "" {
+		result.Filters = append(result.Filters, harborapi.ReplicationFilter{Type: harborapi.FilterResource, Value: filters.Resource})
+	}
+
+	return result
+}
+
+// normalize returns the policy as returned by Harbor, so it can be compared.
+func normalize(policy harborapi.ReplicationPolicy) ([]byte, error) {
+	policy.ID = 0
+
+	// The local Harbor is returned with ID 0
+	if policy.SourceRegistry != nil && policy.SourceRegistry.ID == 0 {
+		policy.SourceRegistry = nil
+	}
+
+	if policy.DestinationRegistry != nil && policy.DestinationRegistry.ID == 0 {
+		policy.DestinationRegistry = nil
+	}
+
+	if policy.Trigger != nil && policy.Trigger.Settings != nil && policy.Trigger.Settings.Cron == "" {
+		trigger := *policy.Trigger
+		trigger.Settings = nil
+		policy.Trigger = &trigger
+	}
+
+	filters := append([]harborapi.ReplicationFilter{}, policy.Filters...)
+	sort.Slice(filters, func(i, j int) bool {
+		return filters[i].Type < filters[j].Type
+	})
+	policy.Filters = filters
+
+	return json.Marshal(&policy)
+}
+
+// IsInSync returns whether the current policy matches the desired one.
+func IsInSync(desired, current *harborapi.ReplicationPolicy) (bool, error) {
+	desiredJSON, err := normalize(*desired)
+	if err != nil {
+		return false, errors.Wrap(err, "cannot encode desired policy")
+	}
+
+	currentJSON, err := normalize(*current)
+	if err != nil {
+		return false, errors.Wrap(err, "cannot encode current policy")
+	}
+
+	return bytes.Equal(desiredJSON, currentJSON), nil
+}
+
+// Sync creates the policy in Harbor or updates it, then updates the status with the last execution.
+func (r *Reconciler) Sync(ctx context.Context, policy *goharborv1alpha1.HarborReplicationPolicy) error {
+	api, err := r.getClient(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	if api == nil {
+		return errors.Errorf("harbor %s not found", policy.Spec.HarborName)
+	}
+
+	registryID, err := r.getRegistryID(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	desired := GetPolicy(policy, registryID)
+
+	current, err := r.getPolicy(ctx, api, policy)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		id, err := api.CreateReplicationPolicy(ctx, desired)
+		if err != nil {
+			return errors.Wrap(err, "cannot create policy")
+		}
+
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, EventReasonPolicyCreated, "policy %d created", id)
+
+		policy.Status.PolicyID = id
+
+		return nil
+	}
+
+	policy.Status.PolicyID = current.ID
+
+	inSync, err := IsInSync(desired, current)
+	if err != nil {
+		return err
+	}
+
+	if !inSync {
+		err = api.UpdateReplicationPolicy(ctx, current.ID, desired)
+		if err != nil {
+			return errors.Wrapf(err, "cannot update policy %d", current.ID)
+		}
+
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, EventReasonPolicyUpdated, "policy %d updated", current.ID)
+	}
+
+	return r.updateLastExecution(ctx, api, policy)
+}
+
+// getPolicy returns the policy in Harbor, nil if it does not exist yet.
+func (r *Reconciler) getPolicy(ctx context.Context, api *harborapi.Client, policy *goharborv1alpha1.HarborReplicationPolicy) (*harborapi.ReplicationPolicy, error) {
+	if policy.Status.PolicyID != 0 {
+		current, err := api.GetReplicationPolicy(ctx, policy.Status.PolicyID)
+		if err == nil {
+			return current, nil
+		}
+
+		if !harborapi.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "cannot get policy %d", policy.Status.PolicyID)
+		}
+
+		// The policy has been deleted from the portal
+		policy.Status.PolicyID = 0
+	}
+
+	current, err := api.GetReplicationPolicyByName(ctx, policy.GetPolicyName())
+
+	return current, errors.Wrapf(err, "cannot get policy %s", policy.GetPolicyName())
+}
+
+func (r *Reconciler) updateLastExecution(ctx context.Context, api *harborapi.Client, policy *goharborv1alpha1.HarborReplicationPolicy) error {
+	execution, err := api.GetLastReplicationExecution(ctx, policy.Status.PolicyID)
+	if err != nil {
+		return errors.Wrap(err, "cannot get last execution")
+	}
+
+	if execution == nil {
+		policy.Status.LastExecution = nil
+		return nil
+	}
+
+	previous := policy.Status.LastExecution
+
+	policy.Status.LastExecution = &goharborv1alpha1.ReplicationExecution{
+		ID:         execution.ID,
+		Status:     execution.Status,
+		StatusText: execution.StatusText,
+		Trigger:    execution.Trigger,
+		StartTime:  execution.StartTime,
+		EndTime:    execution.EndTime,
+		Total:      execution.Total,
+		Succeeded:  execution.Succeed,
+		Failed:     execution.Failed,
+		InProgress: execution.InProgress,
+		Stopped:    execution.Stopped,
+	}
+
+	if previous != nil && previous.ID == execution.ID && previous.Status == execution.Status {
+		return nil
+	}
+
+	switch execution.Status {
+	case harborapi.ExecutionSucceeded:
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, EventReasonExecutionSucceeded, "execution %d succeeded: %d/%d", execution.ID, execution.Succeed, execution.Total)
+	case harborapi.ExecutionFailed:
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, EventReasonExecutionFailed, "execution %d failed: %d/%d: %s", execution.ID, execution.Failed, execution.Total, execution.StatusText)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) UpdateCondition(ctx context.Context, policy *goharborv1alpha1.HarborReplicationPolicy, conditionType goharborv1alpha1.HarborConditionType, status corev1.ConditionStatus, reasons ...string) error {
+	updated, _, err := conditions.Update(policy.Status.Conditions, conditionType, status, reasons...)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update condition %s", conditionType)
+	}
+
+	policy.Status.Conditions = updated
+
+	return nil
+}
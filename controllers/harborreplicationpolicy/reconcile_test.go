@@ -0,0 +1,258 @@
+package harborreplicationpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/harborapi/harborapitest"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+var _ = Describe("Reconcile", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var server *harborapitest.Server
+	var harbor *goharborv1alpha1.Harbor
+	var endpoint *goharborv1alpha1.HarborRegistryEndpoint
+	var policy *goharborv1alpha1.HarborReplicationPolicy
+	var req ctrl.Request
+
+	BeforeEach(func() {
+		server = harborapitest.NewServer()
+		server.HandleJSON(http.MethodGet, "/replication/policies", http.StatusOK, []harborapi.ReplicationPolicy{})
+		server.Handle(http.MethodPost, "/replication/policies", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Location", "/api/replication/policies/4")
+			w.WriteHeader(http.StatusCreated)
+		})
+		server.HandleJSON(http.MethodPut, "/replication/policies/4", http.StatusOK, nil)
+		server.HandleJSON(http.MethodDelete, "/replication/policies/4", http.StatusOK, nil)
+		server.HandleJSON(http.MethodGet, "/replication/executions", http.StatusOK, []harborapi.ReplicationExecution{})
+
+		harbor = harborapitest.NewHarbor("ns")
+
+		endpoint = &goharborv1alpha1.HarborRegistryEndpoint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hub",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborRegistryEndpointSpec{
+				HarborName: "harbor",
+				Type:       "docker-hub",
+				URL:        "https://hub.docker.com",
+			},
+			Status: goharborv1alpha1.HarborRegistryEndpointStatus{
+				RegistryID: 7,
+			},
+		}
+
+		policy = &goharborv1alpha1.HarborReplicationPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "mirror",
+				Namespace:  "ns",
+				Finalizers: []string{goharborv1alpha1.HarborFinalizer},
+			},
+			Spec: goharborv1alpha1.HarborReplicationPolicySpec{
+				HarborName:       "harbor",
+				RegistryEndpoint: "hub",
+				Direction:        goharborv1alpha1.ReplicationPull,
+				Filters: goharborv1alpha1.ReplicationFilters{
+					Name: "library/**",
+				},
+				Trigger: goharborv1alpha1.ReplicationTrigger{
+					Type: goharborv1alpha1.ReplicationTriggerScheduled,
+					Cron: "0 0 * * * *",
+				},
+				DeletionPolicy: goharborv1alpha1.DeletionPolicyDelete,
+			},
+		}
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "mirror"}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	setup := func(objects ...runtime.Object) {
+		r, ctx = setupTest(context.TODO(), append(objects, harbor, harborapitest.NewAdminPasswordSecret(harbor))...)
+	}
+
+	getPolicy := func() *goharborv1alpha1.HarborReplicationPolicy {
+		current := &goharborv1alpha1.HarborReplicationPolicy{}
+		Expect(r.Client.Get(ctx, req.NamespacedName, current)).To(Succeed())
+
+		return current
+	}
+
+	It("Should add the finalizer first", func() {
+		policy.SetFinalizers(nil)
+		setup(policy, endpoint)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getPolicy().GetFinalizers()).To(ConsistOf(goharborv1alpha1.HarborFinalizer))
+		Expect(server.Requests(http.MethodPost, "/replication/policies")).To(BeEmpty())
+	})
+
+	It("Should create the policy pulling from the registry endpoint", func() {
+		setup(policy, endpoint)
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(reconciliation.DefaultResyncPeriod))
+
+		requests := server.Requests(http.MethodPost, "/replication/policies")
+		Expect(requests).To(HaveLen(1))
+
+		created := &harborapi.ReplicationPolicy{}
+		Expect(json.Unmarshal(requests[0].Body, created)).To(Succeed())
+		Expect(created.Name).To(Equal("mirror"))
+		Expect(created.SourceRegistry).ToNot(BeNil())
+		Expect(created.SourceRegistry.ID).To(Equal(int64(7)))
+		Expect(created.DestinationRegistry).To(BeNil())
+		Expect(created.Trigger.Type).To(Equal(harborapi.TriggerScheduled))
+		Expect(created.Trigger.Settings.Cron).To(Equal("0 0 * * * *"))
+
+		current := getPolicy()
+		Expect(current.Status.PolicyID).To(Equal(int64(4)))
+		Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.ReadyConditionType)).To(BeTrue())
+	})
+
+	It("Should wait for the registry endpoint to be created", func() {
+		endpoint.Status.RegistryID = 0
+		setup(policy, endpoint)
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(HaveOccurred())
+		Expect(server.Requests(http.MethodPost, "/replication/policies")).To(BeEmpty())
+
+		condition := conditions.Get(getPolicy().Status.Conditions, goharborv1alpha1.ReadyConditionType)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("registry endpoint hub is not created yet"))
+	})
+
+	It("Should reject a registry endpoint of another harbor", func() {
+		endpoint.Spec.HarborName = "other"
+		setup(policy, endpoint)
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(HaveOccurred())
+		Expect(server.Requests(http.MethodPost, "/replication/policies")).To(BeEmpty())
+	})
+
+	Context("With an existing policy", func() {
+		BeforeEach(func() {
+			existing := GetPolicy(policy, 7)
+			existing.ID = 4
+			server.HandleJSON(http.MethodGet, "/replication/policies/4", http.StatusOK, existing)
+
+			policy.Status.PolicyID = 4
+		})
+
+		It("Should not update a policy in sync", func() {
+			setup(policy, endpoint)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPut, "/replication/policies/4")).To(BeEmpty())
+			Expect(server.Requests(http.MethodPost, "/replication/policies")).To(BeEmpty())
+		})
+
+		It("Should update the policy when the spec changes", func() {
+			policy.Spec.Filters.Tag = "v*"
+			setup(policy, endpoint)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPut, "/replication/policies/4")).To(HaveLen(1))
+			Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(EventReasonPolicyUpdated)))
+		})
+
+		It("Should recreate a policy deleted through the portal", func() {
+			server.HandleJSON(http.MethodGet, "/replication/policies/4", http.StatusNotFound, nil)
+			setup(policy, endpoint)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPost, "/replication/policies")).To(HaveLen(1))
+			Expect(getPolicy().Status.PolicyID).To(Equal(int64(4)))
+		})
+
+		It("Should report the last execution once", func() {
+			server.HandleJSON(http.MethodGet, "/replication/executions", http.StatusOK, []harborapi.ReplicationExecution{
+				{ID: 1, PolicyID: 4, Status: harborapi.ExecutionSucceeded, Total: 2, Succeed: 2},
+				{ID: 2, PolicyID: 4, Status: harborapi.ExecutionFailed, StatusText: "unauthorized", Total: 2, Failed: 1, Succeed: 1},
+			})
+			setup(policy, endpoint)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+
+			requests := server.Requests(http.MethodGet, "/replication/executions")
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Query).To(ContainSubstring("policy_id=4"))
+
+			execution := getPolicy().Status.LastExecution
+			Expect(execution).ToNot(BeNil())
+			Expect(execution.ID).To(Equal(int64(2)))
+			Expect(execution.Status).To(Equal(harborapi.ExecutionFailed))
+			Expect(execution.Failed).To(Equal(int64(1)))
+
+			events := r.Recorder.(*record.FakeRecorder).Events
+			Expect(events).To(Receive(ContainSubstring(EventReasonExecutionFailed)))
+
+			_, err = r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(events).ToNot(Receive())
+		})
+	})
+
+	Context("Deletion", func() {
+		BeforeEach(func() {
+			now := metav1.Now()
+			policy.SetDeletionTimestamp(&now)
+			policy.Status.PolicyID = 4
+		})
+
+		It("Should delete the policy then remove the finalizer", func() {
+			setup(policy)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodDelete, "/replication/policies/4")).To(HaveLen(1))
+			Expect(getPolicy().GetFinalizers()).To(BeEmpty())
+		})
+
+		It("Should keep the finalizer when the deletion fails", func() {
+			server.HandleJSON(http.MethodDelete, "/replication/policies/4", http.StatusInternalServerError, nil)
+			setup(policy)
+
+			_, err := r.Reconcile(req)
+			Expect(err).To(HaveOccurred())
+			Expect(getPolicy().GetFinalizers()).To(ConsistOf(goharborv1alpha1.HarborFinalizer))
+		})
+
+		It("Should remove the finalizer when the Harbor does not exist", func() {
+			r, ctx = setupTest(context.TODO(), policy)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodDelete, "/replication/policies/4")).To(BeEmpty())
+			Expect(getPolicy().GetFinalizers()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,305 @@
+package harborrestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/backup"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborrestores,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborrestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborbackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="batch",resources="jobs",verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources="pods",verbs=list;delete;deletecollection
+// +kubebuilder:rbac:groups="",resources="events",verbs=create;patch
+
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.TODO()
+	application.SetName(&ctx, r.GetName())
+	application.SetVersion(&ctx, r.GetVersion())
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "reconcile", opentracing.Tags{
+		"HarborRestore.Namespace": req.Namespace,
+		"HarborRestore.Name":      req.Name,
+	})
+	defer span.Finish()
+
+	reqLogger := r.Log.WithValues("Request", req.NamespacedName, "HarborRestore.Namespace", req.Namespace, "HarborRestore.Name", req.Name)
+
+	logger.Set(&ctx, reqLogger)
+
+	harborRestore := &goharborv1alpha1.HarborRestore{}
+
+	err := r.Client.Get(ctx, req.NamespacedName, harborRestore)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			reqLogger.Info("HarborRestore does not exists")
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if !harborRestore.ObjectMeta.DeletionTimestamp.IsZero() {
+		reqLogger.Info("HarborRestore is being deleted")
+		return reconcile.Result{}, nil
+	}
+
+	if conditions.IsTrue(harborRestore.Status.Conditions, goharborv1alpha1.CompletedConditionType) ||
+		conditions.IsTrue(harborRestore.Status.Conditions, goharborv1alpha1.FailedConditionType) {
+		return reconcile.Result{}, nil
+	}
+
+	result := reconcile.Result{}
+
+	err = r.RunRestore(ctx, &result, harborRestore)
+	if err != nil {
+		return result, errors.Wrap(err, "cannot run restoration")
+	}
+
+	return result, reconciliation.UpdateStatus(ctx, r.Client, &result, harborRestore)
+}
+
+func (r *Reconciler) RunRestore(ctx context.Context, result *ctrl.Result, harborRestore *goharborv1alpha1.HarborRestore) error {
+	harborBackup := &goharborv1alpha1.HarborBackup{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: harborRestore.GetNamespace(), Name: harborRestore.Spec.BackupName}, harborBackup)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return r.Fail(ctx, harborRestore, "backup-not-found", fmt.Sprintf("backup %s not found", harborRestore.Spec.BackupName))
+		}
+
+		return errors.Wrap(err, "cannot get backup")
+	}
+
+	switch {
+	case conditions.IsTrue(harborBackup.Status.Conditions, goharborv1alpha1.FailedConditionType):
+		return r.Fail(ctx, harborRestore, "backup-failed", fmt.Sprintf("backup %s failed", harborBackup.GetName()))
+	case !conditions.IsTrue(harborBackup.Status.Conditions, goharborv1alpha1.CompletedConditionType):
+		result.RequeueAfter = DefaultRequeueWait
+
+		return r.UpdateCondition(ctx, harborRestore, goharborv1alpha1.CompletedConditionType, corev1.ConditionFalse, "waiting-backup", fmt.Sprintf("backup %s is not completed yet", harborBackup.GetName()))
+	}
+
+	harbor := &goharborv1alpha1.Harbor{}
+
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: harborRestore.GetNamespace(), Name: harborRestore.Spec.HarborName}, harbor)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return r.Fail(ctx, harborRestore, "harbor-not-found", fmt.Sprintf("harbor %s not found", harborRestore.Spec.HarborName))
+		}
+
+		return errors.Wrap(err, "cannot get harbor")
+	}
+
+	if harborRestore.Status.StartTime == nil {
+		now := metav1.Now()
+		readOnly := harbor.Spec.ReadOnly
+
+		harborRestore.Status.StartTime = &now
+		harborRestore.Status.HarborReadOnly = &readOnly
+
+		result.Requeue = true
+
+		return r.UpdateCondition(ctx, harborRestore, goharborv1alpha1.CompletedConditionType, corev1.ConditionFalse, "started", "switching harbor to read-only")
+	}
+
+	err = backup.SetHarborReadOnly(ctx, r.Client, harbor, true)
+	if err != nil {
+		return errors.Wrap(err, "cannot switch harbor to read-only")
+	}
+
+	rolledOut, err := backup.IsHarborRolledOut(ctx, r.Client, harbor)
+	if err != nil {
+		return errors.Wrap(err, "cannot check harbor rollout")
+	}
+
+	if !rolledOut {
+		// Jobs would race with writes until core runs in read-only mode
+		result.RequeueAfter = DefaultRequeueWait
+
+		return r.UpdateCondition(ctx, harborRestore, goharborv1alpha1.CompletedConditionType, corev1.ConditionFalse, "waiting-read-only", "waiting for harbor to roll out in read-only mode")
+	}
+
+	items := getItems(harbor, harborBackup)
+
+	var jobs []*batchv1.Job
+
+	for _, item := range items {
+		if item.Kind == backup.SecretItem {
+			err := r.RestoreSecretKey(ctx, harborBackup, harbor)
+			if err != nil {
+				return errors.Wrap(err, "cannot restore secret key")
+			}
+
+			continue
+		}
+
+		jobs = append(jobs, backup.GetJob(backup.RestoreDirection, harborRestore.GetName(), harborBackup, harbor, item))
+	}
+
+	progress, err := backup.RunJobs(ctx, r.Client, r.Scheme, harborRestore, jobs)
+	if err != nil {
+		return errors.Wrap(err, "cannot run jobs")
+	}
+
+	switch {
+	case progress.IsFailed():
+		return r.Fail(ctx, harborRestore, "job-failed", progress.FailureMessage())
+	case !progress.IsCompleted():
+		result.RequeueAfter = DefaultRequeueWait
+
+		return r.UpdateCondition(ctx, harborRestore, goharborv1alpha1.CompletedConditionType, corev1.ConditionFalse, "running", fmt.Sprintf("%d/%d jobs completed", progress.Completed, progress.Total))
+	}
+
+	// Restart core so restored data and secret key are loaded
+	err = r.Client.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(harbor.GetNamespace()), client.MatchingLabels{
+		"app":    goharborv1alpha1.CoreName,
+		"harbor": harbor.GetName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot restart core")
+	}
+
+	err = r.releaseHarbor(ctx, harborRestore)
+	if err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	harborRestore.Status.CompletionTime = &now
+
+	return r.UpdateCondition(ctx, harborRestore, goharborv1alpha1.CompletedConditionType, corev1.ConditionTrue, "completed", fmt.Sprintf("%d items restored", len(items)))
+}
+
+// getItems returns items of the backup which can be restored in the Harbor.
+func getItems(harbor *goharborv1alpha1.Harbor, harborBackup *goharborv1alpha1.HarborBackup) []backup.Item {
+	saved := map[string]bool{}
+	for _, name := range harborBackup.Status.Items {
+		saved[name] = true
+	}
+
+	var items []backup.Item
+
+	for _, item := range backup.Items(harbor, harborBackup.Spec.Images) {
+		if saved[item.Name] {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// RestoreSecretKey overrides the core secret key of the Harbor with the one saved by the backup.
+// The secret key is used to encrypt some database values, so it must match the restored database.
+func (r *Reconciler) RestoreSecretKey(ctx context.Context, harborBackup *goharborv1alpha1.HarborBackup, harbor *goharborv1alpha1.Harbor) error {
+	saved := &corev1.Secret{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: harborBackup.GetNamespace(), Name: backup.SecretKeySecretName(harborBackup)}, saved)
+	if err != nil {
+		return errors.Wrap(err, "cannot get saved secret key")
+	}
+
+	coreSecret, err := backup.GetCoreSecret(ctx, r.Client, harbor)
+	if err != nil {
+		return err
+	}
+
+	secretKey := saved.Data[backup.CoreSecretKey]
+	if bytes.Equal(coreSecret.Data[backup.CoreSecretKey], secretKey) {
+		return nil
+	}
+
+	patch := client.MergeFrom(coreSecret.DeepCopy())
+
+	if coreSecret.Data == nil {
+		coreSecret.Data = map[string][]byte{}
+	}
+
+	coreSecret.Data[backup.CoreSecretKey] = secretKey
+
+	err = r.Client.Patch(ctx, coreSecret, patch)
+
+	return errors.Wrap(err, "cannot update core secret")
+}
+
+// releaseHarbor switches the Harbor back to its original read-only state.
+func (r *Reconciler) releaseHarbor(ctx context.Context, harborRestore *goharborv1alpha1.HarborRestore) error {
+	if harborRestore.Status.HarborReadOnly == nil || *harborRestore.Status.HarborReadOnly {
+		return nil
+	}
+
+	harbor := &goharborv1alpha1.Harbor{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: harborRestore.GetNamespace(), Name: harborRestore.Spec.HarborName}, harbor)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+
+		return errors.Wrap(err, "cannot get harbor")
+	}
+
+	err = backup.SetHarborReadOnly(ctx, r.Client, harbor, false)
+
+	return errors.Wrap(err, "cannot switch harbor back to read-write")
+}
+
+func (r *Reconciler) Fail(ctx context.Context, harborRestore *goharborv1alpha1.HarborRestore, reason, message string) error {
+	logger.Get(ctx).Info("restoration failed", "Reason", reason, "Message", message)
+
+	err := r.releaseHarbor(ctx, harborRestore)
+	if err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	harborRestore.Status.CompletionTime = &now
+
+	err = r.UpdateCondition(ctx, harborRestore, goharborv1alpha1.CompletedConditionType, corev1.ConditionFalse, reason, message)
+	if err != nil {
+		return err
+	}
+
+	return r.UpdateCondition(ctx, harborRestore, goharborv1alpha1.FailedConditionType, corev1.ConditionTrue, reason, message)
+}
+
+func (r *Reconciler) UpdateCondition(ctx context.Context, harborRestore *goharborv1alpha1.HarborRestore, conditionType goharborv1alpha1.HarborConditionType, status corev1.ConditionStatus, reasons ...string) error {
+	updated, transition, err := conditions.Update(harborRestore.Status.Conditions, conditionType, status, reasons...)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update condition %s", conditionType)
+	}
+
+	harborRestore.Status.Conditions = updated
+
+	if transition && status == corev1.ConditionTrue {
+		eventType := corev1.EventTypeNormal
+		if conditionType == goharborv1alpha1.FailedConditionType {
+			eventType = corev1.EventTypeWarning
+		}
+
+		r.Recorder.Event(harborRestore, eventType, string(conditionType), conditions.Get(updated, conditionType).Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package harborrestore
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/backup"
+	"github.com/goharbor/harbor-operator/pkg/backup/backuptest"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+)
+
+var _ = Describe("Reconcile", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var harbor *goharborv1alpha1.Harbor
+	var harborBackup *goharborv1alpha1.HarborBackup
+	var harborRestore *goharborv1alpha1.HarborRestore
+	var savedSecretKey *corev1.Secret
+	var req ctrl.Request
+
+	BeforeEach(func() {
+		harbor = backuptest.NewHarbor("ns")
+
+		harborBackup = &goharborv1alpha1.HarborBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nightly",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborBackupSpec{
+				HarborName: "harbor",
+				Target: goharborv1alpha1.BackupTarget{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "backups"},
+				},
+			},
+			Status: goharborv1alpha1.HarborBackupStatus{
+				Conditions: []goharborv1alpha1.HarborCondition{
+					{Type: goharborv1alpha1.CompletedConditionType, Status: corev1.ConditionTrue},
+				},
+				Items: []string{backup.CoreDatabaseItem, backup.CoreSecretKeyItem},
+			},
+		}
+
+		savedSecretKey = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      backup.SecretKeySecretName(harborBackup),
+				Namespace: "ns",
+			},
+			Data: map[string][]byte{
+				backup.CoreSecretKey: []byte("saved"),
+			},
+		}
+
+		harborRestore = &goharborv1alpha1.HarborRestore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "restore",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborRestoreSpec{
+				HarborName: "harbor",
+				BackupName: "nightly",
+			},
+		}
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "restore"}}
+	})
+
+	getRestore := func() *goharborv1alpha1.HarborRestore {
+		current := &goharborv1alpha1.HarborRestore{}
+		Expect(r.Client.Get(ctx, req.NamespacedName, current)).To(Succeed())
+
+		return current
+	}
+
+	getHarbor := func() *goharborv1alpha1.Harbor {
+		current := &goharborv1alpha1.Harbor{}
+		Expect(r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "harbor"}, current)).To(Succeed())
+
+		return current
+	}
+
+	listJobs := func() []batchv1.Job {
+		jobs := &batchv1.JobList{}
+		Expect(r.Client.List(ctx, jobs)).To(Succeed())
+
+		return jobs.Items
+	}
+
+	startRestore := func() {
+		readOnly := false
+		now := metav1.Now()
+		harbor.Spec.ReadOnly = true
+		harborRestore.Status.StartTime = &now
+		harborRestore.Status.HarborReadOnly = &readOnly
+	}
+
+	It("Should wait for the backup to be completed", func() {
+		harborBackup.Status.Conditions = nil
+
+		r, ctx = setupTest(context.TODO(), harbor, harborBackup, harborRestore)
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(DefaultRequeueWait))
+		Expect(conditions.Get(getRestore().Status.Conditions, goharborv1alpha1.CompletedConditionType).Reason).To(Equal("waiting-backup"))
+		Expect(getHarbor().Spec.ReadOnly).To(BeFalse())
+	})
+
+	It("Should fail when the backup failed", func() {
+		harborBackup.Status.Conditions = []goharborv1alpha1.HarborCondition{
+			{Type: goharborv1alpha1.FailedConditionType, Status: corev1.ConditionTrue},
+		}
+
+		r, ctx = setupTest(context.TODO(), harbor, harborBackup, harborRestore)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conditions.Get(getRestore().Status.Conditions, goharborv1alpha1.FailedConditionType).Reason).To(Equal("backup-failed"))
+	})
+
+	It("Should wait for core to roll out in read-only mode before running jobs", func() {
+		r, ctx = setupTest(context.TODO(), harbor, harborBackup, harborRestore, backuptest.NewCoreDeployment(harbor, false))
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+
+		result, err = r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(DefaultRequeueWait))
+		Expect(getHarbor().Spec.ReadOnly).To(BeTrue())
+		Expect(conditions.Get(getRestore().Status.Conditions, goharborv1alpha1.CompletedConditionType).Reason).To(Equal("waiting-read-only"))
+		Expect(listJobs()).To(BeEmpty())
+	})
+
+	It("Should restore the secret key, run jobs and switch the Harbor back to read-write", func() {
+		startRestore()
+
+		r, ctx = setupTest(context.TODO(), harbor, harborBackup, harborRestore, savedSecretKey,
+			backuptest.NewCoreDeployment(harbor, true), backuptest.NewCoreSecret(harbor, "current"))
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(DefaultRequeueWait))
+
+		coreSecret := &corev1.Secret{}
+		Expect(r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: harbor.NormalizeComponentName(goharborv1alpha1.CoreName)}, coreSecret)).To(Succeed())
+		Expect(coreSecret.Data).To(HaveKeyWithValue(backup.CoreSecretKey, []byte("saved")))
+
+		jobs := listJobs()
+		Expect(jobs).To(HaveLen(1))
+		Expect(jobs[0].GetName()).To(Equal("restore-core-database"))
+
+		jobs[0].Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		Expect(r.Client.Status().Update(ctx, &jobs[0])).To(Succeed())
+
+		_, err = r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+
+		current := getRestore()
+		Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.CompletedConditionType)).To(BeTrue())
+		Expect(current.Status.CompletionTime).ToNot(BeNil())
+		Expect(getHarbor().Spec.ReadOnly).To(BeFalse())
+	})
+
+	It("Should fail and switch the Harbor back to read-write when a job fails", func() {
+		startRestore()
+
+		r, ctx = setupTest(context.TODO(), harbor, harborBackup, harborRestore, savedSecretKey,
+			backuptest.NewCoreDeployment(harbor, true), backuptest.NewCoreSecret(harbor, "current"))
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+
+		jobs := listJobs()
+		Expect(jobs).To(HaveLen(1))
+
+		jobs[0].Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "exit code 1"}}
+		Expect(r.Client.Status().Update(ctx, &jobs[0])).To(Succeed())
+
+		_, err = r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+
+		current := getRestore()
+		Expect(conditions.Get(current.Status.Conditions, goharborv1alpha1.FailedConditionType).Reason).To(Equal("job-failed"))
+		Expect(getHarbor().Spec.ReadOnly).To(BeFalse())
+	})
+})
@@ -0,0 +1,109 @@
+package harborretentionpolicy
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+)
+
+var _ = Describe("HarborRetentionPolicy", func() {
+	var policy *goharborv1alpha1.HarborRetentionPolicy
+
+	BeforeEach(func() {
+		policy = &goharborv1alpha1.HarborRetentionPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "library",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborRetentionPolicySpec{
+				HarborName: "harbor",
+				Project:    goharborv1alpha1.ProjectReference{Name: "library"},
+				Schedule:   "0 0 0 * * *",
+				Rules: []goharborv1alpha1.RetentionRule{{
+					Template:  goharborv1alpha1.RetentionLatestPushed,
+					Parameter: 10,
+				}, {
+					Template: goharborv1alpha1.RetentionAlways,
+					Tags: goharborv1alpha1.PatternSelector{
+						Pattern: "v*",
+					},
+					Repositories: goharborv1alpha1.PatternSelector{
+						Pattern: "tmp/**",
+						Exclude: true,
+					},
+				}},
+			},
+		}
+	})
+
+	Context("Policy", func() {
+		It("Should map rules", func() {
+			desired := GetPolicy(policy, 1)
+			Expect(desired.Scope).To(Equal(&harborapi.RetentionScope{Level: harborapi.RetentionScopeProject, Ref: 1}))
+			Expect(desired.Trigger.Settings).To(HaveKeyWithValue(harborapi.RetentionCronSettingsKey, "0 0 0 * * *"))
+			Expect(desired.Rules).To(HaveLen(2))
+
+			Expect(desired.Rules[0].Params).To(HaveKeyWithValue("latestPushedK", BeEquivalentTo(10)))
+			Expect(desired.Rules[0].TagSelectors).To(ConsistOf(harborapi.Selector{Kind: "doublestar", Decoration: "matches", Pattern: "**"}))
+
+			Expect(desired.Rules[1].Params).To(BeEmpty())
+			Expect(desired.Rules[1].TagSelectors).To(ConsistOf(harborapi.Selector{Kind: "doublestar", Decoration: "matches", Pattern: "v*"}))
+			Expect(desired.Rules[1].ScopeSelectors).To(HaveKeyWithValue("repository", ConsistOf(harborapi.Selector{Kind: "doublestar", Decoration: "repoExcludes", Pattern: "tmp/**"})))
+		})
+	})
+
+	Context("Drift", func() {
+		var current *harborapi.RetentionPolicy
+
+		BeforeEach(func() {
+			current = &harborapi.RetentionPolicy{}
+
+			// As returned by Harbor, with rule IDs and references
+			Expect(json.Unmarshal([]byte(`{
+				"id": 4,
+				"algorithm": "or",
+				"rules": [{
+					"id": 1, "priority": 1, "disabled": false, "action": "retain", "template": "latestPushedK",
+					"params": {"latestPushedK": 10},
+					"tag_selectors": [{"kind": "doublestar", "decoration": "matches", "pattern": "**"}],
+					"scope_selectors": {"repository": [{"kind": "doublestar", "decoration": "repoMatches", "pattern": "**"}]}
+				}, {
+					"id": 2, "priority": 1, "disabled": false, "action": "retain", "template": "always",
+					"params": {},
+					"tag_selectors": [{"kind": "doublestar", "decoration": "matches", "pattern": "v*"}],
+					"scope_selectors": {"repository": [{"kind": "doublestar", "decoration": "repoExcludes", "pattern": "tmp/**"}]}
+				}],
+				"trigger": {"kind": "Schedule", "settings": {"cron": "0 0 0 * * *"}, "references": {"job_id": 3}},
+				"scope": {"level": "project", "ref": 1}
+			}`), current)).To(Succeed())
+		})
+
+		It("Should be in sync", func() {
+			inSync, err := IsInSync(GetPolicy(policy, 1), current)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inSync).To(BeTrue())
+		})
+
+		It("Should detect rules edited in the portal", func() {
+			current.Rules[0].Params["latestPushedK"] = 5
+
+			inSync, err := IsInSync(GetPolicy(policy, 1), current)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inSync).To(BeFalse())
+		})
+
+		It("Should detect schedule changes", func() {
+			policy.Spec.Schedule = ""
+
+			inSync, err := IsInSync(GetPolicy(policy, 1), current)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inSync).To(BeFalse())
+		})
+	})
+})
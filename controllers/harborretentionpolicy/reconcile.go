@@ -0,0 +1,311 @@
+package harborretentionpolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+	"github.com/goharbor/harbor-operator/pkg/references"
+)
+
+const (
+	EventReasonPolicyCreated = "PolicyCreated"
+	EventReasonPolicyUpdated = "PolicyUpdated"
+	EventReasonPolicyCleared = "PolicyCleared"
+)
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborretentionpolicies,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborretentionpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborprojects,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="events",verbs=create;patch
+
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.TODO()
+	application.SetName(&ctx, r.GetName())
+	application.SetVersion(&ctx, r.GetVersion())
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "reconcile", opentracing.Tags{
+		"HarborRetentionPolicy.Namespace": req.Namespace,
+		"HarborRetentionPolicy.Name":      req.Name,
+	})
+	defer span.Finish()
+
+	reqLogger := r.Log.WithValues("Request", req.NamespacedName, "HarborRetentionPolicy.Namespace", req.Namespace, "HarborRetentionPolicy.Name", req.Name)
+
+	logger.Set(&ctx, reqLogger)
+
+	policy := &goharborv1alpha1.HarborRetentionPolicy{}
+
+	err := r.Client.Get(ctx, req.NamespacedName, policy)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			reqLogger.Info("HarborRetentionPolicy does not exists")
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if !policy.ObjectMeta.DeletionTimestamp.IsZero() {
+		reqLogger.Info("HarborRetentionPolicy is being deleted")
+		return reconcile.Result{}, r.Finalize(ctx, policy)
+	}
+
+	updated, err := reconciliation.UpdateFinalizer(ctx, r.Client, policy, policy.Spec.DeletionPolicy == goharborv1alpha1.DeletionPolicyDelete)
+	if err != nil || updated {
+		return reconcile.Result{}, err
+	}
+
+	result := reconcile.Result{
+		RequeueAfter: reconciliation.DefaultResyncPeriod,
+	}
+
+	syncErr := r.Sync(ctx, policy)
+	if syncErr != nil {
+		err = r.UpdateCondition(ctx, policy, goharborv1alpha1.ReadyConditionType, corev1.ConditionFalse, "sync-failed", syncErr.Error())
+	} else {
+		err = r.UpdateCondition(ctx, policy, goharborv1alpha1.ReadyConditionType, corev1.ConditionTrue, "synced", fmt.Sprintf("policy %d is in sync", policy.Status.PolicyID))
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	policy.Status.ObservedGeneration = policy.GetGeneration()
+
+	err = reconciliation.UpdateStatus(ctx, r.Client, &result, policy)
+	if err != nil {
+		return result, err
+	}
+
+	return result, errors.Wrap(syncErr, "cannot sync retention policy")
+}
+
+// Finalize clears the rules and the schedule of the policy then removes the finalizer.
+// Harbor does not delete retention policies, they are deleted with their project.
+func (r *Reconciler) Finalize(ctx context.Context, policy *goharborv1alpha1.HarborRetentionPolicy) error {
+	if !reconciliation.HasFinalizer(policy) {
+		return nil
+	}
+
+	if policy.Status.PolicyID != 0 {
+		api, err := r.getClient(ctx, policy)
+		if err != nil {
+			return err
+		}
+
+		// Without Harbor, there is nothing to clear
+		if api != nil {
+			empty := policy.DeepCopy()
+			empty.Spec.Rules = nil
+			empty.Spec.Schedule = ""
+
+			err = api.UpdateRetentionPolicy(ctx, policy.Status.PolicyID, GetPolicy(empty, policy.Status.ProjectID))
+			if err != nil && !harborapi.IsNotFound(err) {
+				return errors.Wrapf(err, "cannot clear policy %d", policy.Status.PolicyID)
+			}
+
+			r.Recorder.Eventf(policy, corev1.EventTypeNormal, EventReasonPolicyCleared, "policy %d cleared", policy.Status.PolicyID)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(policy, goharborv1alpha1.HarborFinalizer)
+
+	err := r.Client.Update(ctx, policy)
+
+	return errors.Wrap(err, "cannot remove finalizer")
+}
+
+// getClient returns a client for the Harbor, nil if the Harbor does not exist.
+func (r *Reconciler) getClient(ctx context.Context, policy *goharborv1alpha1.HarborRetentionPolicy) (*harborapi.Client, error) {
+	api, _, err := harborapi.NewFromName(ctx, r.Client, policy.GetNamespace(), policy.Spec.HarborName, harborapi.UserAgent(r.GetName(), r.GetVersion()))
+
+	return api, err
+}
+
+func getSelector(selector goharborv1alpha1.PatternSelector, matches, excludes string) []harborapi.Selector {
+	decoration := matches
+	if selector.Exclude {
+		decoration = excludes
+	}
+
+	return []harborapi.Selector{{
+		Kind:       harborapi.SelectorKindDoublestar,
+		Decoration: decoration,
+		Pattern:    selector.GetPattern(),
+	}}
+}
+
+// GetPolicy returns the retention policy of the project matching the spec.
+func GetPolicy(policy *goharborv1alpha1.HarborRetentionPolicy, projectID int64) *harborapi.RetentionPolicy {
+	rules := make([]harborapi.RetentionRule, len(policy.Spec.Rules))
+
+	for i, rule := range policy.Spec.Rules {
+		rules[i] = harborapi.RetentionRule{
+			Disabled:     rule.Disabled,
+			Action:       harborapi.RetentionActionRetain,
+			Template:     string(rule.Template),
+			TagSelectors: getSelector(rule.Tags, harborapi.SelectorMatches, harborapi.SelectorExcludes),
+			ScopeSelectors: map[string][]harborapi.Selector{
+				harborapi.ScopeSelectorRepository: getSelector(rule.Repositories, harborapi.SelectorRepoMatches, harborapi.SelectorRepoExcludes),
+			},
+		}
+
+		if rule.Template != goharborv1alpha1.RetentionAlways {
+			rules[i].Params = map[string]interface{}{
+				string(rule.Template): rule.Parameter,
+			}
+		}
+	}
+
+	return &harborapi.RetentionPolicy{
+		Algorithm: harborapi.RetentionAlgorithmOr,
+		Rules:     rules,
+		Trigger: &harborapi.RetentionTrigger{
+			Kind: harborapi.RetentionTriggerSchedule,
+			Settings: map[string]interface{}{
+				harborapi.RetentionCronSettingsKey: policy.Spec.Schedule,
+			},
+		},
+		Scope: &harborapi.RetentionScope{
+			Level: harborapi.RetentionScopeProject,
+			Ref:   projectID,
+		},
+	}
+}
+
+// normalize returns the policy without the fields set by Harbor, so it can be compared.
+func normalize(policy harborapi.RetentionPolicy) ([]byte, error) {
+	policy.ID = 0
+
+	rules := make([]harborapi.RetentionRule, len(policy.Rules))
+
+	for i, rule := range policy.Rules {
+		rule.ID = 0
+		rule.Priority = 0
+
+		if len(rule.Params) == 0 {
+			rule.Params = nil
+		}
+
+		rules[i] = rule
+	}
+
+	policy.Rules = rules
+
+	if policy.Trigger != nil {
+		cron, _ := policy.Trigger.Settings[harborapi.RetentionCronSettingsKey].(string)
+
+		policy.Trigger = &harborapi.RetentionTrigger{
+			Kind: policy.Trigger.Kind,
+			Settings: map[string]interface{}{
+				harborapi.RetentionCronSettingsKey: cron,
+			},
+		}
+	}
+
+	return json.Marshal(&policy)
+}
+
+// IsInSync returns whether the current policy matches the desired one.
+func IsInSync(desired, current *harborapi.RetentionPolicy) (bool, error) {
+	desiredJSON, err := normalize(*desired)
+	if err != nil {
+		return false, errors.Wrap(err, "cannot encode desired policy")
+	}
+
+	currentJSON, err := normalize(*current)
+	if err != nil {
+		return false, errors.Wrap(err, "cannot encode current policy")
+	}
+
+	return bytes.Equal(desiredJSON, currentJSON), nil
+}
+
+// Sync creates the retention policy of the project in Harbor or updates it, then updates the status.
+func (r *Reconciler) Sync(ctx context.Context, policy *goharborv1alpha1.HarborRetentionPolicy) error {
+	api, err := r.getClient(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	if api == nil {
+		return errors.Errorf("harbor %s not found", policy.Spec.HarborName)
+	}
+
+	project, err := references.GetProject(ctx, r.Client, api, policy.GetNamespace(), policy.Spec.HarborName, policy.Spec.Project)
+	if err != nil {
+		return err
+	}
+
+	policy.Status.ProjectID = project.ID
+
+	desired := GetPolicy(policy, project.ID)
+
+	id, err := project.GetRetentionID()
+	if err != nil {
+		return errors.Wrapf(err, "invalid retention policy of project %d", project.ID)
+	}
+
+	if id == 0 {
+		id, err = api.CreateRetentionPolicy(ctx, desired)
+		if err != nil {
+			return errors.Wrap(err, "cannot create policy")
+		}
+
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, EventReasonPolicyCreated, "policy %d created", id)
+
+		policy.Status.PolicyID = id
+
+		return nil
+	}
+
+	policy.Status.PolicyID = id
+
+	current, err := api.GetRetentionPolicy(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get policy %d", id)
+	}
+
+	inSync, err := IsInSync(desired, current)
+	if err != nil || inSync {
+		return err
+	}
+
+	err = api.UpdateRetentionPolicy(ctx, id, desired)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update policy %d", id)
+	}
+
+	r.Recorder.Eventf(policy, corev1.EventTypeNormal, EventReasonPolicyUpdated, "policy %d updated", id)
+
+	return nil
+}
+
+func (r *Reconciler) UpdateCondition(ctx context.Context, policy *goharborv1alpha1.HarborRetentionPolicy, conditionType goharborv1alpha1.HarborConditionType, status corev1.ConditionStatus, reasons ...string) error {
+	updated, _, err := conditions.Update(policy.Status.Conditions, conditionType, status, reasons...)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update condition %s", conditionType)
+	}
+
+	policy.Status.Conditions = updated
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package harborretentionpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/harborapi/harborapitest"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+var _ = Describe("Reconcile", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var server *harborapitest.Server
+	var harbor *goharborv1alpha1.Harbor
+	var policy *goharborv1alpha1.HarborRetentionPolicy
+	var req ctrl.Request
+
+	BeforeEach(func() {
+		server = harborapitest.NewServer()
+		server.HandleJSON(http.MethodGet, "/projects", http.StatusOK, []harborapi.Project{{ID: 1, Name: "library"}})
+		server.Handle(http.MethodPost, "/retentions", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Location", "/api/retentions/9")
+			w.WriteHeader(http.StatusCreated)
+		})
+		server.HandleJSON(http.MethodPut, "/retentions/9", http.StatusOK, nil)
+
+		harbor = harborapitest.NewHarbor("ns")
+
+		policy = &goharborv1alpha1.HarborRetentionPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "library",
+				Namespace:  "ns",
+				Finalizers: []string{goharborv1alpha1.HarborFinalizer},
+			},
+			Spec: goharborv1alpha1.HarborRetentionPolicySpec{
+				HarborName: "harbor",
+				Project:    goharborv1alpha1.ProjectReference{Name: "library"},
+				Schedule:   "0 0 0 * * *",
+				Rules: []goharborv1alpha1.RetentionRule{{
+					Template:  goharborv1alpha1.RetentionLatestPushed,
+					Parameter: 10,
+				}},
+				DeletionPolicy: goharborv1alpha1.DeletionPolicyDelete,
+			},
+		}
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "library"}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	setup := func(objects ...runtime.Object) {
+		r, ctx = setupTest(context.TODO(), append(objects, harbor, harborapitest.NewAdminPasswordSecret(harbor))...)
+	}
+
+	getPolicy := func() *goharborv1alpha1.HarborRetentionPolicy {
+		current := &goharborv1alpha1.HarborRetentionPolicy{}
+		Expect(r.Client.Get(ctx, req.NamespacedName, current)).To(Succeed())
+
+		return current
+	}
+
+	// withRetention makes the project reference its retention policy, as Harbor does once created.
+	withRetention := func() {
+		server.HandleJSON(http.MethodGet, "/projects", http.StatusOK, []harborapi.Project{{
+			ID:       1,
+			Name:     "library",
+			Metadata: map[string]string{harborapi.ProjectRetentionIDKey: "9"},
+		}})
+	}
+
+	It("Should add the finalizer first", func() {
+		policy.SetFinalizers(nil)
+		setup(policy)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getPolicy().GetFinalizers()).To(ConsistOf(goharborv1alpha1.HarborFinalizer))
+		Expect(server.Requests(http.MethodPost, "/retentions")).To(BeEmpty())
+	})
+
+	It("Should create the retention policy of the project", func() {
+		setup(policy)
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(reconciliation.DefaultResyncPeriod))
+
+		requests := server.Requests(http.MethodPost, "/retentions")
+		Expect(requests).To(HaveLen(1))
+
+		created := &harborapi.RetentionPolicy{}
+		Expect(json.Unmarshal(requests[0].Body, created)).To(Succeed())
+		Expect(created.Scope).To(Equal(&harborapi.RetentionScope{Level: harborapi.RetentionScopeProject, Ref: 1}))
+		Expect(created.Rules).To(HaveLen(1))
+
+		current := getPolicy()
+		Expect(current.Status.ProjectID).To(Equal(int64(1)))
+		Expect(current.Status.PolicyID).To(Equal(int64(9)))
+		Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.ReadyConditionType)).To(BeTrue())
+	})
+
+	It("Should report a missing project", func() {
+		policy.Spec.Project.Name = "missing"
+		setup(policy)
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(HaveOccurred())
+		Expect(server.Requests(http.MethodPost, "/retentions")).To(BeEmpty())
+
+		condition := conditions.Get(getPolicy().Status.Conditions, goharborv1alpha1.ReadyConditionType)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("project missing not found"))
+	})
+
+	It("Should wait for the referenced HarborProject to be created", func() {
+		policy.Spec.Project = goharborv1alpha1.ProjectReference{HarborProject: "library"}
+		project := &goharborv1alpha1.HarborProject{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "library",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborProjectSpec{
+				HarborName: "harbor",
+			},
+		}
+
+		setup(policy, project)
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(HaveOccurred())
+		Expect(server.Requests(http.MethodPost, "/retentions")).To(BeEmpty())
+
+		condition := conditions.Get(getPolicy().Status.Conditions, goharborv1alpha1.ReadyConditionType)
+		Expect(condition.Message).To(ContainSubstring("harbor project library is not created yet"))
+	})
+
+	Context("With an existing policy", func() {
+		BeforeEach(func() {
+			withRetention()
+
+			existing := GetPolicy(policy, 1)
+			existing.ID = 9
+			server.HandleJSON(http.MethodGet, "/retentions/9", http.StatusOK, existing)
+		})
+
+		It("Should not update a policy in sync", func() {
+			setup(policy)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPut, "/retentions/9")).To(BeEmpty())
+			Expect(server.Requests(http.MethodPost, "/retentions")).To(BeEmpty())
+			Expect(getPolicy().Status.PolicyID).To(Equal(int64(9)))
+		})
+
+		It("Should update the policy when the spec changes", func() {
+			policy.Spec.Schedule = "0 0 12 * * *"
+			setup(policy)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+
+			requests := server.Requests(http.MethodPut, "/retentions/9")
+			Expect(requests).To(HaveLen(1))
+
+			updated := &harborapi.RetentionPolicy{}
+			Expect(json.Unmarshal(requests[0].Body, updated)).To(Succeed())
+			Expect(updated.Trigger.Settings).To(HaveKeyWithValue(harborapi.RetentionCronSettingsKey, "0 0 12 * * *"))
+		})
+	})
+
+	Context("Deletion", func() {
+		BeforeEach(func() {
+			now := metav1.Now()
+			policy.SetDeletionTimestamp(&now)
+			policy.Status.ProjectID = 1
+			policy.Status.PolicyID = 9
+		})
+
+		It("Should clear rules and schedule then remove the finalizer", func() {
+			setup(policy)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+
+			requests := server.Requests(http.MethodPut, "/retentions/9")
+			Expect(requests).To(HaveLen(1))
+
+			cleared := &harborapi.RetentionPolicy{}
+			Expect(json.Unmarshal(requests[0].Body, cleared)).To(Succeed())
+			Expect(cleared.Rules).To(BeEmpty())
+			Expect(cleared.Trigger.Settings).To(HaveKeyWithValue(harborapi.RetentionCronSettingsKey, ""))
+			Expect(cleared.Scope.Ref).To(Equal(int64(1)))
+
+			Expect(getPolicy().GetFinalizers()).To(BeEmpty())
+		})
+
+		It("Should remove the finalizer when the project is already deleted", func() {
+			server.HandleJSON(http.MethodPut, "/retentions/9", http.StatusNotFound, nil)
+			setup(policy)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getPolicy().GetFinalizers()).To(BeEmpty())
+		})
+
+		It("Should keep the finalizer when the policy cannot be cleared", func() {
+			server.HandleJSON(http.MethodPut, "/retentions/9", http.StatusInternalServerError, nil)
+			setup(policy)
+
+			_, err := r.Reconcile(req)
+			Expect(err).To(HaveOccurred())
+			Expect(getPolicy().GetFinalizers()).To(ConsistOf(goharborv1alpha1.HarborFinalizer))
+		})
+
+		It("Should remove the finalizer when the Harbor does not exist", func() {
+			r, ctx = setupTest(context.TODO(), policy)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodPut, "/retentions/9")).To(BeEmpty())
+			Expect(getPolicy().GetFinalizers()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,477 @@
+package harborrobotaccount
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+const (
+	EventReasonTokenRotated  = "TokenRotated"
+	EventReasonRobotDeleted  = "RobotAccountDeleted"
+	EventReasonSecretWritten = "SecretWritten"
+)
+
+// RotationMarginRatio is the part of the token lifetime remaining when it is rotated.
+const RotationMarginRatio = 10
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborrobotaccounts,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborrobotaccounts/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harbors,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources="secrets",verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources="events",verbs=create;patch
+
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.TODO()
+	application.SetName(&ctx, r.GetName())
+	application.SetVersion(&ctx, r.GetVersion())
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "reconcile", opentracing.Tags{
+		"HarborRobotAccount.Namespace": req.Namespace,
+		"HarborRobotAccount.Name":      req.Name,
+	})
+	defer span.Finish()
+
+	reqLogger := r.Log.WithValues("Request", req.NamespacedName, "HarborRobotAccount.Namespace", req.Namespace, "HarborRobotAccount.Name", req.Name)
+
+	logger.Set(&ctx, reqLogger)
+
+	robot := &goharborv1alpha1.HarborRobotAccount{}
+
+	err := r.Client.Get(ctx, req.NamespacedName, robot)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			reqLogger.Info("HarborRobotAccount does not exists")
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if !robot.ObjectMeta.DeletionTimestamp.IsZero() {
+		reqLogger.Info("HarborRobotAccount is being deleted")
+		return reconcile.Result{}, r.Finalize(ctx, robot)
+	}
+
+	updated, err := reconciliation.UpdateFinalizer(ctx, r.Client, robot, true)
+	if err != nil || updated {
+		return reconcile.Result{}, err
+	}
+
+	result := reconcile.Result{
+		RequeueAfter: reconciliation.DefaultResyncPeriod,
+	}
+
+	syncErr := r.Sync(ctx, robot)
+	if syncErr != nil {
+		err = r.UpdateCondition(ctx, robot, goharborv1alpha1.ReadyConditionType, corev1.ConditionFalse, "sync-failed", syncErr.Error())
+	} else {
+		err = r.UpdateCondition(ctx, robot, goharborv1alpha1.ReadyConditionType, corev1.ConditionTrue, "synced", fmt.Sprintf("secret written in %d namespaces", len(robot.Status.Namespaces)))
+
+		if robot.Status.ExpiresAt != nil {
+			if wait := time.Until(RotationDeadline(&robot.Status)); wait < result.RequeueAfter {
+				result.RequeueAfter = wait
+			}
+		}
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	robot.Status.ObservedGeneration = robot.GetGeneration()
+
+	err = reconciliation.UpdateStatus(ctx, r.Client, &result, robot)
+	if err != nil {
+		return result, err
+	}
+
+	if syncErr == nil && !result.Requeue && len(robot.Status.RetiredRobots) > 0 {
+		// Secrets and status hold the current robot account, replaced ones can be deleted
+		err = r.DeleteRetiredRobots(ctx, robot)
+		if err != nil {
+			return result, errors.Wrap(err, "cannot delete retired robot accounts")
+		}
+
+		err = reconciliation.UpdateStatus(ctx, r.Client, &result, robot)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, errors.Wrap(syncErr, "cannot sync robot account")
+}
+
+// Finalize deletes the robot account from Harbor and the secrets from other namespaces, then removes the finalizer.
+func (r *Reconciler) Finalize(ctx context.Context, robot *goharborv1alpha1.HarborRobotAccount) error {
+	if !reconciliation.HasFinalizer(robot) {
+		return nil
+	}
+
+	if robot.Status.RobotID != 0 {
+		api, _, err := r.getClient(ctx, robot)
+		if err != nil {
+			return err
+		}
+
+		// Without Harbor, there is nothing to delete
+		if api != nil {
+			err = r.deleteRobots(ctx, api, robot, append(robot.Status.RetiredRobots, goharborv1alpha1.RetiredRobot{
+				ProjectID: robot.Status.ProjectID,
+				RobotID:   robot.Status.RobotID,
+				RobotName: robot.Status.RobotName,
+			}))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, namespace := range robot.Status.Namespaces {
+		if namespace == robot.GetNamespace() {
+			// Deleted with its owner
+			continue
+		}
+
+		err := r.DeleteSecret(ctx, robot, namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(robot, goharborv1alpha1.HarborFinalizer)
+
+	err := r.Client.Update(ctx, robot)
+
+	return errors.Wrap(err, "cannot remove finalizer")
+}
+
+// getClient returns a client for the Harbor hosting the project, nil if the Harbor does not exist.
+func (r *Reconciler) getClient(ctx context.Context, robot *goharborv1alpha1.HarborRobotAccount) (*harborapi.Client, *goharborv1alpha1.Harbor, error) {
+	return harborapi.NewFromName(ctx, r.Client, robot.GetNamespace(), robot.Spec.HarborName, harborapi.UserAgent(r.GetName(), r.GetVersion()))
+}
+
+// RotationDeadline returns the time the token must be rotated, before it expires.
+func RotationDeadline(status *goharborv1alpha1.HarborRobotAccountStatus) time.Time {
+	lifetime := status.ExpiresAt.Sub(status.RotationTime.Time)
+
+	return status.ExpiresAt.Add(-lifetime / RotationMarginRatio)
+}
+
+func samePermissions(a, b []goharborv1alpha1.RobotPermission) bool {
+	permissions := map[goharborv1alpha1.RobotPermission]bool{}
+	for _, permission := range a {
+		permissions[permission] = true
+	}
+
+	for _, permission := range b {
+		if !permissions[permission] {
+			return false
+		}
+
+		delete(permissions, permission)
+	}
+
+	return len(permissions) == 0
+}
+
+// NeedsRotation returns whether a new robot account must be created,
+// because there is none yet, permissions changed or the token expires soon.
+func NeedsRotation(robot *goharborv1alpha1.HarborRobotAccount, now time.Time) bool {
+	status := &robot.Status
+
+	if status.RobotID == 0 || status.RotationTime == nil {
+		return true
+	}
+
+	if !samePermissions(robot.GetPermissions(), status.Permissions) {
+		return true
+	}
+
+	if status.ExpiresAt == nil {
+		return false
+	}
+
+	return !now.Before(RotationDeadline(status))
+}
+
+// GetRobotName returns the name of the robot account created at the rotation time in Harbor, without the robot prefix.
+// Each rotation creates a new robot account, so the previous one is usable until secrets are updated.
+func GetRobotName(robot *goharborv1alpha1.HarborRobotAccount, rotation time.Time) string {
+	return fmt.Sprintf("%s-%s-%d", robot.GetNamespace(), robot.GetName(), rotation.Unix())
+}
+
+// GetRobotDescription returns the description of robot accounts created for the resource.
+func GetRobotDescription(robot *goharborv1alpha1.HarborRobotAccount) string {
+	return fmt.Sprintf("Managed by HarborRobotAccount %s", getAnnotationValue(robot))
+}
+
+// GetAccess returns the accesses matching the permissions. Pushing requires pulling.
+func GetAccess(projectID int64, permissions []goharborv1alpha1.RobotPermission) []harborapi.RobotAccess {
+	resource := harborapi.RepositoryResource(projectID)
+
+	access := []harborapi.RobotAccess{{
+		Resource: resource,
+		Action:   harborapi.RobotPullAction,
+	}}
+
+	for _, permission := range permissions {
+		if permission == goharborv1alpha1.RobotPushPermission {
+			access = append(access, harborapi.RobotAccess{
+				Resource: resource,
+				Action:   harborapi.RobotPushAction,
+			})
+		}
+	}
+
+	return access
+}
+
+// Sync rotates the robot account if required, then writes the secrets.
+func (r *Reconciler) Sync(ctx context.Context, robot *goharborv1alpha1.HarborRobotAccount) error {
+	api, harbor, err := r.getClient(ctx, robot)
+	if err != nil {
+		return err
+	}
+
+	if api == nil {
+		return errors.Errorf("harbor %s not found", robot.Spec.HarborName)
+	}
+
+	project, err := api.GetProjectByName(ctx, robot.Spec.ProjectName)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get project %s", robot.Spec.ProjectName)
+	}
+
+	if project == nil {
+		return errors.Errorf("project %s not found", robot.Spec.ProjectName)
+	}
+
+	secret, err := r.getSecret(ctx, robot)
+	if err != nil {
+		return err
+	}
+
+	var credentials Credentials
+
+	rotate := secret == nil || robot.Status.ProjectID != project.ID || NeedsRotation(robot, time.Now())
+	if !rotate {
+		var ok bool
+
+		credentials, ok = GetCredentials(secret)
+
+		rotate, err = r.isRevoked(ctx, api, robot)
+		if err != nil {
+			return err
+		}
+
+		// The secret may hold the credentials of a replaced robot account, if it was not written after the rotation
+		rotate = rotate || !ok || credentials.Username != robot.Status.RobotName
+	}
+
+	if rotate {
+		credentials, err = r.Rotate(ctx, api, robot, project.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	host, err := GetRegistryHost(harbor)
+	if err != nil {
+		return err
+	}
+
+	data, err := GetDockerConfigJSON(host, credentials)
+	if err != nil {
+		return err
+	}
+
+	return r.WriteSecrets(ctx, robot, data)
+}
+
+// isRevoked returns whether the robot account has been deleted or disabled in Harbor.
+func (r *Reconciler) isRevoked(ctx context.Context, api *harborapi.Client, robot *goharborv1alpha1.HarborRobotAccount) (bool, error) {
+	current, err := api.GetRobot(ctx, robot.Status.ProjectID, robot.Status.RobotID)
+	if err != nil {
+		if harborapi.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, errors.Wrapf(err, "cannot get robot account %d", robot.Status.RobotID)
+	}
+
+	return current.Disabled, nil
+}
+
+// Rotate creates a new robot account and returns its credentials.
+// The token is not returned by Harbor afterwards, it is only stored in the secrets.
+// The replaced robot account is retired, it is deleted once the secrets and the status hold the new one.
+func (r *Reconciler) Rotate(ctx context.Context, api *harborapi.Client, robot *goharborv1alpha1.HarborRobotAccount, projectID int64) (Credentials, error) {
+	now := metav1.Now()
+
+	var expiresAt int64
+	if robot.Spec.Expiration != nil {
+		expiresAt = now.Add(robot.Spec.Expiration.Duration).Unix()
+	}
+
+	permissions := robot.GetPermissions()
+
+	id, created, err := api.CreateRobot(ctx, projectID, &harborapi.RobotReq{
+		Name:        GetRobotName(robot, now.Time),
+		Description: GetRobotDescription(robot),
+		ExpiresAt:   expiresAt,
+		Access:      GetAccess(projectID, permissions),
+	})
+	if err != nil {
+		return Credentials{}, errors.Wrap(err, "cannot create robot account")
+	}
+
+	current, err := api.GetRobot(ctx, projectID, id)
+	if err != nil {
+		return Credentials{}, errors.Wrapf(err, "cannot get robot account %d", id)
+	}
+
+	if robot.Status.RobotID != 0 {
+		robot.Status.RetiredRobots = append(robot.Status.RetiredRobots, goharborv1alpha1.RetiredRobot{
+			ProjectID: robot.Status.ProjectID,
+			RobotID:   robot.Status.RobotID,
+			RobotName: robot.Status.RobotName,
+		})
+	}
+
+	robot.Status.ProjectID = projectID
+	robot.Status.RobotID = id
+	robot.Status.RobotName = created.Name
+	robot.Status.Permissions = append([]goharborv1alpha1.RobotPermission{}, permissions...)
+	robot.Status.RotationTime = &now
+	robot.Status.ExpiresAt = nil
+
+	if current.ExpiresAt != harborapi.NeverExpires {
+		expiration := metav1.NewTime(time.Unix(current.ExpiresAt, 0))
+		robot.Status.ExpiresAt = &expiration
+	}
+
+	r.Recorder.Eventf(robot, corev1.EventTypeNormal, EventReasonTokenRotated, "robot account %s created", created.Name)
+
+	return Credentials{
+		Username: created.Name,
+		Password: created.Token,
+	}, nil
+}
+
+// DeleteRetiredRobots deletes robot accounts replaced by the current one,
+// and robot accounts of the resource left by rotations whose status was not saved.
+func (r *Reconciler) DeleteRetiredRobots(ctx context.Context, robot *goharborv1alpha1.HarborRobotAccount) error {
+	api, _, err := r.getClient(ctx, robot)
+	if err != nil {
+		return err
+	}
+
+	if api == nil {
+		return errors.Errorf("harbor %s not found", robot.Spec.HarborName)
+	}
+
+	robots, err := api.ListRobots(ctx, robot.Status.ProjectID)
+	if err != nil {
+		return errors.Wrap(err, "cannot list robot accounts")
+	}
+
+	retired := robot.Status.RetiredRobots
+	description := GetRobotDescription(robot)
+
+	for _, current := range robots {
+		if current.ID != robot.Status.RobotID && current.Description == description {
+			retired = append(retired, goharborv1alpha1.RetiredRobot{
+				ProjectID: current.ProjectID,
+				RobotID:   current.ID,
+				RobotName: current.Name,
+			})
+		}
+	}
+
+	err = r.deleteRobots(ctx, api, robot, retired)
+	if err != nil {
+		return err
+	}
+
+	robot.Status.RetiredRobots = nil
+
+	return nil
+}
+
+// deleteRobots deletes the robot accounts from Harbor.
+func (r *Reconciler) deleteRobots(ctx context.Context, api *harborapi.Client, robot *goharborv1alpha1.HarborRobotAccount, robots []goharborv1alpha1.RetiredRobot) error {
+	deleted := map[int64]bool{}
+
+	for _, retired := range robots {
+		if retired.RobotID == 0 || deleted[retired.RobotID] {
+			continue
+		}
+
+		err := api.DeleteRobot(ctx, retired.ProjectID, retired.RobotID)
+		if err != nil && !harborapi.IsNotFound(err) {
+			return errors.Wrapf(err, "cannot delete robot account %d", retired.RobotID)
+		}
+
+		deleted[retired.RobotID] = true
+
+		r.Recorder.Eventf(robot, corev1.EventTypeNormal, EventReasonRobotDeleted, "robot account %s deleted", retired.RobotName)
+	}
+
+	return nil
+}
+
+// WriteSecrets writes the secret in every namespace and deletes it from namespaces no longer targeted.
+func (r *Reconciler) WriteSecrets(ctx context.Context, robot *goharborv1alpha1.HarborRobotAccount, data []byte) error {
+	namespaces := GetNamespaces(robot)
+	targeted := map[string]bool{}
+
+	for _, namespace := range namespaces {
+		targeted[namespace] = true
+
+		err := r.WriteSecret(ctx, robot, namespace, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, namespace := range robot.Status.Namespaces {
+		if !targeted[namespace] {
+			err := r.DeleteSecret(ctx, robot, namespace)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	robot.Status.Namespaces = namespaces
+
+	return nil
+}
+
+func (r *Reconciler) UpdateCondition(ctx context.Context, robot *goharborv1alpha1.HarborRobotAccount, conditionType goharborv1alpha1.HarborConditionType, status corev1.ConditionStatus, reasons ...string) error {
+	updated, _, err := conditions.Update(robot.Status.Conditions, conditionType, status, reasons...)
+	if err != nil {
+		return errors.Wrapf(err, "cannot update condition %s", conditionType)
+	}
+
+	robot.Status.Conditions = updated
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package harborrobotaccount
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/harborapi/harborapitest"
+	"github.com/goharbor/harbor-operator/pkg/reconciliation"
+)
+
+var _ = Describe("Reconcile", func() {
+	var r *Reconciler
+	var ctx context.Context
+	var server *harborapitest.Server
+	var harbor *goharborv1alpha1.Harbor
+	var robot *goharborv1alpha1.HarborRobotAccount
+	var req ctrl.Request
+
+	BeforeEach(func() {
+		server = harborapitest.NewServer()
+		server.HandleJSON(http.MethodGet, "/projects", http.StatusOK, []harborapi.Project{{ID: 1, Name: "library"}})
+		server.Handle(http.MethodPost, "/projects/1/robots", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Location", "/api/projects/1/robots/5")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"name":"robot$ns-ci","token":"token"}`))
+		})
+		server.HandleJSON(http.MethodGet, "/projects/1/robots/5", http.StatusOK, &harborapi.Robot{ID: 5, Name: "robot$ns-ci", ProjectID: 1, ExpiresAt: harborapi.NeverExpires})
+		server.HandleJSON(http.MethodDelete, "/projects/1/robots/3", http.StatusOK, nil)
+		server.HandleJSON(http.MethodDelete, "/projects/1/robots/5", http.StatusOK, nil)
+
+		harbor = harborapitest.NewHarbor("ns")
+
+		robot = &goharborv1alpha1.HarborRobotAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "ci",
+				Namespace:  "ns",
+				Finalizers: []string{goharborv1alpha1.HarborFinalizer},
+			},
+			Spec: goharborv1alpha1.HarborRobotAccountSpec{
+				HarborName:       "harbor",
+				ProjectName:      "library",
+				TargetNamespaces: []string{"build"},
+			},
+		}
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "ci"}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	setup := func(objects ...runtime.Object) {
+		r, ctx = setupTest(context.TODO(), append(objects, harbor, harborapitest.NewAdminPasswordSecret(harbor))...)
+	}
+
+	getRobot := func() *goharborv1alpha1.HarborRobotAccount {
+		current := &goharborv1alpha1.HarborRobotAccount{}
+		Expect(r.Client.Get(ctx, req.NamespacedName, current)).To(Succeed())
+
+		return current
+	}
+
+	getSecret := func(namespace string) (*corev1.Secret, error) {
+		secret := &corev1.Secret{}
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "ci"}, secret)
+
+		return secret, err
+	}
+
+	It("Should add the finalizer first", func() {
+		robot.SetFinalizers(nil)
+		setup(robot)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getRobot().GetFinalizers()).To(ConsistOf(goharborv1alpha1.HarborFinalizer))
+		Expect(server.Requests(http.MethodPost, "/projects/1/robots")).To(BeEmpty())
+	})
+
+	It("Should create the robot account and write secrets in all namespaces", func() {
+		setup(robot)
+
+		result, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(reconciliation.DefaultResyncPeriod))
+		Expect(server.Requests(http.MethodPost, "/projects/1/robots")).To(HaveLen(1))
+
+		current := getRobot()
+		Expect(current.Status.RobotID).To(Equal(int64(5)))
+		Expect(current.Status.RobotName).To(Equal("robot$ns-ci"))
+		Expect(current.Status.ExpiresAt).To(BeNil())
+		Expect(current.Status.Namespaces).To(ConsistOf("ns", "build"))
+		Expect(conditions.IsTrue(current.Status.Conditions, goharborv1alpha1.ReadyConditionType)).To(BeTrue())
+
+		for _, namespace := range []string{"ns", "build"} {
+			secret, err := getSecret(namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secret.Type).To(Equal(corev1.SecretTypeDockerConfigJson))
+
+			credentials, ok := GetCredentials(secret)
+			Expect(ok).To(BeTrue())
+			Expect(credentials.Username).To(Equal("robot$ns-ci"))
+			Expect(credentials.Password).To(Equal("token"))
+		}
+
+		secret, err := getSecret("ns")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.GetOwnerReferences()).To(HaveLen(1))
+
+		_, err = r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.Requests(http.MethodPost, "/projects/1/robots")).To(HaveLen(1), "robot account rotated again")
+	})
+
+	It("Should delete the replaced robot account once secrets are written", func() {
+		now := metav1.Now()
+		robot.Status.ProjectID = 1
+		robot.Status.RobotID = 3
+		robot.Status.RobotName = "robot$ns-ci-old"
+		robot.Status.RotationTime = &now
+		robot.Status.Permissions = []goharborv1alpha1.RobotPermission{goharborv1alpha1.RobotPushPermission}
+
+		server.HandleJSON(http.MethodGet, "/projects/1/robots", http.StatusOK, []harborapi.Robot{
+			{ID: 3, ProjectID: 1, Name: "robot$ns-ci-old", Description: GetRobotDescription(robot)},
+			{ID: 5, ProjectID: 1, Name: "robot$ns-ci", Description: GetRobotDescription(robot)},
+		})
+
+		setup(robot)
+
+		_, err := r.Reconcile(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.Requests(http.MethodPost, "/projects/1/robots")).To(HaveLen(1))
+		Expect(server.Requests(http.MethodDelete, "/projects/1/robots/3")).To(HaveLen(1))
+		Expect(server.Requests(http.MethodDelete, "/projects/1/robots/5")).To(BeEmpty())
+
+		current := getRobot()
+		Expect(current.Status.RobotID).To(Equal(int64(5)))
+		Expect(current.Status.RetiredRobots).To(BeEmpty())
+	})
+
+	It("Should report a missing project", func() {
+		robot.Spec.ProjectName = "missing"
+		setup(robot)
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(HaveOccurred())
+
+		condition := conditions.Get(getRobot().Status.Conditions, goharborv1alpha1.ReadyConditionType)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("project missing not found"))
+	})
+
+	Context("Deletion", func() {
+		BeforeEach(func() {
+			now := metav1.Now()
+			robot.SetDeletionTimestamp(&now)
+			robot.Status.ProjectID = 1
+			robot.Status.RobotID = 5
+			robot.Status.RobotName = "robot$ns-ci"
+			robot.Status.Namespaces = []string{"ns", "build"}
+		})
+
+		It("Should delete the robot account and secrets of other namespaces", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ci",
+					Namespace:   "build",
+					Annotations: map[string]string{goharborv1alpha1.RobotAccountAnnotation: "ns/ci"},
+				},
+			}
+
+			setup(robot, secret)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodDelete, "/projects/1/robots/5")).To(HaveLen(1))
+			Expect(getRobot().GetFinalizers()).To(BeEmpty())
+
+			_, err = getSecret("build")
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Should keep secrets not managed by the robot account", func() {
+			setup(robot, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "build"}})
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getRobot().GetFinalizers()).To(BeEmpty())
+
+			_, err = getSecret("build")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should remove the finalizer when the Harbor does not exist", func() {
+			r, ctx = setupTest(context.TODO(), robot)
+
+			_, err := r.Reconcile(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Requests(http.MethodDelete, "/projects/1/robots/5")).To(BeEmpty())
+			Expect(getRobot().GetFinalizers()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,114 @@
+package harborrobotaccount
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+)
+
+var _ = Describe("HarborRobotAccount", func() {
+	var robot *goharborv1alpha1.HarborRobotAccount
+
+	BeforeEach(func() {
+		robot = &goharborv1alpha1.HarborRobotAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ci",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborRobotAccountSpec{
+				HarborName:       "harbor",
+				ProjectName:      "library",
+				TargetNamespaces: []string{"build", "ns", "build"},
+			},
+		}
+	})
+
+	Context("Rotation", func() {
+		var rotation time.Time
+
+		BeforeEach(func() {
+			rotation = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			expiration := metav1.NewTime(rotation.Add(10 * 24 * time.Hour))
+
+			robot.Status = goharborv1alpha1.HarborRobotAccountStatus{
+				RobotID:      1,
+				Permissions:  []goharborv1alpha1.RobotPermission{goharborv1alpha1.RobotPullPermission},
+				RotationTime: &metav1.Time{Time: rotation},
+				ExpiresAt:    &expiration,
+			}
+		})
+
+		It("Should rotate without robot account", func() {
+			robot.Status.RobotID = 0
+
+			Expect(NeedsRotation(robot, rotation)).To(BeTrue())
+		})
+
+		It("Should rotate when permissions change", func() {
+			Expect(NeedsRotation(robot, rotation)).To(BeFalse())
+
+			robot.Spec.Permissions = []goharborv1alpha1.RobotPermission{goharborv1alpha1.RobotPushPermission}
+			Expect(NeedsRotation(robot, rotation)).To(BeTrue())
+		})
+
+		It("Should rotate before the token expires", func() {
+			Expect(RotationDeadline(&robot.Status)).To(Equal(rotation.Add(9 * 24 * time.Hour)))
+
+			Expect(NeedsRotation(robot, rotation.Add(8*24*time.Hour))).To(BeFalse())
+			Expect(NeedsRotation(robot, rotation.Add(9*24*time.Hour))).To(BeTrue())
+		})
+
+		It("Should not rotate tokens which never expire", func() {
+			robot.Status.ExpiresAt = nil
+
+			Expect(NeedsRotation(robot, rotation.Add(365*24*time.Hour))).To(BeFalse())
+		})
+	})
+
+	It("Should name robot accounts after the rotation time", func() {
+		rotation := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		Expect(GetRobotName(robot, rotation)).To(Equal("ns-ci-1577836800"))
+		Expect(GetRobotName(robot, rotation.Add(time.Hour))).ToNot(Equal(GetRobotName(robot, rotation)))
+		Expect(GetRobotDescription(robot)).To(Equal("Managed by HarborRobotAccount ns/ci"))
+	})
+
+	It("Should grant pull access with push", func() {
+		access := GetAccess(3, []goharborv1alpha1.RobotPermission{goharborv1alpha1.RobotPushPermission})
+
+		Expect(access).To(ConsistOf(
+			harborapi.RobotAccess{Resource: "/project/3/repository", Action: harborapi.RobotPullAction},
+			harborapi.RobotAccess{Resource: "/project/3/repository", Action: harborapi.RobotPushAction},
+		))
+	})
+
+	It("Should write the secret in the namespace of the resource first", func() {
+		Expect(GetNamespaces(robot)).To(Equal([]string{"ns", "build"}))
+	})
+
+	It("Should read back credentials from the docker configuration", func() {
+		data, err := GetDockerConfigJSON("harbor.example.com", Credentials{
+			Username: "robot$ns-ci",
+			Password: "token",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"harbor.example.com"`))
+		Expect(string(data)).To(ContainSubstring(`"auth":"cm9ib3QkbnMtY2k6dG9rZW4="`))
+
+		credentials, ok := GetCredentials(&corev1.Secret{
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: data,
+			},
+		})
+		Expect(ok).To(BeTrue())
+		Expect(credentials.Username).To(Equal("robot$ns-ci"))
+		Expect(credentials.Password).To(Equal("token"))
+	})
+})
@@ -0,0 +1,178 @@
+package harborrobotaccount
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+// Credentials of a robot account.
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+type DockerConfig struct {
+	Auths map[string]Credentials `json:"auths"`
+}
+
+// GetRegistryHost returns the host of the registry, used as key of the docker configuration.
+func GetRegistryHost(harbor *goharborv1alpha1.Harbor) (string, error) {
+	u, err := url.Parse(harbor.Spec.PublicURL)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot parse public url")
+	}
+
+	return u.Host, nil
+}
+
+// GetDockerConfigJSON returns the content of a kubernetes.io/dockerconfigjson secret for the credentials.
+func GetDockerConfigJSON(host string, credentials Credentials) ([]byte, error) {
+	credentials.Auth = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", credentials.Username, credentials.Password)))
+
+	data, err := json.Marshal(&DockerConfig{
+		Auths: map[string]Credentials{
+			host: credentials,
+		},
+	})
+
+	return data, errors.Wrap(err, "cannot encode docker configuration")
+}
+
+// GetCredentials returns the credentials stored in the secret, ok is false if there is none.
+func GetCredentials(secret *corev1.Secret) (credentials Credentials, ok bool) {
+	config := &DockerConfig{}
+
+	err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], config)
+	if err != nil {
+		return credentials, false
+	}
+
+	for _, credentials := range config.Auths {
+		return credentials, credentials.Username != "" && credentials.Password != ""
+	}
+
+	return credentials, false
+}
+
+func getAnnotationValue(robot *goharborv1alpha1.HarborRobotAccount) string {
+	return fmt.Sprintf("%s/%s", robot.GetNamespace(), robot.GetName())
+}
+
+// isManaged returns whether the secret has been written for the robot account.
+func isManaged(robot *goharborv1alpha1.HarborRobotAccount, secret *corev1.Secret) bool {
+	return secret.GetAnnotations()[goharborv1alpha1.RobotAccountAnnotation] == getAnnotationValue(robot)
+}
+
+// getSecret returns the secret of the robot account in its own namespace, nil if it does not exist.
+func (r *Reconciler) getSecret(ctx context.Context, robot *goharborv1alpha1.HarborRobotAccount) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: robot.GetNamespace(), Name: robot.GetSecretName()}, secret)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "cannot get secret")
+	}
+
+	return secret, nil
+}
+
+// WriteSecret creates or updates the secret in the namespace.
+// The secret in the namespace of the robot account is owned by it, others are deleted by the finalizer.
+func (r *Reconciler) WriteSecret(ctx context.Context, robot *goharborv1alpha1.HarborRobotAccount, namespace string, data []byte) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      robot.GetSecretName(),
+			Namespace: namespace,
+		},
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.GetResourceVersion() != "" && !isManaged(robot, secret) {
+			return errors.Errorf("secret %s/%s is not managed by the robot account", namespace, secret.GetName())
+		}
+
+		annotations := secret.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[goharborv1alpha1.RobotAccountAnnotation] = getAnnotationValue(robot)
+		secret.SetAnnotations(annotations)
+
+		secret.Type = corev1.SecretTypeDockerConfigJson
+		secret.Data = map[string][]byte{
+			corev1.DockerConfigJsonKey: data,
+		}
+
+		if namespace == robot.GetNamespace() {
+			return controllerutil.SetControllerReference(robot, secret, r.Scheme)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "cannot write secret in namespace %s", namespace)
+	}
+
+	if op != controllerutil.OperationResultNone {
+		r.Recorder.Eventf(robot, corev1.EventTypeNormal, EventReasonSecretWritten, "secret %s/%s %s", namespace, secret.GetName(), op)
+	}
+
+	return nil
+}
+
+// DeleteSecret deletes the secret from the namespace, if it is managed by the robot account.
+func (r *Reconciler) DeleteSecret(ctx context.Context, robot *goharborv1alpha1.HarborRobotAccount, namespace string) error {
+	secret := &corev1.Secret{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: robot.GetSecretName()}, secret)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+
+		return errors.Wrapf(err, "cannot get secret in namespace %s", namespace)
+	}
+
+	if !isManaged(robot, secret) {
+		return nil
+	}
+
+	err = r.Client.Delete(ctx, secret)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return errors.Wrapf(err, "cannot delete secret in namespace %s", namespace)
+	}
+
+	return nil
+}
+
+// GetNamespaces returns the namespaces to write the secret into.
+func GetNamespaces(robot *goharborv1alpha1.HarborRobotAccount) []string {
+	namespaces := []string{robot.GetNamespace()}
+	known := map[string]bool{robot.GetNamespace(): true}
+
+	for _, namespace := range robot.Spec.TargetNamespaces {
+		if !known[namespace] {
+			known[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	return namespaces
+}
@@ -13,6 +13,16 @@ import (
 
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
 	"github.com/goharbor/harbor-operator/pkg/controllers/harbor"
+	"github.com/goharbor/harbor-operator/pkg/controllers/harborbackup"
+	"github.com/goharbor/harbor-operator/pkg/controllers/harborconfiguration"
+	"github.com/goharbor/harbor-operator/pkg/controllers/harborgarbagecollection"
+	"github.com/goharbor/harbor-operator/pkg/controllers/harborimmutabletagrule"
+	"github.com/goharbor/harbor-operator/pkg/controllers/harborproject"
+	"github.com/goharbor/harbor-operator/pkg/controllers/harborregistryendpoint"
+	"github.com/goharbor/harbor-operator/pkg/controllers/harborreplicationpolicy"
+	"github.com/goharbor/harbor-operator/pkg/controllers/harborrobotaccount"
+	"github.com/goharbor/harbor-operator/pkg/controllers/harborrestore"
+	"github.com/goharbor/harbor-operator/pkg/controllers/harborretentionpolicy"
 	"github.com/goharbor/harbor-operator/pkg/factories/logger"
 	"github.com/goharbor/harbor-operator/pkg/manager"
 	"github.com/goharbor/harbor-operator/pkg/scheme"
@@ -87,6 +97,116 @@ func main() {
 		os.Exit(exitCodeFailure)
 	}
 
+	backupReconciler, err := harborbackup.New(ctx, OperatorName, OperatorVersion)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HarborBackup")
+		os.Exit(exitCodeFailure)
+	}
+
+	if err := backupReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller", "controller", "HarborBackup")
+		os.Exit(exitCodeFailure)
+	}
+
+	restoreReconciler, err := harborrestore.New(ctx, OperatorName, OperatorVersion)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HarborRestore")
+		os.Exit(exitCodeFailure)
+	}
+
+	if err := restoreReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller", "controller", "HarborRestore")
+		os.Exit(exitCodeFailure)
+	}
+
+	gcReconciler, err := harborgarbagecollection.New(ctx, OperatorName, OperatorVersion)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HarborGarbageCollection")
+		os.Exit(exitCodeFailure)
+	}
+
+	if err := gcReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller", "controller", "HarborGarbageCollection")
+		os.Exit(exitCodeFailure)
+	}
+
+	projectReconciler, err := harborproject.New(ctx, OperatorName, OperatorVersion)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HarborProject")
+		os.Exit(exitCodeFailure)
+	}
+
+	if err := projectReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller", "controller", "HarborProject")
+		os.Exit(exitCodeFailure)
+	}
+
+	robotReconciler, err := harborrobotaccount.New(ctx, OperatorName, OperatorVersion)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HarborRobotAccount")
+		os.Exit(exitCodeFailure)
+	}
+
+	if err := robotReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller", "controller", "HarborRobotAccount")
+		os.Exit(exitCodeFailure)
+	}
+
+	registryReconciler, err := harborregistryendpoint.New(ctx, OperatorName, OperatorVersion)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HarborRegistryEndpoint")
+		os.Exit(exitCodeFailure)
+	}
+
+	if err := registryReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller", "controller", "HarborRegistryEndpoint")
+		os.Exit(exitCodeFailure)
+	}
+
+	replicationReconciler, err := harborreplicationpolicy.New(ctx, OperatorName, OperatorVersion)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HarborReplicationPolicy")
+		os.Exit(exitCodeFailure)
+	}
+
+	if err := replicationReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller", "controller", "HarborReplicationPolicy")
+		os.Exit(exitCodeFailure)
+	}
+
+	retentionReconciler, err := harborretentionpolicy.New(ctx, OperatorName, OperatorVersion)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HarborRetentionPolicy")
+		os.Exit(exitCodeFailure)
+	}
+
+	if err := retentionReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller", "controller", "HarborRetentionPolicy")
+		os.Exit(exitCodeFailure)
+	}
+
+	immutableReconciler, err := harborimmutabletagrule.New(ctx, OperatorName, OperatorVersion)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HarborImmutableTagRule")
+		os.Exit(exitCodeFailure)
+	}
+
+	if err := immutableReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller", "controller", "HarborImmutableTagRule")
+		os.Exit(exitCodeFailure)
+	}
+
+	configurationReconciler, err := harborconfiguration.New(ctx, OperatorName, OperatorVersion)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HarborConfiguration")
+		os.Exit(exitCodeFailure)
+	}
+
+	if err := configurationReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller", "controller", "HarborConfiguration")
+		os.Exit(exitCodeFailure)
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager", "version", OperatorVersion)
@@ -0,0 +1,74 @@
+// Package backuptest provides a Harbor running in read-only mode for tests of controllers switching it to read-only.
+package backuptest
+
+import (
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/backup"
+)
+
+// NewHarbor returns a Harbor named harbor in the given namespace, with core using the core-database secret.
+// Its status tells its generation is applied and ready.
+func NewHarbor(namespace string) *goharborv1alpha1.Harbor {
+	return &goharborv1alpha1.Harbor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "harbor",
+			Namespace:  namespace,
+			Generation: 1,
+		},
+		Spec: goharborv1alpha1.HarborSpec{
+			PublicURL: "https://harbor.example.com",
+			Components: goharborv1alpha1.HarborComponents{
+				Core: &goharborv1alpha1.CoreComponent{
+					DatabaseSecret: "core-database",
+				},
+			},
+		},
+		Status: goharborv1alpha1.HarborStatus{
+			ObservedGeneration: 1,
+			Conditions: []goharborv1alpha1.HarborCondition{
+				{Type: goharborv1alpha1.AppliedConditionType, Status: corev1.ConditionTrue},
+				{Type: goharborv1alpha1.ReadyConditionType, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+}
+
+// NewCoreDeployment returns the core deployment of the Harbor, with all pods running in the given read-only mode.
+func NewCoreDeployment(harbor *goharborv1alpha1.Harbor, readOnly bool) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      harbor.NormalizeComponentName(goharborv1alpha1.CoreName),
+			Namespace: harbor.GetNamespace(),
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          1,
+			UpdatedReplicas:   1,
+			AvailableReplicas: 1,
+		},
+	}
+
+	deployment.Spec.Template.Annotations = map[string]string{
+		goharborv1alpha1.ReadOnlyAnnotation: strconv.FormatBool(readOnly),
+	}
+
+	return deployment
+}
+
+// NewCoreSecret returns the generated core secret of the Harbor, holding the given secret key.
+func NewCoreSecret(harbor *goharborv1alpha1.Harbor, secretKey string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      harbor.NormalizeComponentName(goharborv1alpha1.CoreName),
+			Namespace: harbor.GetNamespace(),
+		},
+		Data: map[string][]byte{
+			backup.CoreSecretKey: []byte(secretKey),
+		},
+	}
+}
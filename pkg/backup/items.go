@@ -0,0 +1,117 @@
+package backup
+
+import (
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+type ItemKind string
+
+const (
+	// DatabaseItem is a PostgreSQL database, dumped with pg_dump
+	DatabaseItem ItemKind = "database"
+	// SecretItem is a key of a secret, copied as a file
+	SecretItem ItemKind = "secret"
+	// StorageItem is a registry storage, copied by the storage image
+	StorageItem ItemKind = "storage"
+)
+
+const (
+	CoreDatabaseItem         = "core-database"
+	ClairDatabaseItem        = "clair-database"
+	NotaryServerDatabaseItem = "notary-server-database"
+	NotarySignerDatabaseItem = "notary-signer-database"
+	CoreSecretKeyItem        = "core-secretkey"
+	RegistryStorageItem      = "registry-storage"
+	ChartMuseumStorageItem   = "chartmuseum-storage"
+)
+
+const (
+	// CoreSecretKey is the key of the generated core secret containing the encryption key
+	CoreSecretKey = "secretKey"
+)
+
+// Item is a piece of data of an Harbor to backup or restore.
+type Item struct {
+	Name string
+	Kind ItemKind
+
+	// The secret containing the database connection settings, the storage configuration or the secret key.
+	Secret string
+	// The key of the secret to copy, for SecretItem only.
+	SecretKey string
+}
+
+// Path returns the path of the item, relative to the backup root.
+func (i Item) Path() string {
+	switch i.Kind {
+	case DatabaseItem:
+		return i.Name + ".dump"
+	case StorageItem:
+		return i.Name + "/"
+	default:
+		return i.Name
+	}
+}
+
+// Items returns items to backup for the given Harbor.
+// Storage items are returned only if a storage image is configured.
+func Items(harbor *goharborv1alpha1.Harbor, images goharborv1alpha1.BackupImages) []Item {
+	components := harbor.Spec.Components
+
+	var items []Item
+
+	if components.Core != nil {
+		items = append(items, Item{
+			Name:   CoreDatabaseItem,
+			Kind:   DatabaseItem,
+			Secret: components.Core.DatabaseSecret,
+		}, Item{
+			Name:      CoreSecretKeyItem,
+			Kind:      SecretItem,
+			Secret:    harbor.NormalizeComponentName(goharborv1alpha1.CoreName),
+			SecretKey: CoreSecretKey,
+		})
+	}
+
+	if components.Clair != nil {
+		items = append(items, Item{
+			Name:   ClairDatabaseItem,
+			Kind:   DatabaseItem,
+			Secret: components.Clair.DatabaseSecret,
+		})
+	}
+
+	if components.Notary != nil {
+		items = append(items, Item{
+			Name:   NotaryServerDatabaseItem,
+			Kind:   DatabaseItem,
+			Secret: components.Notary.Server.DatabaseSecret,
+		}, Item{
+			Name:   NotarySignerDatabaseItem,
+			Kind:   DatabaseItem,
+			Secret: components.Notary.Signer.DatabaseSecret,
+		})
+	}
+
+	if images.Storage == "" {
+		return items
+	}
+
+	if components.Registry != nil && components.Registry.StorageSecret != "" {
+		items = append(items, Item{
+			Name:   RegistryStorageItem,
+			Kind:   StorageItem,
+			Secret: components.Registry.StorageSecret,
+		})
+	}
+
+	if components.ChartMuseum != nil && components.ChartMuseum.StorageSecret != "" {
+		items = append(items, Item{
+			Name:   ChartMuseumStorageItem,
+			Kind:   StorageItem,
+			Secret: components.ChartMuseum.StorageSecret,
+		})
+	}
+
+	return items
+}
@@ -0,0 +1,317 @@
+package backup
+
+import (
+	"fmt"
+	"path"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+type Direction string
+
+const (
+	BackupDirection  Direction = "backup"
+	RestoreDirection Direction = "restore"
+)
+
+const (
+	DefaultDatabaseImage = "postgres:12-alpine"
+	DefaultS3Image       = "minio/mc:latest"
+	DefaultCopyImage     = "busybox:1.31"
+)
+
+const (
+	S3AccessKeyKey = "access-key"
+	S3SecretKeyKey = "secret-key"
+)
+
+const (
+	backupVolumeName  = "backup"
+	sourceVolumeName  = "source"
+	backupMountPath   = "/backup"
+	sourceMountPath   = "/source"
+	storageMountPath  = "/etc/storage"
+	transferContainer = "transfer"
+)
+
+var backoffLimit int32 = 2
+
+func imageOrDefault(image, defaultImage string) string {
+	if image == "" {
+		return defaultImage
+	}
+
+	return image
+}
+
+// JobName returns the name of the job handling the item for the given backup or restore.
+func JobName(ownerName string, item Item) string {
+	return fmt.Sprintf("%s-%s", ownerName, item.Name)
+}
+
+// GetJob returns the job transferring the item between the Harbor and the target of the backup.
+// With a S3 target, files are transferred through an emptyDir volume by an additional container.
+func GetJob(direction Direction, ownerName string, backup *goharborv1alpha1.HarborBackup, harbor *goharborv1alpha1.Harbor, item Item) *batchv1.Job {
+	labels := map[string]string{
+		"app":       string(direction),
+		"harbor":    harbor.GetName(),
+		"backup":    backup.GetName(),
+		"component": item.Name,
+	}
+
+	volumes := []corev1.Volume{getBackupVolume(backup)}
+
+	if item.Kind != DatabaseItem {
+		volumes = append(volumes, getSourceVolume(item))
+	}
+
+	dataContainer := getDataContainer(direction, backup, item)
+
+	var initContainers, containers []corev1.Container
+
+	switch {
+	case backup.Spec.Target.S3 == nil:
+		containers = []corev1.Container{dataContainer}
+	case direction == BackupDirection:
+		initContainers = []corev1.Container{dataContainer}
+		containers = []corev1.Container{getS3Container(direction, backup, item)}
+	default:
+		initContainers = []corev1.Container{getS3Container(direction, backup, item)}
+		containers = []corev1.Container{dataContainer}
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      JobName(ownerName, item),
+			Namespace: harbor.GetNamespace(),
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:  corev1.RestartPolicyNever,
+					Volumes:        volumes,
+					InitContainers: initContainers,
+					Containers:     containers,
+				},
+			},
+		},
+	}
+}
+
+func getBackupVolume(backup *goharborv1alpha1.HarborBackup) corev1.Volume {
+	volume := corev1.Volume{
+		Name: backupVolumeName,
+	}
+
+	if backup.Spec.Target.PersistentVolumeClaim != nil {
+		volume.PersistentVolumeClaim = backup.Spec.Target.PersistentVolumeClaim.DeepCopy()
+	} else {
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	}
+
+	return volume
+}
+
+func getSourceVolume(item Item) corev1.Volume {
+	volume := corev1.Volume{
+		Name: sourceVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: item.Secret,
+			},
+		},
+	}
+
+	if item.Kind == SecretItem {
+		volume.Secret.Items = []corev1.KeyToPath{{
+			Key:  item.SecretKey,
+			Path: item.SecretKey,
+		}}
+	}
+
+	return volume
+}
+
+func getBackupVolumeMount(backup *goharborv1alpha1.HarborBackup) corev1.VolumeMount {
+	mount := corev1.VolumeMount{
+		Name:      backupVolumeName,
+		MountPath: backupMountPath,
+	}
+
+	if backup.Spec.Target.PersistentVolumeClaim != nil {
+		// Multiple backups share the same claim
+		mount.SubPath = backup.GetName()
+	}
+
+	return mount
+}
+
+func getDataContainer(direction Direction, backup *goharborv1alpha1.HarborBackup, item Item) corev1.Container {
+	backupMount := getBackupVolumeMount(backup)
+	file := path.Join(backupMountPath, item.Path())
+
+	switch item.Kind {
+	case DatabaseItem:
+		container := corev1.Container{
+			Name:         string(item.Kind),
+			Image:        imageOrDefault(backup.Spec.Images.Database, DefaultDatabaseImage),
+			Env:          getDatabaseEnv(item.Secret),
+			VolumeMounts: []corev1.VolumeMount{backupMount},
+		}
+
+		if direction == BackupDirection {
+			container.Command = []string{"pg_dump", "--format=custom", "--file=" + file}
+		} else {
+			container.Command = []string{"pg_restore", "--clean", "--if-exists", "--no-owner", "--dbname=$(PGDATABASE)", file}
+		}
+
+		return container
+	case StorageItem:
+		return corev1.Container{
+			Name:  string(item.Kind),
+			Image: backup.Spec.Images.Storage,
+			Env: []corev1.EnvVar{
+				{
+					Name:  "BACKUP_DIRECTION",
+					Value: string(direction),
+				}, {
+					Name:  "BACKUP_ITEM",
+					Value: item.Name,
+				}, {
+					Name:  "BACKUP_PATH",
+					Value: file,
+				},
+			},
+			VolumeMounts: []corev1.VolumeMount{backupMount, {
+				Name:      sourceVolumeName,
+				MountPath: storageMountPath,
+				ReadOnly:  true,
+			}},
+		}
+	default:
+		// Secrets are restored by the operator, only backup is handled by a job
+		return corev1.Container{
+			Name:    string(item.Kind),
+			Image:   imageOrDefault(backup.Spec.Images.Copy, DefaultCopyImage),
+			Command: []string{"cp", path.Join(sourceMountPath, item.SecretKey), file},
+			VolumeMounts: []corev1.VolumeMount{backupMount, {
+				Name:      sourceVolumeName,
+				MountPath: sourceMountPath,
+				ReadOnly:  true,
+			}},
+		}
+	}
+}
+
+func getDatabaseEnv(secretName string) []corev1.EnvVar {
+	keys := []struct {
+		env string
+		key string
+	}{
+		{"PGHOST", goharborv1alpha1.HarborCoreDatabaseHostKey},
+		{"PGPORT", goharborv1alpha1.HarborCoreDatabasePortKey},
+		{"PGDATABASE", goharborv1alpha1.HarborCoreDatabaseNameKey},
+		{"PGUSER", goharborv1alpha1.HarborCoreDatabaseUserKey},
+		{"PGPASSWORD", goharborv1alpha1.HarborCoreDatabasePasswordKey},
+	}
+
+	env := make([]corev1.EnvVar, len(keys))
+
+	for i, key := range keys {
+		env[i] = corev1.EnvVar{
+			Name: key.env,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					Key: key.key,
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretName,
+					},
+				},
+			},
+		}
+	}
+
+	return env
+}
+
+func getS3Container(direction Direction, backup *goharborv1alpha1.HarborBackup, item Item) corev1.Container {
+	s3 := backup.Spec.Target.S3
+
+	remote := path.Join("target", s3.Bucket, s3.Prefix, backup.GetName())
+
+	var source, destination, options string
+
+	switch direction {
+	case BackupDirection:
+		source, destination = backupMountPath+"/", remote+"/"
+		options = "--recursive "
+	default:
+		source, destination = path.Join(remote, item.Path()), path.Join(backupMountPath, item.Path())
+
+		if item.Kind == StorageItem {
+			options = "--recursive "
+		}
+	}
+
+	copyCommand := fmt.Sprintf("mc cp %s'%s' '%s'", options, source, destination)
+
+	return corev1.Container{
+		Name:    transferContainer,
+		Image:   imageOrDefault(backup.Spec.Images.S3, DefaultS3Image),
+		Command: []string{"/bin/sh", "-c", `mc alias set target "$S3_ENDPOINT" "$S3_ACCESS_KEY" "$S3_SECRET_KEY" && ` + copyCommand},
+		Env: []corev1.EnvVar{
+			{
+				Name:  "S3_ENDPOINT",
+				Value: s3.Endpoint,
+			}, {
+				Name: "S3_ACCESS_KEY",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						Key: S3AccessKeyKey,
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: s3.CredentialsSecret,
+						},
+					},
+				},
+			}, {
+				Name: "S3_SECRET_KEY",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						Key: S3SecretKeyKey,
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: s3.CredentialsSecret,
+						},
+					},
+				},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{getBackupVolumeMount(backup)},
+	}
+}
+
+// JobStatus returns whether the job completed or failed, with the failure message.
+func JobStatus(job *batchv1.Job) (completed, failed bool, message string) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, false, ""
+		case batchv1.JobFailed:
+			return false, true, fmt.Sprintf("job %s failed: %s", job.GetName(), condition.Message)
+		}
+	}
+
+	return false, false, ""
+}
@@ -0,0 +1,191 @@
+package backup
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+var _ = Describe("Backup jobs", func() {
+	var harbor *goharborv1alpha1.Harbor
+	var backup *goharborv1alpha1.HarborBackup
+
+	BeforeEach(func() {
+		harbor = &goharborv1alpha1.Harbor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "harbor",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborSpec{
+				Components: goharborv1alpha1.HarborComponents{
+					Core: &goharborv1alpha1.CoreComponent{
+						DatabaseSecret: "core-database",
+					},
+					Registry: &goharborv1alpha1.RegistryComponent{
+						StorageSecret: "registry-storage",
+					},
+				},
+			},
+		}
+
+		backup = &goharborv1alpha1.HarborBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nightly",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborBackupSpec{
+				HarborName: "harbor",
+				Target: goharborv1alpha1.BackupTarget{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: "backups",
+					},
+				},
+			},
+		}
+	})
+
+	Describe("Items", func() {
+		It("Should skip storage without storage image", func() {
+			items := Items(harbor, backup.Spec.Images)
+
+			Expect(items).To(HaveLen(2))
+			Expect(items[0].Name).To(Equal(CoreDatabaseItem))
+			Expect(items[1].Name).To(Equal(CoreSecretKeyItem))
+			Expect(items[1].Secret).To(Equal("harbor-core"))
+		})
+
+		It("Should include storage with storage image", func() {
+			backup.Spec.Images.Storage = "storage-copier"
+
+			items := Items(harbor, backup.Spec.Images)
+
+			Expect(items).To(HaveLen(3))
+			Expect(items[2].Name).To(Equal(RegistryStorageItem))
+			Expect(items[2].Path()).To(Equal("registry-storage/"))
+		})
+	})
+
+	Context("With a persistent volume claim target", func() {
+		It("Should dump the database in the backup directory", func() {
+			job := GetJob(BackupDirection, backup.GetName(), backup, harbor, Items(harbor, backup.Spec.Images)[0])
+
+			Expect(job.GetName()).To(Equal("nightly-core-database"))
+
+			spec := job.Spec.Template.Spec
+			Expect(spec.InitContainers).To(BeEmpty())
+			Expect(spec.Containers).To(HaveLen(1))
+			Expect(spec.Containers[0].Command).To(Equal([]string{"pg_dump", "--format=custom", "--file=/backup/core-database.dump"}))
+			Expect(spec.Containers[0].VolumeMounts[0].SubPath).To(Equal("nightly"))
+			Expect(spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("backups"))
+		})
+	})
+
+	Context("With a S3 target", func() {
+		BeforeEach(func() {
+			backup.Spec.Target = goharborv1alpha1.BackupTarget{
+				S3: &goharborv1alpha1.S3BackupTarget{
+					Endpoint:          "https://s3.example.com",
+					Bucket:            "bucket",
+					CredentialsSecret: "credentials",
+				},
+			}
+		})
+
+		It("Should upload files after the dump", func() {
+			job := GetJob(BackupDirection, backup.GetName(), backup, harbor, Items(harbor, backup.Spec.Images)[0])
+
+			spec := job.Spec.Template.Spec
+			Expect(spec.InitContainers).To(HaveLen(1))
+			Expect(spec.InitContainers[0].Name).To(Equal(string(DatabaseItem)))
+			Expect(spec.Containers).To(HaveLen(1))
+			Expect(spec.Containers[0].Name).To(Equal(transferContainer))
+			Expect(spec.Containers[0].Command[2]).To(HaveSuffix("mc cp --recursive '/backup/' 'target/bucket/nightly/'"))
+			Expect(spec.Volumes[0].EmptyDir).ToNot(BeNil())
+		})
+
+		It("Should download files before the restoration", func() {
+			job := GetJob(RestoreDirection, "restore", backup, harbor, Items(harbor, backup.Spec.Images)[0])
+
+			Expect(job.GetName()).To(Equal("restore-core-database"))
+
+			spec := job.Spec.Template.Spec
+			Expect(spec.InitContainers).To(HaveLen(1))
+			Expect(spec.InitContainers[0].Command[2]).To(HaveSuffix("mc cp 'target/bucket/nightly/core-database.dump' '/backup/core-database.dump'"))
+			Expect(spec.Containers[0].Command[0]).To(Equal("pg_restore"))
+		})
+	})
+
+	Describe("Read-only rollout", func() {
+		var deployment *appsv1.Deployment
+
+		BeforeEach(func() {
+			harbor.SetGeneration(2)
+			harbor.Spec.ReadOnly = true
+			harbor.Status.ObservedGeneration = 2
+			harbor.Status.Conditions = []goharborv1alpha1.HarborCondition{
+				{Type: goharborv1alpha1.AppliedConditionType, Status: corev1.ConditionTrue},
+				{Type: goharborv1alpha1.ReadyConditionType, Status: corev1.ConditionTrue},
+			}
+
+			replicas := int32(1)
+			deployment = &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       harbor.NormalizeComponentName(goharborv1alpha1.CoreName),
+					Namespace:  "ns",
+					Generation: 3,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: &replicas,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{goharborv1alpha1.ReadOnlyAnnotation: "true"},
+						},
+					},
+				},
+				Status: appsv1.DeploymentStatus{
+					ObservedGeneration: 3,
+					Replicas:           1,
+					UpdatedReplicas:    1,
+					AvailableReplicas:  1,
+				},
+			}
+		})
+
+		It("Should wait for the Harbor to apply its generation", func() {
+			c := fake.NewFakeClient(deployment)
+
+			harbor.Status.ObservedGeneration = 1
+			Expect(IsHarborRolledOut(context.TODO(), c, harbor)).To(BeFalse())
+
+			harbor.Status.ObservedGeneration = 2
+			Expect(IsHarborRolledOut(context.TODO(), c, harbor)).To(BeTrue())
+
+			harbor.Status.Conditions[0].Status = corev1.ConditionFalse
+			Expect(IsHarborRolledOut(context.TODO(), c, harbor)).To(BeFalse())
+		})
+
+		It("Should wait for core pods to run in read-only mode", func() {
+			deployment.Spec.Template.Annotations[goharborv1alpha1.ReadOnlyAnnotation] = "false"
+			Expect(IsHarborRolledOut(context.TODO(), fake.NewFakeClient(deployment), harbor)).To(BeFalse())
+
+			deployment.Spec.Template.Annotations[goharborv1alpha1.ReadOnlyAnnotation] = "true"
+			deployment.Status.UpdatedReplicas = 0
+			Expect(IsHarborRolledOut(context.TODO(), fake.NewFakeClient(deployment), harbor)).To(BeFalse())
+
+			deployment.Status.UpdatedReplicas = 1
+			Expect(IsHarborRolledOut(context.TODO(), fake.NewFakeClient(deployment), harbor)).To(BeTrue())
+		})
+
+		It("Should wait for the core deployment to exist", func() {
+			Expect(IsHarborRolledOut(context.TODO(), fake.NewFakeClient(), harbor)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,147 @@
+package backup
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/conditions"
+	"github.com/goharbor/harbor-operator/pkg/rollout"
+)
+
+// Progress is the state of the jobs of a backup or a restoration.
+type Progress struct {
+	Total     int
+	Completed int
+	Failures  []string
+}
+
+func (p *Progress) IsCompleted() bool {
+	return p.Completed == p.Total
+}
+
+func (p *Progress) IsFailed() bool {
+	return len(p.Failures) > 0
+}
+
+func (p *Progress) FailureMessage() string {
+	return strings.Join(p.Failures, ", ")
+}
+
+// RunJobs creates missing jobs, owned by owner if not nil, and returns their progress.
+func RunJobs(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner metav1.Object, jobs []*batchv1.Job) (*Progress, error) {
+	progress := &Progress{
+		Total: len(jobs),
+	}
+
+	for _, job := range jobs {
+		current := &batchv1.Job{}
+
+		err := c.Get(ctx, types.NamespacedName{Namespace: job.GetNamespace(), Name: job.GetName()}, current)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "cannot get job %s", job.GetName())
+			}
+
+			if owner != nil {
+				err = controllerutil.SetControllerReference(owner, job, scheme)
+				if err != nil {
+					return nil, errors.Wrapf(err, "cannot set controller reference for job %s", job.GetName())
+				}
+			}
+
+			err = c.Create(ctx, job)
+			if err != nil && !apierrors.IsAlreadyExists(err) {
+				return nil, errors.Wrapf(err, "cannot create job %s", job.GetName())
+			}
+
+			continue
+		}
+
+		completed, failed, message := JobStatus(current)
+
+		switch {
+		case completed:
+			progress.Completed++
+		case failed:
+			progress.Failures = append(progress.Failures, message)
+		}
+	}
+
+	return progress, nil
+}
+
+// SetHarborReadOnly switches the read-only mode of the Harbor.
+func SetHarborReadOnly(ctx context.Context, c client.Client, harbor *goharborv1alpha1.Harbor, readOnly bool) error {
+	if harbor.Spec.ReadOnly == readOnly {
+		return nil
+	}
+
+	patch := client.MergeFrom(harbor.DeepCopy())
+
+	harbor.Spec.ReadOnly = readOnly
+
+	err := c.Patch(ctx, harbor, patch)
+
+	return errors.Wrapf(err, "cannot set read-only to %v", readOnly)
+}
+
+// IsHarborRolledOut returns whether the Harbor applied its current generation and core runs in the desired read-only mode.
+// The Harbor status is not enough: it is ready as soon as the core deployment is updated,
+// while pods started before SetHarborReadOnly may still serve writes.
+func IsHarborRolledOut(ctx context.Context, c client.Client, harbor *goharborv1alpha1.Harbor) (bool, error) {
+	if harbor.Status.ObservedGeneration != harbor.GetGeneration() ||
+		!conditions.IsTrue(harbor.Status.Conditions, goharborv1alpha1.AppliedConditionType) ||
+		!conditions.IsTrue(harbor.Status.Conditions, goharborv1alpha1.ReadyConditionType) {
+		return false, nil
+	}
+
+	deployment := &appsv1.Deployment{}
+
+	err := c.Get(ctx, types.NamespacedName{
+		Namespace: harbor.GetNamespace(),
+		Name:      harbor.NormalizeComponentName(goharborv1alpha1.CoreName),
+	}, deployment)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, errors.Wrap(err, "cannot get core deployment")
+	}
+
+	if deployment.Spec.Template.GetAnnotations()[goharborv1alpha1.ReadOnlyAnnotation] != strconv.FormatBool(harbor.Spec.ReadOnly) {
+		return false, nil
+	}
+
+	return rollout.IsComplete(deployment), nil
+}
+
+// SecretKeySecretName returns the name of the secret holding the core secret key of the backup.
+func SecretKeySecretName(backup *goharborv1alpha1.HarborBackup) string {
+	return backup.GetName() + "-" + CoreSecretKeyItem
+}
+
+// GetCoreSecret returns the generated core secret of the Harbor, containing the secret key.
+func GetCoreSecret(ctx context.Context, c client.Client, harbor *goharborv1alpha1.Harbor) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+
+	err := c.Get(ctx, types.NamespacedName{
+		Namespace: harbor.GetNamespace(),
+		Name:      harbor.NormalizeComponentName(goharborv1alpha1.CoreName),
+	}, secret)
+
+	return secret, errors.Wrap(err, "cannot get core secret")
+}
@@ -0,0 +1,157 @@
+package certificates
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultKeySize = 4096
+
+	// Certificates are renewed when less than a third of their lifetime remains
+	renewalRatio = 3
+
+	serialNumberBits = 128
+	organization     = "Harbor Operator"
+)
+
+// KeyPair is a certificate with its private key.
+type KeyPair struct {
+	Certificate *x509.Certificate
+	Key         *rsa.PrivateKey
+}
+
+// NewCA returns a self-signed certificate authority.
+func NewCA(commonName string, keySize int, duration time.Duration) (*KeyPair, error) {
+	template := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{organization},
+		},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	return newKeyPair(template, nil, keySize, duration)
+}
+
+// Issue returns a certificate signed by the certificate authority.
+func (ca *KeyPair) Issue(commonName string, dnsNames []string, keySize int, duration time.Duration) (*KeyPair, error) {
+	template := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{organization},
+		},
+		DNSNames:    dnsNames,
+		KeyUsage:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	return newKeyPair(template, ca, keySize, duration)
+}
+
+func newKeyPair(template *x509.Certificate, issuer *KeyPair, keySize int, duration time.Duration) (*KeyPair, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot generate key")
+	}
+
+	template.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialNumberBits))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot generate serial number")
+	}
+
+	now := time.Now()
+	template.NotBefore = now.Add(-time.Minute)
+	template.NotAfter = now.Add(duration)
+
+	parent, signer := template, key
+	if issuer != nil {
+		parent, signer = issuer.Certificate, issuer.Key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create certificate")
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse certificate")
+	}
+
+	return &KeyPair{
+		Certificate: certificate,
+		Key:         key,
+	}, nil
+}
+
+// Parse returns the key pair encoded in PEM, the key being PKCS1 encoded.
+func Parse(certificatePEM, keyPEM []byte) (*KeyPair, error) {
+	block, _ := pem.Decode(certificatePEM)
+	if block == nil {
+		return nil, errors.New("no certificate found")
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse certificate")
+	}
+
+	block, _ = pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no key found")
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse key")
+	}
+
+	return &KeyPair{
+		Certificate: certificate,
+		Key:         key,
+	}, nil
+}
+
+// CertificatePEM returns the PEM encoded certificate.
+func (k *KeyPair) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: k.Certificate.Raw,
+	})
+}
+
+// KeyPEM returns the PEM encoded key.
+// Harbor expects PKCS1 keys.
+func (k *KeyPair) KeyPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(k.Key),
+	})
+}
+
+// RenewalTime returns when less than a third of the certificate lifetime remains.
+func (k *KeyPair) RenewalTime() time.Time {
+	lifetime := k.Certificate.NotAfter.Sub(k.Certificate.NotBefore)
+
+	return k.Certificate.NotAfter.Add(-lifetime / renewalRatio)
+}
+
+// NeedsRenewal returns whether the certificate should be renewed.
+func (k *KeyPair) NeedsRenewal(now time.Time) bool {
+	return !now.Before(k.RenewalTime())
+}
+
+// IsIssuedBy returns whether the certificate is signed by the certificate authority.
+func (k *KeyPair) IsIssuedBy(ca *KeyPair) bool {
+	return k.Certificate.CheckSignatureFrom(ca.Certificate) == nil
+}
@@ -0,0 +1,82 @@
+package conditions
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+// Get returns the condition of the given type. Returns an Unknown condition if not found.
+func Get(conditions []goharborv1alpha1.HarborCondition, conditionType goharborv1alpha1.HarborConditionType) goharborv1alpha1.HarborCondition {
+	for _, condition := range conditions {
+		if condition.Type == conditionType {
+			return condition
+		}
+	}
+
+	return goharborv1alpha1.HarborCondition{
+		Type:   conditionType,
+		Status: corev1.ConditionUnknown,
+	}
+}
+
+// GetStatus returns the status of the condition of the given type.
+func GetStatus(conditions []goharborv1alpha1.HarborCondition, conditionType goharborv1alpha1.HarborConditionType) corev1.ConditionStatus {
+	return Get(conditions, conditionType).Status
+}
+
+// IsTrue returns whether the condition of the given type is True.
+func IsTrue(conditions []goharborv1alpha1.HarborCondition, conditionType goharborv1alpha1.HarborConditionType) bool {
+	return GetStatus(conditions, conditionType) == corev1.ConditionTrue
+}
+
+// Update sets the condition of the given type in the list, with an optional reason and message.
+// It returns the updated list and whether the status of the condition changed.
+func Update(conditions []goharborv1alpha1.HarborCondition, conditionType goharborv1alpha1.HarborConditionType, status corev1.ConditionStatus, reasons ...string) ([]goharborv1alpha1.HarborCondition, bool, error) {
+	var reason, message string
+
+	switch len(reasons) {
+	case 0: // nolint:mnd
+	case 1: // nolint:mnd
+		reason = reasons[0]
+	case 2: // nolint:mnd
+		reason = reasons[0]
+		message = reasons[1]
+	default:
+		return conditions, false, errors.Errorf("expecting reason and message, got %d parameters", len(reasons))
+	}
+
+	now := metav1.Now()
+
+	for i, condition := range conditions {
+		if condition.Type == conditionType {
+			now.DeepCopyInto(&condition.LastUpdateTime)
+
+			transition := condition.LastTransitionTime.IsZero() || condition.Status != status
+			if transition {
+				now.DeepCopyInto(&condition.LastTransitionTime)
+			}
+
+			condition.Status = status
+			condition.Reason = reason
+			condition.Message = message
+
+			conditions[i] = condition
+
+			return conditions, transition, nil
+		}
+	}
+
+	condition := goharborv1alpha1.HarborCondition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+	now.DeepCopyInto(&condition.LastUpdateTime)
+	now.DeepCopyInto(&condition.LastTransitionTime)
+
+	return append(conditions, condition), true, nil
+}
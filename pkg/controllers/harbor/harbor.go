@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/goharbor/harbor-operator/controllers/harbor"
+	"github.com/goharbor/harbor-operator/pkg/scheme"
 )
 
 const (
@@ -80,10 +81,16 @@ func GetConfig() (*harbor.Config, error) {
 		return nil, errors.Wrap(err, "fail to get harbor class configuration")
 	}
 
+	certManager, err := scheme.IsCertManagerEnabled()
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to get cert-manager configuration")
+	}
+
 	return &harbor.Config{
 		ConcurrentReconciles: concurrentReconciles,
 		WatchChildren:        watchChildren,
 		ClassName:            className,
+		CertManager:          certManager,
 	}, nil
 }
 
@@ -0,0 +1,53 @@
+package harborconfiguration
+
+import (
+	"context"
+
+	"github.com/ovh/configstore"
+	"github.com/pkg/errors"
+
+	"github.com/goharbor/harbor-operator/controllers/harborconfiguration"
+)
+
+const (
+	ConfigPrefix      = "harborconfiguration-controller"
+	ReconciliationKey = ConfigPrefix + "-max-reconcile"
+)
+
+const (
+	DefaultConcurrentReconcile = 1
+)
+
+func getConcurrentConfiguration() (int, error) {
+	concurrentReconciles, err := configstore.Filter().GetItemValueInt(ReconciliationKey)
+	if err != nil {
+		_, ok := err.(configstore.ErrItemNotFound)
+		if !ok {
+			return 0, errors.Wrapf(err, "key %s", ReconciliationKey)
+		}
+
+		concurrentReconciles = DefaultConcurrentReconcile
+	}
+
+	return int(concurrentReconciles), nil
+}
+
+func GetConfig() (*harborconfiguration.Config, error) {
+	concurrentReconciles, err := getConcurrentConfiguration()
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to get concurrent reconciles configuration")
+	}
+
+	return &harborconfiguration.Config{
+		ConcurrentReconciles: concurrentReconciles,
+	}, nil
+}
+
+func New(ctx context.Context, name, version string) (*harborconfiguration.Reconciler, error) {
+	config, err := GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get configuration")
+	}
+
+	return harborconfiguration.New(ctx, name, version, config)
+}
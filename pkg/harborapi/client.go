@@ -0,0 +1,316 @@
+package harborapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	nettracing "github.com/opentracing-contrib/go-stdlib/nethttp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+const (
+	AdminUsername = "admin"
+	APIPath       = "/api"
+
+	DefaultTimeout = 30 * time.Second
+
+	// internalTLSCAKey is the key of the CA certificate in internal TLS secrets
+	internalTLSCAKey = "ca.crt"
+)
+
+// Error is returned when Harbor API responds with an unexpected status code.
+type Error struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	apiErr, ok := errors.Cause(err).(*Error)
+	return ok && apiErr.StatusCode == statusCode
+}
+
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+// Client calls Harbor core API with basic authentication.
+type Client struct {
+	BaseURL   *url.URL
+	Username  string
+	Password  string
+	UserAgent string
+
+	HTTPClient *http.Client
+}
+
+// UserAgent returns the user agent identifying the operator in Harbor logs.
+func UserAgent(name, version string) string {
+	return fmt.Sprintf("%s(%s)", name, version)
+}
+
+// GetURL returns the URL used by the operator to reach Harbor core of the given Harbor.
+// Unlike the health, read through the apiserver proxy, the core service is called directly:
+// the apiserver proxy does not forward the Authorization header required by the API.
+// In an istio mesh, core only accepts mutual TLS from the sidecars, so it is called through the public url served by the gateways.
+func GetURL(harbor *goharborv1alpha1.Harbor) (*url.URL, error) {
+	if harbor.IsServiceMeshEnabled() {
+		return getMeshURL(harbor)
+	}
+
+	scheme := "http"
+	if harbor.IsInternalTLSEnabled() {
+		scheme = "https"
+	}
+
+	return &url.URL{
+		Scheme: scheme,
+		Host:   fmt.Sprintf("%s.%s.svc", harbor.NormalizeComponentName(goharborv1alpha1.CoreName), harbor.GetNamespace()),
+	}, nil
+}
+
+func getMeshURL(harbor *goharborv1alpha1.Harbor) (*url.URL, error) {
+	if harbor.GetExposeType() == goharborv1alpha1.ExposeTypeIngress && (harbor.Spec.Expose == nil || harbor.Spec.Expose.Istio == nil || len(harbor.Spec.Expose.Istio.Gateways) == 0) {
+		return nil, errors.Errorf("core cannot be reached outside of the %s mesh: virtual services are not bound to any gateway, set spec.expose.istio.gateways", harbor.Spec.ServiceMesh)
+	}
+
+	u, err := url.Parse(harbor.Spec.PublicURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse public url")
+	}
+
+	if u.Host == "" {
+		return nil, errors.Errorf("public url %s has no host", harbor.Spec.PublicURL)
+	}
+
+	return &url.URL{
+		Scheme: u.Scheme,
+		Host:   u.Host,
+	}, nil
+}
+
+// NewHTTPClient returns the HTTP client calling Harbor core of the given Harbor.
+// With internal TLS, the CA of the certificate of core, read from its secret, is trusted,
+// unless core is called through the public url in a service mesh.
+func NewHTTPClient(ctx context.Context, c client.Client, harbor *goharborv1alpha1.Harbor) (*http.Client, error) {
+	var transport http.RoundTripper = http.DefaultTransport
+
+	if harbor.IsInternalTLSEnabled() && !harbor.IsServiceMeshEnabled() {
+		secretName := harbor.InternalTLSSecretName(goharborv1alpha1.CoreName)
+		secret := &corev1.Secret{}
+
+		err := c.Get(ctx, types.NamespacedName{Namespace: harbor.GetNamespace(), Name: secretName}, secret)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot get internal TLS certificate of core")
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(secret.Data[internalTLSCAKey]) {
+			return nil, errors.Errorf("no certificate authority found in key %s of secret %s", internalTLSCAKey, secretName)
+		}
+
+		transport = &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			TLSClientConfig:     &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+			TLSHandshakeTimeout: DefaultTimeout,
+		}
+	}
+
+	return &http.Client{
+		Timeout:   DefaultTimeout,
+		Transport: &nettracing.Transport{RoundTripper: transport},
+	}, nil
+}
+
+// New returns a client for the given Harbor, authenticated as admin.
+// The password currently set in Harbor is used, from AdminPasswordSecret until it is first rotated.
+func New(ctx context.Context, c client.Client, harbor *goharborv1alpha1.Harbor, userAgent string) (*Client, error) {
+	secretName := harbor.AppliedAdminPasswordSecretName()
+	secret := &corev1.Secret{}
+
+	err := c.Get(ctx, types.NamespacedName{Namespace: harbor.GetNamespace(), Name: secretName}, secret)
+	if apierrors.IsNotFound(err) {
+		secretName = harbor.Spec.AdminPasswordSecret
+		err = c.Get(ctx, types.NamespacedName{Namespace: harbor.GetNamespace(), Name: secretName}, secret)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get admin password")
+	}
+
+	password, ok := secret.Data[goharborv1alpha1.HarborAdminPasswordKey]
+	if !ok {
+		return nil, errors.Errorf("key %s not found in secret %s", goharborv1alpha1.HarborAdminPasswordKey, secretName)
+	}
+
+	baseURL, err := GetURL(harbor)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := NewHTTPClient(ctx, c, harbor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		BaseURL:    baseURL,
+		Username:   AdminUsername,
+		Password:   string(password),
+		UserAgent:  userAgent,
+		HTTPClient: httpClient,
+	}, nil
+}
+
+// NewFromName returns a client for the Harbor with the given name, nil if the Harbor does not exist.
+func NewFromName(ctx context.Context, c client.Client, namespace, name, userAgent string) (*Client, *goharborv1alpha1.Harbor, error) {
+	harbor := &goharborv1alpha1.Harbor{}
+
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, harbor)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+
+		return nil, nil, errors.Wrap(err, "cannot get harbor")
+	}
+
+	api, err := New(ctx, c, harbor, userAgent)
+
+	return api, harbor, err
+}
+
+// Do calls the API path with the given method.
+// body is JSON encoded if not nil, the response is decoded in result if not nil.
+func (c *Client) Do(ctx context.Context, method, path string, body, result interface{}) (*http.Response, error) {
+	res, data, err := c.do(ctx, method, path, body)
+	if err != nil {
+		return res, err
+	}
+
+	if result != nil && len(data) > 0 {
+		err = json.Unmarshal(data, result)
+		if err != nil {
+			return res, errors.Wrap(err, "cannot decode response")
+		}
+	}
+
+	return res, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body interface{}) (*http.Response, []byte, error) {
+	var reader io.Reader
+
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "cannot encode body")
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	u := *c.BaseURL
+	parts := strings.SplitN(path, "?", 2)
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + APIPath + parts[0]
+	if len(parts) > 1 {
+		u.RawQuery = parts[1]
+	}
+
+	req, err := http.NewRequest(method, u.String(), reader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "cannot create request")
+	}
+
+	req = req.WithContext(ctx)
+	req.SetBasicAuth(c.Username, c.Password)
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "%s %s", method, path)
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res, nil, errors.Wrap(err, "cannot read response")
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return res, data, errors.Wrapf(&Error{StatusCode: res.StatusCode, Body: string(data)}, "%s %s", method, path)
+	}
+
+	return res, data, nil
+}
+
+// idFromLocation returns the ID of a resource created by Harbor, from the Location header of the response.
+func idFromLocation(location string) (int64, error) {
+	id, err := strconv.ParseInt(path.Base(location), 10, 64)
+
+	return id, errors.Wrapf(err, "cannot parse location %s", location)
+}
+
+func (c *Client) Get(ctx context.Context, path string, result interface{}) error {
+	_, err := c.Do(ctx, http.MethodGet, path, nil, result)
+	return err
+}
+
+func (c *Client) Post(ctx context.Context, path string, body interface{}) (*http.Response, error) {
+	return c.Do(ctx, http.MethodPost, path, body, nil)
+}
+
+func (c *Client) Put(ctx context.Context, path string, body interface{}) error {
+	_, err := c.Do(ctx, http.MethodPut, path, body, nil)
+	return err
+}
+
+func (c *Client) Delete(ctx context.Context, path string) error {
+	_, err := c.Do(ctx, http.MethodDelete, path, nil, nil)
+	return err
+}
+
+// GetRaw calls the API path and returns the raw response body, e.g. for logs.
+func (c *Client) GetRaw(ctx context.Context, path string) (string, error) {
+	_, data, err := c.do(ctx, http.MethodGet, path, nil)
+
+	return string(data), err
+}
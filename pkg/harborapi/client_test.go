@@ -0,0 +1,69 @@
+package harborapi
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+var _ = Describe("URL", func() {
+	var harbor *goharborv1alpha1.Harbor
+
+	BeforeEach(func() {
+		harbor = &goharborv1alpha1.Harbor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "harbor",
+				Namespace: "ns",
+			},
+			Spec: goharborv1alpha1.HarborSpec{
+				PublicURL: "https://harbor.example.com/",
+			},
+		}
+	})
+
+	It("Should call the core service", func() {
+		u, err := GetURL(harbor)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u.String()).To(Equal("http://harbor-core.ns.svc"))
+	})
+
+	Context("In an istio mesh", func() {
+		BeforeEach(func() {
+			harbor.Spec.ServiceMesh = goharborv1alpha1.ServiceMeshIstio
+		})
+
+		It("Should call the public url through the gateways", func() {
+			harbor.Spec.Expose = &goharborv1alpha1.ExposeSpec{
+				Istio: &goharborv1alpha1.ExposeIstioSpec{Gateways: []string{"istio-system/public"}},
+			}
+			harbor.Spec.InternalTLS = &goharborv1alpha1.InternalTLSSpec{Enabled: true}
+
+			u, err := GetURL(harbor)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(u.String()).To(Equal("https://harbor.example.com"))
+		})
+
+		It("Should call the public url served by the proxy", func() {
+			harbor.Spec.Expose = &goharborv1alpha1.ExposeSpec{Type: goharborv1alpha1.ExposeTypeLoadBalancer}
+
+			u, err := GetURL(harbor)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(u.String()).To(Equal("https://harbor.example.com"))
+		})
+
+		It("Should fail without gateway", func() {
+			_, err := GetURL(harbor)
+			Expect(err).To(MatchError(ContainSubstring("set spec.expose.istio.gateways")))
+		})
+
+		It("Should fail without host in the public url", func() {
+			harbor.Spec.PublicURL = "harbor.example.com"
+			harbor.Spec.Expose = &goharborv1alpha1.ExposeSpec{Type: goharborv1alpha1.ExposeTypeLoadBalancer}
+
+			_, err := GetURL(harbor)
+			Expect(err).To(MatchError(ContainSubstring("has no host")))
+		})
+	})
+})
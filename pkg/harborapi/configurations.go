@@ -0,0 +1,69 @@
+package harborapi
+
+import (
+	"context"
+)
+
+const (
+	configurationsPath = "/configurations"
+	cveWhitelistPath   = "/system/CVEWhitelist"
+)
+
+const (
+	ConfigEmailHost                  = "email_host"
+	ConfigEmailPort                  = "email_port"
+	ConfigEmailUsername              = "email_username"
+	ConfigEmailPassword              = "email_password"
+	ConfigEmailFrom                  = "email_from"
+	ConfigEmailSSL                   = "email_ssl"
+	ConfigEmailInsecure              = "email_insecure"
+	ConfigEmailIdentity              = "email_identity"
+	ConfigProjectCreationRestriction = "project_creation_restriction"
+	ConfigRobotTokenDuration         = "robot_token_duration"
+	ConfigSelfRegistration           = "self_registration"
+)
+
+// ConfigurationValue is a configuration item as returned by Harbor.
+// Passwords are never returned.
+type ConfigurationValue struct {
+	Value    interface{} `json:"value"`
+	Editable bool        `json:"editable"`
+}
+
+type CVEWhitelistItem struct {
+	CVEID string `json:"cve_id"`
+}
+
+// CVEWhitelist is the list of CVEs ignored by vulnerability scans.
+// The system list has project ID 0.
+type CVEWhitelist struct {
+	ID        int64              `json:"id,omitempty"`
+	ProjectID int64              `json:"project_id"`
+	ExpiresAt *int64             `json:"expires_at"`
+	Items     []CVEWhitelistItem `json:"items"`
+}
+
+func (c *Client) GetConfigurations(ctx context.Context) (map[string]ConfigurationValue, error) {
+	configurations := map[string]ConfigurationValue{}
+
+	err := c.Get(ctx, configurationsPath, &configurations)
+
+	return configurations, err
+}
+
+// UpdateConfigurations updates the given configuration items, the others are kept.
+func (c *Client) UpdateConfigurations(ctx context.Context, values map[string]interface{}) error {
+	return c.Put(ctx, configurationsPath, values)
+}
+
+func (c *Client) GetSystemCVEWhitelist(ctx context.Context) (*CVEWhitelist, error) {
+	whitelist := &CVEWhitelist{}
+
+	err := c.Get(ctx, cveWhitelistPath, whitelist)
+
+	return whitelist, err
+}
+
+func (c *Client) UpdateSystemCVEWhitelist(ctx context.Context, whitelist *CVEWhitelist) error {
+	return c.Put(ctx, cveWhitelistPath, whitelist)
+}
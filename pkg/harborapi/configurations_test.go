@@ -0,0 +1,66 @@
+package harborapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Configurations", func() {
+	var server *httptest.Server
+	var api *Client
+	var update map[string]interface{}
+
+	BeforeEach(func() {
+		update = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch {
+			case req.URL.Path == "/api/configurations" && req.Method == http.MethodGet:
+				_, _ = w.Write([]byte(`{"self_registration":{"value":true,"editable":true},"robot_token_duration":{"value":43200,"editable":true}}`))
+			case req.URL.Path == "/api/configurations" && req.Method == http.MethodPut:
+				Expect(json.NewDecoder(req.Body).Decode(&update)).To(Succeed())
+			case req.URL.Path == "/api/system/CVEWhitelist" && req.Method == http.MethodGet:
+				_, _ = w.Write([]byte(`{"id":1,"project_id":0,"expires_at":null,"items":[{"cve_id":"CVE-2019-10164"}]}`))
+			default:
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}))
+
+		u, err := url.Parse(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+
+		api = &Client{
+			BaseURL:    u,
+			Username:   AdminUsername,
+			HTTPClient: server.Client(),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should get configuration items", func() {
+		configurations, err := api.GetConfigurations(context.TODO())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configurations).To(HaveKeyWithValue(ConfigSelfRegistration, ConfigurationValue{Value: true, Editable: true}))
+	})
+
+	It("Should only send updated items", func() {
+		Expect(api.UpdateConfigurations(context.TODO(), map[string]interface{}{ConfigSelfRegistration: false})).To(Succeed())
+		Expect(update).To(Equal(map[string]interface{}{ConfigSelfRegistration: false}))
+	})
+
+	It("Should get the system CVE allowlist", func() {
+		whitelist, err := api.GetSystemCVEWhitelist(context.TODO())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(whitelist.ExpiresAt).To(BeNil())
+		Expect(whitelist.Items).To(ConsistOf(CVEWhitelistItem{CVEID: "CVE-2019-10164"}))
+	})
+})
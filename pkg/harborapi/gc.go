@@ -0,0 +1,101 @@
+package harborapi
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	GCStatusPending  = "pending"
+	GCStatusRunning  = "running"
+	GCStatusFinished = "finished"
+	GCStatusError    = "error"
+	GCStatusStopped  = "stopped"
+)
+
+const (
+	gcPath         = "/system/gc"
+	gcSchedulePath = gcPath + "/schedule"
+)
+
+// GCSchedule is the body of a garbage collection trigger.
+type GCSchedule struct {
+	Schedule   GCScheduleType         `json:"schedule"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+type GCScheduleType struct {
+	Type string `json:"type"`
+}
+
+// GCJob is a garbage collection execution.
+type GCJob struct {
+	ID           int64     `json:"id"`
+	Status       string    `json:"job_status"`
+	CreationTime time.Time `json:"creation_time"`
+	UpdateTime   time.Time `json:"update_time"`
+}
+
+// IsDone returns whether the execution is over, successfully or not.
+func (j *GCJob) IsDone() bool {
+	switch j.Status {
+	case GCStatusFinished, GCStatusError, GCStatusStopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// TriggerGC runs a garbage collection immediately and returns the ID of the execution.
+// deleteUntagged is supported since Harbor 2.0, previous versions ignore it.
+func (c *Client) TriggerGC(ctx context.Context, deleteUntagged bool) (int64, error) {
+	res, err := c.Post(ctx, gcSchedulePath, &GCSchedule{
+		Schedule: GCScheduleType{
+			Type: "Manual",
+		},
+		Parameters: map[string]interface{}{
+			"delete_untagged": deleteUntagged,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Harbor redirects to the created execution
+	return idFromLocation(res.Header.Get("Location"))
+}
+
+// GetGC returns the garbage collection execution with the given ID.
+func (c *Client) GetGC(ctx context.Context, id int64) (*GCJob, error) {
+	job := &GCJob{}
+
+	err := c.Get(ctx, fmt.Sprintf("%s/%d", gcPath, id), job)
+
+	return job, err
+}
+
+// GetGCLog returns the log of the garbage collection execution with the given ID.
+func (c *Client) GetGCLog(ctx context.Context, id int64) (string, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("%s/%d/log", gcPath, id))
+}
+
+var freedSpaceRegexp = regexp.MustCompile(`frees up (\d+) MB`)
+
+// ParseFreedBytes returns the space freed according to a garbage collection log.
+// ok is false if the log does not report it.
+func ParseFreedBytes(log string) (bytes int64, ok bool) {
+	match := freedSpaceRegexp.FindStringSubmatch(log)
+	if match == nil {
+		return 0, false
+	}
+
+	mb, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return mb * 1024 * 1024, true
+}
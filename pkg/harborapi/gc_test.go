@@ -0,0 +1,105 @@
+package harborapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Garbage collection", func() {
+	var server *httptest.Server
+	var api *Client
+	var requests []*http.Request
+	var bodies []map[string]interface{}
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requests = append(requests, req)
+
+			if req.Body != nil {
+				body := map[string]interface{}{}
+				_ = json.NewDecoder(req.Body).Decode(&body)
+				bodies = append(bodies, body)
+			}
+
+			switch req.URL.Path {
+			case "/api/system/gc/schedule":
+				w.Header().Set("Location", "/api/system/gc/schedule/12")
+				w.WriteHeader(http.StatusCreated)
+			case "/api/system/gc/12":
+				_, _ = w.Write([]byte(`{"id":12,"job_status":"finished"}`))
+			case "/api/system/gc/12/log":
+				_, _ = w.Write([]byte("2020-01-04T03:04:10Z [INFO] The GC job actual frees up 34 MB space."))
+			default:
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}))
+
+		u, err := url.Parse(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+
+		api = &Client{
+			BaseURL:    u,
+			Username:   AdminUsername,
+			Password:   "Harbor12345",
+			HTTPClient: server.Client(),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should trigger a manual garbage collection", func() {
+		id, err := api.TriggerGC(context.TODO(), true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(BeEquivalentTo(12))
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Method).To(Equal(http.MethodPost))
+
+		username, password, ok := requests[0].BasicAuth()
+		Expect(ok).To(BeTrue())
+		Expect(username).To(Equal(AdminUsername))
+		Expect(password).To(Equal("Harbor12345"))
+
+		Expect(bodies[0]).To(HaveKeyWithValue("schedule", HaveKeyWithValue("type", "Manual")))
+		Expect(bodies[0]).To(HaveKeyWithValue("parameters", HaveKeyWithValue("delete_untagged", true)))
+	})
+
+	It("Should get the execution", func() {
+		job, err := api.GetGC(context.TODO(), 12)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.ID).To(BeEquivalentTo(12))
+		Expect(job.IsDone()).To(BeTrue())
+	})
+
+	It("Should return a not found error", func() {
+		_, err := api.GetGC(context.TODO(), 13)
+		Expect(err).To(HaveOccurred())
+		Expect(IsNotFound(err)).To(BeTrue())
+		Expect(IsConflict(err)).To(BeFalse())
+	})
+
+	It("Should parse the freed space from the log", func() {
+		log, err := api.GetGCLog(context.TODO(), 12)
+		Expect(err).ToNot(HaveOccurred())
+
+		freed, ok := ParseFreedBytes(log)
+		Expect(ok).To(BeTrue())
+		Expect(freed).To(BeEquivalentTo(34 * 1024 * 1024))
+	})
+
+	It("Should not parse the freed space if not reported", func() {
+		_, ok := ParseFreedBytes("level=info msg=\"blobs eligible for deletion\"")
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,40 @@
+package harborapitest
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+)
+
+const (
+	// AdminPassword is the password of the admin user of Harbors returned by NewHarbor.
+	AdminPassword = "Harbor12345"
+)
+
+// NewHarbor returns a Harbor named harbor in the given namespace, with its admin password in the admin-password secret.
+func NewHarbor(namespace string) *goharborv1alpha1.Harbor {
+	return &goharborv1alpha1.Harbor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "harbor",
+			Namespace: namespace,
+		},
+		Spec: goharborv1alpha1.HarborSpec{
+			PublicURL:           "https://harbor.example.com",
+			AdminPasswordSecret: "admin-password",
+		},
+	}
+}
+
+// NewAdminPasswordSecret returns the secret holding AdminPassword, as the AdminPasswordSecret of the Harbor.
+func NewAdminPasswordSecret(harbor *goharborv1alpha1.Harbor) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      harbor.Spec.AdminPasswordSecret,
+			Namespace: harbor.GetNamespace(),
+		},
+		Data: map[string][]byte{
+			goharborv1alpha1.HarborAdminPasswordKey: []byte(AdminPassword),
+		},
+	}
+}
@@ -0,0 +1,128 @@
+// Package harborapitest serves a fake Harbor API for tests of controllers calling Harbor.
+package harborapitest
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+)
+
+// Request is a request received by the server.
+type Request struct {
+	Method   string
+	Path     string
+	Query    string
+	Host     string
+	Username string
+	Password string
+	Body     []byte
+}
+
+// Server replies to requests with the handler registered for their method and path,
+// and with 404 to other requests.
+type Server struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	handlers map[string]http.HandlerFunc
+	requests []Request
+
+	defaultTransport http.RoundTripper
+}
+
+// NewServer starts a server and routes all requests of the default HTTP transport to it,
+// so clients returned by harborapi.New reach it whatever the Harbor is. Close restores the default transport.
+func NewServer() *Server {
+	s := &Server{
+		handlers:         map[string]http.HandlerFunc{},
+		defaultTransport: http.DefaultTransport,
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	dialer := &net.Dialer{}
+	http.DefaultTransport = &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, s.Listener.Addr().String())
+		},
+	}
+
+	return s
+}
+
+// Close shuts down the server and restores the default HTTP transport.
+func (s *Server) Close() {
+	http.DefaultTransport = s.defaultTransport
+
+	s.Server.Close()
+}
+
+// Handle registers the handler for the method and the API path, as passed to harborapi.Client.
+func (s *Server) Handle(method, path string, handler http.HandlerFunc) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.handlers[method+" "+path] = handler
+}
+
+// HandleJSON registers a handler replying with the status code and the JSON encoded body, if not nil.
+func (s *Server) HandleJSON(method, path string, statusCode int, body interface{}) {
+	s.Handle(method, path, func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+
+		if body != nil {
+			_ = json.NewEncoder(w).Encode(body)
+		}
+	})
+}
+
+// Requests returns the requests received for the method and the API path.
+func (s *Server) Requests(method, path string) []Request {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var requests []Request
+
+	for _, request := range s.requests {
+		if request.Method == method && request.Path == path {
+			requests = append(requests, request)
+		}
+	}
+
+	return requests
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	username, password, _ := req.BasicAuth()
+
+	request := Request{
+		Method:   req.Method,
+		Path:     strings.TrimPrefix(req.URL.Path, harborapi.APIPath),
+		Query:    req.URL.RawQuery,
+		Host:     req.Host,
+		Username: username,
+		Password: password,
+		Body:     body,
+	}
+
+	s.lock.Lock()
+	s.requests = append(s.requests, request)
+	handler, ok := s.handlers[request.Method+" "+request.Path]
+	s.lock.Unlock()
+
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	handler(w, req)
+}
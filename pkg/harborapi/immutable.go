@@ -0,0 +1,54 @@
+package harborapi
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	immutableTagRulesPath = "/immutabletagrules"
+
+	ImmutableAction   = "immutable"
+	ImmutableTemplate = "immutable_template"
+)
+
+type ImmutableTagRule struct {
+	ID             int64                 `json:"id,omitempty"`
+	ProjectID      int64                 `json:"project_id"`
+	Priority       int                   `json:"priority"`
+	Disabled       bool                  `json:"disabled"`
+	Action         string                `json:"action"`
+	Template       string                `json:"template"`
+	TagSelectors   []Selector            `json:"tag_selectors"`
+	ScopeSelectors map[string][]Selector `json:"scope_selectors"`
+}
+
+func immutableTagRulesPathFor(projectID int64) string {
+	return fmt.Sprintf("%s/%d%s", projectsPath, projectID, immutableTagRulesPath)
+}
+
+func (c *Client) ListImmutableTagRules(ctx context.Context, projectID int64) ([]ImmutableTagRule, error) {
+	var rules []ImmutableTagRule
+
+	err := c.Get(ctx, fmt.Sprintf("%s?page_size=%d", immutableTagRulesPathFor(projectID), maxPageSize), &rules)
+
+	return rules, err
+}
+
+// CreateImmutableTagRule creates the rule and returns its ID.
+func (c *Client) CreateImmutableTagRule(ctx context.Context, rule *ImmutableTagRule) (int64, error) {
+	res, err := c.Post(ctx, immutableTagRulesPathFor(rule.ProjectID), rule)
+	if err != nil {
+		return 0, err
+	}
+
+	return idFromLocation(res.Header.Get("Location"))
+}
+
+func (c *Client) UpdateImmutableTagRule(ctx context.Context, id int64, rule *ImmutableTagRule) error {
+	return c.Put(ctx, fmt.Sprintf("%s/%d", immutableTagRulesPathFor(rule.ProjectID), id), rule)
+}
+
+func (c *Client) DeleteImmutableTagRule(ctx context.Context, projectID, id int64) error {
+	return c.Delete(ctx, fmt.Sprintf("%s/%d", immutableTagRulesPathFor(projectID), id))
+}
@@ -0,0 +1,118 @@
+package harborapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// maxPageSize is the maximum number of items returned by list endpoints
+const maxPageSize = 100
+
+const (
+	projectsPath = "/projects"
+	quotasPath   = "/quotas"
+)
+
+const (
+	ProjectPublicKey            = "public"
+	ProjectAutoScanKey          = "auto_scan"
+	ProjectContentTrustKey      = "enable_content_trust"
+	ProjectPreventVulnerableKey = "prevent_vul"
+	ProjectSeverityKey          = "severity"
+)
+
+// UnlimitedQuota is the quota value without limit.
+const UnlimitedQuota int64 = -1
+
+// ProjectReq is the body of project creation and update.
+type ProjectReq struct {
+	Name         string            `json:"project_name,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	StorageLimit *int64            `json:"storage_limit,omitempty"`
+}
+
+type Project struct {
+	ID        int64             `json:"project_id"`
+	Name      string            `json:"name"`
+	Metadata  map[string]string `json:"metadata"`
+	RepoCount int64             `json:"repo_count"`
+}
+
+type Quota struct {
+	ID   int64            `json:"id"`
+	Hard map[string]int64 `json:"hard"`
+	Used map[string]int64 `json:"used"`
+}
+
+const (
+	QuotaStorageKey = "storage"
+)
+
+// GetProjectByName returns the project with the given name, nil if it does not exist.
+func (c *Client) GetProjectByName(ctx context.Context, name string) (*Project, error) {
+	var projects []Project
+
+	// name is a fuzzy filter
+	err := c.Get(ctx, fmt.Sprintf("%s?page_size=%d&name=%s", projectsPath, maxPageSize, url.QueryEscape(name)), &projects)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range projects {
+		if project.Name == name {
+			return &project, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (c *Client) GetProject(ctx context.Context, id int64) (*Project, error) {
+	project := &Project{}
+
+	err := c.Get(ctx, fmt.Sprintf("%s/%d", projectsPath, id), project)
+
+	return project, err
+}
+
+// CreateProject creates the project and returns its ID.
+func (c *Client) CreateProject(ctx context.Context, project *ProjectReq) (int64, error) {
+	res, err := c.Post(ctx, projectsPath, project)
+	if err != nil {
+		return 0, err
+	}
+
+	return idFromLocation(res.Header.Get("Location"))
+}
+
+func (c *Client) UpdateProject(ctx context.Context, id int64, project *ProjectReq) error {
+	return c.Put(ctx, fmt.Sprintf("%s/%d", projectsPath, id), project)
+}
+
+func (c *Client) DeleteProject(ctx context.Context, id int64) error {
+	return c.Delete(ctx, fmt.Sprintf("%s/%d", projectsPath, id))
+}
+
+// GetProjectQuota returns the quota of the project, nil if quotas are not enabled.
+func (c *Client) GetProjectQuota(ctx context.Context, projectID int64) (*Quota, error) {
+	var quotas []Quota
+
+	err := c.Get(ctx, fmt.Sprintf("%s?reference=project&reference_id=%d", quotasPath, projectID), &quotas)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(quotas) == 0 {
+		return nil, nil
+	}
+
+	return &quotas[0], nil
+}
+
+// UpdateQuota sets the hard limits of the quota. Every resource of the quota must be set.
+func (c *Client) UpdateQuota(ctx context.Context, id int64, hard map[string]int64) error {
+	return c.Put(ctx, fmt.Sprintf("%s/%d", quotasPath, id), map[string]interface{}{
+		"hard": hard,
+	})
+}
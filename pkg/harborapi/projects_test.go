@@ -0,0 +1,80 @@
+package harborapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Projects", func() {
+	var server *httptest.Server
+	var api *Client
+	var quotaUpdate map[string]interface{}
+
+	BeforeEach(func() {
+		quotaUpdate = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch {
+			case req.URL.Path == "/api/projects" && req.Method == http.MethodGet:
+				Expect(req.URL.Query().Get("name")).To(Equal("library"))
+				_, _ = w.Write([]byte(`[{"project_id":3,"name":"library-mirror"},{"project_id":1,"name":"library","repo_count":2}]`))
+			case req.URL.Path == "/api/projects" && req.Method == http.MethodPost:
+				w.Header().Set("Location", "/api/projects/4")
+				w.WriteHeader(http.StatusCreated)
+			case req.URL.Path == "/api/quotas":
+				Expect(req.URL.Query().Get("reference_id")).To(Equal("1"))
+				_, _ = w.Write([]byte(`[{"id":7,"hard":{"count":-1,"storage":-1},"used":{"count":2,"storage":1024}}]`))
+			case req.URL.Path == "/api/quotas/7" && req.Method == http.MethodPut:
+				Expect(json.NewDecoder(req.Body).Decode(&quotaUpdate)).To(Succeed())
+			default:
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}))
+
+		u, err := url.Parse(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+
+		api = &Client{
+			BaseURL:    u,
+			Username:   AdminUsername,
+			HTTPClient: server.Client(),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should find the project with the exact name", func() {
+		project, err := api.GetProjectByName(context.TODO(), "library")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project).ToNot(BeNil())
+		Expect(project.ID).To(BeEquivalentTo(1))
+		Expect(project.RepoCount).To(BeEquivalentTo(2))
+	})
+
+	It("Should return the ID of the created project", func() {
+		id, err := api.CreateProject(context.TODO(), &ProjectReq{Name: "ci"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(BeEquivalentTo(4))
+	})
+
+	It("Should update the quota", func() {
+		quota, err := api.GetProjectQuota(context.TODO(), 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(quota.ID).To(BeEquivalentTo(7))
+		Expect(quota.Used).To(HaveKeyWithValue(QuotaStorageKey, BeEquivalentTo(1024)))
+
+		quota.Hard[QuotaStorageKey] = 2048
+
+		Expect(api.UpdateQuota(context.TODO(), quota.ID, quota.Hard)).To(Succeed())
+		Expect(quotaUpdate).To(HaveKeyWithValue("hard", HaveKeyWithValue("storage", BeEquivalentTo(2048))))
+		Expect(quotaUpdate).To(HaveKeyWithValue("hard", HaveKeyWithValue("count", BeEquivalentTo(-1))))
+	})
+})
@@ -0,0 +1,223 @@
+package harborapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	registriesPath  = "/registries"
+	policiesPath    = "/replication/policies"
+	executionsPath  = "/replication/executions"
+	basicCredential = "basic"
+)
+
+const (
+	TriggerManual      = "manual"
+	TriggerScheduled   = "scheduled"
+	TriggerEventBased  = "event_based"
+	FilterName         = "name"
+	FilterTag          = "tag"
+	FilterLabel        = "label"
+	FilterResource     = "resource"
+	ExecutionSucceeded = "Succeed"
+	ExecutionFailed    = "Failed"
+	ExecutionStopped   = "Stopped"
+	ExecutionRunning   = "InProgress"
+)
+
+type RegistryCredential struct {
+	Type         string `json:"type,omitempty"`
+	AccessKey    string `json:"access_key,omitempty"`
+	AccessSecret string `json:"access_secret,omitempty"`
+}
+
+type Registry struct {
+	ID          int64               `json:"id,omitempty"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Type        string              `json:"type"`
+	URL         string              `json:"url"`
+	Credential  *RegistryCredential `json:"credential,omitempty"`
+	Insecure    bool                `json:"insecure"`
+	Status      string              `json:"status,omitempty"`
+}
+
+// RegistryUpdate is the body of registry update.
+type RegistryUpdate struct {
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	URL            string `json:"url"`
+	CredentialType string `json:"credential_type,omitempty"`
+	AccessKey      string `json:"access_key,omitempty"`
+	AccessSecret   string `json:"access_secret,omitempty"`
+	Insecure       bool   `json:"insecure"`
+}
+
+// NewBasicCredential returns the credential of a registry, nil without access key.
+func NewBasicCredential(accessKey, accessSecret string) *RegistryCredential {
+	if accessKey == "" {
+		return nil
+	}
+
+	return &RegistryCredential{
+		Type:         basicCredential,
+		AccessKey:    accessKey,
+		AccessSecret: accessSecret,
+	}
+}
+
+func (c *Client) GetRegistryByName(ctx context.Context, name string) (*Registry, error) {
+	var registries []Registry
+
+	err := c.Get(ctx, fmt.Sprintf("%s?name=%s", registriesPath, url.QueryEscape(name)), &registries)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, registry := range registries {
+		if registry.Name == name {
+			return &registry, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (c *Client) GetRegistry(ctx context.Context, id int64) (*Registry, error) {
+	registry := &Registry{}
+
+	err := c.Get(ctx, fmt.Sprintf("%s/%d", registriesPath, id), registry)
+
+	return registry, err
+}
+
+// CreateRegistry creates the registry and returns its ID.
+func (c *Client) CreateRegistry(ctx context.Context, registry *Registry) (int64, error) {
+	res, err := c.Post(ctx, registriesPath, registry)
+	if err != nil {
+		return 0, err
+	}
+
+	return idFromLocation(res.Header.Get("Location"))
+}
+
+func (c *Client) UpdateRegistry(ctx context.Context, id int64, registry *RegistryUpdate) error {
+	return c.Put(ctx, fmt.Sprintf("%s/%d", registriesPath, id), registry)
+}
+
+func (c *Client) DeleteRegistry(ctx context.Context, id int64) error {
+	return c.Delete(ctx, fmt.Sprintf("%s/%d", registriesPath, id))
+}
+
+// RegistryReference references a registry by its ID. A nil reference is the local Harbor.
+type RegistryReference struct {
+	ID int64 `json:"id"`
+}
+
+type ReplicationFilter struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+type ReplicationTrigger struct {
+	Type     string                      `json:"type"`
+	Settings *ReplicationTriggerSettings `json:"trigger_settings,omitempty"`
+}
+
+type ReplicationTriggerSettings struct {
+	Cron string `json:"cron,omitempty"`
+}
+
+type ReplicationPolicy struct {
+	ID                  int64               `json:"id,omitempty"`
+	Name                string              `json:"name"`
+	Description         string              `json:"description"`
+	SourceRegistry      *RegistryReference  `json:"src_registry"`
+	DestinationRegistry *RegistryReference  `json:"dest_registry"`
+	DestinationNS       string              `json:"dest_namespace"`
+	Trigger             *ReplicationTrigger `json:"trigger"`
+	Filters             []ReplicationFilter `json:"filters"`
+	Deletion            bool                `json:"deletion"`
+	Override            bool                `json:"override"`
+	Enabled             bool                `json:"enabled"`
+}
+
+type ReplicationExecution struct {
+	ID         int64  `json:"id"`
+	PolicyID   int64  `json:"policy_id"`
+	Status     string `json:"status"`
+	StatusText string `json:"status_text"`
+	Trigger    string `json:"trigger"`
+	Total      int64  `json:"total"`
+	Failed     int64  `json:"failed"`
+	Succeed    int64  `json:"succeed"`
+	InProgress int64  `json:"in_progress"`
+	Stopped    int64  `json:"stopped"`
+	StartTime  string `json:"start_time"`
+	EndTime    string `json:"end_time"`
+}
+
+func (c *Client) GetReplicationPolicyByName(ctx context.Context, name string) (*ReplicationPolicy, error) {
+	var policies []ReplicationPolicy
+
+	err := c.Get(ctx, fmt.Sprintf("%s?name=%s", policiesPath, url.QueryEscape(name)), &policies)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, policy := range policies {
+		if policy.Name == name {
+			return &policy, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (c *Client) GetReplicationPolicy(ctx context.Context, id int64) (*ReplicationPolicy, error) {
+	policy := &ReplicationPolicy{}
+
+	err := c.Get(ctx, fmt.Sprintf("%s/%d", policiesPath, id), policy)
+
+	return policy, err
+}
+
+// CreateReplicationPolicy creates the policy and returns its ID.
+func (c *Client) CreateReplicationPolicy(ctx context.Context, policy *ReplicationPolicy) (int64, error) {
+	res, err := c.Post(ctx, policiesPath, policy)
+	if err != nil {
+		return 0, err
+	}
+
+	return idFromLocation(res.Header.Get("Location"))
+}
+
+func (c *Client) UpdateReplicationPolicy(ctx context.Context, id int64, policy *ReplicationPolicy) error {
+	return c.Put(ctx, fmt.Sprintf("%s/%d", policiesPath, id), policy)
+}
+
+func (c *Client) DeleteReplicationPolicy(ctx context.Context, id int64) error {
+	return c.Delete(ctx, fmt.Sprintf("%s/%d", policiesPath, id))
+}
+
+// GetLastReplicationExecution returns the latest execution of the policy, nil if it never ran.
+func (c *Client) GetLastReplicationExecution(ctx context.Context, policyID int64) (*ReplicationExecution, error) {
+	var executions []ReplicationExecution
+
+	err := c.Get(ctx, fmt.Sprintf("%s?policy_id=%d&page_size=%d", executionsPath, policyID, maxPageSize), &executions)
+	if err != nil {
+		return nil, err
+	}
+
+	var last *ReplicationExecution
+
+	for i, execution := range executions {
+		if last == nil || execution.ID > last.ID {
+			last = &executions[i]
+		}
+	}
+
+	return last, nil
+}
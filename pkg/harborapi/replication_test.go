@@ -0,0 +1,75 @@
+package harborapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replication", func() {
+	var server *httptest.Server
+	var api *Client
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch {
+			case req.URL.Path == "/api/registries" && req.Method == http.MethodGet:
+				Expect(req.URL.Query().Get("name")).To(Equal("docker-hub"))
+				_, _ = w.Write([]byte(`[{"id":2,"name":"docker-hub-mirror"},{"id":1,"name":"docker-hub","type":"docker-hub","credential":{"type":"basic","access_key":"user"}}]`))
+			case req.URL.Path == "/api/replication/policies" && req.Method == http.MethodPost:
+				w.Header().Set("Location", "/api/replication/policies/5")
+				w.WriteHeader(http.StatusCreated)
+			case req.URL.Path == "/api/replication/executions":
+				Expect(req.URL.Query().Get("policy_id")).To(Equal("5"))
+				_, _ = w.Write([]byte(`[{"id":8,"status":"Failed","total":3,"failed":1},{"id":9,"status":"InProgress","total":3,"in_progress":3},{"id":7,"status":"Succeed"}]`))
+			default:
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}))
+
+		u, err := url.Parse(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+
+		api = &Client{
+			BaseURL:    u,
+			Username:   AdminUsername,
+			HTTPClient: server.Client(),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should find the registry with the exact name", func() {
+		registry, err := api.GetRegistryByName(context.TODO(), "docker-hub")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(registry).ToNot(BeNil())
+		Expect(registry.ID).To(BeEquivalentTo(1))
+		Expect(registry.Credential).ToNot(BeNil())
+		Expect(registry.Credential.AccessKey).To(Equal("user"))
+	})
+
+	It("Should return the ID of the created policy", func() {
+		id, err := api.CreateReplicationPolicy(context.TODO(), &ReplicationPolicy{Name: "docker-hub-library"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(BeEquivalentTo(5))
+	})
+
+	It("Should return the latest execution", func() {
+		execution, err := api.GetLastReplicationExecution(context.TODO(), 5)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(execution).ToNot(BeNil())
+		Expect(execution.ID).To(BeEquivalentTo(9))
+		Expect(execution.Status).To(Equal(ExecutionRunning))
+		Expect(execution.InProgress).To(BeEquivalentTo(3))
+	})
+
+	It("Should not return an anonymous credential", func() {
+		Expect(NewBasicCredential("", "")).To(BeNil())
+	})
+})
@@ -0,0 +1,98 @@
+package harborapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+const (
+	retentionsPath = "/retentions"
+)
+
+const (
+	// ProjectRetentionIDKey is the project metadata referencing the retention policy of the project
+	ProjectRetentionIDKey = "retention_id"
+
+	RetentionAlgorithmOr     = "or"
+	RetentionActionRetain    = "retain"
+	RetentionScopeProject    = "project"
+	RetentionTriggerSchedule = "Schedule"
+
+	SelectorKindDoublestar   = "doublestar"
+	SelectorMatches          = "matches"
+	SelectorExcludes         = "excludes"
+	SelectorRepoMatches      = "repoMatches"
+	SelectorRepoExcludes     = "repoExcludes"
+	ScopeSelectorRepository  = "repository"
+	RetentionCronSettingsKey = "cron"
+)
+
+// Selector selects tags or repositories matching a pattern.
+type Selector struct {
+	Kind       string `json:"kind"`
+	Decoration string `json:"decoration"`
+	Pattern    string `json:"pattern"`
+}
+
+type RetentionRule struct {
+	ID             int64                  `json:"id,omitempty"`
+	Priority       int                    `json:"priority,omitempty"`
+	Disabled       bool                   `json:"disabled"`
+	Action         string                 `json:"action"`
+	Template       string                 `json:"template"`
+	Params         map[string]interface{} `json:"params,omitempty"`
+	TagSelectors   []Selector             `json:"tag_selectors"`
+	ScopeSelectors map[string][]Selector  `json:"scope_selectors"`
+}
+
+type RetentionTrigger struct {
+	Kind     string                 `json:"kind"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+type RetentionScope struct {
+	Level string `json:"level"`
+	Ref   int64  `json:"ref"`
+}
+
+type RetentionPolicy struct {
+	ID        int64             `json:"id,omitempty"`
+	Algorithm string            `json:"algorithm"`
+	Rules     []RetentionRule   `json:"rules"`
+	Trigger   *RetentionTrigger `json:"trigger"`
+	Scope     *RetentionScope   `json:"scope"`
+}
+
+// GetRetentionID returns the ID of the retention policy of the project, 0 if it has none.
+func (p *Project) GetRetentionID() (int64, error) {
+	value, ok := p.Metadata[ProjectRetentionIDKey]
+	if !ok || value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func (c *Client) GetRetentionPolicy(ctx context.Context, id int64) (*RetentionPolicy, error) {
+	policy := &RetentionPolicy{}
+
+	err := c.Get(ctx, fmt.Sprintf("%s/%d", retentionsPath, id), policy)
+
+	return policy, err
+}
+
+// CreateRetentionPolicy creates the policy and returns its ID.
+// Harbor references it in the metadata of the project.
+func (c *Client) CreateRetentionPolicy(ctx context.Context, policy *RetentionPolicy) (int64, error) {
+	res, err := c.Post(ctx, retentionsPath, policy)
+	if err != nil {
+		return 0, err
+	}
+
+	return idFromLocation(res.Header.Get("Location"))
+}
+
+func (c *Client) UpdateRetentionPolicy(ctx context.Context, id int64, policy *RetentionPolicy) error {
+	return c.Put(ctx, fmt.Sprintf("%s/%d", retentionsPath, id), policy)
+}
@@ -0,0 +1,79 @@
+package harborapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tag rules", func() {
+	var server *httptest.Server
+	var api *Client
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch {
+			case req.URL.Path == "/api/projects" && req.Method == http.MethodGet:
+				_, _ = w.Write([]byte(`[{"project_id":1,"name":"library","metadata":{"retention_id":"4"}}]`))
+			case req.URL.Path == "/api/projects/2":
+				_, _ = w.Write([]byte(`{"project_id":2,"name":"ci","metadata":{}}`))
+			case req.URL.Path == "/api/retentions" && req.Method == http.MethodPost:
+				w.Header().Set("Location", "/api/retentions/5")
+				w.WriteHeader(http.StatusCreated)
+			case req.URL.Path == "/api/projects/1/immutabletagrules" && req.Method == http.MethodGet:
+				_, _ = w.Write([]byte(`[{"id":3,"project_id":1,"tag_selectors":[{"kind":"doublestar","decoration":"matches","pattern":"v*"}]}]`))
+			default:
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}))
+
+		u, err := url.Parse(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+
+		api = &Client{
+			BaseURL:    u,
+			Username:   AdminUsername,
+			HTTPClient: server.Client(),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should return the retention policy of the project", func() {
+		project, err := api.GetProjectByName(context.TODO(), "library")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.ID).To(BeEquivalentTo(1))
+
+		id, err := project.GetRetentionID()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(BeEquivalentTo(4))
+	})
+
+	It("Should not return projects without retention policy", func() {
+		project, err := api.GetProject(context.TODO(), 2)
+		Expect(err).ToNot(HaveOccurred())
+
+		id, err := project.GetRetentionID()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(BeZero())
+	})
+
+	It("Should return the ID of the created retention policy", func() {
+		id, err := api.CreateRetentionPolicy(context.TODO(), &RetentionPolicy{Algorithm: RetentionAlgorithmOr})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(BeEquivalentTo(5))
+	})
+
+	It("Should list immutable tag rules", func() {
+		rules, err := api.ListImmutableTagRules(context.TODO(), 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(HaveLen(1))
+		Expect(rules[0].TagSelectors[0].Pattern).To(Equal("v*"))
+	})
+})
@@ -0,0 +1,89 @@
+package harborapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// RobotPrefix is prepended by Harbor to robot account names.
+const RobotPrefix = "robot$"
+
+const (
+	RobotPullAction = "pull"
+	RobotPushAction = "push"
+)
+
+// NeverExpires is the expiration of robot accounts without expiration.
+const NeverExpires int64 = -1
+
+type RobotAccess struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+}
+
+// RobotReq is the body of robot account creation.
+type RobotReq struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	ExpiresAt   int64         `json:"expires_at,omitempty"`
+	Access      []RobotAccess `json:"access"`
+}
+
+// RobotCreated is the response of robot account creation, the token is not returned afterwards.
+type RobotCreated struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+type Robot struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ProjectID   int64  `json:"project_id"`
+	ExpiresAt   int64  `json:"expires_at"`
+	Disabled    bool   `json:"disabled"`
+}
+
+// RepositoryResource returns the resource of robot accesses to the repositories of the project.
+func RepositoryResource(projectID int64) string {
+	return fmt.Sprintf("/project/%d/repository", projectID)
+}
+
+func robotsPath(projectID int64) string {
+	return fmt.Sprintf("%s/%d/robots", projectsPath, projectID)
+}
+
+// CreateRobot creates the robot account in the project and returns its ID and token.
+func (c *Client) CreateRobot(ctx context.Context, projectID int64, robot *RobotReq) (int64, *RobotCreated, error) {
+	created := &RobotCreated{}
+
+	res, err := c.Do(ctx, http.MethodPost, robotsPath(projectID), robot, created)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	id, err := idFromLocation(res.Header.Get("Location"))
+
+	return id, created, err
+}
+
+func (c *Client) GetRobot(ctx context.Context, projectID, id int64) (*Robot, error) {
+	robot := &Robot{}
+
+	err := c.Get(ctx, fmt.Sprintf("%s/%d", robotsPath(projectID), id), robot)
+
+	return robot, err
+}
+
+func (c *Client) ListRobots(ctx context.Context, projectID int64) ([]Robot, error) {
+	var robots []Robot
+
+	err := c.Get(ctx, fmt.Sprintf("%s?page_size=%d", robotsPath(projectID), maxPageSize), &robots)
+
+	return robots, err
+}
+
+func (c *Client) DeleteRobot(ctx context.Context, projectID, id int64) error {
+	return c.Delete(ctx, fmt.Sprintf("%s/%d", robotsPath(projectID), id))
+}
@@ -0,0 +1,41 @@
+package harborapi
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	usersPath = "/users"
+
+	// AdminUserID is the ID of the admin user created at initialization
+	AdminUserID int64 = 1
+)
+
+type User struct {
+	ID       int64  `json:"user_id"`
+	Username string `json:"username"`
+}
+
+type PasswordReq struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// ChangePassword changes the password of the user.
+// Harbor requires at least 8 characters with an uppercase letter, a lowercase letter and a number.
+func (c *Client) ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword string) error {
+	return c.Put(ctx, fmt.Sprintf("%s/%d/password", usersPath, userID), &PasswordReq{
+		OldPassword: oldPassword,
+		NewPassword: newPassword,
+	})
+}
+
+// GetCurrentUser returns the user the client is authenticated as.
+func (c *Client) GetCurrentUser(ctx context.Context) (*User, error) {
+	user := &User{}
+
+	err := c.Get(ctx, fmt.Sprintf("%s/current", usersPath), user)
+
+	return user, err
+}
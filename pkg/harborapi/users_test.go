@@ -0,0 +1,64 @@
+package harborapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Users", func() {
+	var server *httptest.Server
+	var api *Client
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			username, password, _ := req.BasicAuth()
+			if username != AdminUsername || password != "Harbor12345" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+
+				return
+			}
+
+			switch {
+			case req.URL.Path == "/api/users/current" && req.Method == http.MethodGet:
+				_, _ = w.Write([]byte(`{"user_id":1,"username":"admin"}`))
+			case req.URL.Path == "/api/users/1/password" && req.Method == http.MethodPut:
+			default:
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}))
+
+		u, err := url.Parse(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+
+		api = &Client{
+			BaseURL:    u,
+			Username:   AdminUsername,
+			Password:   "Harbor12345",
+			HTTPClient: server.Client(),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should return the authenticated user", func() {
+		user, err := api.GetCurrentUser(context.TODO())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(user.ID).To(Equal(AdminUserID))
+		Expect(user.Username).To(Equal(AdminUsername))
+	})
+
+	It("Should report unauthorized requests", func() {
+		api.Password = "outdated"
+
+		err := api.ChangePassword(context.TODO(), AdminUserID, "outdated", "Harbor12345")
+		Expect(IsUnauthorized(err)).To(BeTrue())
+		Expect(IsNotFound(err)).To(BeFalse())
+	})
+})
@@ -0,0 +1,76 @@
+package reconciliation
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/factories/logger"
+)
+
+const (
+	// DefaultResyncPeriod is the delay before a resource mirrored in Harbor is checked again.
+	// Harbor does not notify the operator, so changes made through the portal are only reverted then.
+	DefaultResyncPeriod = 5 * time.Minute
+)
+
+// Object is a resource reconciled by a controller.
+type Object interface {
+	metav1.Object
+	runtime.Object
+}
+
+// HasFinalizer returns whether the operator finalizer is registered on the resource.
+func HasFinalizer(obj metav1.Object) bool {
+	for _, finalizer := range obj.GetFinalizers() {
+		if finalizer == goharborv1alpha1.HarborFinalizer {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UpdateFinalizer registers the operator finalizer on the resource if wanted, removes it otherwise.
+// It returns whether the resource has been updated.
+func UpdateFinalizer(ctx context.Context, c client.Client, obj Object, wanted bool) (bool, error) {
+	if wanted == HasFinalizer(obj) {
+		return false, nil
+	}
+
+	if wanted {
+		controllerutil.AddFinalizer(obj, goharborv1alpha1.HarborFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(obj, goharborv1alpha1.HarborFinalizer)
+	}
+
+	err := c.Update(ctx, obj)
+
+	return true, errors.Wrap(err, "cannot update finalizers")
+}
+
+// UpdateStatus applies current in-memory statuses to the remote resource.
+// On conflict, the resource is requeued to be reconciled again with its latest version.
+func UpdateStatus(ctx context.Context, c client.Client, result *ctrl.Result, obj runtime.Object) error {
+	err := c.Status().Update(ctx, obj)
+	if err != nil {
+		result.Requeue = true
+
+		if apierrs.IsConflict(err) {
+			logger.Get(ctx).Error(err, "cannot update status field")
+			return nil
+		}
+
+		return errors.Wrap(err, "cannot update status field")
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package reconciliation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/scheme"
+)
+
+var _ = Describe("Reconciliation", func() {
+	var (
+		ctx     context.Context
+		project *goharborv1alpha1.HarborProject
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		project = &goharborv1alpha1.HarborProject{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "library",
+				Namespace:  "ns",
+				Finalizers: []string{"other"},
+			},
+		}
+	})
+
+	newClient := func(objects ...runtime.Object) client.Client {
+		s, err := scheme.New(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		return fake.NewFakeClientWithScheme(s, objects...)
+	}
+
+	Context("Finalizer", func() {
+		It("Should only update the resource when the finalizer changes", func() {
+			c := newClient(project)
+
+			updated, err := UpdateFinalizer(ctx, c, project, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated).To(BeTrue())
+			Expect(HasFinalizer(project)).To(BeTrue())
+
+			current := &goharborv1alpha1.HarborProject{}
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "library"}, current)).To(Succeed())
+			Expect(current.GetFinalizers()).To(ConsistOf("other", goharborv1alpha1.HarborFinalizer))
+
+			updated, err = UpdateFinalizer(ctx, c, project, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated).To(BeFalse())
+
+			updated, err = UpdateFinalizer(ctx, c, project, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated).To(BeTrue())
+			Expect(HasFinalizer(project)).To(BeFalse())
+
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "library"}, current)).To(Succeed())
+			Expect(current.GetFinalizers()).To(ConsistOf("other"))
+		})
+
+		It("Should fail when the resource does not exist", func() {
+			updated, err := UpdateFinalizer(ctx, newClient(), project, true)
+			Expect(err).To(HaveOccurred())
+			Expect(updated).To(BeTrue())
+		})
+	})
+
+	Context("Status", func() {
+		It("Should update the remote status", func() {
+			c := newClient(project)
+
+			project.Status.Conditions = []goharborv1alpha1.HarborCondition{{
+				Type:   goharborv1alpha1.ReadyConditionType,
+				Status: corev1.ConditionTrue,
+			}}
+
+			var result ctrl.Result
+			Expect(UpdateStatus(ctx, c, &result, project)).To(Succeed())
+			Expect(result.Requeue).To(BeFalse())
+
+			current := &goharborv1alpha1.HarborProject{}
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "library"}, current)).To(Succeed())
+			Expect(current.Status.Conditions).To(HaveLen(1))
+		})
+
+		It("Should requeue and fail when the resource does not exist", func() {
+			var result ctrl.Result
+			Expect(UpdateStatus(ctx, newClient(), &result, project)).ToNot(Succeed())
+			Expect(result.Requeue).To(BeTrue())
+		})
+	})
+})
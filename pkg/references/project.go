@@ -0,0 +1,52 @@
+package references
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+)
+
+// GetProject returns the project referenced by its HarborProject resource, in the namespace, or by its name.
+// The HarborProject must belong to the Harbor api is a client of.
+func GetProject(ctx context.Context, c client.Client, api *harborapi.Client, namespace, harborName string, reference goharborv1alpha1.ProjectReference) (*harborapi.Project, error) {
+	if reference.HarborProject != "" {
+		project := &goharborv1alpha1.HarborProject{}
+
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: reference.HarborProject}, project)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get harbor project %s", reference.HarborProject)
+		}
+
+		if project.Spec.HarborName != harborName {
+			return nil, errors.Errorf("harbor project %s belongs to harbor %s", project.GetName(), project.Spec.HarborName)
+		}
+
+		if project.Status.ProjectID == 0 {
+			return nil, errors.Errorf("harbor project %s is not created yet", project.GetName())
+		}
+
+		result, err := api.GetProject(ctx, project.Status.ProjectID)
+
+		return result, errors.Wrapf(err, "cannot get project %d", project.Status.ProjectID)
+	}
+
+	if reference.Name == "" {
+		return nil, errors.New("no project referenced")
+	}
+
+	result, err := api.GetProjectByName(ctx, reference.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get project %s", reference.Name)
+	}
+
+	if result == nil {
+		return nil, errors.Errorf("project %s not found", reference.Name)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package references
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/harborapi"
+	"github.com/goharbor/harbor-operator/pkg/scheme"
+)
+
+var _ = Describe("Project reference", func() {
+	var server *httptest.Server
+	var api *harborapi.Client
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Path == "/api/projects" && req.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`[{"project_id":1,"name":"library"}]`))
+				return
+			}
+
+			if req.URL.Path == "/api/projects/1" && req.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`{"project_id":1,"name":"library"}`))
+				return
+			}
+
+			http.Error(w, "not found", http.StatusNotFound)
+		}))
+
+		u, err := url.Parse(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+
+		api = &harborapi.Client{
+			BaseURL:    u,
+			Username:   harborapi.AdminUsername,
+			HTTPClient: server.Client(),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should return the project referenced by its name", func() {
+		project, err := GetProject(context.TODO(), nil, api, "ns", "harbor", goharborv1alpha1.ProjectReference{Name: "library"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.ID).To(BeEquivalentTo(1))
+
+		_, err = GetProject(context.TODO(), nil, api, "ns", "harbor", goharborv1alpha1.ProjectReference{Name: "other"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should require a project reference", func() {
+		_, err := GetProject(context.TODO(), nil, api, "ns", "harbor", goharborv1alpha1.ProjectReference{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("With a HarborProject", func() {
+		newProject := func(harborName string, projectID int64) *goharborv1alpha1.HarborProject {
+			return &goharborv1alpha1.HarborProject{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "library",
+					Namespace: "ns",
+				},
+				Spec: goharborv1alpha1.HarborProjectSpec{
+					HarborName: harborName,
+				},
+				Status: goharborv1alpha1.HarborProjectStatus{
+					ProjectID: projectID,
+				},
+			}
+		}
+
+		newClient := func(objects ...runtime.Object) client.Client {
+			s, err := scheme.New(context.TODO())
+			Expect(err).ToNot(HaveOccurred())
+
+			return fake.NewFakeClientWithScheme(s, objects...)
+		}
+
+		reference := goharborv1alpha1.ProjectReference{HarborProject: "library"}
+
+		It("Should return the project created for the HarborProject", func() {
+			c := newClient(newProject("harbor", 1))
+
+			project, err := GetProject(context.TODO(), c, api, "ns", "harbor", reference)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(project.ID).To(BeEquivalentTo(1))
+		})
+
+		It("Should fail when the HarborProject does not exist", func() {
+			_, err := GetProject(context.TODO(), newClient(), api, "ns", "harbor", reference)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should fail when the HarborProject belongs to another harbor", func() {
+			c := newClient(newProject("other", 1))
+
+			_, err := GetProject(context.TODO(), c, api, "ns", "harbor", reference)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should fail when the project is not created yet", func() {
+			c := newClient(newProject("harbor", 0))
+
+			_, err := GetProject(context.TODO(), c, api, "ns", "harbor", reference)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
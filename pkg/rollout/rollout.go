@@ -0,0 +1,21 @@
+package rollout
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// IsComplete returns whether all pods of the deployment run its current template.
+func IsComplete(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.GetGeneration() {
+		return false
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	return deployment.Status.UpdatedReplicas >= replicas &&
+		deployment.Status.Replicas <= deployment.Status.UpdatedReplicas &&
+		deployment.Status.AvailableReplicas >= deployment.Status.UpdatedReplicas
+}
@@ -0,0 +1,32 @@
+package rollout
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Deployment rollout", func() {
+	It("Should wait for all pods to run the current template", func() {
+		replicas := int32(2)
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				Replicas:           3,
+				UpdatedReplicas:    2,
+				AvailableReplicas:  2,
+			},
+		}
+		Expect(IsComplete(deployment)).To(BeFalse())
+
+		deployment.Status.Replicas = 2
+		Expect(IsComplete(deployment)).To(BeTrue())
+
+		deployment.Status.ObservedGeneration = 1
+		Expect(IsComplete(deployment)).To(BeFalse())
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/ovh/configstore"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -11,6 +12,27 @@ import (
 	goharborv1alpha1 "github.com/goharbor/harbor-operator/api/v1alpha1"
 )
 
+const (
+	CertManagerKey     = "cert-manager-enabled"
+	DefaultCertManager = true
+)
+
+// IsCertManagerEnabled returns whether cert-manager resources are managed.
+// cert-manager CRDs must be installed in the cluster when enabled.
+func IsCertManagerEnabled() (bool, error) {
+	enabled, err := configstore.Filter().GetItemValueBool(CertManagerKey)
+	if err != nil {
+		_, ok := err.(configstore.ErrItemNotFound)
+		if !ok {
+			return false, errors.Wrapf(err, "key %s", CertManagerKey)
+		}
+
+		enabled = DefaultCertManager
+	}
+
+	return enabled, nil
+}
+
 func New(ctx context.Context) (*runtime.Scheme, error) {
 	scheme := runtime.NewScheme()
 
@@ -19,9 +41,16 @@ func New(ctx context.Context) (*runtime.Scheme, error) {
 		return nil, errors.Wrap(err, "unable to configure native scheme")
 	}
 
-	err = certv1.AddToScheme(scheme)
+	certManager, err := IsCertManagerEnabled()
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to configure certificate-manager scheme")
+		return nil, errors.Wrap(err, "unable to get cert-manager configuration")
+	}
+
+	if certManager {
+		err = certv1.AddToScheme(scheme)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to configure certificate-manager scheme")
+		}
 	}
 
 	err = goharborv1alpha1.AddToScheme(scheme)